@@ -342,6 +342,72 @@ func TestDurationAggregation(t *testing.T) {
 	}
 }
 
+func TestDurationAggregationBuckets(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+	defer c.Close()
+
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+
+	type Record struct {
+		Timestamp  int64 `frostdb:",asc"`
+		Stacktrace string
+		Value      int64
+	}
+
+	table, err := NewGenericTable[Record](db, "test", memory.NewGoAllocator())
+	require.NoError(t, err)
+	defer table.Release()
+
+	_, err = table.Write(context.Background(),
+		Record{Timestamp: int64(1 * time.Second), Stacktrace: "stack1", Value: 1},
+		Record{Timestamp: int64(4 * time.Second), Stacktrace: "stack1", Value: 2},
+		Record{Timestamp: int64(9 * time.Second), Stacktrace: "stack2", Value: 3},
+		Record{Timestamp: int64(12 * time.Second), Stacktrace: "stack1", Value: 4},
+		Record{Timestamp: int64(17 * time.Second), Stacktrace: "stack2", Value: 5},
+		Record{Timestamp: int64(19 * time.Second), Stacktrace: "stack2", Value: 6},
+	)
+	require.NoError(t, err)
+
+	engine := query.NewEngine(memory.DefaultAllocator, db.TableProvider())
+
+	type group struct {
+		timestamp  int64
+		stacktrace string
+	}
+	result := map[group]int64{}
+	require.NoError(t, engine.ScanTable("test").
+		Aggregate(
+			[]*logicalplan.AggregationFunction{
+				logicalplan.Sum(logicalplan.Col("value")),
+			},
+			[]logicalplan.Expr{
+				logicalplan.Duration(10 * time.Second),
+				logicalplan.Col("stacktrace"),
+			},
+		).
+		Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+			timestamps := r.Column(r.Schema().FieldIndices("timestamp")[0]).(*array.Int64)
+			stacktraces := r.Column(r.Schema().FieldIndices("stacktrace")[0])
+			sums := r.Column(r.Schema().FieldIndices("sum(value)")[0]).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				result[group{
+					timestamp:  timestamps.Value(i),
+					stacktrace: stacktraces.ValueStr(i),
+				}] += sums.Value(i)
+			}
+			return nil
+		}))
+
+	require.Equal(t, map[group]int64{
+		{timestamp: 0, stacktrace: "stack1"}:                       3,
+		{timestamp: 0, stacktrace: "stack2"}:                       3,
+		{timestamp: int64(10 * time.Second), stacktrace: "stack1"}: 4,
+		{timestamp: int64(10 * time.Second), stacktrace: "stack2"}: 11,
+	}, result)
+}
+
 // go test -bench=BenchmarkAggregation -benchmem -count=10 . | tee BenchmarkAggregation
 
 func BenchmarkAggregation(b *testing.B) {
@@ -0,0 +1,600 @@
+package frostdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	tablepb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/table/v1alpha1"
+)
+
+// DefaultBlockCompactionInterval is the default interval at which databases
+// compact the persisted blocks of their tables.
+const DefaultBlockCompactionInterval = 5 * time.Minute
+
+// resolutionKey is the key of the Parquet metadata that holds the resolution
+// in milliseconds the rows of a block were downsampled to.
+const resolutionKey = "downsampling_resolution_ms"
+
+// replacedBlocks are blocks of a table that were replaced by a compacted block
+// in a sink and are yet to be deleted from it.
+type replacedBlocks struct {
+	table  *Table
+	sink   CompactionSink
+	blocks []ulid.ULID
+}
+
+// startBlockCompaction starts a goroutine that periodically compacts the
+// persisted blocks of the database's tables. It is a no-op if the database has
+// no sinks or the block compaction interval is disabled.
+func (db *DB) startBlockCompaction() {
+	interval := db.columnStore.blockCompactionInterval
+	if interval <= 0 || len(db.sinks) == 0 {
+		return
+	}
+
+	var ctx context.Context
+	ctx, db.blockCompactionDone = context.WithCancel(context.Background())
+	db.blockCompactionWg.Add(1)
+	go func() {
+		defer db.blockCompactionWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := db.compactBlocks(ctx, time.Now()); err != nil {
+					level.Error(db.logger).Log("msg", "failed to compact blocks", "err", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopBlockCompaction stops the block compaction goroutine, if any, waits for
+// it to exit and deletes the blocks replaced by its last run.
+func (db *DB) stopBlockCompaction() {
+	if db.blockCompactionDone != nil {
+		db.blockCompactionDone()
+		db.blockCompactionWg.Wait()
+	}
+	if err := db.deleteReplacedBlocks(context.Background()); err != nil {
+		level.Warn(db.logger).Log("msg", "failed to delete replaced blocks", "err", err)
+	}
+}
+
+// compactBlocks compacts the persisted blocks of the tables with block
+// compaction enabled whose window has passed at the given time in all sinks
+// that support it. The blocks replaced by the previous run are deleted first:
+// they are kept around for one run so that scans that read the blocks of a
+// table before they were replaced can still read them. All tables are
+// processed even if compacting the blocks of one fails, in which case the
+// first error is returned. The deletes that no longer apply to any rows of a
+// table once its blocks are compacted are dropped. It must not be called
+// concurrently.
+func (db *DB) compactBlocks(ctx context.Context, now time.Time) error {
+	firstErr := db.deleteReplacedBlocks(ctx)
+
+	db.mtx.RLock()
+	tables := make([]*Table, 0, len(db.tables))
+	for _, table := range db.tables {
+		tables = append(tables, table)
+	}
+	db.mtx.RUnlock()
+
+	for _, table := range tables {
+		config := table.config.Load()
+		if config == nil || config.BlockCompactionWindowMs == 0 {
+			continue
+		}
+
+		// The deletes whose rows were all persisted can be dropped once
+		// they were applied to all the persisted blocks they apply to, which
+		// is only known if all of them are compacted.
+		var trim []*tombstone
+		if db.compactsAllBlocks() {
+			trim = table.persistedTombstones()
+		}
+		var kept []ulid.ULID
+		failed := false
+		for _, sink := range db.sinks {
+			cs, ok := sink.(CompactionSink)
+			if !ok {
+				continue
+			}
+
+			k, err := table.compactBlocks(ctx, cs, config, now, trim)
+			kept = append(kept, k...)
+			if err != nil {
+				failed = true
+				table.metrics.compactionsFailed.Inc()
+				level.Warn(db.logger).Log(
+					"msg", "failed to compact blocks",
+					"table", table.Name(),
+					"sink", sink.String(),
+					"err", err,
+				)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		if !failed {
+			// A delete still applies to the blocks older than its block
+			// that were left as they are.
+			table.dropTombstones(slices.DeleteFunc(trim, func(ts *tombstone) bool {
+				return slices.ContainsFunc(kept, func(block ulid.ULID) bool {
+					return block.Compare(ts.block) < 0
+				})
+			}))
+		}
+	}
+	return firstErr
+}
+
+// compactsAllBlocks returns whether the block compaction of the database
+// compacts every persisted block: all its sinks support compaction, it has no
+// sources that aren't sinks, and no blocks are replicated to secondary sinks
+// after they were persisted.
+func (db *DB) compactsAllBlocks() bool {
+	if len(db.sinks) > 1 && db.columnStore.replicationPolicy == ReplicateBestEffort {
+		return false
+	}
+	for _, sink := range db.sinks {
+		if _, ok := sink.(CompactionSink); !ok {
+			return false
+		}
+	}
+	for _, source := range db.sources {
+		if !slices.ContainsFunc(db.sinks, func(sink DataSink) bool { return any(sink) == any(source) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteReplacedBlocks deletes the blocks that were replaced by compacted
+// blocks. Blocks that fail to be deleted are retried by the next call.
+func (db *DB) deleteReplacedBlocks(ctx context.Context) error {
+	var firstErr error
+	replaced := db.replacedBlocks
+	db.replacedBlocks = nil
+	for _, r := range replaced {
+		if _, _, err := r.sink.DeleteBlocks(ctx, filepath.Join(db.name, r.table.Name()), r.blocks); err != nil {
+			level.Warn(db.logger).Log(
+				"msg", "failed to delete replaced blocks",
+				"table", r.table.Name(),
+				"sink", r.sink.String(),
+				"err", err,
+			)
+			db.replacedBlocks = append(db.replacedBlocks, r)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// compactBlocks merges the blocks of the table persisted to the given sink
+// whose ULID timestamps fall into the same compaction window once the window
+// has passed at the given time. Blocks that are alone in their window are
+// compacted as well if downsampling is enabled and they weren't downsampled to
+// the configured resolution yet, or if any of the given tombstones applies to
+// them, to remove their deleted rows. It returns the blocks that were left as
+// they are.
+func (t *Table) compactBlocks(ctx context.Context, sink CompactionSink, config *tablepb.TableConfig, now time.Time, purge []*tombstone) ([]ulid.ULID, error) {
+	prefix := filepath.Join(t.db.name, t.Name())
+	blocks, err := sink.PersistedBlocks(ctx, prefix, t.schema.Load())
+	if err != nil {
+		return nil, fmt.Errorf("list blocks: %w", err)
+	}
+
+	window := config.BlockCompactionWindowMs
+	var resolution time.Duration
+	if config.Downsampling != nil {
+		resolution = time.Duration(config.Downsampling.ResolutionMs) * time.Millisecond
+	}
+
+	windows := map[uint64][]PersistedBlock{}
+	for _, block := range blocks {
+		start := block.ULID.Time() / window * window
+		windows[start] = append(windows[start], block)
+	}
+	starts := make([]uint64, 0, len(windows))
+	for start := range windows {
+		starts = append(starts, start)
+	}
+	slices.Sort(starts)
+
+	var kept []ulid.ULID
+	for i, start := range starts {
+		if ulid.Time(start + window).After(now) {
+			for _, start := range starts[i:] {
+				for _, block := range windows[start] {
+					kept = append(kept, block.ULID)
+				}
+			}
+			break
+		}
+		group := windows[start]
+		if len(group) < 2 &&
+			(resolution == 0 || group[0].Resolution == resolution) &&
+			len(blockTombstones(purge, group[0].ULID)) == 0 {
+			kept = append(kept, group[0].ULID)
+			continue
+		}
+		if err := t.compactBlockGroup(ctx, sink, prefix, group, config.Downsampling); err != nil {
+			return kept, err
+		}
+	}
+	return kept, nil
+}
+
+// compactBlockGroup replaces the given blocks with a single block in the sink.
+func (t *Table) compactBlockGroup(ctx context.Context, sink CompactionSink, prefix string, group []PersistedBlock, downsampling *tablepb.Downsampling) error {
+	slices.SortFunc(group, func(a, b PersistedBlock) int {
+		return a.ULID.Compare(b.ULID)
+	})
+	data, err := t.compactPersistedBlocks(ctx, sink, prefix, group, downsampling)
+	if err != nil {
+		return err
+	}
+
+	block, err := compactedBlockULID(group)
+	if err != nil {
+		return err
+	}
+	replaced := make([]ulid.ULID, 0, len(group))
+	for _, b := range group {
+		replaced = append(replaced, b.ULID)
+	}
+	if err := sink.ReplaceBlocks(ctx, prefix, replaced, block, data, t.schema.Load()); err != nil {
+		return err
+	}
+
+	t.db.replacedBlocks = append(t.db.replacedBlocks, replacedBlocks{
+		table:  t,
+		sink:   sink,
+		blocks: replaced,
+	})
+	t.metrics.blocksCompacted.Add(float64(len(replaced)))
+	level.Debug(t.logger).Log(
+		"msg", "compacted blocks",
+		"table", t.Name(),
+		"sink", sink.String(),
+		"blocks", len(replaced),
+		"ulid", block,
+		"size", len(data),
+	)
+	return nil
+}
+
+// compactedBlockULID returns the ULID of the block the given blocks are
+// compacted into, which directly follows the newest of them. It has the
+// timestamp of the newest block, so that the compacted block is neither
+// removed by retention nor read in place of an in-memory block before any of
+// its blocks would have been. Being newer than all compacted blocks, the
+// deletes that apply to it applied to all of them. It is derived from the
+// compacted blocks, so that the blocks are compacted into the same block in
+// all sinks they are replicated to.
+func compactedBlockULID(group []PersistedBlock) (ulid.ULID, error) {
+	var newest ulid.ULID
+	for _, b := range group {
+		if b.ULID.Compare(newest) > 0 {
+			newest = b.ULID
+		}
+	}
+	// Increment the entropy, the last 10 bytes.
+	id := newest
+	for i := len(id) - 1; i >= 6; i-- {
+		id[i]++
+		if id[i] != 0 {
+			return id, nil
+		}
+	}
+	return ulid.ULID{}, fmt.Errorf("no ULID follows block %s", newest)
+}
+
+// compactPersistedBlocks reads the given blocks from the sink and merges them
+// into a single Parquet file, optionally downsampling their rows.
+func (t *Table) compactPersistedBlocks(ctx context.Context, sink CompactionSink, prefix string, group []PersistedBlock, downsampling *tablepb.Downsampling) ([]byte, error) {
+	t.tombstonesMtx.RLock()
+	tombstones := t.tombstones
+	t.tombstonesMtx.RUnlock()
+
+	rowGroups := make([]dynparquet.DynamicRowGroup, 0, len(group))
+	for _, b := range group {
+		data, err := sink.ReadBlock(ctx, prefix, b.ULID)
+		if err != nil {
+			return nil, fmt.Errorf("read block %s: %w", b.ULID, err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		buf, err := dynparquet.ReaderFromBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("open block %s: %w", b.ULID, err)
+		}
+		if buf.NumRows() == 0 {
+			continue
+		}
+
+		rg := buf.MultiDynamicRowGroup()
+		// Deletes are applied to persisted blocks depending on their ULID,
+		// so the ones that apply to this block might not apply to the
+		// compacted block anymore.
+		if ts := blockTombstones(tombstones, b.ULID); len(ts) > 0 {
+			if rg, err = t.purgeDeletedRowGroup(ctx, rg, ts); err != nil {
+				return nil, fmt.Errorf("purge deleted rows of block %s: %w", b.ULID, err)
+			}
+			if rg == nil {
+				continue
+			}
+		}
+		rowGroups = append(rowGroups, rg)
+	}
+
+	var options []parquet.WriterOption
+	if downsampling != nil {
+		options = append(options, parquet.KeyValueMetadata(resolutionKey, strconv.FormatUint(downsampling.ResolutionMs, 10)))
+	}
+
+	buf := &bytes.Buffer{}
+	if len(rowGroups) == 0 {
+		// All the rows were deleted.
+		if err := t.writeRecordsToParquet(buf, nil, false, options...); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	merged, err := t.schema.Load().MergeDynamicRowGroups(rowGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows parquet.Rows
+	if downsampling != nil {
+		downsampled, err := t.downsample(merged, downsampling)
+		if err != nil {
+			return nil, fmt.Errorf("downsample: %w", err)
+		}
+		rows = downsampled.Rows()
+	} else {
+		rows = merged.Rows()
+	}
+	defer rows.Close()
+
+	var rowReader parquet.RowReader = rows
+	if downsampling == nil && t.schema.Load().UniquePrimaryIndex {
+		rowReader = parquet.DedupeRowReader(rows, merged.Schema().Comparator(merged.SortingColumns()...))
+	}
+	if err := t.writeRows(buf, merged.DynamicColumns(), rowReader, options...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// purgeDeletedRowGroup returns the given sorted row group with the rows
+// deleted by the given tombstones removed, or nil if all of its rows were
+// deleted.
+func (t *Table) purgeDeletedRowGroup(ctx context.Context, rg dynparquet.DynamicRowGroup, tombstones []*tombstone) (dynparquet.DynamicRowGroup, error) {
+	record, err := t.applyTombstones(ctx, memory.NewGoAllocator(), rg, tombstones)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	defer record.Release()
+	if record.NumRows() == 0 {
+		return nil, nil
+	}
+
+	var b bytes.Buffer
+	if err := t.writeRecordsToParquet(&b, []arrow.Record{record}, false); err != nil {
+		return nil, err
+	}
+	buf, err := dynparquet.ReaderFromBytes(b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return buf.MultiDynamicRowGroup(), nil
+}
+
+// downsample truncates the values of the time column of the rows of the given
+// row group to the resolution and merges the rows that are then equal on all
+// sorting columns into one by summing up their other numeric columns. Other
+// columns keep the values of the first row merged.
+func (t *Table) downsample(rg dynparquet.DynamicRowGroup, config *tablepb.Downsampling) (*dynparquet.Buffer, error) {
+	schema := rg.Schema()
+	timeColumn := -1
+	var timeNode parquet.Node
+	var sumColumns []int
+	for i, path := range schema.Columns() {
+		leaf, ok := schema.Lookup(path...)
+		if !ok || len(path) != 1 || leaf.MaxRepetitionLevel > 0 {
+			continue
+		}
+		if path[0] == config.Column {
+			timeColumn = i
+			timeNode = leaf.Node
+			continue
+		}
+		if isSortingColumn(t.schema.Load(), path[0]) {
+			continue
+		}
+		switch leaf.Node.Type().Kind() {
+		case parquet.Int32, parquet.Int64, parquet.Float, parquet.Double:
+			sumColumns = append(sumColumns, i)
+		}
+	}
+	if timeColumn == -1 {
+		return nil, fmt.Errorf("time column %q not found", config.Column)
+	}
+
+	// Truncate the time of all rows and sort them again, since rows that
+	// are only ordered by time after other sorting columns may change their
+	// order.
+	truncated, err := t.schema.Load().NewBuffer(rg.DynamicColumns())
+	if err != nil {
+		return nil, err
+	}
+	resolution := downsamplingResolution(timeNode, config.ResolutionMs)
+	batch := make([]parquet.Row, 1024)
+	if err := readAllRows(rg.Rows(), batch, func(rows []parquet.Row) error {
+		for _, row := range rows {
+			for j, v := range row {
+				if v.Column() != timeColumn || v.IsNull() {
+					continue
+				}
+				ts := v.Int64()
+				ts -= ((ts % resolution) + resolution) % resolution
+				row[j] = parquet.Int64Value(ts).Level(v.RepetitionLevel(), v.DefinitionLevel(), timeColumn)
+			}
+		}
+		_, err := truncated.WriteRows(rows)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	truncated.Sort()
+
+	downsampled, err := t.schema.Load().NewBuffer(rg.DynamicColumns())
+	if err != nil {
+		return nil, err
+	}
+	compare := truncated.Schema().Comparator(truncated.SortingColumns()...)
+	var current parquet.Row
+	if err := readAllRows(truncated.Rows(), batch, func(rows []parquet.Row) error {
+		for _, row := range rows {
+			if current != nil && compare(current, row) == 0 {
+				sumRows(current, row, sumColumns)
+				continue
+			}
+			if current != nil {
+				if _, err := downsampled.WriteRows([]parquet.Row{current}); err != nil {
+					return err
+				}
+			}
+			current = row.Clone()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		if _, err := downsampled.WriteRows([]parquet.Row{current}); err != nil {
+			return nil, err
+		}
+	}
+	return downsampled, nil
+}
+
+// readAllRows reads all rows in batches of the given buffer's size, calls fn
+// with each batch and closes the rows.
+func readAllRows(rows parquet.Rows, batch []parquet.Row, fn func([]parquet.Row) error) error {
+	defer rows.Close()
+	for {
+		n, err := rows.ReadRows(batch)
+		if n > 0 {
+			if err := fn(batch[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sumRows adds the values of the given columns of src to the ones of dst.
+// Null values are treated as absent.
+func sumRows(dst, src parquet.Row, columns []int) {
+	for _, column := range columns {
+		i := slices.IndexFunc(dst, func(v parquet.Value) bool { return v.Column() == column })
+		j := slices.IndexFunc(src, func(v parquet.Value) bool { return v.Column() == column })
+		if i == -1 || j == -1 || src[j].IsNull() {
+			continue
+		}
+		a, b := dst[i], src[j]
+		if a.IsNull() {
+			dst[i] = b
+			continue
+		}
+		var sum parquet.Value
+		switch a.Kind() {
+		case parquet.Int32:
+			sum = parquet.Int32Value(a.Int32() + b.Int32())
+		case parquet.Int64:
+			sum = parquet.Int64Value(a.Int64() + b.Int64())
+		case parquet.Float:
+			sum = parquet.FloatValue(a.Float() + b.Float())
+		case parquet.Double:
+			sum = parquet.DoubleValue(a.Double() + b.Double())
+		default:
+			continue
+		}
+		dst[i] = sum.Level(a.RepetitionLevel(), a.DefinitionLevel(), column)
+	}
+}
+
+// downsamplingResolution returns the given resolution in the unit of the
+// values of the time column. The values of timestamp columns are in the unit
+// of their timestamp type, the values of int64 columns are in milliseconds.
+func downsamplingResolution(node parquet.Node, resolutionMs uint64) int64 {
+	resolution := int64(resolutionMs)
+	if lt := node.Type().LogicalType(); lt != nil && lt.Timestamp != nil {
+		switch {
+		case lt.Timestamp.Unit.Micros != nil:
+			resolution *= int64(time.Millisecond / time.Microsecond)
+		case lt.Timestamp.Unit.Nanos != nil:
+			resolution *= int64(time.Millisecond)
+		}
+	}
+	return resolution
+}
+
+// validateDownsampling returns an error if the time column of the given
+// downsampling configuration isn't an int64 or timestamp sorting column of
+// the schema, or if the resolution doesn't fit in the unit of its values.
+func validateDownsampling(schema *dynparquet.Schema, config *tablepb.Downsampling) error {
+	if schema == nil || config == nil {
+		return nil
+	}
+	def, ok := schema.FindColumn(config.Column)
+	if !ok || def.Dynamic {
+		return fmt.Errorf("downsampling column %q not found", config.Column)
+	}
+	typ := def.StorageLayout.Type()
+	if lt := typ.LogicalType(); typ.Kind() != parquet.Int64 || (lt != nil && lt.Integer == nil && lt.Timestamp == nil) {
+		return fmt.Errorf("downsampling column %q must be of type int64 or timestamp, got %v", config.Column, typ)
+	}
+	if !isSortingColumn(schema, config.Column) {
+		return fmt.Errorf("downsampling column %q must be a sorting column", config.Column)
+	}
+	if config.ResolutionMs == 0 {
+		return errors.New("downsampling resolution must be positive")
+	}
+	if config.ResolutionMs > math.MaxInt64/uint64(time.Millisecond) {
+		return fmt.Errorf("downsampling resolution %dms is too large", config.ResolutionMs)
+	}
+	return nil
+}
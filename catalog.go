@@ -0,0 +1,622 @@
+package frostdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	catalogpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/catalog/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/query/expr"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+// The catalog of a prefix consists of an index holding the metadata of all
+// blocks as of the last compaction and the deltas written since. Updates
+// append a delta instead of rewriting the index, so their cost doesn't grow
+// with the number of blocks. Once maxCatalogDeltas deltas have accumulated,
+// they are compacted into the index.
+//
+// Catalogs assume a single writer: only one process may persist blocks to a
+// prefix at a time, and updates within it are serialized by the catalogMtx
+// of the bucket. Deltas have unique names, but building a catalog and
+// compacting its deltas rewrite the index without conditional writes, which
+// object storage buckets don't generally support, so concurrent writers may
+// lose each other's updates.
+const (
+	// blockMetadataFile is the name of the object the metadata of a block is
+	// stored in, next to the data of the block.
+	blockMetadataFile = "metadata.pb"
+	// catalogDir is the directory under the prefix of a table the catalog
+	// of its blocks is stored in.
+	catalogDir = "catalog"
+	// catalogIndexFile is the name of the object the compacted metadata of
+	// all blocks of a table is stored in.
+	catalogIndexFile = "index.pb"
+	// catalogDeltaSuffix is the suffix of the names of the objects the
+	// deltas of a catalog are stored in. They are named by ULIDs, so their
+	// names sort in the order they were written.
+	catalogDeltaSuffix = ".delta.pb"
+	// maxCatalogDeltas is the number of deltas of a catalog after which they
+	// are compacted into its index.
+	maxCatalogDeltas = 32
+)
+
+// AddBlock writes the metadata of the given persisted block and adds it to
+// the catalog of the prefix. If the prefix has no catalog yet, it is built
+// from the blocks already stored under it.
+func (b *DefaultObjstoreBucket) AddBlock(ctx context.Context, prefix string, block ulid.ULID, schema *dynparquet.Schema) error {
+	ctx, span := b.tracer.Start(ctx, "Sink/AddBlock")
+	defer span.End()
+
+	blockDir := filepath.Join(prefix, block.String())
+	md, err := b.computeBlockMetadata(ctx, blockDir, block, schema)
+	if err != nil {
+		return fmt.Errorf("compute block metadata: %w", err)
+	}
+	if err := b.writeBlockMetadata(ctx, blockDir, md); err != nil {
+		return err
+	}
+
+	metadataName := filepath.Join(blockDir, blockMetadataFile)
+	if err := b.updateCatalog(ctx, prefix, schema, &catalogpb.CatalogDelta{
+		Added: []*catalogpb.BlockMetadata{md},
+	}); err != nil {
+		// Don't leave metadata of a block behind that isn't in the catalog.
+		if deleteErr := b.Delete(ctx, metadataName); deleteErr != nil {
+			err = fmt.Errorf("%v failed to delete block metadata on error: %w", err, deleteErr)
+		}
+		return fmt.Errorf("update catalog: %w", err)
+	}
+	return nil
+}
+
+// readCatalog returns the catalog of the given prefix or nil if the prefix
+// has no catalog.
+func (b *DefaultObjstoreBucket) readCatalog(ctx context.Context, prefix string) (*catalogpb.Catalog, error) {
+	c, _, err := b.loadCatalog(ctx, prefix)
+	return c, err
+}
+
+// loadCatalog returns the catalog of the given prefix with its deltas
+// applied and the names of the deltas, or nil if the prefix has no catalog.
+func (b *DefaultObjstoreBucket) loadCatalog(ctx context.Context, prefix string) (*catalogpb.Catalog, []string, error) {
+	for {
+		// The deltas are listed before the index is read, so that deltas
+		// compacted in between are either read or found missing.
+		deltas, err := b.catalogDeltas(ctx, prefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := b.readObject(ctx, filepath.Join(prefix, catalogDir, catalogIndexFile))
+		if err != nil {
+			if b.IsObjNotFoundErr(err) {
+				return nil, nil, nil
+			}
+			return nil, nil, fmt.Errorf("read catalog: %w", err)
+		}
+		c := &catalogpb.Catalog{}
+		if err := c.UnmarshalVT(data); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal catalog: %w", err)
+		}
+
+		compacted := false
+		for _, name := range deltas {
+			data, err := b.readObject(ctx, name)
+			if err != nil {
+				if b.IsObjNotFoundErr(err) {
+					compacted = true
+					break
+				}
+				return nil, nil, fmt.Errorf("read catalog delta: %w", err)
+			}
+			d := &catalogpb.CatalogDelta{}
+			if err := d.UnmarshalVT(data); err != nil {
+				return nil, nil, fmt.Errorf("unmarshal catalog delta %s: %w", name, err)
+			}
+			applyCatalogDelta(c, d)
+		}
+		if !compacted {
+			return c, deltas, nil
+		}
+		// The deltas were compacted into the index after it was read, read
+		// the catalog again.
+	}
+}
+
+// catalogDeltas returns the names of the deltas of the catalog of the given
+// prefix in the order they were written.
+func (b *DefaultObjstoreBucket) catalogDeltas(ctx context.Context, prefix string) ([]string, error) {
+	var deltas []string
+	if err := b.Iter(ctx, filepath.Join(prefix, catalogDir), func(name string) error {
+		if strings.HasSuffix(name, catalogDeltaSuffix) {
+			deltas = append(deltas, name)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("list catalog deltas: %w", err)
+	}
+	slices.Sort(deltas)
+	return deltas, nil
+}
+
+func (b *DefaultObjstoreBucket) readObject(ctx context.Context, name string) ([]byte, error) {
+	rc, err := b.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// applyCatalogDelta applies the delta to the catalog. Applying deltas is
+// idempotent, so deltas that were already compacted into the index can be
+// applied again.
+func applyCatalogDelta(c *catalogpb.Catalog, d *catalogpb.CatalogDelta) {
+	removed := make(map[string]struct{}, len(d.Removed)+len(d.Added))
+	for _, block := range d.Removed {
+		removed[block] = struct{}{}
+	}
+	for _, md := range d.Added {
+		removed[md.Ulid] = struct{}{}
+	}
+	c.Blocks = slices.DeleteFunc(c.Blocks, func(md *catalogpb.BlockMetadata) bool {
+		_, ok := removed[md.Ulid]
+		return ok
+	})
+	c.Blocks = append(c.Blocks, d.Added...)
+}
+
+// updateCatalog appends the delta to the catalog of the given prefix. If the
+// prefix has no catalog yet, it is built from the blocks stored under it
+// unless no schema is given, in which case the delta is dropped.
+func (b *DefaultObjstoreBucket) updateCatalog(ctx context.Context, prefix string, schema *dynparquet.Schema, delta *catalogpb.CatalogDelta) error {
+	b.catalogMtx.Lock()
+	defer b.catalogMtx.Unlock()
+
+	exists, err := b.Exists(ctx, filepath.Join(prefix, catalogDir, catalogIndexFile))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if schema == nil {
+			// Nothing to update and not enough information to build it.
+			return nil
+		}
+		c, err := b.buildCatalog(ctx, prefix, schema)
+		if err != nil {
+			return fmt.Errorf("build catalog: %w", err)
+		}
+		applyCatalogDelta(c, delta)
+		return b.writeCatalogIndex(ctx, prefix, c)
+	}
+
+	data, err := delta.MarshalVT()
+	if err != nil {
+		return fmt.Errorf("marshal catalog delta: %w", err)
+	}
+	name := filepath.Join(prefix, catalogDir, ulid.Make().String()+catalogDeltaSuffix)
+	if err := b.Upload(ctx, name, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	deltas, err := b.catalogDeltas(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	if len(deltas) >= maxCatalogDeltas {
+		// The delta is stored, so failing to compact doesn't fail the
+		// update. The deltas are compacted with the next update instead.
+		if err := b.compactCatalog(ctx, prefix); err != nil {
+			level.Warn(b.logger).Log("msg", "failed to compact catalog", "prefix", prefix, "err", err)
+		}
+	}
+	return nil
+}
+
+// compactCatalog writes the catalog of the given prefix with its deltas
+// applied to its index and removes the deltas.
+func (b *DefaultObjstoreBucket) compactCatalog(ctx context.Context, prefix string) error {
+	c, deltas, err := b.loadCatalog(ctx, prefix)
+	if err != nil || c == nil {
+		return err
+	}
+	if err := b.writeCatalogIndex(ctx, prefix, c); err != nil {
+		return err
+	}
+	for _, name := range deltas {
+		if err := b.Delete(ctx, name); err != nil && !b.IsObjNotFoundErr(err) {
+			return fmt.Errorf("delete catalog delta %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (b *DefaultObjstoreBucket) writeCatalogIndex(ctx context.Context, prefix string, c *catalogpb.Catalog) error {
+	data, err := c.MarshalVT()
+	if err != nil {
+		return fmt.Errorf("marshal catalog: %w", err)
+	}
+	return b.Upload(ctx, filepath.Join(prefix, catalogDir, catalogIndexFile), bytes.NewReader(data))
+}
+
+// buildCatalog builds the catalog of the blocks stored under the given prefix
+// from their metadata. The metadata of blocks that were persisted without it
+// is computed from their data.
+func (b *DefaultObjstoreBucket) buildCatalog(ctx context.Context, prefix string, schema *dynparquet.Schema) (*catalogpb.Catalog, error) {
+	c := &catalogpb.Catalog{}
+	err := b.Iter(ctx, prefix, func(blockDir string) error {
+		block, ok := b.parseBlockDir(blockDir)
+		if !ok {
+			return nil
+		}
+		md, err := b.readBlockMetadata(ctx, blockDir)
+		if err != nil {
+			return err
+		}
+		if md == nil {
+			md, err = b.computeBlockMetadata(ctx, blockDir, block, schema)
+			if err != nil {
+				if b.IsObjNotFoundErr(err) {
+					// The directory holds no block data.
+					return nil
+				}
+				return err
+			}
+		}
+		c.Blocks = append(c.Blocks, md)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// removeFromCatalog removes the blocks with a ULID timestamp before the given
+// time from the catalog of the prefix, if it has one.
+func (b *DefaultObjstoreBucket) removeFromCatalog(ctx context.Context, prefix string, before time.Time) error {
+	c, err := b.readCatalog(ctx, prefix)
+	if err != nil || c == nil {
+		return err
+	}
+	delta := &catalogpb.CatalogDelta{}
+	for _, md := range c.Blocks {
+		block, err := ulid.Parse(md.Ulid)
+		if err == nil && ulid.Time(block.Time()).Before(before) {
+			delta.Removed = append(delta.Removed, md.Ulid)
+		}
+	}
+	if len(delta.Removed) == 0 {
+		return nil
+	}
+	return b.updateCatalog(ctx, prefix, nil, delta)
+}
+
+func (b *DefaultObjstoreBucket) writeBlockMetadata(ctx context.Context, blockDir string, md *catalogpb.BlockMetadata) error {
+	data, err := md.MarshalVT()
+	if err != nil {
+		return fmt.Errorf("marshal block metadata: %w", err)
+	}
+	if err := b.Upload(ctx, filepath.Join(blockDir, blockMetadataFile), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("upload block metadata: %w", err)
+	}
+	return nil
+}
+
+func (b *DefaultObjstoreBucket) readBlockMetadata(ctx context.Context, blockDir string) (*catalogpb.BlockMetadata, error) {
+	rc, err := b.Get(ctx, filepath.Join(blockDir, blockMetadataFile))
+	if err != nil {
+		if b.IsObjNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("read block metadata: %w", err)
+	}
+	md := &catalogpb.BlockMetadata{}
+	if err := md.UnmarshalVT(data); err != nil {
+		return nil, fmt.Errorf("unmarshal block metadata: %w", err)
+	}
+	return md, nil
+}
+
+func (b *DefaultObjstoreBucket) computeBlockMetadata(ctx context.Context, blockDir string, block ulid.ULID, schema *dynparquet.Schema) (*catalogpb.BlockMetadata, error) {
+	blockName := filepath.Join(blockDir, "data.parquet")
+	attribs, err := b.Attributes(ctx, blockName)
+	if err != nil {
+		return nil, err
+	}
+	md := &catalogpb.BlockMetadata{
+		Ulid: block.String(),
+		Size: attribs.Size,
+	}
+	if attribs.Size == 0 {
+		return md, nil
+	}
+
+	file, err := b.openBlockFile(ctx, blockName, attribs.Size)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := dynparquet.NewSerializedBuffer(file)
+	if err != nil {
+		return nil, err
+	}
+
+	md.Rows = buf.NumRows()
+	if v, ok := file.Lookup(resolutionKey); ok {
+		if md.ResolutionMs, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid resolution %q: %w", v, err)
+		}
+	}
+	for name, concrete := range buf.DynamicColumns() {
+		for _, c := range concrete {
+			md.DynamicColumns = append(md.DynamicColumns, name+"."+c)
+		}
+	}
+	slices.Sort(md.DynamicColumns)
+
+	for i, path := range file.Schema().Columns() {
+		if len(path) != 1 || !isSortingColumn(schema, path[0]) {
+			continue
+		}
+		chunks := make([]parquet.ColumnChunk, 0, len(file.RowGroups()))
+		for _, rg := range file.RowGroups() {
+			chunks = append(chunks, rg.ColumnChunks()[i])
+		}
+		stats, err := columnStatistics(path[0], chunks)
+		if err != nil {
+			// Columns without statistics are never used to prune the block.
+			level.Debug(b.logger).Log("msg", "no statistics for column", "block", block, "column", path[0], "err", err)
+			continue
+		}
+		md.Statistics = append(md.Statistics, stats)
+	}
+	return md, nil
+}
+
+// isSortingColumn returns whether the given concrete column is a sorting
+// column of the schema or a concrete column of a dynamic sorting column.
+func isSortingColumn(schema *dynparquet.Schema, column string) bool {
+	for _, sc := range schema.SortingColumns() {
+		name := sc.ColumnName()
+		if column == name {
+			return true
+		}
+		if _, ok := schema.FindDynamicColumn(name); ok && strings.HasPrefix(column, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// columnStatistics aggregates the column indexes of the given chunks of a
+// column.
+func columnStatistics(name string, chunks []parquet.ColumnChunk) (*catalogpb.ColumnStatistics, error) {
+	stats := &catalogpb.ColumnStatistics{Name: name}
+	var minValue, maxValue parquet.Value
+	for _, chunk := range chunks {
+		index, err := chunk.ColumnIndex()
+		if err != nil {
+			return nil, err
+		}
+		stats.NullCount += expr.NullCount(index)
+		stats.NumValues += chunk.NumValues()
+
+		typ := chunk.Type()
+		if v := expr.Min(index); !v.IsNull() && (minValue.IsNull() || typ.Compare(v, minValue) < 0) {
+			minValue = v.Clone()
+		}
+		if v := expr.Max(index); !v.IsNull() && (maxValue.IsNull() || typ.Compare(v, maxValue) > 0) {
+			maxValue = v.Clone()
+		}
+		stats.Kind = int32(typ.Kind())
+	}
+	if !minValue.IsNull() && !maxValue.IsNull() {
+		stats.Min = minValue.Bytes()
+		stats.Max = maxValue.Bytes()
+		stats.HasBounds = true
+	}
+	return stats, nil
+}
+
+// canPruneBlocks returns whether blocks can be pruned by the given filter
+// using their metadata. Aggregations need to see the statistics of all
+// columns they aggregate, which the metadata doesn't hold.
+func canPruneBlocks(filter logicalplan.Expr) bool {
+	if filter == nil {
+		return false
+	}
+	prunable := true
+	filter.Accept(expr.PreExprVisitorFunc(func(e logicalplan.Expr) bool {
+		if _, ok := e.(*logicalplan.AggregationFunction); ok {
+			prunable = false
+		}
+		return prunable
+	}))
+	return prunable
+}
+
+// dynamicFilterColumns returns the concrete columns of dynamic columns of the
+// schema that the filter refers to.
+func dynamicFilterColumns(schema *dynparquet.Schema, filter logicalplan.Expr) []string {
+	if schema == nil || filter == nil {
+		return nil
+	}
+	var columns []string
+	filter.Accept(expr.PreExprVisitorFunc(func(e logicalplan.Expr) bool {
+		if c, ok := e.(*logicalplan.Column); ok {
+			if _, ok := schema.FindDynamicColumnForConcreteColumn(c.ColumnName); ok {
+				columns = append(columns, c.ColumnName)
+			}
+		}
+		return true
+	}))
+	return columns
+}
+
+// mayContainUsefulData returns whether the block described by the metadata
+// may hold rows that satisfy the filter. The given dynamic columns the block
+// doesn't have are treated as holding only nulls, all other columns without
+// statistics as possibly satisfying the filter.
+func mayContainUsefulData(filter expr.TrueNegativeFilter, md *catalogpb.BlockMetadata, dynamicColumns []string) (bool, error) {
+	p, err := newBlockParticulate(md, dynamicColumns)
+	if err != nil {
+		return true, err
+	}
+	return filter.Eval(p, true)
+}
+
+// blockParticulate is an expr.Particulate of the statistics of a block. Each
+// column chunk summarizes the column across all row groups of the block.
+type blockParticulate struct {
+	schema *parquet.Schema
+	chunks []parquet.ColumnChunk
+}
+
+func newBlockParticulate(md *catalogpb.BlockMetadata, dynamicColumns []string) (*blockParticulate, error) {
+	group := parquet.Group{}
+	stats := make(map[string]*catalogpb.ColumnStatistics, len(md.Statistics))
+	for _, s := range md.Statistics {
+		if !s.HasBounds && s.NullCount != s.NumValues {
+			// The bounds of the non-null values are unknown.
+			continue
+		}
+		kind := parquet.Kind(s.Kind)
+		typ, ok := kindTypes[kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported column kind %v", kind)
+		}
+		group[s.Name] = parquet.Optional(parquet.Leaf(typ))
+		stats[s.Name] = s
+	}
+	for _, name := range dynamicColumns {
+		if _, ok := stats[name]; ok || slices.Contains(md.DynamicColumns, name) {
+			continue
+		}
+		group[name] = parquet.Optional(parquet.Leaf(parquet.ByteArrayType))
+		stats[name] = &catalogpb.ColumnStatistics{
+			Name:      name,
+			Kind:      int32(parquet.ByteArray),
+			NullCount: md.Rows,
+			NumValues: md.Rows,
+		}
+	}
+
+	schema := parquet.NewSchema("block", group)
+	p := &blockParticulate{schema: schema}
+	for i, field := range schema.Fields() {
+		s := stats[field.Name()]
+		index := &statsColumnIndex{nullCount: s.NullCount, numValues: s.NumValues}
+		if s.HasBounds {
+			var err error
+			if index.min, err = decodeValue(parquet.Kind(s.Kind), s.Min); err != nil {
+				return nil, err
+			}
+			if index.max, err = decodeValue(parquet.Kind(s.Kind), s.Max); err != nil {
+				return nil, err
+			}
+		}
+		p.chunks = append(p.chunks, &statsColumnChunk{
+			typ:    field.Type(),
+			column: i,
+			index:  index,
+		})
+	}
+	return p, nil
+}
+
+func (p *blockParticulate) Schema() *parquet.Schema { return p.schema }
+
+func (p *blockParticulate) ColumnChunks() []parquet.ColumnChunk { return p.chunks }
+
+var kindTypes = map[parquet.Kind]parquet.Type{
+	parquet.Boolean:           parquet.BooleanType,
+	parquet.Int32:             parquet.Int32Type,
+	parquet.Int64:             parquet.Int64Type,
+	parquet.Int96:             parquet.Int96Type,
+	parquet.Float:             parquet.FloatType,
+	parquet.Double:            parquet.DoubleType,
+	parquet.ByteArray:         parquet.ByteArrayType,
+	parquet.FixedLenByteArray: parquet.ByteArrayType,
+}
+
+// decodeValue decodes a value of the given kind from its plain encoding.
+func decodeValue(kind parquet.Kind, data []byte) (parquet.Value, error) {
+	size := 0
+	switch kind {
+	case parquet.Boolean:
+		size = 1
+	case parquet.Int32, parquet.Float:
+		size = 4
+	case parquet.Int64, parquet.Double:
+		size = 8
+	case parquet.Int96:
+		size = 12
+	}
+	if size != 0 && len(data) != size {
+		return parquet.Value{}, fmt.Errorf("invalid %v value of %d bytes", kind, len(data))
+	}
+	return kind.Value(data), nil
+}
+
+// statsColumnChunk is a parquet.ColumnChunk that only provides the column
+// index of a column.
+type statsColumnChunk struct {
+	typ    parquet.Type
+	column int
+	index  *statsColumnIndex
+}
+
+func (c *statsColumnChunk) Type() parquet.Type { return c.typ }
+
+func (c *statsColumnChunk) Column() int { return c.column }
+
+func (c *statsColumnChunk) Pages() parquet.Pages { return nil }
+
+func (c *statsColumnChunk) ColumnIndex() (parquet.ColumnIndex, error) { return c.index, nil }
+
+func (c *statsColumnChunk) OffsetIndex() (parquet.OffsetIndex, error) {
+	return nil, parquet.ErrMissingOffsetIndex
+}
+
+func (c *statsColumnChunk) BloomFilter() parquet.BloomFilter { return nil }
+
+func (c *statsColumnChunk) NumValues() int64 { return c.index.numValues }
+
+// statsColumnIndex is a parquet.ColumnIndex of a single page holding the
+// statistics of a column.
+type statsColumnIndex struct {
+	min, max  parquet.Value
+	nullCount int64
+	numValues int64
+}
+
+func (i *statsColumnIndex) NumPages() int { return 1 }
+
+func (i *statsColumnIndex) NullCount(int) int64 { return i.nullCount }
+
+func (i *statsColumnIndex) NullPage(int) bool { return i.nullCount == i.numValues }
+
+func (i *statsColumnIndex) MinValue(int) parquet.Value { return i.min }
+
+func (i *statsColumnIndex) MaxValue(int) parquet.Value { return i.max }
+
+func (i *statsColumnIndex) IsAscending() bool { return false }
+
+func (i *statsColumnIndex) IsDescending() bool { return false }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	frostdb "github.com/youscentia/ydb-frostdb"
+	"github.com/youscentia/ydb-frostdb/server"
+)
+
+const (
+	addressFlagName          = "address"
+	storagePathFlagName      = "storage-path"
+	walFlagName              = "wal"
+	activeMemorySizeFlagName = "active-memory-size"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "frostdb-server",
+	Short: "Serve FrostDB queries over gRPC",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		address, err := cmd.Flags().GetString(addressFlagName)
+		if err != nil {
+			return err
+		}
+		storagePath, err := cmd.Flags().GetString(storagePathFlagName)
+		if err != nil {
+			return err
+		}
+		enableWAL, err := cmd.Flags().GetBool(walFlagName)
+		if err != nil {
+			return err
+		}
+		activeMemorySize, err := cmd.Flags().GetInt64(activeMemorySizeFlagName)
+		if err != nil {
+			return err
+		}
+
+		options := []frostdb.Option{
+			frostdb.WithActiveMemorySize(activeMemorySize),
+		}
+		if storagePath != "" {
+			options = append(options, frostdb.WithStoragePath(storagePath))
+		}
+		if enableWAL {
+			options = append(options, frostdb.WithWAL())
+		}
+		return run(address, options...)
+	},
+}
+
+func init() {
+	rootCmd.Flags().String(addressFlagName, ":7777", "address to listen on for gRPC requests")
+	rootCmd.Flags().String(storagePathFlagName, "", "path to the directory databases are stored in")
+	rootCmd.Flags().Bool(walFlagName, false, "enable the write-ahead log (requires --"+storagePathFlagName+")")
+	rootCmd.Flags().Int64(activeMemorySizeFlagName, 512*frostdb.MiB, "size in bytes of the in-memory active block of each table")
+}
+
+func run(address string, options ...frostdb.Option) error {
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+
+	store, err := frostdb.New(append(options, frostdb.WithLogger(logger))...)
+	if err != nil {
+		return fmt.Errorf("open column store: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			level.Error(logger).Log("msg", "failed to close column store", "err", err)
+		}
+	}()
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", address, err)
+	}
+
+	srv := grpc.NewServer()
+	server.New(store, server.WithLogger(logger)).Register(srv)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		level.Info(logger).Log("msg", "shutting down")
+		srv.GracefulStop()
+	}()
+
+	level.Info(logger).Log("msg", "serving", "address", lis.Addr().String())
+	return srv.Serve(lis)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(fmt.Errorf("error running server: %w", err))
+		os.Exit(1)
+	}
+}
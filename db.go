@@ -59,10 +59,17 @@ type ColumnStore struct {
 	activeMemorySize    int64
 	storagePath         string
 	enableWAL           bool
+	walCompression      walpb.Entry_Write_Compression
 	manualBlockRotation bool
 	snapshotTriggerSize int64
 	metrics             globalMetrics
 	recoveryConcurrency int
+	retentionInterval   time.Duration
+
+	blockCompactionInterval time.Duration
+
+	replicationPolicy        ReplicationPolicy
+	replicationRetryInterval time.Duration
 
 	// indexDegree is the degree of the btree index (default = 2)
 	indexDegree int
@@ -103,6 +110,11 @@ func New(
 		indexDegree:         2,
 		splitSize:           2,
 		activeMemorySize:    512 * MiB,
+		retentionInterval:   DefaultRetentionInterval,
+
+		blockCompactionInterval: DefaultBlockCompactionInterval,
+
+		replicationRetryInterval: DefaultReplicationRetryInterval,
 	}
 
 	for _, option := range options {
@@ -119,8 +131,14 @@ func New(
 		return nil, fmt.Errorf("storage path must be configured if WAL is enabled")
 	}
 
+	if s.replicationPolicy == ReplicateBestEffort && len(s.sinks) > 1 {
+		if _, ok := s.sinks[0].(ReadableSink); !ok {
+			return nil, fmt.Errorf("best effort replication requires the primary sink %s to be readable", s.sinks[0])
+		}
+	}
+
 	for _, cfg := range s.indexConfig {
-		if cfg.Type == index.CompactionTypeParquetDisk {
+		if cfg.NewLevel == nil && cfg.Type == index.CompactionTypeParquetDisk {
 			if !s.enableWAL || s.storagePath == "" {
 				return nil, fmt.Errorf("persistent disk compaction requires WAL and storage path to be enabled")
 			}
@@ -212,6 +230,21 @@ func WithWAL() Option {
 	}
 }
 
+// WithWALCompression sets the compression of the arrow records written to the
+// WAL. Records are decoded regardless of the compression they were written
+// with, so it can be changed for databases with an existing WAL.
+func WithWALCompression(compression walpb.Entry_Write_Compression) Option {
+	return func(s *ColumnStore) error {
+		switch compression {
+		case walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED, walpb.Entry_Write_COMPRESSION_ZSTD:
+		default:
+			return fmt.Errorf("unsupported WAL compression: %s", compression)
+		}
+		s.walCompression = compression
+		return nil
+	}
+}
+
 func WithStoragePath(path string) Option {
 	return func(s *ColumnStore) error {
 		s.storagePath = path
@@ -272,6 +305,46 @@ func WithRecoveryConcurrency(concurrency int) Option {
 	}
 }
 
+// WithRetentionInterval sets how often each database removes the persisted
+// blocks of its tables that fall outside the table's retention (see
+// WithRetention). A non-positive interval disables the retention loop.
+func WithRetentionInterval(interval time.Duration) Option {
+	return func(s *ColumnStore) error {
+		s.retentionInterval = interval
+		return nil
+	}
+}
+
+// WithBlockCompactionInterval sets how often each database compacts the
+// persisted blocks of its tables (see WithBlockCompaction). A non-positive
+// interval disables the block compaction loop.
+func WithBlockCompactionInterval(interval time.Duration) Option {
+	return func(s *ColumnStore) error {
+		s.blockCompactionInterval = interval
+		return nil
+	}
+}
+
+// WithReplicationPolicy sets how blocks are persisted when more than one sink
+// is configured. The first sink is the primary sink, all others are
+// secondaries. See ReplicateToAll and ReplicateBestEffort.
+func WithReplicationPolicy(policy ReplicationPolicy) Option {
+	return func(s *ColumnStore) error {
+		s.replicationPolicy = policy
+		return nil
+	}
+}
+
+// WithReplicationRetryInterval sets how often each database retries uploading
+// blocks to the secondary sinks that failed to receive them under the
+// ReplicateBestEffort policy. A non-positive interval disables retries.
+func WithReplicationRetryInterval(interval time.Duration) Option {
+	return func(s *ColumnStore) error {
+		s.replicationRetryInterval = interval
+		return nil
+	}
+}
+
 // Close persists all data from the columnstore to storage.
 // It is no longer valid to use the coumnstore for reads or writes, and the object should not longer be reused.
 func (s *ColumnStore) Close() error {
@@ -377,6 +450,31 @@ type DB struct {
 
 	snapshotInProgress atomic.Bool
 
+	// durability is the Durability of writes that don't override it.
+	durability atomic.Int32
+
+	// retention goroutine lifecycle controls
+	retentionDone context.CancelFunc
+	retentionWg   sync.WaitGroup
+
+	// replication goroutine lifecycle controls and the blocks that failed to
+	// be uploaded to secondary sinks.
+	replicationDone context.CancelFunc
+	replicationWg   sync.WaitGroup
+	replication     replicationQueue
+
+	// block compaction goroutine lifecycle controls and the blocks that were
+	// replaced by compacted blocks and are yet to be deleted.
+	blockCompactionDone context.CancelFunc
+	blockCompactionWg   sync.WaitGroup
+	replacedBlocks      []replacedBlocks
+
+	// leader is the database this database follows, if any, and the
+	// follower goroutine lifecycle controls.
+	leader       Leader
+	followerDone context.CancelFunc
+	followerWg   sync.WaitGroup
+
 	metrics         snapshotMetrics
 	metricsProvider tableMetricsProvider
 }
@@ -387,7 +485,10 @@ type DataSinkSource interface {
 	DataSource
 }
 
-// DataSource is remote source of data that can be queried.
+// DataSource is remote source of data that can be queried. The row groups of
+// persisted blocks must be passed to the scan callback as BlockRowGroups, so
+// that the deletes performed after the blocks were persisted are applied to
+// them.
 type DataSource interface {
 	fmt.Stringer
 	Scan(ctx context.Context, prefix string, schema *dynparquet.Schema, filter logicalplan.Expr, lastBlockTimestamp uint64, callback func(context.Context, any) error) error
@@ -401,6 +502,100 @@ type DataSink interface {
 	Delete(ctx context.Context, name string) error
 }
 
+// RetentionSink is a DataSink that is able to remove expired blocks. Blocks
+// are only removed by the retention loop of a DB from sinks that implement it.
+type RetentionSink interface {
+	DataSink
+	// DeleteBlocksBefore removes the blocks stored under the given prefix
+	// whose ULID timestamp is before the given time. It returns the number of
+	// blocks and bytes that were removed.
+	DeleteBlocksBefore(ctx context.Context, prefix string, before time.Time) (int, int64, error)
+}
+
+// CatalogSink is a DataSink that keeps a catalog of the blocks persisted to
+// it, which allows it to prune blocks when scanning without listing or opening
+// them. Blocks are added to the catalog after they have been uploaded.
+type CatalogSink interface {
+	DataSink
+	// AddBlock adds the given block stored under the given prefix to the
+	// catalog of the prefix.
+	AddBlock(ctx context.Context, prefix string, block ulid.ULID, schema *dynparquet.Schema) error
+}
+
+// PersistedBlock describes a block persisted to a CompactionSink.
+type PersistedBlock struct {
+	ULID ulid.ULID
+	// Size is the size in bytes of the data of the block.
+	Size int64
+	// Resolution is the resolution the rows of the block were downsampled
+	// to, zero if they weren't.
+	Resolution time.Duration
+}
+
+// ReadableSink is a DataSink whose persisted blocks can be read back. The
+// primary sink must implement it under the ReplicateBestEffort policy, as
+// failed uploads to secondary sinks are retried with the data of the blocks
+// read back from it rather than held in memory until the retry.
+type ReadableSink interface {
+	DataSink
+	// ReadBlock returns the data of the given block stored under the given
+	// prefix.
+	ReadBlock(ctx context.Context, prefix string, block ulid.ULID) ([]byte, error)
+}
+
+// CompactionSink is a DataSink whose persisted blocks can be compacted. Blocks
+// are only compacted by the block compaction loop of a DB in sinks that
+// implement it.
+type CompactionSink interface {
+	ReadableSink
+	// PersistedBlocks returns the blocks stored under the given prefix.
+	PersistedBlocks(ctx context.Context, prefix string, schema *dynparquet.Schema) ([]PersistedBlock, error)
+	// ReplaceBlocks atomically replaces the given blocks stored under the
+	// given prefix with a new block holding the given data: scans either
+	// see all of the old blocks or the new one. The old blocks must remain
+	// readable until they are removed with DeleteBlocks.
+	ReplaceBlocks(ctx context.Context, prefix string, blocks []ulid.ULID, block ulid.ULID, data []byte, schema *dynparquet.Schema) error
+	// DeleteBlocks removes the given blocks stored under the given prefix.
+	// It returns the number of blocks and bytes that were removed.
+	DeleteBlocks(ctx context.Context, prefix string, blocks []ulid.ULID) (int, int64, error)
+}
+
+// MoveSink is a DataSink whose persisted blocks can be moved to another
+// prefix. The blocks of a table are moved when it is renamed or dropped, which
+// fails with ErrMoveUnsupported if a sink of the database doesn't implement it.
+type MoveSink interface {
+	DataSink
+	// MoveBlocks moves the blocks stored under the prefix from whose ULID
+	// timestamp is before the given time to the prefix to. It returns the
+	// number of blocks that were moved. Blocks are moved one at a time and
+	// moving them again after a failure completes the move, so a block may
+	// be stored under both prefixes in between but is never lost.
+	MoveBlocks(ctx context.Context, from, to string, before time.Time, schema *dynparquet.Schema) (int, error)
+}
+
+// BlockRowGroup is a row group read from a persisted block by a DataSource.
+// Reads of tables with deletes fail on other row groups, as the deletes can't
+// be applied to rows whose block is unknown.
+type BlockRowGroup interface {
+	dynparquet.DynamicRowGroup
+	// Block returns the ULID of the block the row group was read from.
+	Block() ulid.ULID
+}
+
+// BlockSource is a DataSource that is able to list and scan the blocks it
+// holds individually. When a DB has multiple sources that implement it, they
+// are treated as replicas of each other: every block is read from only one of
+// them, falling back to the next source that holds the block if reading it
+// fails.
+type BlockSource interface {
+	DataSource
+	// Blocks returns the ULIDs of the blocks stored under the given prefix.
+	Blocks(ctx context.Context, prefix string) ([]ulid.ULID, error)
+	// ScanBlock scans the given block stored under the given prefix like Scan
+	// scans all of them.
+	ScanBlock(ctx context.Context, prefix string, block ulid.ULID, schema *dynparquet.Schema, filter logicalplan.Expr, lastBlockTimestamp uint64, callback func(context.Context, any) error) error
+}
+
 type DBOption func(*DB) error
 
 func WithCompactionAfterOpen(compact bool, tableNames []string) DBOption {
@@ -489,6 +684,10 @@ func (s *ColumnStore) DB(ctx context.Context, name string, opts ...DBOption) (*D
 	if err := applyOptsToDB(db); err != nil {
 		return nil, err
 	}
+	if db.leader != nil {
+		// Blocks are only persisted by the leader.
+		db.sinks = nil
+	}
 
 	if dbSetupErr := func() error {
 		if db.storagePath != "" {
@@ -513,6 +712,9 @@ func (s *ColumnStore) DB(ctx context.Context, name string, opts ...DBOption) (*D
 				}
 
 				for _, prefix := range prefixes {
+					if prefix == droppedTablesDir {
+						continue
+					}
 					_, err := db.readOnlyTable(prefix)
 					if err != nil {
 						return err
@@ -521,6 +723,12 @@ func (s *ColumnStore) DB(ctx context.Context, name string, opts ...DBOption) (*D
 			}
 		}
 
+		if db.leader != nil {
+			// Followers apply the WAL of their leader instead of keeping
+			// their own.
+			return db.loadLeaderSnapshot(ctx)
+		}
+
 		if s.enableWAL {
 			if err := func() error {
 				// Unlock the store mutex while the WAL is replayed, otherwise
@@ -542,6 +750,7 @@ func (s *ColumnStore) DB(ctx context.Context, name string, opts ...DBOption) (*D
 						[]wal.Option{
 							wal.WithMetrics(s.metrics.metricsForFileWAL(name)),
 							wal.WithStoreMetrics(s.metrics.metricsForWAL(name)),
+							wal.WithCompression(s.walCompression),
 						}, s.testingOptions.walTestingOptions...,
 					)...,
 				)
@@ -600,6 +809,11 @@ func (s *ColumnStore) DB(ctx context.Context, name string, opts ...DBOption) (*D
 		}
 	}
 
+	db.startRetention()
+	db.startReplication()
+	db.startBlockCompaction()
+	db.startFollower()
+
 	s.dbs[name] = db
 	return db, nil
 }
@@ -724,22 +938,64 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 		}
 	}
 
-	// persistedTables is a map from a table name to the last transaction
-	// persisted.
-	persistedTables := make(map[string]uint64)
+	// history is the drops and renames of tables in the WAL. The persisted
+	// transactions and the deletes of a table are recorded under the name it
+	// has at the end of the replay.
+	var history tableHistory
+	type persistedEntry struct {
+		tx     uint64
+		table  string
+		nextTx uint64
+	}
+	type deleteEntry struct {
+		table string
+		ts    *tombstone
+	}
+	var (
+		persistedEntries []persistedEntry
+		deleteEntries    []deleteEntry
+	)
 	var lastTx uint64
 
 	start := time.Now()
-	if err := wal.Replay(snapshotTx+1, func(_ uint64, record *walpb.Record) error {
+	if err := wal.Replay(snapshotTx+1, func(tx uint64, record *walpb.Record) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 		switch e := record.Entry.EntryType.(type) {
 		case *walpb.Entry_TableBlockPersisted_:
-			persistedTables[e.TableBlockPersisted.TableName] = e.TableBlockPersisted.NextTx
+			persistedEntries = append(persistedEntries, persistedEntry{
+				tx:     tx,
+				table:  e.TableBlockPersisted.TableName,
+				nextTx: e.TableBlockPersisted.NextTx,
+			})
 			// The loaded snapshot might have persisted data, this is handled in
 			// the replay loop below.
 			return nil
+		case *walpb.Entry_Delete_:
+			ts, err := tombstoneFromProto(tx, e.Delete.Filter, e.Delete.BlockId)
+			if err != nil {
+				return err
+			}
+			deleteEntries = append(deleteEntries, deleteEntry{table: e.Delete.TableName, ts: ts})
+			return nil
+		case *walpb.Entry_TruncateTable_:
+			ts, err := tombstoneFromProto(tx, nil, e.TruncateTable.BlockId)
+			if err != nil {
+				return err
+			}
+			deleteEntries = append(deleteEntries, deleteEntry{table: e.TruncateTable.TableName, ts: ts})
+			return nil
+		case *walpb.Entry_DropTable_:
+			history = append(history, tableEvent{tx: tx, name: e.DropTable.TableName})
+			return nil
+		case *walpb.Entry_RenameTable_:
+			history = append(history, tableEvent{
+				tx:      tx,
+				name:    e.RenameTable.TableName,
+				newName: e.RenameTable.NewTableName,
+			})
+			return nil
 		default:
 			return nil
 		}
@@ -747,11 +1003,82 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 		return err
 	}
 
+	// persistedTables is a map from a table name to the last transaction
+	// persisted.
+	persistedTables := make(map[string]uint64)
+	for _, p := range persistedEntries {
+		if name, ok := history.resolve(p.tx, p.table); ok {
+			persistedTables[name] = p.nextTx
+		}
+	}
+	// deletes is a map from a table name to the tombstones of the deletes
+	// performed on it. Unlike writes, deletes are recovered even if the table
+	// was persisted after them since they apply to the blocks persisted
+	// before them. They are added to the tables once all tables are
+	// recovered, as a table might only be recreated by a later entry.
+	deletes := make(map[string][]*tombstone)
+	for _, d := range deleteEntries {
+		if name, ok := history.resolve(d.ts.tx, d.table); ok {
+			deletes[name] = append(deletes[name], d.ts)
+		}
+	}
+
 	// performSnapshot is set to true if a snapshot should be performed after
 	// replay. This is set in cases where there could be "dead bytes" in the
 	// WAL (i.e. entries that occupy space on disk but are useless).
 	performSnapshot := false
 
+	// replayWrite inserts a write logged at the given tx into its table.
+	replayWrite := func(tx uint64, entry *walpb.Entry_Write) error {
+		tableName := entry.TableName
+		resolvedName, ok := history.resolve(tx, tableName)
+		if !ok {
+			// The table is dropped by a later entry.
+			return nil
+		}
+		if lastPersistedTx, ok := persistedTables[resolvedName]; ok && tx < lastPersistedTx {
+			// This write has already been successfully persisted, so we can
+			// skip it.
+			return nil
+		}
+
+		table, err := db.GetTable(tableName)
+		var tableErr ErrTableNotFound
+		if errors.As(err, &tableErr) {
+			// This means the WAL was truncated at a point where this write
+			// was already successfully persisted to disk in more optimized
+			// form than the WAL.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get table: %w", err)
+		}
+
+		switch entry.Arrow {
+		case true:
+			// The ipc reader decompresses the record body according to
+			// its metadata, so records written with any compression
+			// can be replayed.
+			if _, ok := walpb.Entry_Write_Compression_name[int32(entry.Compression)]; !ok {
+				return fmt.Errorf("unsupported WAL record compression: %d", entry.Compression)
+			}
+			reader, err := ipc.NewReader(bytes.NewReader(entry.Data))
+			if err != nil {
+				return fmt.Errorf("create ipc reader: %w", err)
+			}
+			record, err := reader.Read()
+			if err != nil {
+				return fmt.Errorf("read record: %w", err)
+			}
+			defer reader.Release()
+			size := util.TotalRecordSize(record)
+			table.active.index.InsertPart(parts.NewArrowPart(tx, record, uint64(size), table.schema.Load(), parts.WithCompactionLevel(int(index.L0))))
+		default:
+			panic("parquet writes are deprecated")
+		}
+		return nil
+	}
+
 	if err := wal.Replay(snapshotTx+1, func(tx uint64, record *walpb.Record) error {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -775,7 +1102,12 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 				return err
 			}
 
-			nextNonPersistedTxn, wasPersisted := persistedTables[entry.TableName]
+			resolvedName, ok := history.resolve(tx, entry.TableName)
+			if !ok {
+				// The table is dropped by a later entry.
+				return nil
+			}
+			nextNonPersistedTxn, wasPersisted := persistedTables[resolvedName]
 			if wasPersisted && tx < nextNonPersistedTxn {
 				// This block has already been successfully persisted, so we can
 				// skip it. Note that if this new table block is the active
@@ -791,6 +1123,7 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 					db.mtx.Lock()
 					defer db.mtx.Unlock()
 					config := NewTableConfig(schema, FromConfig(entry.Config))
+					config.SchemaVersion = entry.Config.SchemaVersion
 					if _, ok := db.roTables[tableName]; ok {
 						table, err = db.promoteReadOnlyTableLocked(tableName, config)
 						if err != nil {
@@ -854,7 +1187,9 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 					return fmt.Errorf("initialize schema: %w", err)
 				}
 
-				table.schema = schema
+				// The schema of the table was altered.
+				table.config.Store(entry.Config)
+				table.schema.Store(schema)
 			}
 
 			table.active, err = newTableBlock(table, table.active.minTx, tx, id)
@@ -862,42 +1197,68 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 				return err
 			}
 		case *walpb.Entry_Write_:
-			entry := e.Write
-			tableName := entry.TableName
-			if lastPersistedTx, ok := persistedTables[tableName]; ok && tx < lastPersistedTx {
-				// This write has already been successfully persisted, so we can
-				// skip it.
+			return replayWrite(tx, e.Write)
+		case *walpb.Entry_Transaction_:
+			for _, write := range e.Transaction.Writes {
+				if err := replayWrite(tx, write); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *walpb.Entry_Delete_:
+			// Deletes were collected in the first replay pass.
+			return nil
+		case *walpb.Entry_DropTable_:
+			// Only tables loaded from the snapshot still exist, the entries
+			// of tables dropped by the WAL are skipped.
+			db.removeTable(e.DropTable.TableName)
+			return nil
+		case *walpb.Entry_TruncateTable_:
+			entry := e.TruncateTable
+			resolvedName, ok := history.resolve(tx, entry.TableName)
+			if !ok {
+				return nil
+			}
+			if lastPersistedTx, ok := persistedTables[resolvedName]; ok && tx < lastPersistedTx {
+				// The block created by the truncation has already been
+				// persisted.
 				return nil
 			}
 
-			table, err := db.GetTable(tableName)
+			table, err := db.GetTable(entry.TableName)
 			var tableErr ErrTableNotFound
 			if errors.As(err, &tableErr) {
-				// This means the WAL was truncated at a point where this write
-				// was already successfully persisted to disk in more optimized
-				// form than the WAL.
+				// The table is created by a later entry.
 				return nil
 			}
 			if err != nil {
 				return fmt.Errorf("get table: %w", err)
 			}
 
-			switch e.Write.Arrow {
-			case true:
-				reader, err := ipc.NewReader(bytes.NewReader(entry.Data))
-				if err != nil {
-					return fmt.Errorf("create ipc reader: %w", err)
-				}
-				record, err := reader.Read()
-				if err != nil {
-					return fmt.Errorf("read record: %w", err)
-				}
-				defer reader.Release()
-				size := util.TotalRecordSize(record)
-				table.active.index.InsertPart(parts.NewArrowPart(tx, record, uint64(size), table.schema, parts.WithCompactionLevel(int(index.L0))))
-			default:
-				panic("parquet writes are deprecated")
+			var id ulid.ULID
+			if err := id.UnmarshalBinary(entry.BlockId); err != nil {
+				return err
+			}
+			// The tombstone of the truncation was collected in the first
+			// replay pass.
+			table.mtx.Lock()
+			prev := table.active
+			err = table.truncateLocked(tx, id)
+			table.mtx.Unlock()
+			if err != nil {
+				return err
+			}
+			table.dropPendingBlock(prev)
+			return nil
+		case *walpb.Entry_RenameTable_:
+			entry := e.RenameTable
+			db.mtx.Lock()
+			_, ok := db.tables[entry.TableName]
+			_, exists := db.tables[entry.NewTableName]
+			if ok && !exists {
+				db.renameTableLocked(entry.TableName, entry.NewTableName)
 			}
+			db.mtx.Unlock()
 			return nil
 		case *walpb.Entry_TableBlockPersisted_:
 			// If a block was persisted but the entry still exists in the WAL,
@@ -915,6 +1276,20 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 		return err
 	}
 
+	for tableName, tombstones := range deletes {
+		table, err := db.GetTable(tableName)
+		var tableErr ErrTableNotFound
+		if errors.As(err, &tableErr) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get table: %w", err)
+		}
+		for _, ts := range tombstones {
+			table.addTombstone(ts)
+		}
+	}
+
 	resetTxn := snapshotTx
 	if lastTx > resetTxn {
 		resetTxn = lastTx
@@ -924,6 +1299,13 @@ func (db *DB) recover(ctx context.Context, wal WAL) error {
 	for _, table := range db.tables {
 		block := table.ActiveBlock()
 		block.uncompressedInsertsSize.Store(block.Index().LevelSize(index.L0))
+		if block.prevTx != 0 {
+			// The previous blocks of the table were persisted before the
+			// database was closed.
+			table.mtx.Lock()
+			table.droppedTx = block.minTx
+			table.mtx.Unlock()
+		}
 	}
 	db.mtx.Unlock()
 
@@ -972,8 +1354,11 @@ func (db *DB) Close(options ...CloseOption) error {
 		opt(opts)
 	}
 
+	// Stop following the leader before closing the tables.
+	db.stopFollower()
+
 	shouldPersist := len(db.sinks) > 0 && !db.columnStore.manualBlockRotation
-	if !shouldPersist && db.columnStore.snapshotTriggerSize != 0 && !opts.clearStorage {
+	if !shouldPersist && db.leader == nil && db.columnStore.snapshotTriggerSize != 0 && !opts.clearStorage {
 		start := time.Now()
 		db.snapshot(context.Background(), false, func() {
 			level.Info(db.logger).Log("msg", "snapshot on close completed", "duration", time.Since(start))
@@ -1016,6 +1401,10 @@ func (db *DB) Close(options ...CloseOption) error {
 }
 
 func (db *DB) closeInternal() error {
+	db.stopFollower()
+	db.stopRetention()
+	db.stopReplication()
+	db.stopBlockCompaction()
 	defer func() {
 		// Clean up the txPool even on error.
 		if db.txPool != nil {
@@ -1085,6 +1474,11 @@ func (db *DB) readOnlyTable(name string) (*Table, error) {
 	if ok {
 		return table, nil
 	}
+	// The table may already have been created for the prefix of another
+	// source.
+	if table, ok := db.roTables[name]; ok {
+		return table, nil
+	}
 
 	table, err := newTable(
 		db,
@@ -1117,13 +1511,18 @@ func (db *DB) promoteReadOnlyTableLocked(name string, config *tablepb.TableConfi
 		return nil, err
 	}
 	table.config.Store(config)
-	table.schema = schema
+	table.schema.Store(schema)
 	delete(db.roTables, name)
 	return table, nil
 }
 
 // Table will get or create a new table with the given name and config. If a table already exists with the given name, it will have it's configuration updated.
+// The schema of an existing table is not changed, the schema of the config is ignored for it (see AlterTable).
 func (db *DB) Table(name string, config *tablepb.TableConfig) (*Table, error) {
+	if db.leader != nil {
+		// Tables of followers are created by their leader.
+		return db.GetTable(name)
+	}
 	return db.table(name, config, generateULID())
 }
 
@@ -1131,14 +1530,17 @@ func (db *DB) table(name string, config *tablepb.TableConfig, id ulid.ULID) (*Ta
 	if config == nil {
 		return nil, fmt.Errorf("table config cannot be nil")
 	}
-	if !validateName(name) {
+	if err := validateRetention(config); err != nil {
+		return nil, err
+	}
+	if !validateTableName(name) {
 		return nil, errors.New("invalid table name")
 	}
 	db.mtx.RLock()
 	table, ok := db.tables[name]
 	db.mtx.RUnlock()
 	if ok {
-		table.config.Store(config)
+		table.updateConfig(config)
 		return table, nil
 	}
 
@@ -1311,6 +1713,12 @@ func validateName(name string) bool {
 	return !strings.Contains(name, "/")
 }
 
+// validateTableName returns whether the name is valid for a table. The
+// prefix the blocks of dropped tables are moved to is reserved.
+func validateTableName(name string) bool {
+	return validateName(name) && name != droppedTablesDir
+}
+
 // dropStorage removes all data from the storage directory, but leaves the empty
 // storage directory.
 func (db *DB) dropStorage() error {
@@ -1,16 +1,19 @@
 package frostdb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,22 +22,30 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+	"github.com/parquet-go/parquet-go"
 	"github.com/polarsignals/iceberg-go"
 	"github.com/polarsignals/iceberg-go/catalog"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/youscentia/ydb-frostdb/dynparquet"
+	catalogpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/catalog/v1alpha1"
+	schemapb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha1"
+	schemav2pb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha2"
 	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
 	"github.com/youscentia/ydb-frostdb/index"
+	"github.com/youscentia/ydb-frostdb/internal/records"
 	"github.com/youscentia/ydb-frostdb/query"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
 	"github.com/youscentia/ydb-frostdb/query/physicalplan"
 	"github.com/youscentia/ydb-frostdb/recovery"
 	"github.com/youscentia/ydb-frostdb/storage"
+	"github.com/youscentia/ydb-frostdb/wal"
 )
 
 func TestDBWithWALAndBucket(t *testing.T) {
@@ -3246,6 +3257,97 @@ func Test_Iceberg(t *testing.T) {
 	require.Equal(t, int64(4), rows)
 }
 
+func Test_Iceberg_Delete(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	iceberg, err := storage.NewIceberg("/", catalog.NewHDFS("/", bucket), bucket)
+	require.NoError(t, err)
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithManualBlockRotation(),
+		WithReadWriteStorage(iceberg),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	insert := func() {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		db.Wait(tx)
+	}
+	rotate := func() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	rows := func() int64 {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		require.NoError(t, query.NewEngine(pool, db.TableProvider()).
+			ScanTable("test").
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				rows += r.NumRows()
+				return nil
+			}))
+		return rows
+	}
+
+	insert()
+	rotate()
+	require.Equal(t, int64(3), rows())
+
+	// The deleted rows of the persisted block are not read anymore.
+	tx, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+	require.NoError(t, err)
+	db.Wait(tx)
+	require.Equal(t, int64(1), rows())
+
+	// Rows written after the delete are kept.
+	insert()
+	rotate()
+	require.Equal(t, int64(4), rows())
+
+	// Neither are the rows of truncated persisted blocks.
+	tx, err = db.TruncateTable(ctx, "test")
+	require.NoError(t, err)
+	db.Wait(tx)
+	require.Zero(t, rows())
+}
+
+func Test_Iceberg_TableOperations(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	iceberg, err := storage.NewIceberg("/", catalog.NewHDFS("/", bucket), bucket)
+	require.NoError(t, err)
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithManualBlockRotation(),
+		WithReadWriteStorage(iceberg),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	_, err = db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	// Iceberg can't move the persisted blocks of a table, which would be
+	// read by a table created with its name.
+	ctx := context.Background()
+	require.ErrorIs(t, db.RenameTable(ctx, "test", "renamed"), ErrMoveUnsupported)
+	require.ErrorIs(t, db.DropTable(ctx, "test"), ErrMoveUnsupported)
+	_, err = db.GetTable("test")
+	require.NoError(t, err)
+}
+
 type TestBucket struct {
 	sync.Mutex
 	record map[string]struct{}
@@ -3264,59 +3366,246 @@ func (b *TestBucket) GetRange(ctx context.Context, path string, offset, length i
 }
 
 // Test_DB_EmptyPersist ensures that we don't write an empty block when the db is shutdown and there is no data.
-func Test_DB_EmptyPersist(t *testing.T) {
-	config := NewTableConfig(
-		dynparquet.SampleDefinition(),
-	)
+func Test_DB_Delete(t *testing.T) {
+	for _, snapshot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("snapshot=%t", snapshot), func(t *testing.T) {
+			config := NewTableConfig(
+				dynparquet.SampleDefinition(),
+			)
+			bucket := objstore.NewInMemBucket()
+			options := []Option{
+				WithLogger(newTestLogger(t)),
+				WithWAL(),
+				WithStoragePath(t.TempDir()),
+				WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+				WithManualBlockRotation(),
+			}
+			if snapshot {
+				options = append(options, WithSnapshotTriggerSize(1*GiB))
+			}
 
-	logger := newTestLogger(t)
-	assertBucket := &AssertBucket{
-		Bucket: objstore.NewInMemBucket(),
-		uploadFunc: func(_ context.Context, _ string, _ io.Reader) error {
-			t.Fatal("unexpected upload")
-			return nil
-		},
-	}
+			c, err := New(options...)
+			require.NoError(t, err)
+			db, err := c.DB(context.Background(), "test")
+			require.NoError(t, err)
+			table, err := db.Table("test", config)
+			require.NoError(t, err)
 
-	sinksource := NewDefaultObjstoreBucket(assertBucket)
+			ctx := context.Background()
+			insert := func() {
+				r, err := dynparquet.NewTestSamples().ToRecord()
+				require.NoError(t, err)
+				defer r.Release()
+				_, err = table.InsertRecord(ctx, r)
+				require.NoError(t, err)
+			}
+			rotate := func() {
+				var wg sync.WaitGroup
+				wg.Add(1)
+				require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+				wg.Wait()
+			}
+			countRows := func(table *Table) int64 {
+				pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+				defer pool.AssertSize(t, 0)
+				rows := int64(0)
+				require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+					return table.Iterator(
+						ctx,
+						tx,
+						pool,
+						[]logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+							rows += ar.NumRows()
+							return nil
+						}},
+					)
+				}))
+				return rows
+			}
+			persistedRows := func() int64 {
+				rows := int64(0)
+				for _, data := range blockData(bucket) {
+					f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+					require.NoError(t, err)
+					rows += f.NumRows()
+				}
+				return rows
+			}
 
-	c, err := New(
-		WithLogger(logger),
-		WithReadWriteStorage(sinksource),
-	)
-	require.NoError(t, err)
-	db, err := c.DB(context.Background(), "test")
-	require.NoError(t, err)
-	_, err = db.Table("test", config)
-	require.NoError(t, err)
+			// The first block is persisted before the delete, so its deleted
+			// rows can only be hidden when reading it.
+			insert()
+			rotate()
+			insert()
+			_, err = table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+			require.NoError(t, err)
+			insert()
+			require.Equal(t, int64(5), countRows(table))
 
-	require.NoError(t, c.Close())
+			// Persisting the second block drops the rows deleted from it.
+			rotate()
+			require.Equal(t, int64(3+4), persistedRows())
+			require.Equal(t, int64(5), countRows(table))
+
+			// The delete is recovered after a restart.
+			require.NoError(t, c.Close())
+			c, err = New(options...)
+			require.NoError(t, err)
+			defer c.Close()
+			db, err = c.DB(context.Background(), "test")
+			require.NoError(t, err)
+			table, err = db.Table("test", config)
+			require.NoError(t, err)
+			require.Equal(t, int64(5), countRows(table))
+		})
+	}
 }
 
-type AssertBucket struct {
-	objstore.Bucket
+func Test_DB_TableOperations(t *testing.T) {
+	for _, snapshot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("snapshot=%t", snapshot), func(t *testing.T) {
+			config := NewTableConfig(
+				dynparquet.SampleDefinition(),
+			)
+			bucket := objstore.NewInMemBucket()
+			options := []Option{
+				WithLogger(newTestLogger(t)),
+				WithWAL(),
+				WithStoragePath(t.TempDir()),
+				WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+				WithManualBlockRotation(),
+			}
+			if snapshot {
+				options = append(options, WithSnapshotTriggerSize(1*GiB))
+			}
 
-	uploadFunc func(ctx context.Context, path string, r io.Reader) error
-}
+			c, err := New(options...)
+			require.NoError(t, err)
+			db, err := c.DB(context.Background(), "test")
+			require.NoError(t, err)
 
-func (a *AssertBucket) Upload(ctx context.Context, path string, r io.Reader) error {
-	if a.uploadFunc != nil {
-		return a.uploadFunc(ctx, path, r)
+			ctx := context.Background()
+			samples := int64(len(dynparquet.NewTestSamples()))
+			insert := func(table *Table) {
+				r, err := dynparquet.NewTestSamples().ToRecord()
+				require.NoError(t, err)
+				defer r.Release()
+				_, err = table.InsertRecord(ctx, r)
+				require.NoError(t, err)
+			}
+			rotate := func(table *Table) {
+				var wg sync.WaitGroup
+				wg.Add(1)
+				require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+				wg.Wait()
+			}
+			rows := func(db *DB, name string) int64 {
+				table, err := db.GetTable(name)
+				require.NoError(t, err)
+				rows := int64(0)
+				require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+					return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+						rows += ar.NumRows()
+						return nil
+					}})
+				}))
+				return rows
+			}
+			blocks := func(table string) int {
+				n := 0
+				for name := range blockData(bucket) {
+					if strings.HasPrefix(name, filepath.Join("test", table)+"/") {
+						n++
+					}
+				}
+				return n
+			}
+
+			// Every table has a persisted block and rows in memory.
+			for _, name := range []string{"truncated", "dropped", "kept", "renamed"} {
+				table, err := db.Table(name, config)
+				require.NoError(t, err)
+				insert(table)
+				rotate(table)
+				insert(table)
+				require.Equal(t, 2*samples, rows(db, name))
+			}
+
+			truncated, err := db.GetTable("truncated")
+			require.NoError(t, err)
+			_, err = db.TruncateTable(ctx, "truncated")
+			require.NoError(t, err)
+			require.Equal(t, int64(0), rows(db, "truncated"))
+			insert(truncated)
+			require.Equal(t, samples, rows(db, "truncated"))
+			// The persisted block is kept but hidden.
+			require.Equal(t, 1, blocks("truncated"))
+
+			dropped, err := db.GetTable("dropped")
+			require.NoError(t, err)
+			require.NoError(t, db.DropTable(ctx, "dropped", WithDeletePersistedBlocks()))
+			_, err = db.GetTable("dropped")
+			require.ErrorAs(t, err, &ErrTableNotFound{})
+			require.Equal(t, 0, blocks("dropped"))
+			_, err = dropped.InsertRecord(ctx, arrow.Record(nil))
+			require.ErrorIs(t, err, ErrTableClosing)
+			require.ErrorAs(t, db.DropTable(ctx, "dropped"), &ErrTableNotFound{})
+
+			// The blocks of a table dropped without deleting them are moved
+			// out of the way of a table created with the same name.
+			require.NoError(t, db.DropTable(ctx, "kept"))
+			require.Equal(t, 0, blocks("kept"))
+			require.Equal(t, 1, blocks(droppedTablesDir))
+			kept, err := db.Table("kept", config)
+			require.NoError(t, err)
+			require.Equal(t, int64(0), rows(db, "kept"))
+			insert(kept)
+			require.Equal(t, samples, rows(db, "kept"))
+			require.ErrorContains(t, db.RenameTable(ctx, "kept", droppedTablesDir), "invalid table name")
+
+			require.ErrorContains(t, db.RenameTable(ctx, "renamed", "truncated"), "already exists")
+			require.NoError(t, db.RenameTable(ctx, "renamed", "moved"))
+			_, err = db.GetTable("renamed")
+			require.ErrorAs(t, err, &ErrTableNotFound{})
+			require.Equal(t, 2*samples, rows(db, "moved"))
+			require.Equal(t, 0, blocks("renamed"))
+			require.Equal(t, 1, blocks("moved"))
+			moved, err := db.GetTable("moved")
+			require.NoError(t, err)
+			insert(moved)
+			require.Equal(t, 3*samples, rows(db, "moved"))
+
+			// The operations are recovered after a restart.
+			require.NoError(t, c.Close())
+			c, err = New(options...)
+			require.NoError(t, err)
+			defer c.Close()
+			db, err = c.DB(context.Background(), "test")
+			require.NoError(t, err)
+			require.Equal(t, samples, rows(db, "truncated"))
+			require.Equal(t, 3*samples, rows(db, "moved"))
+			require.Equal(t, samples, rows(db, "kept"))
+			for _, name := range []string{"dropped", "renamed", droppedTablesDir} {
+				_, err = db.GetTable(name)
+				require.ErrorAs(t, err, &ErrTableNotFound{})
+			}
+		})
 	}
-	return a.Bucket.Upload(ctx, path, r)
 }
 
-func Test_DB_Sample(t *testing.T) {
-	t.Parallel()
+func Test_DB_AlterTable(t *testing.T) {
 	config := NewTableConfig(
 		dynparquet.SampleDefinition(),
 	)
-	logger := newTestLogger(t)
-
-	c, err := New(WithLogger(logger))
-	t.Cleanup(func() {
-		require.NoError(t, c.Close())
-	})
+	bucket := objstore.NewInMemBucket()
+	options := []Option{
+		WithLogger(newTestLogger(t)),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+	}
+	c, err := New(options...)
 	require.NoError(t, err)
 	db, err := c.DB(context.Background(), "test")
 	require.NoError(t, err)
@@ -3324,28 +3613,1874 @@ func Test_DB_Sample(t *testing.T) {
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	for i := 0; i < 500; i++ {
-		samples := dynparquet.GenerateTestSamples(10)
-		r, err := samples.ToRecord()
+	samples := int64(len(dynparquet.NewTestSamples()))
+	insert := func(region bool) {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		if region {
+			b := array.NewStringBuilder(memory.DefaultAllocator)
+			defer b.Release()
+			for i := int64(0); i < r.NumRows(); i++ {
+				b.Append("eu")
+			}
+			regions := b.NewArray()
+			defer regions.Release()
+			fields := append(r.Schema().Fields(), arrow.Field{Name: "region", Type: arrow.BinaryTypes.String, Nullable: true})
+			cols := append(r.Columns(), regions)
+			r = array.NewRecord(arrow.NewSchema(fields, nil), cols, r.NumRows())
+			defer r.Release()
+		}
+		table, err := db.GetTable("test")
 		require.NoError(t, err)
 		_, err = table.InsertRecord(ctx, r)
 		require.NoError(t, err)
 	}
+	rotate := func() {
+		table, err := db.GetTable("test")
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	// read returns the number of rows and the number of rows with a region,
+	// and checks that every record has the columns of the altered schema.
+	read := func() (int64, int64) {
+		table, err := db.GetTable("test")
+		require.NoError(t, err)
+		rows, regions := int64(0), int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				require.Empty(t, ar.Schema().FieldIndices("value"))
+				idx := ar.Schema().FieldIndices("region")
+				require.Len(t, idx, 1)
+				// The added column is in the order of the schema.
+				require.Less(t, idx[0], ar.Schema().FieldIndices("stacktrace")[0])
+				rows += ar.NumRows()
+				regions += ar.NumRows() - int64(ar.Column(idx[0]).NullN())
+				return nil
+			}})
+		}))
+		return rows, regions
+	}
+
+	// A persisted block and a pending block are written with the initial
+	// schema.
+	insert(false)
+	rotate()
+	insert(false)
+
+	region := &schemapb.Column{
+		Name: "region",
+		StorageLayout: &schemapb.StorageLayout{
+			Type:     schemapb.StorageLayout_TYPE_STRING,
+			Nullable: true,
+			Encoding: schemapb.StorageLayout_ENCODING_RLE_DICTIONARY,
+		},
+	}
+	require.ErrorContains(t, db.AlterTable(ctx, "test", DropColumn("timestamp")), "sorting column")
+	require.ErrorContains(t, db.AlterTable(ctx, "test", AddColumn(&schemapb.Column{
+		Name:          "region",
+		StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_STRING},
+	})), "must be nullable")
+	require.ErrorContains(t, db.AlterTable(ctx, "test", SetColumnEncoding("timestamp", schemav2pb.StorageLayout_ENCODING_DELTA_BYTE_ARRAY)), "invalid schema")
+	require.ErrorContains(t, db.AlterTable(ctx, "test", AddSortingColumn(&schemapb.SortingColumn{Name: "region", Direction: schemapb.SortingColumn_DIRECTION_ASCENDING})), "not found")
+	require.ErrorAs(t, db.AlterTable(ctx, "unknown", DropColumn("value")), &ErrTableNotFound{})
+	require.Zero(t, table.config.Load().SchemaVersion)
+
+	require.NoError(t, db.AlterTable(ctx, "test",
+		AddColumn(region),
+		DropColumn("value"),
+		SetColumnCompression("stacktrace", schemav2pb.StorageLayout_COMPRESSION_ZSTD),
+		AddSortingColumn(&schemapb.SortingColumn{Name: "region", Direction: schemapb.SortingColumn_DIRECTION_ASCENDING, NullsFirst: true}),
+	))
+	require.Equal(t, uint64(1), table.config.Load().SchemaVersion)
+	_, ok := table.Schema().ColumnByName("region")
+	require.True(t, ok)
+
+	// The schema is changed with AlterTable only, the table is still looked
+	// up with the config it was created with.
+	_, err = db.Table("test", config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), table.config.Load().SchemaVersion)
+	_, ok = table.Schema().ColumnByName("region")
+	require.True(t, ok)
+	require.False(t, proto.Equal(config.GetDeprecatedSchema(), table.config.Load().GetDeprecatedSchema()))
+	altered := NewTableConfig(proto.Clone(table.config.Load().GetDeprecatedSchema()).(*schemapb.Schema))
+	_, err = db.Table("test", altered)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), table.config.Load().SchemaVersion)
+
+	insert(true)
+	rows, regions := read()
+	require.Equal(t, 3*samples, rows)
+	require.Equal(t, samples, regions)
+
+	// The blocks written with both versions of the schema are persisted and
+	// the schema change is recovered after a restart.
+	rotate()
+	require.NoError(t, c.Close())
+	c, err = New(options...)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err = c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err = db.GetTable("test")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), table.config.Load().SchemaVersion)
+	insert(true)
+	rows, regions = read()
+	require.Equal(t, 4*samples, rows)
+	require.Equal(t, 2*samples, regions)
+}
+
+func Test_DB_Update(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+	options := []Option{
+		WithLogger(newTestLogger(t)),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+	}
+	c, err := New(options...)
+	require.NoError(t, err)
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	for _, name := range []string{"samples", "metadata"} {
+		_, err := db.Table(name, config)
+		require.NoError(t, err)
+	}
 
-	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
-	defer pool.AssertSize(t, 0)
-	lock := &sync.Mutex{}
-	rows := int64(0)
-	sampleSize := int64(13)
-	engine := query.NewEngine(pool, db.TableProvider())
-	err = engine.ScanTable("test").
-		Sample(sampleSize, 1024*1024). // Sample 13 rows, materialize the reservoir at 1MB
-		Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
-			lock.Lock()
-			defer lock.Unlock()
-			rows += r.NumRows()
-			return nil
-		})
+	ctx := context.Background()
+	samples := int64(len(dynparquet.NewTestSamples()))
+	r, err := dynparquet.NewTestSamples().ToRecord()
 	require.NoError(t, err)
-	require.Equal(t, sampleSize, rows)
+	defer r.Release()
+	rows := func(db *DB, name string) int64 {
+		table, err := db.GetTable(name)
+		require.NoError(t, err)
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}})
+		}))
+		return rows
+	}
+
+	tx, err := db.Update(ctx, func(tx *WriteTx) error {
+		if err := tx.InsertRecord("samples", r); err != nil {
+			return err
+		}
+		return tx.InsertRecord("metadata", r)
+	})
+	require.NoError(t, err)
+	db.Wait(tx)
+	require.Equal(t, samples, rows(db, "samples"))
+	require.Equal(t, samples, rows(db, "metadata"))
+
+	// Nothing is written if the update fails.
+	errAbort := errors.New("abort")
+	_, err = db.Update(ctx, func(tx *WriteTx) error {
+		if err := tx.InsertRecord("samples", r); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	_, err = db.Update(ctx, func(tx *WriteTx) error {
+		if err := tx.InsertRecord("samples", r); err != nil {
+			return err
+		}
+		return tx.InsertRecord("unknown", r)
+	})
+	require.ErrorAs(t, err, &ErrTableNotFound{})
+	require.Equal(t, samples, rows(db, "samples"))
+
+	tx, err = db.Update(ctx, func(*WriteTx) error { return nil })
+	require.NoError(t, err)
+	require.Zero(t, tx)
+
+	// The writes are logged as a single record and recovered together.
+	require.NoError(t, c.Close())
+	c, err = New(options...)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err = c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	require.Equal(t, samples, rows(db, "samples"))
+	require.Equal(t, samples, rows(db, "metadata"))
+
+	transactions := 0
+	require.NoError(t, db.wal.Replay(0, func(_ uint64, record *walpb.Record) error {
+		switch e := record.Entry.EntryType.(type) {
+		case *walpb.Entry_Transaction_:
+			transactions++
+			require.Len(t, e.Transaction.Writes, 2)
+			require.Equal(t, "samples", e.Transaction.Writes[0].TableName)
+			require.Equal(t, "metadata", e.Transaction.Writes[1].TableName)
+		case *walpb.Entry_Write_:
+			require.Fail(t, "unexpected write entry")
+		}
+		return nil
+	}))
+	require.Equal(t, 1, transactions)
+}
+
+func Test_DB_ReadTx(t *testing.T) {
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(objstore.NewInMemBucket())),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	insert := func() uint64 {
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		db.Wait(tx)
+		return tx
+	}
+	rows := func(tx uint64) (int64, error) {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		err := query.NewEngine(pool, db.TableProvider(), query.WithReadTx(tx)).
+			ScanTable("test").
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				rows += r.NumRows()
+				return nil
+			})
+		return rows, err
+	}
+
+	first := insert()
+	insert()
+	n, err := rows(first)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	n, err = rows(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), n)
+
+	_, err = rows(db.HighWatermark() + 1)
+	require.Error(t, err)
+
+	// The parts of both inserts are merged into a part of the second one.
+	require.NoError(t, table.EnsureCompaction())
+	_, err = rows(first)
+	require.ErrorIs(t, err, ErrTxCompacted)
+	n, err = rows(db.HighWatermark())
+	require.NoError(t, err)
+	require.Equal(t, int64(6), n)
+
+	// Reads fail if the table is compacted past their txn while they scan it.
+	pinned := insert()
+	insert()
+	compacted := false
+	err = query.NewEngine(memory.DefaultAllocator, db.TableProvider(), query.WithReadTx(pinned)).
+		ScanTable("test").
+		Execute(ctx, func(_ context.Context, _ arrow.Record) error {
+			if !compacted {
+				compacted = true
+				return table.EnsureCompaction()
+			}
+			return nil
+		})
+	require.True(t, compacted)
+	require.ErrorIs(t, err, ErrTxCompacted)
+
+	// Compactions removing rows deleted after a txn fail its reads, even if
+	// the compacted parts aren't newer than the txn.
+	beforeDelete := insert()
+	deleteTx, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+	require.NoError(t, err)
+	db.Wait(deleteTx)
+	n, err = rows(beforeDelete)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), n)
+	require.NoError(t, table.EnsureCompaction())
+	_, err = rows(beforeDelete)
+	require.ErrorIs(t, err, ErrTxCompacted)
+	n, err = rows(deleteTx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+
+	// The rows of persisted blocks aren't filtered by txn.
+	third := insert()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+	wg.Wait()
+	_, err = rows(third)
+	require.ErrorIs(t, err, ErrTxCompacted)
+	n, err = rows(db.HighWatermark())
+	require.NoError(t, err)
+	require.Equal(t, int64(8), n)
+}
+
+func Test_DB_ColumnTypes(t *testing.T) {
+	type Sample struct {
+		Timestamp time.Time `frostdb:",asc(0)"`
+		Hash      []byte
+		Gauge     float32
+		Price     int64 `frostdb:",decimal(10),scale(2)"`
+		Buckets   [][]int64
+	}
+
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(objstore.NewInMemBucket())),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+
+	b := records.NewBuild[Sample](memory.NewGoAllocator())
+	defer b.Release()
+	table, err := db.Table("test", NewTableConfig(b.Schema("test")))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, b.Append(
+		Sample{Timestamp: time.Unix(0, 1), Hash: []byte{0x1}, Gauge: 0.5, Price: 150, Buckets: [][]int64{{1, 2}, {}, {3}}},
+		Sample{Timestamp: time.Unix(0, 2), Hash: []byte{0x2}, Gauge: 1.5, Price: 250, Buckets: [][]int64{{4}}},
+		Sample{Timestamp: time.Unix(0, 3), Hash: []byte{0x3}, Gauge: 2.5, Price: 350},
+	))
+	r := b.NewRecord()
+	defer r.Release()
+	tx, err := table.InsertRecord(ctx, r)
+	require.NoError(t, err)
+	db.Wait(tx)
+
+	rows := func(filter logicalplan.Expr) []string {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		var rows []string
+		require.NoError(t, query.NewEngine(pool, db.TableProvider()).
+			ScanTable("test").
+			Filter(filter).
+			Project(
+				logicalplan.Col("timestamp"),
+				logicalplan.Col("hash"),
+				logicalplan.Col("gauge"),
+				logicalplan.Col("price"),
+				logicalplan.Col("buckets"),
+			).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				for i := 0; i < int(r.NumRows()); i++ {
+					row := make([]string, 0, r.NumCols())
+					for j := 0; j < int(r.NumCols()); j++ {
+						row = append(row, r.Column(j).ValueStr(i))
+					}
+					rows = append(rows, strings.Join(row, " "))
+				}
+				return nil
+			}))
+		return rows
+	}
+	expect := func() {
+		require.Equal(t, []string{
+			"1970-01-01 00:00:00.000000001Z AQ== 0.5 1.5 [[1,2],[],[3]]",
+		}, rows(logicalplan.Col("timestamp").Lt(logicalplan.Literal(time.Unix(0, 2)))))
+		require.Equal(t, []string{
+			"1970-01-01 00:00:00.000000002Z Ag== 1.5 2.5 [[4]]",
+		}, rows(logicalplan.Col("timestamp").Eq(logicalplan.Literal(time.Unix(0, 2)))))
+		require.Equal(t, []string{
+			"1970-01-01 00:00:00.000000003Z Aw== 2.5 3.5 (null)",
+		}, rows(logicalplan.Col("timestamp").Gt(logicalplan.Literal(int64(2)))))
+		require.Len(t, rows(logicalplan.Col("hash").Eq(logicalplan.Literal([]byte{0x1}))), 1)
+		require.Len(t, rows(logicalplan.Col("gauge").GtEq(logicalplan.Literal(1.5))), 2)
+		require.Len(t, rows(logicalplan.Col("price").Lt(logicalplan.Literal(2.6))), 2)
+		require.Len(t, rows(logicalplan.Col("price").Gt(logicalplan.Literal(int64(3)))), 1)
+	}
+
+	// In-memory arrow parts.
+	expect()
+
+	// Compacted parquet parts.
+	require.NoError(t, table.EnsureCompaction())
+	expect()
+
+	// Persisted blocks.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+	wg.Wait()
+	expect()
+}
+
+func Test_DB_EmptyPersist(t *testing.T) {
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+
+	logger := newTestLogger(t)
+	assertBucket := &AssertBucket{
+		Bucket: objstore.NewInMemBucket(),
+		uploadFunc: func(_ context.Context, _ string, _ io.Reader) error {
+			t.Fatal("unexpected upload")
+			return nil
+		},
+	}
+
+	sinksource := NewDefaultObjstoreBucket(assertBucket)
+
+	c, err := New(
+		WithLogger(logger),
+		WithReadWriteStorage(sinksource),
+	)
+	require.NoError(t, err)
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	_, err = db.Table("test", config)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Close())
+}
+
+type AssertBucket struct {
+	objstore.Bucket
+
+	uploadFunc func(ctx context.Context, path string, r io.Reader) error
+}
+
+func (a *AssertBucket) Upload(ctx context.Context, path string, r io.Reader) error {
+	if a.uploadFunc != nil {
+		return a.uploadFunc(ctx, path, r)
+	}
+	return a.Bucket.Upload(ctx, path, r)
+}
+
+func Test_DB_Sample(t *testing.T) {
+	t.Parallel()
+	config := NewTableConfig(
+		dynparquet.SampleDefinition(),
+	)
+	logger := newTestLogger(t)
+
+	c, err := New(WithLogger(logger))
+	t.Cleanup(func() {
+		require.NoError(t, c.Close())
+	})
+	require.NoError(t, err)
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 500; i++ {
+		samples := dynparquet.GenerateTestSamples(10)
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+	}
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	lock := &sync.Mutex{}
+	rows := int64(0)
+	sampleSize := int64(13)
+	engine := query.NewEngine(pool, db.TableProvider())
+	err = engine.ScanTable("test").
+		Sample(sampleSize, 1024*1024). // Sample 13 rows, materialize the reservoir at 1MB
+		Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+			lock.Lock()
+			defer lock.Unlock()
+			rows += r.NumRows()
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, sampleSize, rows)
+}
+
+// blockData returns the data files of the blocks in the bucket, leaving out
+// their metadata and the catalog.
+func blockData(bucket *objstore.InMemBucket) map[string][]byte {
+	data := map[string][]byte{}
+	for name, b := range bucket.Objects() {
+		if filepath.Base(name) == "data.parquet" {
+			data[name] = b
+		}
+	}
+	return data
+}
+
+func Test_DB_Retention(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+		WithRetentionInterval(0),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	persistBlock := func(table *Table) {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	blocks := func(table string) int {
+		n := 0
+		for name := range blockData(bucket) {
+			if strings.HasPrefix(name, filepath.Join("test", table)+"/") {
+				n++
+			}
+		}
+		return n
+	}
+
+	expiring, err := db.Table("expiring", NewTableConfig(dynparquet.SampleDefinition(), WithRetention(time.Hour)))
+	require.NoError(t, err)
+	kept, err := db.Table("kept", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		persistBlock(expiring)
+		persistBlock(kept)
+	}
+	require.Equal(t, 2, blocks("expiring"))
+	require.Equal(t, 2, blocks("kept"))
+
+	// Blocks within the retention window are kept.
+	require.NoError(t, db.enforceRetention(ctx, time.Now()))
+	require.Equal(t, 2, blocks("expiring"))
+	require.Equal(t, 0.0, testutil.ToFloat64(expiring.metrics.blocksReclaimed))
+
+	// Only blocks of tables with a retention are removed once expired.
+	require.NoError(t, db.enforceRetention(ctx, time.Now().Add(2*time.Hour)))
+	require.Equal(t, 0, blocks("expiring"))
+	require.Equal(t, 2, blocks("kept"))
+	require.Equal(t, 2.0, testutil.ToFloat64(expiring.metrics.blocksReclaimed))
+	require.Greater(t, testutil.ToFloat64(expiring.metrics.blockBytesReclaimed), 0.0)
+	require.Equal(t, 0.0, testutil.ToFloat64(kept.metrics.blocksReclaimed))
+
+	// Retentions that can't be represented are rejected up front rather than
+	// disabling retention or removing every block.
+	require.Error(t, WithRetention(time.Microsecond)(NewTableConfig(dynparquet.SampleDefinition())))
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	config.RetentionMs = math.MaxUint64
+	_, err = db.Table("overflowing", config)
+	require.Error(t, err)
+	config = NewTableConfig(dynparquet.SampleDefinition())
+	config.RetentionMs = math.MaxUint64
+	_, err = db.Table("kept", config)
+	require.Error(t, err)
+	require.Zero(t, kept.config.Load().RetentionMs)
+}
+
+func Test_DB_RetentionLoop(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+		WithRetentionInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition(), WithRetention(time.Millisecond)))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(ctx, r)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(table.metrics.blocksReclaimed) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, blockData(bucket))
+}
+
+func Test_DB_Replication(t *testing.T) {
+	ctx := context.Background()
+	persistBlock := func(t *testing.T, table *Table) {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	// newSecondary returns a bucket whose uploads fail while fail is set.
+	newSecondary := func(fail *atomic.Bool) (*objstore.InMemBucket, *AssertBucket) {
+		bucket := objstore.NewInMemBucket()
+		return bucket, &AssertBucket{
+			Bucket: bucket,
+			uploadFunc: func(ctx context.Context, path string, r io.Reader) error {
+				if fail.Load() {
+					return errors.New("injected upload error")
+				}
+				return bucket.Upload(ctx, path, r)
+			},
+		}
+	}
+
+	t.Run("all", func(t *testing.T) {
+		var fail atomic.Bool
+		primary := objstore.NewInMemBucket()
+		secondary, failing := newSecondary(&fail)
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(primary)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(failing)),
+			WithManualBlockRotation(),
+		)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+		require.NoError(t, err)
+
+		// A block that can't be uploaded to all sinks isn't persisted to any.
+		fail.Store(true)
+		persistBlock(t, table)
+		require.Empty(t, blockData(primary))
+		require.Empty(t, blockData(secondary))
+		require.Equal(t, 0.0, testutil.ToFloat64(table.metrics.blockPersisted))
+
+		fail.Store(false)
+		persistBlock(t, table)
+		require.Len(t, blockData(primary), 1)
+		require.Equal(t, primary.Objects(), secondary.Objects())
+		require.Equal(t, 1.0, testutil.ToFloat64(table.metrics.blockPersisted))
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		var fail atomic.Bool
+		primary := objstore.NewInMemBucket()
+		secondary, failing := newSecondary(&fail)
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(primary)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(failing)),
+			WithManualBlockRotation(),
+			WithReplicationPolicy(ReplicateBestEffort),
+			WithReplicationRetryInterval(10*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+		require.NoError(t, err)
+
+		// The block is persisted to the primary sink and replicated to the
+		// secondary sink once uploads succeed again.
+		fail.Store(true)
+		persistBlock(t, table)
+		require.Len(t, blockData(primary), 1)
+		require.Empty(t, blockData(secondary))
+		require.Equal(t, 1.0, testutil.ToFloat64(table.metrics.blockPersisted))
+		require.GreaterOrEqual(t, testutil.ToFloat64(table.metrics.replicationsFailed), 1.0)
+
+		fail.Store(false)
+		require.Eventually(t, func() bool {
+			return db.replication.len() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, primary.Objects(), secondary.Objects())
+	})
+
+	t.Run("best effort unreadable primary", func(t *testing.T) {
+		_, err := New(
+			WithLogger(newTestLogger(t)),
+			// Hide the ReadBlock method of the primary sink.
+			WithWriteOnlyStorage(struct{ DataSink }{NewDefaultObjstoreBucket(objstore.NewInMemBucket())}),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(objstore.NewInMemBucket())),
+			WithReplicationPolicy(ReplicateBestEffort),
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("best effort dropped", func(t *testing.T) {
+		var fail atomic.Bool
+		primary := objstore.NewInMemBucket()
+		secondary, failing := newSecondary(&fail)
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(primary)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(failing)),
+			WithManualBlockRotation(),
+			WithReplicationPolicy(ReplicateBestEffort),
+			WithReplicationRetryInterval(time.Hour),
+		)
+		require.NoError(t, err)
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+		require.NoError(t, err)
+
+		// Failed uploads that haven't been retried when the database is
+		// closed are dropped, as the queue isn't persisted.
+		fail.Store(true)
+		persistBlock(t, table)
+		require.Len(t, blockData(primary), 1)
+		require.Empty(t, blockData(secondary))
+		require.Equal(t, 1, db.replication.len())
+		require.NoError(t, c.Close())
+		require.Equal(t, 0, db.replication.len())
+		require.Equal(t, 1.0, testutil.ToFloat64(table.metrics.replicationsDropped))
+	})
+}
+
+// unavailableBucket lists the blocks of the underlying bucket but fails to
+// serve them.
+type unavailableBucket struct {
+	objstore.Bucket
+}
+
+func (b unavailableBucket) Attributes(context.Context, string) (objstore.ObjectAttributes, error) {
+	return objstore.ObjectAttributes{}, errors.New("injected unavailable error")
+}
+
+func Test_DB_ReadFallback(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	primary := objstore.NewInMemBucket()
+	secondary := objstore.NewInMemBucket()
+
+	// Replicate two blocks to both buckets.
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(primary)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(secondary)),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	db, err := c.DB(ctx, "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		r.Release()
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	require.NoError(t, c.Close())
+	require.Len(t, blockData(secondary), 2)
+	rows := int64(2 * len(dynparquet.NewTestSamples()))
+
+	countRows := func(sources ...DataSource) (int64, error) {
+		options := []Option{WithLogger(newTestLogger(t))}
+		for _, source := range sources {
+			options = append(options, WithReadOnlyStorage(source))
+		}
+		c, err := New(options...)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", config)
+		require.NoError(t, err)
+
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		n := int64(0)
+		err = table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(
+				ctx,
+				tx,
+				pool,
+				[]logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+					n += ar.NumRows()
+					return nil
+				}},
+			)
+		})
+		return n, err
+	}
+
+	// Blocks held by multiple sources are only read once.
+	n, err := countRows(NewDefaultObjstoreBucket(primary), NewDefaultObjstoreBucket(secondary))
+	require.NoError(t, err)
+	require.Equal(t, rows, n)
+
+	// Blocks are read from the next source if the first one fails to serve
+	// them.
+	n, err = countRows(NewDefaultObjstoreBucket(unavailableBucket{primary}), NewDefaultObjstoreBucket(secondary))
+	require.NoError(t, err)
+	require.Equal(t, rows, n)
+
+	_, err = countRows(NewDefaultObjstoreBucket(unavailableBucket{primary}), NewDefaultObjstoreBucket(unavailableBucket{secondary}))
+	require.Error(t, err)
+}
+
+// openedBlocksBucket counts the block data files opened for reading.
+type openedBlocksBucket struct {
+	objstore.Bucket
+	opened atomic.Int64
+}
+
+func (b *openedBlocksBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	if filepath.Base(name) == "data.parquet" {
+		b.opened.Add(1)
+	}
+	return b.Bucket.Attributes(ctx, name)
+}
+
+func Test_DB_BlockCatalog(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	bucket := objstore.NewInMemBucket()
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	db, err := c.DB(ctx, "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	// Each block holds the test samples at timestamps 10*i+2 and the i-th
+	// block also holds a sample of its own type.
+	persistBlock := func(i int) {
+		samples := dynparquet.NewTestSamples()
+		for j := range samples {
+			samples[j].Timestamp += int64(10 * i)
+		}
+		samples = append(samples, dynparquet.Sample{
+			ExampleType: fmt.Sprintf("type%d", i),
+			Labels:      map[string]string{fmt.Sprintf("label%d", i): "value"},
+			Timestamp:   int64(10*i + 2),
+			Value:       1,
+		})
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	for i := 0; i < 3; i++ {
+		persistBlock(i)
+	}
+
+	objects := bucket.Objects()
+	require.Len(t, blockData(bucket), 3)
+	require.Contains(t, objects, "test/test/catalog/index.pb")
+	for name := range blockData(bucket) {
+		require.Contains(t, objects, filepath.Join(filepath.Dir(name), "metadata.pb"))
+	}
+	// The catalog is built with the first block, the others are appended as
+	// deltas.
+	sink := NewDefaultObjstoreBucket(bucket)
+	deltas, err := sink.catalogDeltas(ctx, "test/test")
+	require.NoError(t, err)
+	require.Len(t, deltas, 2)
+	catalog, err := sink.readCatalog(ctx, "test/test")
+	require.NoError(t, err)
+	require.Len(t, catalog.Blocks, 3)
+	for _, md := range catalog.Blocks {
+		require.Equal(t, int64(4), md.Rows)
+		require.Contains(t, md.DynamicColumns, "labels.namespace")
+	}
+	require.NoError(t, c.Close())
+
+	// query reads the table from a fresh store and returns the number of
+	// rows read and blocks opened. Iterators only filter whole row groups, so
+	// all rows of the blocks that are read are returned.
+	query := func(filter logicalplan.Expr) (int64, int64) {
+		opened := &openedBlocksBucket{Bucket: bucket}
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadOnlyStorage(NewDefaultObjstoreBucket(opened)),
+		)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", config)
+		require.NoError(t, err)
+
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(
+				ctx,
+				tx,
+				pool,
+				[]logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+					rows += ar.NumRows()
+					return nil
+				}},
+				logicalplan.WithFilter(filter),
+			)
+		}))
+		return rows, opened.opened.Load()
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter logicalplan.Expr
+		rows   int64
+		opened int64
+	}{
+		{
+			name:   "none",
+			rows:   12,
+			opened: 3,
+		},
+		{
+			name:   "time range",
+			filter: logicalplan.Col("timestamp").GtEq(logicalplan.Literal(int64(20))),
+			rows:   4,
+			opened: 1,
+		},
+		{
+			name:   "sorting column",
+			filter: logicalplan.Col("example_type").Gt(logicalplan.Literal("type1")),
+			rows:   4,
+			opened: 1,
+		},
+		{
+			name:   "dynamic column",
+			filter: logicalplan.Col("labels.label2").Eq(logicalplan.Literal("value")),
+			rows:   4,
+			opened: 1,
+		},
+		{
+			name:   "no match",
+			filter: logicalplan.Col("timestamp").Gt(logicalplan.Literal(int64(100))),
+			rows:   0,
+			opened: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, opened := query(tc.filter)
+			require.Equal(t, tc.rows, rows)
+			require.Equal(t, tc.opened, opened)
+		})
+	}
+
+	// A catalog lost or written before the first block with metadata was
+	// persisted is rebuilt from the existing blocks.
+	for name := range objects {
+		if filepath.Base(name) != "data.parquet" {
+			require.NoError(t, bucket.Delete(ctx, name))
+		}
+	}
+	c, err = New(
+		WithLogger(newTestLogger(t)),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err = c.DB(ctx, "test")
+	require.NoError(t, err)
+	table, err = db.Table("test", config)
+	require.NoError(t, err)
+	persistBlock(3)
+
+	catalog, err = sink.readCatalog(ctx, "test/test")
+	require.NoError(t, err)
+	require.Len(t, catalog.Blocks, 4)
+	rows, opened := query(logicalplan.Col("timestamp").GtEq(logicalplan.Literal(int64(20))))
+	require.Equal(t, int64(8), rows)
+	require.Equal(t, int64(2), opened)
+
+	// Blocks removed by retention are removed from the catalog.
+	_, _, err = sink.DeleteBlocksBefore(ctx, "test/test", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	catalog, err = sink.readCatalog(ctx, "test/test")
+	require.NoError(t, err)
+	require.Empty(t, catalog.Blocks)
+	require.Empty(t, blockData(bucket))
+}
+
+func Test_DB_CatalogDeltas(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	sink := NewDefaultObjstoreBucket(bucket)
+	schema, err := dynparquet.SchemaFromDefinition(dynparquet.SampleDefinition())
+	require.NoError(t, err)
+
+	// The catalog of a prefix without blocks is built empty.
+	require.NoError(t, sink.updateCatalog(ctx, "test/test", schema, &catalogpb.CatalogDelta{}))
+	index := bucket.Objects()["test/test/catalog/index.pb"]
+	require.NotNil(t, index)
+
+	var blocks []string
+	for i := 0; i < maxCatalogDeltas-1; i++ {
+		block := ulid.Make().String()
+		blocks = append(blocks, block)
+		require.NoError(t, sink.updateCatalog(ctx, "test/test", schema, &catalogpb.CatalogDelta{
+			Added: []*catalogpb.BlockMetadata{{Ulid: block}},
+		}))
+	}
+	// Updates don't rewrite the index.
+	require.Equal(t, index, bucket.Objects()["test/test/catalog/index.pb"])
+	deltas, err := sink.catalogDeltas(ctx, "test/test")
+	require.NoError(t, err)
+	require.Len(t, deltas, maxCatalogDeltas-1)
+
+	// Deltas are compacted into the index once there are too many of them.
+	require.NoError(t, sink.updateCatalog(ctx, "test/test", schema, &catalogpb.CatalogDelta{
+		Removed: blocks[:2],
+	}))
+	deltas, err = sink.catalogDeltas(ctx, "test/test")
+	require.NoError(t, err)
+	require.Empty(t, deltas)
+	require.NotEqual(t, index, bucket.Objects()["test/test/catalog/index.pb"])
+
+	catalog, err := sink.readCatalog(ctx, "test/test")
+	require.NoError(t, err)
+	var got []string
+	for _, md := range catalog.Blocks {
+		got = append(got, md.Ulid)
+	}
+	require.Equal(t, blocks[2:], got)
+
+	// Deltas that are applied again after they were compacted don't change
+	// the catalog.
+	applyCatalogDelta(catalog, &catalogpb.CatalogDelta{Added: []*catalogpb.BlockMetadata{{Ulid: blocks[2]}}, Removed: blocks[:2]})
+	require.Len(t, catalog.Blocks, len(blocks)-2)
+}
+
+func Test_DB_BlockCompaction(t *testing.T) {
+	ctx := context.Background()
+	newTable := func(t *testing.T, bucket objstore.Bucket, options ...TableOption) (*DB, *Table) {
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+			WithManualBlockRotation(),
+			WithBlockCompactionInterval(0),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { c.Close() })
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition(), options...))
+		require.NoError(t, err)
+		return db, table
+	}
+	insert := func(t *testing.T, table *Table, offset int64) {
+		samples := dynparquet.NewTestSamples()
+		for i := range samples {
+			samples[i].Timestamp += offset
+		}
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+	}
+	rotate := func(t *testing.T, table *Table) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+		wg.Wait()
+	}
+	// read returns the timestamps and values of all rows of the table.
+	read := func(t *testing.T, table *Table) (int64, map[int64]int64) {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		values := map[int64]int64{}
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(
+				ctx,
+				tx,
+				pool,
+				[]logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+					rows += ar.NumRows()
+					schema := ar.Schema()
+					ts := ar.Column(schema.FieldIndices("timestamp")[0]).(*array.Int64)
+					value := ar.Column(schema.FieldIndices("value")[0]).(*array.Int64)
+					for i := 0; i < int(ar.NumRows()); i++ {
+						values[ts.Value(i)] += value.Value(i)
+					}
+					return nil
+				}},
+			)
+		}))
+		return rows, values
+	}
+	catalogBlocks := func(t *testing.T, bucket *objstore.InMemBucket) []*catalogpb.BlockMetadata {
+		catalog, err := NewDefaultObjstoreBucket(bucket).readCatalog(ctx, "test/test")
+		require.NoError(t, err)
+		return catalog.Blocks
+	}
+
+	t.Run("compaction", func(t *testing.T) {
+		bucket := objstore.NewInMemBucket()
+		db, table := newTable(t, bucket, WithBlockCompaction(time.Hour))
+
+		// The rows of the first block matching the delete are only hidden
+		// when reading it, so they have to be removed when compacting it.
+		insert(t, table, 0)
+		rotate(t, table)
+		insert(t, table, 10)
+		_, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+		require.NoError(t, err)
+		insert(t, table, 20)
+		rotate(t, table)
+		insert(t, table, 30)
+		rotate(t, table)
+		require.Len(t, blockData(bucket), 3)
+		rows, values := read(t, table)
+		require.Equal(t, int64(8), rows)
+
+		// Blocks are only compacted once their window has passed.
+		require.NoError(t, db.compactBlocks(ctx, time.Now()))
+		require.Len(t, catalogBlocks(t, bucket), 3)
+		require.Len(t, table.tombstones, 1)
+
+		// The delete no longer applies to any rows once the first block was
+		// compacted.
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(2*time.Hour)))
+		require.Len(t, catalogBlocks(t, bucket), 1)
+		require.Empty(t, table.tombstones)
+		require.Equal(t, int64(8), catalogBlocks(t, bucket)[0].Rows)
+		require.Equal(t, 3.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+		compactedRows, compactedValues := read(t, table)
+		require.Equal(t, rows, compactedRows)
+		require.Equal(t, values, compactedValues)
+
+		// The replaced blocks are only deleted by the next run, which has
+		// nothing left to compact.
+		require.Len(t, blockData(bucket), 4)
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(2*time.Hour)))
+		require.Len(t, blockData(bucket), 1)
+		require.Equal(t, 3.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+		compactedRows, compactedValues = read(t, table)
+		require.Equal(t, rows, compactedRows)
+		require.Equal(t, values, compactedValues)
+	})
+
+	t.Run("deletes", func(t *testing.T) {
+		bucket := objstore.NewInMemBucket()
+		db, table := newTable(t, bucket, WithBlockCompaction(time.Millisecond))
+
+		insert(t, table, 0)
+		rotate(t, table)
+		_, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+		require.NoError(t, err)
+
+		// The active block may hold rows written before the delete, so the
+		// block that is alone in its window isn't compacted yet.
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(time.Hour)))
+		require.Equal(t, 0.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+		require.Len(t, table.tombstones, 1)
+
+		// Once it was rotated, the block is compacted to remove the deleted
+		// rows and the delete is dropped.
+		rotate(t, table)
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(time.Hour)))
+		require.Equal(t, 1.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+		require.Empty(t, table.tombstones)
+		blocks := catalogBlocks(t, bucket)
+		require.Len(t, blocks, 1)
+		require.Equal(t, int64(1), blocks[0].Rows)
+		rows, _ := read(t, table)
+		require.Equal(t, int64(1), rows)
+
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(time.Hour)))
+		require.Equal(t, 1.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+	})
+
+	t.Run("downsampling", func(t *testing.T) {
+		bucket := objstore.NewInMemBucket()
+		db, table := newTable(t, bucket,
+			WithBlockCompaction(time.Hour),
+			WithDownsampling("timestamp", 10*time.Millisecond),
+		)
+
+		insert(t, table, 0)
+		insert(t, table, 1)
+		rotate(t, table)
+		insert(t, table, 5)
+		insert(t, table, 10)
+		rotate(t, table)
+		rows, _ := read(t, table)
+		require.Equal(t, int64(12), rows)
+
+		// The rows of each series are aggregated into buckets of 10ms.
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(2*time.Hour)))
+		blocks := catalogBlocks(t, bucket)
+		require.Len(t, blocks, 1)
+		require.Equal(t, int64(10), blocks[0].ResolutionMs)
+		rows, values := read(t, table)
+		require.Equal(t, int64(6), rows)
+		require.Equal(t, map[int64]int64{0: 3 * 11, 10: 11}, values)
+
+		// Blocks that were downsampled already aren't compacted again.
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(2*time.Hour)))
+		require.Equal(t, 2.0, testutil.ToFloat64(table.metrics.blocksCompacted))
+	})
+
+	t.Run("downsampling timestamps", func(t *testing.T) {
+		type Sample struct {
+			Name      string    `frostdb:",rle_dict,asc(0)"`
+			Timestamp time.Time `frostdb:",asc(1)"`
+			Value     int64
+		}
+		b := records.NewBuild[Sample](memory.NewGoAllocator())
+		defer b.Release()
+
+		bucket := objstore.NewInMemBucket()
+		c, err := New(
+			WithLogger(newTestLogger(t)),
+			WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+			WithManualBlockRotation(),
+			WithBlockCompactionInterval(0),
+		)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", NewTableConfig(b.Schema("test"),
+			WithBlockCompaction(time.Hour),
+			WithDownsampling("timestamp", 10*time.Millisecond),
+		))
+		require.NoError(t, err)
+
+		// The timestamps are in nanoseconds, the resolution is scaled to them.
+		for _, ms := range []int64{1, 3, 12} {
+			require.NoError(t, b.Append(Sample{Name: "a", Timestamp: time.UnixMilli(ms), Value: ms}))
+			r := b.NewRecord()
+			_, err := table.InsertRecord(ctx, r)
+			r.Release()
+			require.NoError(t, err)
+			rotate(t, table)
+		}
+		require.NoError(t, db.compactBlocks(ctx, time.Now().Add(2*time.Hour)))
+		require.Len(t, catalogBlocks(t, bucket), 1)
+
+		values := map[time.Time]int64{}
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				ts := ar.Column(ar.Schema().FieldIndices("timestamp")[0]).(*array.Timestamp)
+				value := ar.Column(ar.Schema().FieldIndices("value")[0]).(*array.Int64)
+				for i := 0; i < int(ar.NumRows()); i++ {
+					values[ts.Value(i).ToTime(arrow.Nanosecond)] += value.Value(i)
+				}
+				return nil
+			}})
+		}))
+		require.Equal(t, map[time.Time]int64{
+			time.UnixMilli(0).UTC():  4,
+			time.UnixMilli(10).UTC(): 12,
+		}, values)
+	})
+
+	t.Run("invalid downsampling column", func(t *testing.T) {
+		c, err := New(WithLogger(newTestLogger(t)))
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		_, err = db.Table("test", NewTableConfig(dynparquet.SampleDefinition(), WithDownsampling("value", time.Second)))
+		require.Error(t, err)
+		config := NewTableConfig(dynparquet.SampleDefinition(), WithDownsampling("timestamp", time.Second))
+		config.Downsampling.ResolutionMs = math.MaxUint64
+		_, err = db.Table("test", config)
+		require.ErrorContains(t, err, "too large")
+	})
+}
+
+func Test_DB_QuerySpill(t *testing.T) {
+	ctx := context.Background()
+	c, err := New(WithLogger(newTestLogger(t)))
+	require.NoError(t, err)
+	defer c.Close()
+
+	db, err := c.DB(ctx, "test")
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		samples := make(dynparquet.Samples, 0, 50)
+		for j := 0; j < 50; j++ {
+			samples = append(samples, dynparquet.Sample{
+				ExampleType: "cpu",
+				Labels:      map[string]string{"group": fmt.Sprint((i*50 + j) % 37)},
+				Timestamp:   int64(i*50 + j),
+				Value:       int64(j),
+			})
+		}
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(ctx, r)
+		r.Release()
+		require.NoError(t, err)
+	}
+
+	query := func(t *testing.T, options ...physicalplan.Option) (map[string]int64, []string) {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		engine := query.NewEngine(pool, db.TableProvider(), query.WithPhysicalplanOptions(options...))
+
+		sums := map[string]int64{}
+		require.NoError(t, engine.ScanTable("test").
+			Aggregate(
+				[]*logicalplan.AggregationFunction{logicalplan.Sum(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("labels.group")},
+			).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				groups := r.Column(0).(*array.Dictionary)
+				values := r.Column(1).(*array.Int64)
+				for i := 0; i < int(r.NumRows()); i++ {
+					group := groups.Dictionary().(*array.String).Value(groups.GetValueIndex(i))
+					_, ok := sums[group]
+					require.False(t, ok, "group %s returned twice", group)
+					sums[group] = values.Value(i)
+				}
+				return nil
+			}))
+
+		var distinct []string
+		require.NoError(t, engine.ScanTable("test").
+			Distinct(logicalplan.Col("labels.group")).
+			Execute(ctx, func(_ context.Context, r arrow.Record) error {
+				groups := r.Column(0).(*array.Dictionary)
+				for i := 0; i < int(r.NumRows()); i++ {
+					distinct = append(distinct, groups.Dictionary().(*array.String).Value(groups.GetValueIndex(i)))
+				}
+				return nil
+			}))
+		return sums, distinct
+	}
+
+	expectedSums, expectedDistinct := query(t)
+	require.Len(t, expectedSums, 37)
+	require.Len(t, expectedDistinct, 37)
+
+	dir := t.TempDir()
+	sums, distinct := query(t,
+		physicalplan.WithMemoryBudget(1),
+		physicalplan.WithSpillDir(c.fs, dir),
+	)
+	require.Equal(t, expectedSums, sums)
+	require.ElementsMatch(t, expectedDistinct, distinct)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// nopWALTicker never ticks, so WAL records are only written when waited for.
+type nopWALTicker struct{}
+
+func (nopWALTicker) C() <-chan time.Time { return nil }
+
+func (nopWALTicker) Stop() {}
+
+func Test_DB_WriteDurability(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	logger := newTestLogger(t)
+	dir := t.TempDir()
+
+	c, err := New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(dir),
+		WithTestingOptions(WithTestingWalOptions(wal.WithTestingLoopTicker(nopWALTicker{}))),
+	)
+	require.NoError(t, err)
+	db, err := c.DB(ctx, "test", WithDurability(DurabilitySync))
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	samples := dynparquet.NewTestSamples()
+	insert := func(ctx context.Context) uint64 {
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		return tx
+	}
+	lastIndex := func() uint64 {
+		idx, err := db.wal.LastIndex()
+		require.NoError(t, err)
+		return idx
+	}
+
+	// Sync writes return once their record is written.
+	tx := insert(ctx)
+	require.Equal(t, tx, lastIndex())
+
+	// Writes can opt out of the durability of the database.
+	tx = insert(WithWriteDurability(ctx, DurabilityAsync))
+	require.Less(t, lastIndex(), tx)
+
+	// Deletes are durable as well, along with the records before them.
+	tx, err = table.Delete(ctx, logicalplan.Col("timestamp").Eq(logicalplan.Literal(int64(-1))))
+	require.NoError(t, err)
+	require.Equal(t, tx, lastIndex())
+	require.NoError(t, c.Close())
+
+	// All the writes are replayed.
+	c, err = New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(dir),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err = c.DB(ctx, "test")
+	require.NoError(t, err)
+	table, err = db.Table("test", config)
+	require.NoError(t, err)
+
+	pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer pool.AssertSize(t, 0)
+	rows := int64(0)
+	require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+		return table.Iterator(ctx, tx, pool, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+			rows += ar.NumRows()
+			return nil
+		}})
+	}))
+	require.Equal(t, int64(6), rows)
+}
+
+// failingDurableWAL is a WAL that fails to make its records durable.
+type failingDurableWAL struct {
+	WAL
+}
+
+func (failingDurableWAL) WaitDurable(uint64) error {
+	return errors.New("fsync failed")
+}
+
+func Test_DB_WriteDurabilityFailure(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(ctx, "test", WithDurability(DurabilitySync))
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+	table.wal = failingDurableWAL{WAL: table.wal}
+
+	rows := func() int64 {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, pool, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}})
+		}))
+		return rows
+	}
+
+	// Writes that can't be confirmed to be durable are still applied.
+	samples := dynparquet.NewTestSamples()
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(ctx, r)
+	require.ErrorIs(t, err, ErrDurabilityUnknown)
+	require.Equal(t, int64(3), rows())
+
+	_, err = db.Update(ctx, func(tx *WriteTx) error {
+		return tx.InsertRecord("test", r)
+	})
+	require.ErrorIs(t, err, ErrDurabilityUnknown)
+	require.Equal(t, int64(6), rows())
+
+	_, err = table.Delete(ctx, logicalplan.Col("value").Eq(logicalplan.Literal(int64(5))))
+	require.ErrorIs(t, err, ErrDurabilityUnknown)
+	require.Equal(t, int64(4), rows())
+
+	// Asynchronous writes don't wait for the WAL.
+	_, err = table.InsertRecord(WithWriteDurability(ctx, DurabilityAsync), r)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), rows())
+}
+
+func Test_DB_WALCompression(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	logger := newTestLogger(t)
+	dir := t.TempDir()
+
+	rows := func(compression walpb.Entry_Write_Compression) int64 {
+		c, err := New(
+			WithLogger(logger),
+			WithWAL(),
+			WithStoragePath(dir),
+			WithWALCompression(compression),
+		)
+		require.NoError(t, err)
+		defer c.Close()
+		db, err := c.DB(ctx, "test")
+		require.NoError(t, err)
+		table, err := db.Table("test", config)
+		require.NoError(t, err)
+
+		samples := dynparquet.NewTestSamples()
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}})
+		}))
+		return rows
+	}
+
+	// Records written with and without compression are replayed.
+	require.Equal(t, int64(3), rows(walpb.Entry_Write_COMPRESSION_ZSTD))
+	require.Equal(t, int64(6), rows(walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED))
+	require.Equal(t, int64(9), rows(walpb.Entry_Write_COMPRESSION_ZSTD))
+
+	_, err := New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(dir),
+		WithWALCompression(walpb.Entry_Write_Compression(42)),
+	)
+	require.ErrorContains(t, err, "unsupported WAL compression")
+}
+
+// truncatingLeader reports the WAL of the leader as truncated the first time
+// it is tailed.
+type truncatingLeader struct {
+	Leader
+	truncated atomic.Bool
+}
+
+func (l *truncatingLeader) Tail(ctx context.Context, tx uint64, handler wal.ReplayHandlerFunc) error {
+	if l.truncated.CompareAndSwap(false, true) {
+		return wal.ErrTruncated
+	}
+	return l.Leader.Tail(ctx, tx, handler)
+}
+
+func Test_DB_Follower(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	logger := newTestLogger(t)
+	bucket := objstore.NewInMemBucket()
+
+	leaderStore, err := New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(bucket)),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer leaderStore.Close()
+	leaderDB, err := leaderStore.DB(ctx, "test")
+	require.NoError(t, err)
+	leaderTable, err := leaderDB.Table("test", config)
+	require.NoError(t, err)
+
+	samples := dynparquet.NewTestSamples()
+	insert := func() {
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		_, err = leaderTable.InsertRecord(WithWriteDurability(ctx, DurabilitySync), r)
+		require.NoError(t, err)
+	}
+	insert()
+
+	rows := func(db *DB) int64 {
+		table, err := db.GetTable("test")
+		require.NoError(t, err)
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}})
+		}))
+		return rows
+	}
+
+	for _, tc := range []struct {
+		name   string
+		leader func() Leader
+	}{
+		{name: "local", leader: func() Leader { return NewLocalLeader(leaderDB) }},
+		{name: "truncated", leader: func() Leader { return &truncatingLeader{Leader: NewLocalLeader(leaderDB)} }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			followerStore, err := New(
+				WithLogger(logger),
+				WithReadOnlyStorage(NewDefaultObjstoreBucket(bucket)),
+			)
+			require.NoError(t, err)
+			defer followerStore.Close()
+			followerDB, err := followerStore.DB(ctx, "test", WithFollower(tc.leader()))
+			require.NoError(t, err)
+
+			// The follower starts from a snapshot of the leader.
+			expected := rows(leaderDB)
+			require.Equal(t, expected, rows(followerDB))
+
+			// Writes to the leader are replicated.
+			insert()
+			expected += 3
+			require.Eventually(t, func() bool {
+				return rows(followerDB) == expected
+			}, 5*time.Second, 10*time.Millisecond)
+
+			_, err = leaderTable.Delete(
+				WithWriteDurability(ctx, DurabilitySync),
+				logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")),
+			)
+			require.NoError(t, err)
+			expected = rows(leaderDB)
+			require.Less(t, expected, int64(6))
+			require.Eventually(t, func() bool {
+				return rows(followerDB) == expected
+			}, 5*time.Second, 10*time.Millisecond)
+
+			// Blocks persisted by the leader are read from storage.
+			var wg sync.WaitGroup
+			wg.Add(1)
+			require.NoError(t, leaderTable.RotateBlock(ctx, leaderTable.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+			wg.Wait()
+			insert()
+			expected += 3
+			require.Eventually(t, func() bool {
+				followerTable, err := followerDB.GetTable("test")
+				require.NoError(t, err)
+				followerTable.mtx.RLock()
+				defer followerTable.mtx.RUnlock()
+				return len(followerTable.pendingBlocks) == 0 && followerTable.ActiveBlock().Size() > 0
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(t, expected, rows(followerDB))
+
+			// Followers are read-only.
+			followerTable, err := followerDB.Table("test", config)
+			require.NoError(t, err)
+			r, err := samples.ToRecord()
+			require.NoError(t, err)
+			defer r.Release()
+			_, err = followerTable.InsertRecord(ctx, r)
+			require.ErrorIs(t, err, ErrFollower)
+			_, err = followerDB.Table("other", config)
+			require.ErrorAs(t, err, &ErrTableNotFound{})
+		})
+	}
+}
+
+func Test_DB_RestorePersistedBlocks(t *testing.T) {
+	ctx := context.Background()
+	c, err := New(
+		WithLogger(newTestLogger(t)),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+		WithReadWriteStorage(NewDefaultObjstoreBucket(objstore.NewInMemBucket())),
+		WithManualBlockRotation(),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	db, err := c.DB(ctx, "test", WithDurability(DurabilitySync))
+	require.NoError(t, err)
+	table, err := db.Table("test", NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	insert := func() uint64 {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		return tx
+	}
+
+	beforePersistTx := insert()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+	wg.Wait()
+
+	// The block persisted after the target isn't needed by the restore.
+	restored, err := c.RestoreDB(ctx, "test", RestoreTarget{Tx: beforePersistTx})
+	require.NoError(t, err)
+	require.Equal(t, beforePersistTx, restored.HighWatermark())
+
+	// The rows of the persisted block would be missing from the restored
+	// database, whether the WAL or a snapshot records that it was persisted.
+	_, err = c.RestoreDB(ctx, "test", RestoreTarget{Tx: insert()})
+	require.ErrorIs(t, err, errRestorePersistedBlocks)
+	require.NoError(t, db.Snapshot(ctx))
+	_, err = c.RestoreDB(ctx, "test", RestoreTarget{Tx: db.HighWatermark()})
+	require.ErrorIs(t, err, errRestorePersistedBlocks)
+}
+
+func Test_DB_Restore(t *testing.T) {
+	ctx := context.Background()
+	config := NewTableConfig(dynparquet.SampleDefinition())
+	logger := newTestLogger(t)
+
+	c, err := New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	db, err := c.DB(ctx, "test", WithDurability(DurabilitySync))
+	require.NoError(t, err)
+	table, err := db.Table("test", config)
+	require.NoError(t, err)
+
+	samples := dynparquet.NewTestSamples()
+	insert := func() uint64 {
+		r, err := samples.ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		return tx
+	}
+	rows := func(db *DB) int64 {
+		table, err := db.GetTable("test")
+		require.NoError(t, err)
+		rows := int64(0)
+		require.NoError(t, table.View(ctx, func(ctx context.Context, tx uint64) error {
+			return table.Iterator(ctx, tx, memory.DefaultAllocator, []logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}})
+		}))
+		return rows
+	}
+
+	beforeSnapshotTx := insert()
+	require.NoError(t, db.Snapshot(ctx))
+	snapshotTx := db.HighWatermark()
+	insertTx := insert()
+	// Records are timestamped in milliseconds.
+	time.Sleep(5 * time.Millisecond)
+	insertTime := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	deleteTx, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+	require.NoError(t, err)
+	expected := rows(db)
+	require.Less(t, expected, int64(6))
+
+	for _, tc := range []struct {
+		name   string
+		target RestoreTarget
+		tx     uint64
+		rows   int64
+	}{
+		{name: "snapshot", target: RestoreTarget{Tx: snapshotTx}, tx: snapshotTx, rows: 3},
+		{name: "tx", target: RestoreTarget{Tx: insertTx}, tx: insertTx, rows: 6},
+		{name: "time", target: RestoreTarget{Time: time.Now()}, tx: deleteTx, rows: expected},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			restored, err := c.RestoreDB(ctx, "test", tc.target)
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("test-restore-%d", tc.tx), restored.name)
+			require.Equal(t, tc.tx, restored.HighWatermark())
+			require.Equal(t, tc.rows, rows(restored))
+
+			// The restored database accepts writes.
+			restoredTable, err := restored.GetTable("test")
+			require.NoError(t, err)
+			r, err := samples.ToRecord()
+			require.NoError(t, err)
+			defer r.Release()
+			_, err = restoredTable.InsertRecord(WithWriteDurability(ctx, DurabilitySync), r)
+			require.NoError(t, err)
+			require.Equal(t, tc.rows+3, rows(restored))
+		})
+	}
+
+	// Restoring to a txn that was already restored to fails.
+	_, err = c.RestoreDB(ctx, "test", RestoreTarget{Time: insertTime})
+	require.ErrorContains(t, err, "already exists")
+	// The WAL was truncated after the snapshot.
+	_, err = c.RestoreDB(ctx, "test", RestoreTarget{Tx: beforeSnapshotTx})
+	require.ErrorContains(t, err, "missing")
+	_, err = c.RestoreDB(ctx, "test", RestoreTarget{Tx: deleteTx + 1})
+	require.ErrorContains(t, err, "after the last WAL record")
+
+	// The restored database is not modified.
+	require.Equal(t, expected, rows(db))
+	require.NoError(t, c.Close())
+
+	// Restored databases are reopened like any other.
+	c, err = New(
+		WithLogger(logger),
+		WithWAL(),
+		WithStoragePath(c.storagePath),
+	)
+	require.NoError(t, err)
+	defer c.Close()
+	restored, err := c.GetDB(fmt.Sprintf("test-restore-%d", insertTx))
+	require.NoError(t, err)
+	require.Equal(t, int64(9), rows(restored))
 }
@@ -0,0 +1,65 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDurabilityUnknown is returned by synchronous writes whose WAL record
+// could not be confirmed to be durable. The write was applied nonetheless and
+// is visible to transactions after the returned one, but it may be lost in a
+// crash.
+var ErrDurabilityUnknown = errors.New("durability of the write is unknown")
+
+// Durability determines when writes to a database are acknowledged.
+type Durability int32
+
+const (
+	// DurabilityAsync acknowledges writes as soon as their WAL record is
+	// queued. Records are written in batches in the background, so writes
+	// acknowledged shortly before a crash can be lost. This is the default.
+	DurabilityAsync Durability = iota
+	// DurabilitySync acknowledges writes once their WAL record has been
+	// written and fsynced. Concurrent writes share a single fsync (group
+	// commit). It has no effect on databases or tables without a WAL.
+	DurabilitySync
+)
+
+// WithDurability sets the durability of the writes to the database. It can be
+// overridden per write with WithWriteDurability.
+func WithDurability(d Durability) DBOption {
+	return func(db *DB) error {
+		db.durability.Store(int32(d))
+		return nil
+	}
+}
+
+type durabilityKey struct{}
+
+// WithWriteDurability returns a context that sets the durability of the
+// writes it is used for, overriding the durability of the database.
+func WithWriteDurability(ctx context.Context, d Durability) context.Context {
+	return context.WithValue(ctx, durabilityKey{}, d)
+}
+
+// writeDurability returns the durability of a write made with the given
+// context.
+func (db *DB) writeDurability(ctx context.Context) Durability {
+	if d, ok := ctx.Value(durabilityKey{}).(Durability); ok {
+		return d
+	}
+	return Durability(db.durability.Load())
+}
+
+// waitDurable waits for the WAL record of the given txn to be durable if the
+// write made with the given context is synchronous.
+func (db *DB) waitDurable(ctx context.Context, wal WAL, tx uint64) error {
+	if db.writeDurability(ctx) != DurabilitySync {
+		return nil
+	}
+	if err := wal.WaitDurable(tx); err != nil {
+		return fmt.Errorf("%w: wait for log: %w", ErrDurabilityUnknown, err)
+	}
+	return nil
+}
@@ -10,6 +10,9 @@ import (
 
 const (
 	DynamicColumnsKey = "dynamic_columns"
+	// BlockKey is the key of the metadata that holds the ULID of the block
+	// a persisted file was written from.
+	BlockKey = "block"
 )
 
 var ErrNoDynamicColumns = errors.New("no dynamic columns metadata found, it must be present")
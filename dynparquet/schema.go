@@ -452,6 +452,8 @@ func parquetColumnMetaDataToStorageLayout(metadata format.ColumnMetaData, nullab
 		layout.Type = schemapb.StorageLayout_TYPE_DOUBLE
 	case format.Boolean:
 		layout.Type = schemapb.StorageLayout_TYPE_BOOL
+	case format.Float:
+		layout.Type = schemapb.StorageLayout_TYPE_FLOAT
 	}
 
 	return layout
@@ -463,6 +465,9 @@ type StorageLayout interface {
 	GetNullable() bool
 	GetEncodingInt32() int32
 	GetCompressionInt32() int32
+	GetPrecision() int32
+	GetScale() int32
+	GetListDepth() uint32
 }
 
 type v1storageLayoutWrapper struct {
@@ -520,11 +525,27 @@ func storageLayoutToParquetNode(l StorageLayout) (parquet.Node, error) {
 		node = parquet.Int(32)
 	case int32(schemapb.StorageLayout_TYPE_UINT64):
 		node = parquet.Uint(64)
+	case int32(schemapb.StorageLayout_TYPE_TIMESTAMP):
+		node = parquet.Timestamp(parquet.Nanosecond)
+	case int32(schemapb.StorageLayout_TYPE_FLOAT):
+		node = parquet.Leaf(parquet.FloatType)
+	case int32(schemapb.StorageLayout_TYPE_BINARY):
+		node = parquet.Leaf(parquet.ByteArrayType)
+	case int32(schemapb.StorageLayout_TYPE_DECIMAL):
+		// Decimals are stored as int64, which holds up to 18 digits.
+		if l.GetPrecision() < 1 || l.GetPrecision() > 18 {
+			return nil, fmt.Errorf("decimal precision must be between 1 and 18, got %d", l.GetPrecision())
+		}
+		if l.GetScale() < 0 || l.GetScale() > l.GetPrecision() {
+			return nil, fmt.Errorf("decimal scale must be between 0 and the precision, got %d", l.GetScale())
+		}
+		node = parquet.Decimal(int(l.GetScale()), int(l.GetPrecision()), parquet.Int64Type)
 	default:
 		return nil, fmt.Errorf("unknown storage layout type: %v", l.GetTypeInt32())
 	}
 
-	if l.GetNullable() {
+	nested := l.GetListDepth() > 1
+	if l.GetNullable() && !nested {
 		node = parquet.Optional(node)
 	}
 
@@ -544,7 +565,19 @@ func storageLayoutToParquetNode(l StorageLayout) (parquet.Node, error) {
 		node = parquet.Compressed(node, comp)
 	}
 
-	if l.GetRepeated() {
+	if nested {
+		// Nested lists can't be represented with repeated leaves, they use
+		// the LIST logical type instead. Only the outermost list is nullable.
+		for i := uint32(0); i < l.GetListDepth(); i++ {
+			node = parquet.List(node)
+		}
+		if l.GetNullable() {
+			node = parquet.Optional(node)
+		}
+		return node, nil
+	}
+
+	if l.GetRepeated() || l.GetListDepth() == 1 {
 		node = parquet.Repeated(node)
 	}
 
@@ -646,6 +679,30 @@ func (s *Schema) ColumnByName(name string) (ColumnDefinition, bool) {
 	return s.columns[i], true
 }
 
+// ContainsField reports whether the top-level field with the given name of a
+// parquet file or arrow record written with the schema belongs to the schema.
+// The concrete columns of dynamic columns and prehashed columns belong to the
+// schema if the column they are derived from does.
+func (s *Schema) ContainsField(name string) bool {
+	if def, ok := s.def.(*schemav2pb.Schema); ok {
+		for _, node := range def.Root.Nodes {
+			if nameFromNodeDef(node) == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if IsHashedColumn(name) {
+		name = strings.TrimPrefix(name, prehashedPrefix+".")
+	}
+	if _, ok := s.FindColumn(name); ok {
+		return true
+	}
+	_, ok := s.FindDynamicColumnForConcreteColumn(name)
+	return ok
+}
+
 func (s *Schema) Columns() []ColumnDefinition {
 	return s.columns
 }
@@ -1504,12 +1561,17 @@ func schemaRootFieldNames(schema *parquet.Schema) []string {
 }
 
 // mapMergedColumnNameIndexes maps the column indexes of the original row group
-// to the indexes of the merged schema.
+// to the indexes of the merged schema. Columns of the original row group that
+// are not part of the merged schema, e.g. columns dropped from the schema, are
+// skipped.
 func mapMergedColumnNameIndexes(merged, original []string) []int {
 	origColsLen := len(original)
 	indexMapping := make([]int, len(merged))
 	j := 0
 	for i, col := range merged {
+		for j < origColsLen && original[j] < col {
+			j++
+		}
 		if j < origColsLen && original[j] == col {
 			indexMapping[i] = j
 			j++
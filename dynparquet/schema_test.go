@@ -149,6 +149,28 @@ func TestMapMergedColumnNameIndexes(t *testing.T) {
 			2,
 			3,
 		},
+	}, {
+		name: "superset_dropped_columns",
+		merged: []string{
+			"example_type",
+			"labels.container",
+			"timestamp",
+			"value",
+		},
+		original: []string{
+			"dropped",
+			"example_type",
+			"labels.container",
+			"stacktrace",
+			"timestamp",
+			"value",
+		},
+		expected: []int{
+			1,
+			2,
+			4,
+			5,
+		},
 	}}
 
 	for _, tc := range testCases {
@@ -393,3 +415,49 @@ func BenchmarkMergeDynamicColumnSets(b *testing.B) {
 		MergeDynamicColumnSets(sets)
 	}
 }
+
+func TestStorageLayoutToParquetNode(t *testing.T) {
+	for _, tc := range []struct {
+		layout *schemapb.StorageLayout
+		want   string
+	}{
+		{
+			layout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_TIMESTAMP},
+			want:   "required int64 c (TIMESTAMP(isAdjustedToUTC=true,unit=NANOS));",
+		},
+		{
+			layout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_FLOAT, Nullable: true},
+			want:   "optional float c;",
+		},
+		{
+			layout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_BINARY},
+			want:   "required binary c;",
+		},
+		{
+			layout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_DECIMAL, Precision: 10, Scale: 2},
+			want:   "required int64 c (DECIMAL(10,2));",
+		},
+		{
+			layout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64, Nullable: true, Repeated: true, ListDepth: 2},
+			want: "optional group c (LIST) {\n" +
+				"\t\trepeated group list {\n" +
+				"\t\t\trequired group element (LIST) {\n" +
+				"\t\t\t\trepeated group list {\n" +
+				"\t\t\t\t\trequired int64 element (INT(64,true));\n" +
+				"\t\t\t\t}\n" +
+				"\t\t\t}\n" +
+				"\t\t}\n" +
+				"\t}",
+		},
+	} {
+		node, err := storageLayoutToParquetNode(&v1storageLayoutWrapper{tc.layout})
+		require.NoError(t, err)
+		require.Equal(t, "message s {\n\t"+tc.want+"\n}", parquet.NewSchema("s", parquet.Group{"c": node}).String())
+	}
+
+	_, err := storageLayoutToParquetNode(&v1storageLayoutWrapper{&schemapb.StorageLayout{
+		Type:      schemapb.StorageLayout_TYPE_DECIMAL,
+		Precision: 19,
+	}})
+	require.Error(t, err)
+}
@@ -0,0 +1,347 @@
+package frostdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+	"google.golang.org/protobuf/proto"
+
+	tablepb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/table/v1alpha1"
+	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/wal"
+)
+
+// ErrFollower is returned when writing to a database that follows a leader.
+var ErrFollower = errors.New("database is a read-only follower")
+
+// followerRetryInterval is the interval at which followers retry following
+// their leader after a failure.
+const followerRetryInterval = time.Second
+
+// Leader is the source of the WAL records and snapshots of a database that
+// follower databases replicate (see WithFollower).
+type Leader interface {
+	// Snapshot writes a snapshot of the leader to w and returns the txn it
+	// was taken at.
+	Snapshot(ctx context.Context, w io.Writer) (uint64, error)
+	// Tail calls the handler with the WAL records of the leader starting at
+	// the given txn as they are written, until the context is canceled or an
+	// error occurs. wal.ErrTruncated is returned if the WAL of the leader no
+	// longer contains the record at the given txn.
+	Tail(ctx context.Context, tx uint64, handler wal.ReplayHandlerFunc) error
+}
+
+// NewLocalLeader returns a Leader for a database in the same process. The
+// database must have a WAL.
+func NewLocalLeader(db *DB) Leader {
+	return &localLeader{db: db}
+}
+
+type localLeader struct {
+	db *DB
+}
+
+func (l *localLeader) Snapshot(ctx context.Context, w io.Writer) (uint64, error) {
+	// All txns up to the high watermark are committed, and the snapshot
+	// only contains the writes up to the given txn.
+	tx := l.db.HighWatermark()
+	if err := WriteSnapshot(ctx, tx, l.db, w); err != nil {
+		return 0, err
+	}
+	return tx, nil
+}
+
+func (l *localLeader) Tail(ctx context.Context, tx uint64, handler wal.ReplayHandlerFunc) error {
+	w, ok := l.db.wal.(*wal.FileWAL)
+	if !ok {
+		return fmt.Errorf("leader database %s does not have a WAL", l.db.name)
+	}
+	return w.Tail(ctx, tx, handler)
+}
+
+// WithFollower makes the database a read-only replica of the given leader. The
+// database is initialized from a snapshot of the leader and then applies the
+// WAL records of the leader as they are written. It must be set when the
+// database is opened.
+//
+// Followers don't have a WAL and don't persist blocks, writes to them return
+// ErrFollower. Blocks are dropped once the leader persisted them, so followers
+// should be configured with the storage of the leader as a data source to
+// query them. Tables of the leader that are configured without a WAL are not
+// replicated. The tables of followers are replaced when they fall behind the
+// leader and reload its snapshot, so they should be looked up by name for
+// every read instead of being held on to.
+func WithFollower(leader Leader) DBOption {
+	return func(db *DB) error {
+		if db.txPool != nil {
+			return errors.New("an existing database cannot become a follower")
+		}
+		db.leader = leader
+		return nil
+	}
+}
+
+// startFollower starts a goroutine that applies the WAL records of the leader
+// of the database, if any.
+func (db *DB) startFollower() {
+	if db.leader == nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, db.followerDone = context.WithCancel(context.Background())
+	db.followerWg.Add(1)
+	go func() {
+		defer db.followerWg.Done()
+		for {
+			err := db.leader.Tail(ctx, db.HighWatermark()+1, db.applyLeaderRecord)
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, wal.ErrTruncated) {
+				// The follower fell behind the WAL of the leader, catch up
+				// with a new snapshot.
+				level.Info(db.logger).Log("msg", "leader WAL truncated, loading a new snapshot", "tx", db.HighWatermark())
+				if err = db.loadLeaderSnapshot(ctx); err == nil {
+					continue
+				}
+			}
+			level.Warn(db.logger).Log("msg", "failed to follow leader", "err", err)
+			select {
+			case <-time.After(followerRetryInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopFollower stops the follower goroutine, if any, and waits for it to exit.
+func (db *DB) stopFollower() {
+	if db.followerDone != nil {
+		db.followerDone()
+		db.followerWg.Wait()
+	}
+}
+
+// loadLeaderSnapshot replaces the tables of the database with a snapshot of
+// its leader.
+func (db *DB) loadLeaderSnapshot(ctx context.Context) error {
+	buf := &bytes.Buffer{}
+	tx, err := db.leader.Snapshot(ctx, buf)
+	if err != nil {
+		return fmt.Errorf("snapshot leader: %w", err)
+	}
+
+	// Load the snapshot into new tables so reads see the previous tables
+	// until the snapshot is fully loaded.
+	tables := map[string]*Table{}
+	if err := loadSnapshotTables(ctx, db, bytes.NewReader(buf.Bytes()), int64(buf.Len()), SnapshotDir(db, tx), func(name string, config *tablepb.TableConfig, id ulid.ULID) (*Table, error) {
+		table, err := newTable(
+			db,
+			name,
+			config,
+			db.metricsProvider.metricsForTable(name),
+			db.logger,
+			db.tracer,
+			db.wal,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("instantiate table: %w", err)
+		}
+		table.active, err = newTableBlock(table, 0, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		tables[name] = table
+		return table, nil
+	}); err != nil {
+		return fmt.Errorf("load leader snapshot: %w", err)
+	}
+
+	db.mtx.Lock()
+	prev := db.tables
+	db.tables = tables
+	for name := range tables {
+		delete(db.roTables, name)
+	}
+	db.mtx.Unlock()
+	for _, table := range prev {
+		table.close()
+	}
+	db.resetToTxn(tx, nil)
+	return nil
+}
+
+// applyLeaderRecord applies a WAL record of the leader to the database.
+func (db *DB) applyLeaderRecord(tx uint64, record *walpb.Record) error {
+	switch e := record.Entry.EntryType.(type) {
+	case *walpb.Entry_NewTableBlock_:
+		if err := db.followNewTableBlock(tx, e.NewTableBlock); err != nil {
+			return err
+		}
+	case *walpb.Entry_Write_:
+		if err := db.followWrite(tx, e.Write); err != nil {
+			return err
+		}
+	case *walpb.Entry_Transaction_:
+		for _, write := range e.Transaction.Writes {
+			if err := db.followWrite(tx, write); err != nil {
+				return err
+			}
+		}
+	case *walpb.Entry_Delete_:
+		table, err := db.GetTable(e.Delete.TableName)
+		if err != nil {
+			return fmt.Errorf("get table: %w", err)
+		}
+		ts, err := tombstoneFromProto(tx, e.Delete.Filter, e.Delete.BlockId)
+		if err != nil {
+			return err
+		}
+		table.addTombstone(ts)
+	case *walpb.Entry_TableBlockPersisted_:
+		table, err := db.GetTable(e.TableBlockPersisted.TableName)
+		if err != nil {
+			return fmt.Errorf("get table: %w", err)
+		}
+		var id ulid.ULID
+		if err := id.UnmarshalBinary(e.TableBlockPersisted.BlockId); err != nil {
+			return err
+		}
+		// The block can now be read from storage.
+		table.mtx.RLock()
+		var persisted *TableBlock
+		for block := range table.pendingBlocks {
+			if block.ulid == id {
+				persisted = block
+			}
+		}
+		table.mtx.RUnlock()
+		if persisted != nil {
+			table.dropPendingBlock(persisted)
+		}
+	case *walpb.Entry_DropTable_:
+		db.removeTable(e.DropTable.TableName)
+	case *walpb.Entry_TruncateTable_:
+		table, err := db.GetTable(e.TruncateTable.TableName)
+		if err != nil {
+			return fmt.Errorf("get table: %w", err)
+		}
+		var id ulid.ULID
+		if err := id.UnmarshalBinary(e.TruncateTable.BlockId); err != nil {
+			return err
+		}
+		table.mtx.Lock()
+		prev := table.active
+		err = table.truncateLocked(tx, id)
+		table.mtx.Unlock()
+		if err != nil {
+			return err
+		}
+		table.addTombstone(&tombstone{tx: tx, block: id})
+		table.dropPendingBlock(prev)
+	case *walpb.Entry_RenameTable_:
+		db.mtx.Lock()
+		if _, ok := db.tables[e.RenameTable.TableName]; ok {
+			db.renameTableLocked(e.RenameTable.TableName, e.RenameTable.NewTableName)
+		}
+		db.mtx.Unlock()
+	case *walpb.Entry_Snapshot_:
+	default:
+		return fmt.Errorf("unexpected WAL entry type: %T", e)
+	}
+
+	// Make the record visible to reads.
+	db.resetToTxn(tx, nil)
+	return nil
+}
+
+// followWrite inserts a write of the leader into the active block of its
+// table.
+func (db *DB) followWrite(tx uint64, entry *walpb.Entry_Write) error {
+	table, err := db.GetTable(entry.TableName)
+	if err != nil {
+		return fmt.Errorf("get table: %w", err)
+	}
+	if !entry.Arrow {
+		return fmt.Errorf("unsupported write of table %s: parquet writes are deprecated", entry.TableName)
+	}
+	reader, err := ipc.NewReader(bytes.NewReader(entry.Data))
+	if err != nil {
+		return fmt.Errorf("create ipc reader: %w", err)
+	}
+	defer reader.Release()
+	record, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("read record: %w", err)
+	}
+	return table.ActiveBlock().InsertRecord(context.Background(), tx, record)
+}
+
+// followNewTableBlock creates the table of the entry or rotates its active
+// block. The previous active block stays readable until the leader persists
+// it.
+func (db *DB) followNewTableBlock(tx uint64, entry *walpb.Entry_NewTableBlock) error {
+	var id ulid.ULID
+	if err := id.UnmarshalBinary(entry.BlockId); err != nil {
+		return err
+	}
+
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	table, ok := db.tables[entry.TableName]
+	if !ok {
+		var err error
+		if _, ok := db.roTables[entry.TableName]; ok {
+			table, err = db.promoteReadOnlyTableLocked(entry.TableName, entry.Config)
+			if err != nil {
+				return fmt.Errorf("promoting read only table: %w", err)
+			}
+		} else {
+			table, err = newTable(
+				db,
+				entry.TableName,
+				entry.Config,
+				db.metricsProvider.metricsForTable(entry.TableName),
+				db.logger,
+				db.tracer,
+				db.wal,
+			)
+			if err != nil {
+				return fmt.Errorf("instantiate table: %w", err)
+			}
+		}
+		table.active, err = newTableBlock(table, 0, tx, id)
+		if err != nil {
+			return err
+		}
+		db.tables[entry.TableName] = table
+		return nil
+	}
+
+	table.mtx.Lock()
+	defer table.mtx.Unlock()
+	if !proto.Equal(entry.Config, table.config.Load()) {
+		schema, err := schemaFromTableConfig(entry.Config)
+		if err != nil {
+			return fmt.Errorf("initialize schema: %w", err)
+		}
+		table.config.Store(entry.Config)
+		table.schema.Store(schema)
+	}
+	block, err := newTableBlock(table, table.active.minTx, tx, id)
+	if err != nil {
+		return err
+	}
+	table.pendingBlocks[table.active] = struct{}{}
+	table.active = block
+	return nil
+}
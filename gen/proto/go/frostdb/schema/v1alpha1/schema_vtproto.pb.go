@@ -192,6 +192,21 @@ func (m *StorageLayout) MarshalToSizedBufferVT(dAtA []byte) (int, error) {
 		i -= len(m.unknownFields)
 		copy(dAtA[i:], m.unknownFields)
 	}
+	if m.ListDepth != 0 {
+		i = protohelpers.EncodeVarint(dAtA, i, uint64(m.ListDepth))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Scale != 0 {
+		i = protohelpers.EncodeVarint(dAtA, i, uint64(m.Scale))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Precision != 0 {
+		i = protohelpers.EncodeVarint(dAtA, i, uint64(m.Precision))
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Repeated {
 		i--
 		if m.Repeated {
@@ -359,6 +374,15 @@ func (m *StorageLayout) SizeVT() (n int) {
 	if m.Repeated {
 		n += 2
 	}
+	if m.Precision != 0 {
+		n += 1 + protohelpers.SizeOfVarint(uint64(m.Precision))
+	}
+	if m.Scale != 0 {
+		n += 1 + protohelpers.SizeOfVarint(uint64(m.Scale))
+	}
+	if m.ListDepth != 0 {
+		n += 1 + protohelpers.SizeOfVarint(uint64(m.ListDepth))
+	}
 	n += len(m.unknownFields)
 	return n
 }
@@ -839,6 +863,63 @@ func (m *StorageLayout) UnmarshalVT(dAtA []byte) error {
 				}
 			}
 			m.Repeated = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Precision", wireType)
+			}
+			m.Precision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return protohelpers.ErrIntOverflow
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Precision |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scale", wireType)
+			}
+			m.Scale = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return protohelpers.ErrIntOverflow
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Scale |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListDepth", wireType)
+			}
+			m.ListDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return protohelpers.ErrIntOverflow
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ListDepth |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := protohelpers.Skip(dAtA[iNdEx:])
@@ -178,6 +178,60 @@ func (Type) EnumDescriptor() ([]byte, []int) {
 	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{1}
 }
 
+// Type is the type of a join.
+type Join_Type int32
+
+const (
+	// UNKNOWN_UNSPECIFIED is the default value. It should not be used.
+	Join_TYPE_UNKNOWN_UNSPECIFIED Join_Type = 0
+	// INNER only emits left rows that have a matching right row.
+	Join_TYPE_INNER Join_Type = 1
+	// LEFT emits all left rows. The right columns of left rows without a
+	// matching right row are null.
+	Join_TYPE_LEFT Join_Type = 2
+)
+
+// Enum value maps for Join_Type.
+var (
+	Join_Type_name = map[int32]string{
+		0: "TYPE_UNKNOWN_UNSPECIFIED",
+		1: "TYPE_INNER",
+		2: "TYPE_LEFT",
+	}
+	Join_Type_value = map[string]int32{
+		"TYPE_UNKNOWN_UNSPECIFIED": 0,
+		"TYPE_INNER":               1,
+		"TYPE_LEFT":                2,
+	}
+)
+
+func (x Join_Type) Enum() *Join_Type {
+	p := new(Join_Type)
+	*p = x
+	return p
+}
+
+func (x Join_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Join_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_storage_v1alpha1_storage_proto_enumTypes[2].Descriptor()
+}
+
+func (Join_Type) Type() protoreflect.EnumType {
+	return &file_frostdb_storage_v1alpha1_storage_proto_enumTypes[2]
+}
+
+func (x Join_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Join_Type.Descriptor instead.
+func (Join_Type) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{12, 0}
+}
+
 // Type is the type of aggregation function.
 type AggregationFunction_Type int32
 
@@ -235,11 +289,11 @@ func (x AggregationFunction_Type) String() string {
 }
 
 func (AggregationFunction_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_frostdb_storage_v1alpha1_storage_proto_enumTypes[2].Descriptor()
+	return file_frostdb_storage_v1alpha1_storage_proto_enumTypes[3].Descriptor()
 }
 
 func (AggregationFunction_Type) Type() protoreflect.EnumType {
-	return &file_frostdb_storage_v1alpha1_storage_proto_enumTypes[2]
+	return &file_frostdb_storage_v1alpha1_storage_proto_enumTypes[3]
 }
 
 func (x AggregationFunction_Type) Number() protoreflect.EnumNumber {
@@ -248,7 +302,7 @@ func (x AggregationFunction_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use AggregationFunction_Type.Descriptor instead.
 func (AggregationFunction_Type) EnumDescriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{23, 0}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{24, 0}
 }
 
 // QueryRequest is the message sent to the Query gRPC endpoint.
@@ -261,6 +315,11 @@ type QueryRequest struct {
 	PlanRoot *PlanNode `protobuf:"bytes,1,opt,name=plan_root,json=planRoot,proto3" json:"plan_root,omitempty"`
 	// description describes the query.
 	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// read_tx is the transaction to read the tables of the query at, so that
+	// queries with the same read_tx see the same data. The query fails if the
+	// data of the transaction has since been compacted. Zero reads at the
+	// latest transaction.
+	ReadTx uint64 `protobuf:"varint,3,opt,name=read_tx,json=readTx,proto3" json:"read_tx,omitempty"`
 }
 
 func (x *QueryRequest) Reset() {
@@ -309,6 +368,13 @@ func (x *QueryRequest) GetDescription() string {
 	return ""
 }
 
+func (x *QueryRequest) GetReadTx() uint64 {
+	if x != nil {
+		return x.ReadTx
+	}
+	return 0
+}
+
 // QueryResponse is the message received from the Query gRPC endpoint.
 type QueryResponse struct {
 	state         protoimpl.MessageState
@@ -436,6 +502,7 @@ type PlanNodeSpec struct {
 	//	*PlanNodeSpec_Distinct
 	//	*PlanNodeSpec_Aggregation
 	//	*PlanNodeSpec_Limit
+	//	*PlanNodeSpec_Join
 	Spec isPlanNodeSpec_Spec `protobuf_oneof:"spec"`
 }
 
@@ -527,6 +594,13 @@ func (x *PlanNodeSpec) GetLimit() *Limit {
 	return nil
 }
 
+func (x *PlanNodeSpec) GetJoin() *Join {
+	if x, ok := x.GetSpec().(*PlanNodeSpec_Join); ok {
+		return x.Join
+	}
+	return nil
+}
+
 type isPlanNodeSpec_Spec interface {
 	isPlanNodeSpec_Spec()
 }
@@ -566,6 +640,11 @@ type PlanNodeSpec_Limit struct {
 	Limit *Limit `protobuf:"bytes,7,opt,name=limit,proto3,oneof"`
 }
 
+type PlanNodeSpec_Join struct {
+	// Join is specified if this PlanNode represents a join.
+	Join *Join `protobuf:"bytes,8,opt,name=join,proto3,oneof"`
+}
+
 func (*PlanNodeSpec_TableScan) isPlanNodeSpec_Spec() {}
 
 func (*PlanNodeSpec_SchemaScan) isPlanNodeSpec_Spec() {}
@@ -580,6 +659,8 @@ func (*PlanNodeSpec_Aggregation) isPlanNodeSpec_Spec() {}
 
 func (*PlanNodeSpec_Limit) isPlanNodeSpec_Spec() {}
 
+func (*PlanNodeSpec_Join) isPlanNodeSpec_Spec() {}
+
 // TableScan describes scanning a table to obtain rows.
 type TableScan struct {
 	state         protoimpl.MessageState
@@ -990,6 +1071,85 @@ func (x *Aggregation) GetAggExprs() []*Expr {
 	return nil
 }
 
+// Join describes a join of the rows of the PlanNode's next node (the left
+// side) with the rows of another plan (the right side).
+type Join struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Type is the type of the join.
+	Type Join_Type `protobuf:"varint,1,opt,name=type,proto3,enum=frostdb.storage.v1alpha1.Join_Type" json:"type,omitempty"`
+	// Right is the root of the plan of the right side of the join.
+	Right *PlanNode `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	// LeftKeys are the columns of the left side that are compared with the
+	// right keys.
+	LeftKeys []*Expr `protobuf:"bytes,3,rep,name=left_keys,json=leftKeys,proto3" json:"left_keys,omitempty"`
+	// RightKeys are the columns of the right side that are compared with the
+	// left keys. There must be as many right keys as left keys.
+	RightKeys []*Expr `protobuf:"bytes,4,rep,name=right_keys,json=rightKeys,proto3" json:"right_keys,omitempty"`
+}
+
+func (x *Join) Reset() {
+	*x = Join{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Join) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Join) ProtoMessage() {}
+
+func (x *Join) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Join.ProtoReflect.Descriptor instead.
+func (*Join) Descriptor() ([]byte, []int) {
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Join) GetType() Join_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Join_TYPE_UNKNOWN_UNSPECIFIED
+}
+
+func (x *Join) GetRight() *PlanNode {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+func (x *Join) GetLeftKeys() []*Expr {
+	if x != nil {
+		return x.LeftKeys
+	}
+	return nil
+}
+
+func (x *Join) GetRightKeys() []*Expr {
+	if x != nil {
+		return x.RightKeys
+	}
+	return nil
+}
+
 // Expr is the base type for all expressions.
 type Expr struct {
 	state         protoimpl.MessageState
@@ -1003,7 +1163,7 @@ type Expr struct {
 func (x *Expr) Reset() {
 	*x = Expr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[12]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1016,7 +1176,7 @@ func (x *Expr) String() string {
 func (*Expr) ProtoMessage() {}
 
 func (x *Expr) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[12]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1029,7 +1189,7 @@ func (x *Expr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Expr.ProtoReflect.Descriptor instead.
 func (*Expr) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{12}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Expr) GetDef() *ExprDef {
@@ -1067,7 +1227,7 @@ type ExprDef struct {
 func (x *ExprDef) Reset() {
 	*x = ExprDef{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1080,7 +1240,7 @@ func (x *ExprDef) String() string {
 func (*ExprDef) ProtoMessage() {}
 
 func (x *ExprDef) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1093,7 +1253,7 @@ func (x *ExprDef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExprDef.ProtoReflect.Descriptor instead.
 func (*ExprDef) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{13}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{14}
 }
 
 func (m *ExprDef) GetContent() isExprDef_Content {
@@ -1250,7 +1410,7 @@ type BinaryExpr struct {
 func (x *BinaryExpr) Reset() {
 	*x = BinaryExpr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1263,7 +1423,7 @@ func (x *BinaryExpr) String() string {
 func (*BinaryExpr) ProtoMessage() {}
 
 func (x *BinaryExpr) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1276,7 +1436,7 @@ func (x *BinaryExpr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BinaryExpr.ProtoReflect.Descriptor instead.
 func (*BinaryExpr) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{14}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *BinaryExpr) GetLeft() *Expr {
@@ -1317,7 +1477,7 @@ type IfExpr struct {
 func (x *IfExpr) Reset() {
 	*x = IfExpr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[15]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1330,7 +1490,7 @@ func (x *IfExpr) String() string {
 func (*IfExpr) ProtoMessage() {}
 
 func (x *IfExpr) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[15]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1343,7 +1503,7 @@ func (x *IfExpr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IfExpr.ProtoReflect.Descriptor instead.
 func (*IfExpr) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{15}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *IfExpr) GetCondition() *Expr {
@@ -1382,7 +1542,7 @@ type ConvertExpr struct {
 func (x *ConvertExpr) Reset() {
 	*x = ConvertExpr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[16]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1395,7 +1555,7 @@ func (x *ConvertExpr) String() string {
 func (*ConvertExpr) ProtoMessage() {}
 
 func (x *ConvertExpr) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[16]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1408,7 +1568,7 @@ func (x *ConvertExpr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConvertExpr.ProtoReflect.Descriptor instead.
 func (*ConvertExpr) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{16}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ConvertExpr) GetExpr() *Expr {
@@ -1438,7 +1598,7 @@ type Column struct {
 func (x *Column) Reset() {
 	*x = Column{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[17]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1451,7 +1611,7 @@ func (x *Column) String() string {
 func (*Column) ProtoMessage() {}
 
 func (x *Column) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[17]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1464,7 +1624,7 @@ func (x *Column) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Column.ProtoReflect.Descriptor instead.
 func (*Column) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{17}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Column) GetName() string {
@@ -1487,7 +1647,7 @@ type Literal struct {
 func (x *Literal) Reset() {
 	*x = Literal{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[18]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1500,7 +1660,7 @@ func (x *Literal) String() string {
 func (*Literal) ProtoMessage() {}
 
 func (x *Literal) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[18]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1513,7 +1673,7 @@ func (x *Literal) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Literal.ProtoReflect.Descriptor instead.
 func (*Literal) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{18}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Literal) GetContent() *LiteralContent {
@@ -1549,7 +1709,7 @@ type LiteralContent struct {
 func (x *LiteralContent) Reset() {
 	*x = LiteralContent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1562,7 +1722,7 @@ func (x *LiteralContent) String() string {
 func (*LiteralContent) ProtoMessage() {}
 
 func (x *LiteralContent) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +1735,7 @@ func (x *LiteralContent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LiteralContent.ProtoReflect.Descriptor instead.
 func (*LiteralContent) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{19}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{20}
 }
 
 func (m *LiteralContent) GetValue() isLiteralContent_Value {
@@ -1739,7 +1899,7 @@ type Null struct {
 func (x *Null) Reset() {
 	*x = Null{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1752,7 +1912,7 @@ func (x *Null) String() string {
 func (*Null) ProtoMessage() {}
 
 func (x *Null) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1765,7 +1925,7 @@ func (x *Null) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Null.ProtoReflect.Descriptor instead.
 func (*Null) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{20}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{21}
 }
 
 // Alias is an alias for an expression.
@@ -1783,7 +1943,7 @@ type Alias struct {
 func (x *Alias) Reset() {
 	*x = Alias{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[21]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1796,7 +1956,7 @@ func (x *Alias) String() string {
 func (*Alias) ProtoMessage() {}
 
 func (x *Alias) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[21]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1809,7 +1969,7 @@ func (x *Alias) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Alias.ProtoReflect.Descriptor instead.
 func (*Alias) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{21}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Alias) GetName() string {
@@ -1839,7 +1999,7 @@ type DynamicColumn struct {
 func (x *DynamicColumn) Reset() {
 	*x = DynamicColumn{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[22]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1852,7 +2012,7 @@ func (x *DynamicColumn) String() string {
 func (*DynamicColumn) ProtoMessage() {}
 
 func (x *DynamicColumn) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[22]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1865,7 +2025,7 @@ func (x *DynamicColumn) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DynamicColumn.ProtoReflect.Descriptor instead.
 func (*DynamicColumn) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{22}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *DynamicColumn) GetName() string {
@@ -1890,7 +2050,7 @@ type AggregationFunction struct {
 func (x *AggregationFunction) Reset() {
 	*x = AggregationFunction{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[23]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1903,7 +2063,7 @@ func (x *AggregationFunction) String() string {
 func (*AggregationFunction) ProtoMessage() {}
 
 func (x *AggregationFunction) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[23]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1916,7 +2076,7 @@ func (x *AggregationFunction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AggregationFunction.ProtoReflect.Descriptor instead.
 func (*AggregationFunction) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{23}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *AggregationFunction) GetType() AggregationFunction_Type {
@@ -1946,7 +2106,7 @@ type DurationExpr struct {
 func (x *DurationExpr) Reset() {
 	*x = DurationExpr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[24]
+		mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1959,7 +2119,7 @@ func (x *DurationExpr) String() string {
 func (*DurationExpr) ProtoMessage() {}
 
 func (x *DurationExpr) ProtoReflect() protoreflect.Message {
-	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[24]
+	mi := &file_frostdb_storage_v1alpha1_storage_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1972,7 +2132,7 @@ func (x *DurationExpr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DurationExpr.ProtoReflect.Descriptor instead.
 func (*DurationExpr) Descriptor() ([]byte, []int) {
-	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{24}
+	return file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *DurationExpr) GetMilliseconds() int64 {
@@ -1989,55 +2149,60 @@ var file_frostdb_storage_v1alpha1_storage_proto_rawDesc = []byte{
 	0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61,
 	0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
 	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
-	0x61, 0x31, 0x22, 0x71, 0x0a, 0x0c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x3f, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
-	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
-	0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x52,
-	0x6f, 0x6f, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x27, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x7e,
-	0x0a, 0x08, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x04, 0x6e, 0x65,
-	0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
-	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
-	0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x65,
-	0x78, 0x74, 0x12, 0x3a, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61,
-	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e,
-	0x4e, 0x6f, 0x64, 0x65, 0x53, 0x70, 0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0xef,
-	0x03, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x70, 0x65, 0x63, 0x12,
-	0x44, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74,
-	0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54,
-	0x61, 0x62, 0x6c, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x47, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f,
-	0x73, 0x63, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f,
+	0x61, 0x31, 0x22, 0x8a, 0x01, 0x0a, 0x0c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x72, 0x6f, 0x6f, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x6e,
+	0x52, 0x6f, 0x6f, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x74,
+	0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x72, 0x65, 0x61, 0x64, 0x54, 0x78, 0x22,
+	0x27, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x7e, 0x0a, 0x08, 0x50, 0x6c, 0x61, 0x6e,
+	0x4e, 0x6f, 0x64, 0x65, 0x12, 0x36, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c,
+	0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x12, 0x3a, 0x0a, 0x04,
+	0x73, 0x70, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f,
 	0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e,
-	0x48, 0x00, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x3a,
-	0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
-	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x48, 0x00, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0a, 0x70, 0x72,
-	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24,
-	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x70,
+	0x65, 0x63, 0x52, 0x04, 0x73, 0x70, 0x65, 0x63, 0x22, 0xa5, 0x04, 0x0a, 0x0c, 0x50, 0x6c, 0x61,
+	0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x70, 0x65, 0x63, 0x12, 0x44, 0x0a, 0x0a, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63,
+	0x61, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x12,
+	0x47, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
 	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
-	0x44, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x48, 0x00, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74,
-	0x69, 0x6e, 0x63, 0x74, 0x12, 0x49, 0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73,
-	0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x48, 0x00, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x37, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x08,
+	0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
 	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48,
-	0x00, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x69, 0x6e,
+	0x63, 0x74, 0x48, 0x00, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x12, 0x49,
+	0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41,
+	0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x34, 0x0a, 0x04, 0x6a, 0x6f, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4a, 0x6f, 0x69, 0x6e,
+	0x48, 0x00, 0x52, 0x04, 0x6a, 0x6f, 0x69, 0x6e, 0x42, 0x06, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63,
 	0x22, 0x43, 0x0a, 0x09, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x36, 0x0a,
 	0x04, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72,
 	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31,
@@ -2075,186 +2240,206 @@ var file_frostdb_storage_v1alpha1_storage_proto_rawDesc = []byte{
 	0x67, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
 	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
 	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x08,
-	0x61, 0x67, 0x67, 0x45, 0x78, 0x70, 0x72, 0x73, 0x22, 0x3b, 0x0a, 0x04, 0x45, 0x78, 0x70, 0x72,
-	0x12, 0x33, 0x0a, 0x03, 0x64, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e,
-	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
-	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x44, 0x65, 0x66,
-	0x52, 0x03, 0x64, 0x65, 0x66, 0x22, 0x84, 0x05, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x72, 0x44, 0x65,
-	0x66, 0x12, 0x47, 0x0a, 0x0b, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x65, 0x78, 0x70, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
-	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00, 0x52, 0x0a,
-	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x12, 0x3a, 0x0a, 0x06, 0x63, 0x6f,
-	0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x72, 0x6f,
-	0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x48, 0x00, 0x52, 0x06,
-	0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x3d, 0x0a, 0x07, 0x6c, 0x69, 0x74, 0x65, 0x72, 0x61,
-	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x61, 0x67, 0x67, 0x45, 0x78, 0x70, 0x72, 0x73, 0x22, 0xba, 0x02, 0x0a, 0x04, 0x4a, 0x6f, 0x69,
+	0x6e, 0x12, 0x37, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4a, 0x6f, 0x69, 0x6e, 0x2e,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x05, 0x72, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x72,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x3b, 0x0a, 0x09, 0x6c, 0x65, 0x66, 0x74, 0x5f, 0x6b, 0x65, 0x79,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
 	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
-	0x61, 0x31, 0x2e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x69,
-	0x74, 0x65, 0x72, 0x61, 0x6c, 0x12, 0x50, 0x0a, 0x0e, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63,
-	0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
-	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
-	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63,
-	0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69,
-	0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x62, 0x0a, 0x14, 0x61, 0x67, 0x67, 0x72, 0x65,
-	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
+	0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x08, 0x6c, 0x65, 0x66, 0x74, 0x4b, 0x65, 0x79,
+	0x73, 0x12, 0x3d, 0x0a, 0x0a, 0x72, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
 	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
-	0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x05, 0x61,
-	0x6c, 0x69, 0x61, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x09, 0x72, 0x69, 0x67, 0x68, 0x74, 0x4b, 0x65, 0x79, 0x73,
+	0x22, 0x43, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49,
+	0x4e, 0x4e, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c,
+	0x45, 0x46, 0x54, 0x10, 0x02, 0x22, 0x3b, 0x0a, 0x04, 0x45, 0x78, 0x70, 0x72, 0x12, 0x33, 0x0a,
+	0x03, 0x64, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f,
 	0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x48, 0x00, 0x52, 0x05, 0x61,
-	0x6c, 0x69, 0x61, 0x73, 0x12, 0x44, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x44, 0x65, 0x66, 0x52, 0x03, 0x64,
+	0x65, 0x66, 0x22, 0x84, 0x05, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x72, 0x44, 0x65, 0x66, 0x12, 0x47,
+	0x0a, 0x0b, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x42,
+	0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00, 0x52, 0x0a, 0x62, 0x69, 0x6e,
+	0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x12, 0x3a, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x12, 0x3d, 0x0a, 0x07, 0x6c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x69, 0x74, 0x65, 0x72,
+	0x61, 0x6c, 0x12, 0x50, 0x0a, 0x0e, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x5f, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x62, 0x0a, 0x14, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x48, 0x00, 0x52, 0x13, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x48, 0x00, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61,
+	0x73, 0x12, 0x44, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00, 0x52, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x76, 0x65,
+	0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x45, 0x78, 0x70, 0x72, 0x48,
+	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x02, 0x69, 0x66,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x49, 0x66, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00, 0x52, 0x02, 0x69, 0x66, 0x42, 0x09,
+	0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xa4, 0x01, 0x0a, 0x0a, 0x42, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x12, 0x32, 0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
 	0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00,
-	0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x07, 0x63, 0x6f,
-	0x6e, 0x76, 0x65, 0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72,
+	0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12, 0x34, 0x0a, 0x05,
+	0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72,
 	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x45, 0x78,
-	0x70, 0x72, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x12, 0x32, 0x0a,
-	0x02, 0x69, 0x66, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x72, 0x6f, 0x73,
-	0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0x2e, 0x49, 0x66, 0x45, 0x78, 0x70, 0x72, 0x48, 0x00, 0x52, 0x02, 0x69,
-	0x66, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xa4, 0x01, 0x0a,
-	0x0a, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x12, 0x32, 0x0a, 0x04, 0x6c,
-	0x65, 0x66, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73,
-	0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12,
-	0x34, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x05, 0x72, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x2c, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
 	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x05,
-	0x72, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2c, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x1c, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72,
-	0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4f, 0x70, 0x52,
-	0x02, 0x6f, 0x70, 0x22, 0xae, 0x01, 0x0a, 0x06, 0x49, 0x66, 0x45, 0x78, 0x70, 0x72, 0x12, 0x3c,
-	0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72,
-	0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70,
-	0x72, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x04,
-	0x74, 0x68, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f,
-	0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x74, 0x68, 0x65, 0x6e,
-	0x12, 0x32, 0x0a, 0x04, 0x65, 0x6c, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4f, 0x70, 0x52, 0x02, 0x6f, 0x70,
+	0x22, 0xae, 0x01, 0x0a, 0x06, 0x49, 0x66, 0x45, 0x78, 0x70, 0x72, 0x12, 0x3c, 0x0a, 0x09, 0x63,
+	0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
 	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04,
-	0x65, 0x6c, 0x73, 0x65, 0x22, 0x75, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x45,
-	0x78, 0x70, 0x72, 0x12, 0x32, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72,
-	0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70,
-	0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
-	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
-	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x1c, 0x0a, 0x06, 0x43,
-	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4d, 0x0a, 0x07, 0x4c, 0x69, 0x74,
-	0x65, 0x72, 0x61, 0x6c, 0x12, 0x42, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
-	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
-	0x2e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52,
-	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x9d, 0x03, 0x0a, 0x0e, 0x4c, 0x69, 0x74,
-	0x65, 0x72, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x0a, 0x6e,
-	0x75, 0x6c, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
-	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x48,
-	0x00, 0x52, 0x09, 0x6e, 0x75, 0x6c, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a,
-	0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x48, 0x00, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a,
-	0x0b, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e,
-	0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74,
-	0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00,
-	0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a,
-	0x0b, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x02, 0x48, 0x00, 0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x23, 0x0a, 0x0c, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0b, 0x62,
-	0x69, 0x6e, 0x61, 0x72, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x73, 0x74,
-	0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42,
-	0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x06, 0x0a, 0x04, 0x4e, 0x75, 0x6c, 0x6c,
-	0x22, 0x4f, 0x0a, 0x05, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a,
-	0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x09,
+	0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x68, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x74, 0x68, 0x65, 0x6e, 0x12, 0x32, 0x0a,
+	0x04, 0x65, 0x6c, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72,
 	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70,
-	0x72, 0x22, 0x23, 0x0a, 0x0d, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x6c, 0x73,
+	0x65, 0x22, 0x75, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x45, 0x78, 0x70, 0x72,
+	0x12, 0x32, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04,
+	0x65, 0x78, 0x70, 0x72, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x1c, 0x0a, 0x06, 0x43, 0x6f, 0x6c, 0x75,
 	0x6d, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x13, 0x41, 0x67, 0x67, 0x72, 0x65,
-	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x46,
-	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x32, 0x2e, 0x66,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4d, 0x0a, 0x07, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61,
+	0x6c, 0x12, 0x42, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69,
+	0x74, 0x65, 0x72, 0x61, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x9d, 0x03, 0x0a, 0x0e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61,
+	0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x0a, 0x6e, 0x75, 0x6c, 0x6c,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66,
 	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76,
-	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x09,
+	0x6e, 0x75, 0x6c, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f, 0x6f,
+	0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x69, 0x6e,
+	0x74, 0x33, 0x32, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a,
+	0x0c, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x36, 0x34,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x75,
+	0x69, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x66, 0x6c,
+	0x6f, 0x61, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x48,
+	0x00, 0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a,
+	0x0c, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x69, 0x6e, 0x61,
+	0x72, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x07, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x06, 0x0a, 0x04, 0x4e, 0x75, 0x6c, 0x6c, 0x22, 0x4f, 0x0a,
+	0x05, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x65, 0x78,
+	0x70, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x22, 0x23,
+	0x0a, 0x0d, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x22, 0x9f, 0x02, 0x0a, 0x13, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x46, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x32, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70,
+	0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x22, 0x8b, 0x01, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1c, 0x0a, 0x18, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c,
+	0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x55, 0x4d, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x41, 0x56, 0x47, 0x10, 0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55,
+	0x4e, 0x49, 0x51, 0x55, 0x45, 0x10, 0x06, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x41, 0x4e, 0x44, 0x10, 0x07, 0x22, 0x32, 0x0a, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x45, 0x78, 0x70, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x69, 0x6c,
+	0x6c, 0x69, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x2a, 0x85, 0x02, 0x0a, 0x02, 0x4f, 0x70,
+	0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x50, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05,
+	0x4f, 0x50, 0x5f, 0x45, 0x51, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x50, 0x5f, 0x4e, 0x4f,
+	0x54, 0x5f, 0x45, 0x51, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x4c, 0x54, 0x10,
+	0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x50, 0x5f, 0x4c, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x04, 0x12,
+	0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x47, 0x54, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x50,
+	0x5f, 0x47, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x50, 0x5f, 0x52,
+	0x45, 0x47, 0x45, 0x58, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x07, 0x12, 0x16, 0x0a, 0x12,
+	0x4f, 0x50, 0x5f, 0x52, 0x45, 0x47, 0x45, 0x58, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4d, 0x41, 0x54,
+	0x43, 0x48, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x41, 0x4e, 0x44, 0x10, 0x09,
+	0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x4f, 0x52, 0x10, 0x0a, 0x12, 0x0a, 0x0a, 0x06, 0x4f,
+	0x50, 0x5f, 0x41, 0x44, 0x44, 0x10, 0x0b, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x53, 0x55,
+	0x42, 0x10, 0x0c, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x4d, 0x55, 0x4c, 0x10, 0x0d, 0x12,
+	0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x44, 0x49, 0x56, 0x10, 0x0e, 0x12, 0x0f, 0x0a, 0x0b, 0x4f,
+	0x50, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x53, 0x10, 0x0f, 0x12, 0x13, 0x0a, 0x0f,
+	0x4f, 0x50, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x53, 0x10,
+	0x10, 0x2a, 0x36, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x46, 0x4c, 0x4f, 0x41, 0x54, 0x36, 0x34, 0x10, 0x01, 0x32, 0x6e, 0x0a, 0x0e, 0x46, 0x72, 0x6f,
+	0x73, 0x74, 0x44, 0x42, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5c, 0x0a, 0x05, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x12, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
 	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
-	0x45, 0x78, 0x70, 0x72, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x22, 0x8b, 0x01, 0x0a, 0x04, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e,
-	0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
-	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x55, 0x4d, 0x10, 0x01, 0x12,
-	0x0c, 0x0a, 0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x0c, 0x0a,
-	0x08, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x41, 0x56, 0x47, 0x10, 0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x59, 0x50,
-	0x45, 0x5f, 0x55, 0x4e, 0x49, 0x51, 0x55, 0x45, 0x10, 0x06, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x59,
-	0x50, 0x45, 0x5f, 0x41, 0x4e, 0x44, 0x10, 0x07, 0x22, 0x32, 0x0a, 0x0c, 0x44, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x70, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x69, 0x6c, 0x6c,
-	0x69, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
-	0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x2a, 0x85, 0x02, 0x0a,
-	0x02, 0x4f, 0x70, 0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x50, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
-	0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
-	0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x45, 0x51, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x50,
-	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f,
-	0x4c, 0x54, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x50, 0x5f, 0x4c, 0x54, 0x5f, 0x45, 0x51,
-	0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x47, 0x54, 0x10, 0x05, 0x12, 0x0c, 0x0a,
-	0x08, 0x4f, 0x50, 0x5f, 0x47, 0x54, 0x5f, 0x45, 0x51, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x4f,
-	0x50, 0x5f, 0x52, 0x45, 0x47, 0x45, 0x58, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x07, 0x12,
-	0x16, 0x0a, 0x12, 0x4f, 0x50, 0x5f, 0x52, 0x45, 0x47, 0x45, 0x58, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
-	0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x41, 0x4e,
-	0x44, 0x10, 0x09, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x50, 0x5f, 0x4f, 0x52, 0x10, 0x0a, 0x12, 0x0a,
-	0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x41, 0x44, 0x44, 0x10, 0x0b, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50,
-	0x5f, 0x53, 0x55, 0x42, 0x10, 0x0c, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x4d, 0x55, 0x4c,
-	0x10, 0x0d, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x50, 0x5f, 0x44, 0x49, 0x56, 0x10, 0x0e, 0x12, 0x0f,
-	0x0a, 0x0b, 0x4f, 0x50, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x53, 0x10, 0x0f, 0x12,
-	0x13, 0x0a, 0x0f, 0x4f, 0x50, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49,
-	0x4e, 0x53, 0x10, 0x10, 0x2a, 0x36, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18,
-	0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x4e, 0x53,
-	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x59,
-	0x50, 0x45, 0x5f, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x36, 0x34, 0x10, 0x01, 0x32, 0x6e, 0x0a, 0x0e,
-	0x46, 0x72, 0x6f, 0x73, 0x74, 0x44, 0x42, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5c,
-	0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x26, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
-	0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
-	0x61, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x27, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
-	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x85, 0x02, 0x0a,
-	0x1c, 0x63, 0x6f, 0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f,
-	0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0c, 0x53,
-	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x55, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73,
-	0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67,
-	0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73,
-	0x74, 0x64, 0x62, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0x3b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x76, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x53, 0x58, 0xaa, 0x02, 0x18, 0x46, 0x72, 0x6f,
-	0x73, 0x74, 0x64, 0x62, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x56, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c,
-	0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
-	0xe2, 0x02, 0x24, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x53, 0x74, 0x6f, 0x72, 0x61,
-	0x67, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1a, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64,
-	0x62, 0x3a, 0x3a, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c,
-	0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x66,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x85, 0x02, 0x0a, 0x1c, 0x63, 0x6f,
+	0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0c, 0x53, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x55, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x73, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x3b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0xa2, 0x02, 0x03, 0x46, 0x53, 0x58, 0xaa, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0xca, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x53, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xe2, 0x02, 0x24,
+	0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5c,
+	0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1a, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x3a, 0x3a,
+	0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2269,87 +2454,94 @@ func file_frostdb_storage_v1alpha1_storage_proto_rawDescGZIP() []byte {
 	return file_frostdb_storage_v1alpha1_storage_proto_rawDescData
 }
 
-var file_frostdb_storage_v1alpha1_storage_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_frostdb_storage_v1alpha1_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_frostdb_storage_v1alpha1_storage_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_frostdb_storage_v1alpha1_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_frostdb_storage_v1alpha1_storage_proto_goTypes = []any{
 	(Op)(0),                       // 0: frostdb.storage.v1alpha1.Op
 	(Type)(0),                     // 1: frostdb.storage.v1alpha1.Type
-	(AggregationFunction_Type)(0), // 2: frostdb.storage.v1alpha1.AggregationFunction.Type
-	(*QueryRequest)(nil),          // 3: frostdb.storage.v1alpha1.QueryRequest
-	(*QueryResponse)(nil),         // 4: frostdb.storage.v1alpha1.QueryResponse
-	(*PlanNode)(nil),              // 5: frostdb.storage.v1alpha1.PlanNode
-	(*PlanNodeSpec)(nil),          // 6: frostdb.storage.v1alpha1.PlanNodeSpec
-	(*TableScan)(nil),             // 7: frostdb.storage.v1alpha1.TableScan
-	(*SchemaScan)(nil),            // 8: frostdb.storage.v1alpha1.SchemaScan
-	(*ScanBase)(nil),              // 9: frostdb.storage.v1alpha1.ScanBase
-	(*Filter)(nil),                // 10: frostdb.storage.v1alpha1.Filter
-	(*Distinct)(nil),              // 11: frostdb.storage.v1alpha1.Distinct
-	(*Projection)(nil),            // 12: frostdb.storage.v1alpha1.Projection
-	(*Limit)(nil),                 // 13: frostdb.storage.v1alpha1.Limit
-	(*Aggregation)(nil),           // 14: frostdb.storage.v1alpha1.Aggregation
-	(*Expr)(nil),                  // 15: frostdb.storage.v1alpha1.Expr
-	(*ExprDef)(nil),               // 16: frostdb.storage.v1alpha1.ExprDef
-	(*BinaryExpr)(nil),            // 17: frostdb.storage.v1alpha1.BinaryExpr
-	(*IfExpr)(nil),                // 18: frostdb.storage.v1alpha1.IfExpr
-	(*ConvertExpr)(nil),           // 19: frostdb.storage.v1alpha1.ConvertExpr
-	(*Column)(nil),                // 20: frostdb.storage.v1alpha1.Column
-	(*Literal)(nil),               // 21: frostdb.storage.v1alpha1.Literal
-	(*LiteralContent)(nil),        // 22: frostdb.storage.v1alpha1.LiteralContent
-	(*Null)(nil),                  // 23: frostdb.storage.v1alpha1.Null
-	(*Alias)(nil),                 // 24: frostdb.storage.v1alpha1.Alias
-	(*DynamicColumn)(nil),         // 25: frostdb.storage.v1alpha1.DynamicColumn
-	(*AggregationFunction)(nil),   // 26: frostdb.storage.v1alpha1.AggregationFunction
-	(*DurationExpr)(nil),          // 27: frostdb.storage.v1alpha1.DurationExpr
+	(Join_Type)(0),                // 2: frostdb.storage.v1alpha1.Join.Type
+	(AggregationFunction_Type)(0), // 3: frostdb.storage.v1alpha1.AggregationFunction.Type
+	(*QueryRequest)(nil),          // 4: frostdb.storage.v1alpha1.QueryRequest
+	(*QueryResponse)(nil),         // 5: frostdb.storage.v1alpha1.QueryResponse
+	(*PlanNode)(nil),              // 6: frostdb.storage.v1alpha1.PlanNode
+	(*PlanNodeSpec)(nil),          // 7: frostdb.storage.v1alpha1.PlanNodeSpec
+	(*TableScan)(nil),             // 8: frostdb.storage.v1alpha1.TableScan
+	(*SchemaScan)(nil),            // 9: frostdb.storage.v1alpha1.SchemaScan
+	(*ScanBase)(nil),              // 10: frostdb.storage.v1alpha1.ScanBase
+	(*Filter)(nil),                // 11: frostdb.storage.v1alpha1.Filter
+	(*Distinct)(nil),              // 12: frostdb.storage.v1alpha1.Distinct
+	(*Projection)(nil),            // 13: frostdb.storage.v1alpha1.Projection
+	(*Limit)(nil),                 // 14: frostdb.storage.v1alpha1.Limit
+	(*Aggregation)(nil),           // 15: frostdb.storage.v1alpha1.Aggregation
+	(*Join)(nil),                  // 16: frostdb.storage.v1alpha1.Join
+	(*Expr)(nil),                  // 17: frostdb.storage.v1alpha1.Expr
+	(*ExprDef)(nil),               // 18: frostdb.storage.v1alpha1.ExprDef
+	(*BinaryExpr)(nil),            // 19: frostdb.storage.v1alpha1.BinaryExpr
+	(*IfExpr)(nil),                // 20: frostdb.storage.v1alpha1.IfExpr
+	(*ConvertExpr)(nil),           // 21: frostdb.storage.v1alpha1.ConvertExpr
+	(*Column)(nil),                // 22: frostdb.storage.v1alpha1.Column
+	(*Literal)(nil),               // 23: frostdb.storage.v1alpha1.Literal
+	(*LiteralContent)(nil),        // 24: frostdb.storage.v1alpha1.LiteralContent
+	(*Null)(nil),                  // 25: frostdb.storage.v1alpha1.Null
+	(*Alias)(nil),                 // 26: frostdb.storage.v1alpha1.Alias
+	(*DynamicColumn)(nil),         // 27: frostdb.storage.v1alpha1.DynamicColumn
+	(*AggregationFunction)(nil),   // 28: frostdb.storage.v1alpha1.AggregationFunction
+	(*DurationExpr)(nil),          // 29: frostdb.storage.v1alpha1.DurationExpr
 }
 var file_frostdb_storage_v1alpha1_storage_proto_depIdxs = []int32{
-	5,  // 0: frostdb.storage.v1alpha1.QueryRequest.plan_root:type_name -> frostdb.storage.v1alpha1.PlanNode
-	5,  // 1: frostdb.storage.v1alpha1.PlanNode.next:type_name -> frostdb.storage.v1alpha1.PlanNode
-	6,  // 2: frostdb.storage.v1alpha1.PlanNode.spec:type_name -> frostdb.storage.v1alpha1.PlanNodeSpec
-	7,  // 3: frostdb.storage.v1alpha1.PlanNodeSpec.table_scan:type_name -> frostdb.storage.v1alpha1.TableScan
-	8,  // 4: frostdb.storage.v1alpha1.PlanNodeSpec.schema_scan:type_name -> frostdb.storage.v1alpha1.SchemaScan
-	10, // 5: frostdb.storage.v1alpha1.PlanNodeSpec.filter:type_name -> frostdb.storage.v1alpha1.Filter
-	12, // 6: frostdb.storage.v1alpha1.PlanNodeSpec.projection:type_name -> frostdb.storage.v1alpha1.Projection
-	11, // 7: frostdb.storage.v1alpha1.PlanNodeSpec.distinct:type_name -> frostdb.storage.v1alpha1.Distinct
-	14, // 8: frostdb.storage.v1alpha1.PlanNodeSpec.aggregation:type_name -> frostdb.storage.v1alpha1.Aggregation
-	13, // 9: frostdb.storage.v1alpha1.PlanNodeSpec.limit:type_name -> frostdb.storage.v1alpha1.Limit
-	9,  // 10: frostdb.storage.v1alpha1.TableScan.base:type_name -> frostdb.storage.v1alpha1.ScanBase
-	9,  // 11: frostdb.storage.v1alpha1.SchemaScan.base:type_name -> frostdb.storage.v1alpha1.ScanBase
-	15, // 12: frostdb.storage.v1alpha1.Filter.expr:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 13: frostdb.storage.v1alpha1.Distinct.exprs:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 14: frostdb.storage.v1alpha1.Projection.exprs:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 15: frostdb.storage.v1alpha1.Limit.expr:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 16: frostdb.storage.v1alpha1.Aggregation.group_exprs:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 17: frostdb.storage.v1alpha1.Aggregation.agg_exprs:type_name -> frostdb.storage.v1alpha1.Expr
-	16, // 18: frostdb.storage.v1alpha1.Expr.def:type_name -> frostdb.storage.v1alpha1.ExprDef
-	17, // 19: frostdb.storage.v1alpha1.ExprDef.binary_expr:type_name -> frostdb.storage.v1alpha1.BinaryExpr
-	20, // 20: frostdb.storage.v1alpha1.ExprDef.column:type_name -> frostdb.storage.v1alpha1.Column
-	21, // 21: frostdb.storage.v1alpha1.ExprDef.literal:type_name -> frostdb.storage.v1alpha1.Literal
-	25, // 22: frostdb.storage.v1alpha1.ExprDef.dynamic_column:type_name -> frostdb.storage.v1alpha1.DynamicColumn
-	26, // 23: frostdb.storage.v1alpha1.ExprDef.aggregation_function:type_name -> frostdb.storage.v1alpha1.AggregationFunction
-	24, // 24: frostdb.storage.v1alpha1.ExprDef.alias:type_name -> frostdb.storage.v1alpha1.Alias
-	27, // 25: frostdb.storage.v1alpha1.ExprDef.duration:type_name -> frostdb.storage.v1alpha1.DurationExpr
-	19, // 26: frostdb.storage.v1alpha1.ExprDef.convert:type_name -> frostdb.storage.v1alpha1.ConvertExpr
-	18, // 27: frostdb.storage.v1alpha1.ExprDef.if:type_name -> frostdb.storage.v1alpha1.IfExpr
-	15, // 28: frostdb.storage.v1alpha1.BinaryExpr.left:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 29: frostdb.storage.v1alpha1.BinaryExpr.right:type_name -> frostdb.storage.v1alpha1.Expr
-	0,  // 30: frostdb.storage.v1alpha1.BinaryExpr.op:type_name -> frostdb.storage.v1alpha1.Op
-	15, // 31: frostdb.storage.v1alpha1.IfExpr.condition:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 32: frostdb.storage.v1alpha1.IfExpr.then:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 33: frostdb.storage.v1alpha1.IfExpr.else:type_name -> frostdb.storage.v1alpha1.Expr
-	15, // 34: frostdb.storage.v1alpha1.ConvertExpr.expr:type_name -> frostdb.storage.v1alpha1.Expr
-	1,  // 35: frostdb.storage.v1alpha1.ConvertExpr.type:type_name -> frostdb.storage.v1alpha1.Type
-	22, // 36: frostdb.storage.v1alpha1.Literal.content:type_name -> frostdb.storage.v1alpha1.LiteralContent
-	23, // 37: frostdb.storage.v1alpha1.LiteralContent.null_value:type_name -> frostdb.storage.v1alpha1.Null
-	15, // 38: frostdb.storage.v1alpha1.Alias.expr:type_name -> frostdb.storage.v1alpha1.Expr
-	2,  // 39: frostdb.storage.v1alpha1.AggregationFunction.type:type_name -> frostdb.storage.v1alpha1.AggregationFunction.Type
-	15, // 40: frostdb.storage.v1alpha1.AggregationFunction.expr:type_name -> frostdb.storage.v1alpha1.Expr
-	3,  // 41: frostdb.storage.v1alpha1.FrostDBService.Query:input_type -> frostdb.storage.v1alpha1.QueryRequest
-	4,  // 42: frostdb.storage.v1alpha1.FrostDBService.Query:output_type -> frostdb.storage.v1alpha1.QueryResponse
-	42, // [42:43] is the sub-list for method output_type
-	41, // [41:42] is the sub-list for method input_type
-	41, // [41:41] is the sub-list for extension type_name
-	41, // [41:41] is the sub-list for extension extendee
-	0,  // [0:41] is the sub-list for field type_name
+	6,  // 0: frostdb.storage.v1alpha1.QueryRequest.plan_root:type_name -> frostdb.storage.v1alpha1.PlanNode
+	6,  // 1: frostdb.storage.v1alpha1.PlanNode.next:type_name -> frostdb.storage.v1alpha1.PlanNode
+	7,  // 2: frostdb.storage.v1alpha1.PlanNode.spec:type_name -> frostdb.storage.v1alpha1.PlanNodeSpec
+	8,  // 3: frostdb.storage.v1alpha1.PlanNodeSpec.table_scan:type_name -> frostdb.storage.v1alpha1.TableScan
+	9,  // 4: frostdb.storage.v1alpha1.PlanNodeSpec.schema_scan:type_name -> frostdb.storage.v1alpha1.SchemaScan
+	11, // 5: frostdb.storage.v1alpha1.PlanNodeSpec.filter:type_name -> frostdb.storage.v1alpha1.Filter
+	13, // 6: frostdb.storage.v1alpha1.PlanNodeSpec.projection:type_name -> frostdb.storage.v1alpha1.Projection
+	12, // 7: frostdb.storage.v1alpha1.PlanNodeSpec.distinct:type_name -> frostdb.storage.v1alpha1.Distinct
+	15, // 8: frostdb.storage.v1alpha1.PlanNodeSpec.aggregation:type_name -> frostdb.storage.v1alpha1.Aggregation
+	14, // 9: frostdb.storage.v1alpha1.PlanNodeSpec.limit:type_name -> frostdb.storage.v1alpha1.Limit
+	16, // 10: frostdb.storage.v1alpha1.PlanNodeSpec.join:type_name -> frostdb.storage.v1alpha1.Join
+	10, // 11: frostdb.storage.v1alpha1.TableScan.base:type_name -> frostdb.storage.v1alpha1.ScanBase
+	10, // 12: frostdb.storage.v1alpha1.SchemaScan.base:type_name -> frostdb.storage.v1alpha1.ScanBase
+	17, // 13: frostdb.storage.v1alpha1.Filter.expr:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 14: frostdb.storage.v1alpha1.Distinct.exprs:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 15: frostdb.storage.v1alpha1.Projection.exprs:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 16: frostdb.storage.v1alpha1.Limit.expr:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 17: frostdb.storage.v1alpha1.Aggregation.group_exprs:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 18: frostdb.storage.v1alpha1.Aggregation.agg_exprs:type_name -> frostdb.storage.v1alpha1.Expr
+	2,  // 19: frostdb.storage.v1alpha1.Join.type:type_name -> frostdb.storage.v1alpha1.Join.Type
+	6,  // 20: frostdb.storage.v1alpha1.Join.right:type_name -> frostdb.storage.v1alpha1.PlanNode
+	17, // 21: frostdb.storage.v1alpha1.Join.left_keys:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 22: frostdb.storage.v1alpha1.Join.right_keys:type_name -> frostdb.storage.v1alpha1.Expr
+	18, // 23: frostdb.storage.v1alpha1.Expr.def:type_name -> frostdb.storage.v1alpha1.ExprDef
+	19, // 24: frostdb.storage.v1alpha1.ExprDef.binary_expr:type_name -> frostdb.storage.v1alpha1.BinaryExpr
+	22, // 25: frostdb.storage.v1alpha1.ExprDef.column:type_name -> frostdb.storage.v1alpha1.Column
+	23, // 26: frostdb.storage.v1alpha1.ExprDef.literal:type_name -> frostdb.storage.v1alpha1.Literal
+	27, // 27: frostdb.storage.v1alpha1.ExprDef.dynamic_column:type_name -> frostdb.storage.v1alpha1.DynamicColumn
+	28, // 28: frostdb.storage.v1alpha1.ExprDef.aggregation_function:type_name -> frostdb.storage.v1alpha1.AggregationFunction
+	26, // 29: frostdb.storage.v1alpha1.ExprDef.alias:type_name -> frostdb.storage.v1alpha1.Alias
+	29, // 30: frostdb.storage.v1alpha1.ExprDef.duration:type_name -> frostdb.storage.v1alpha1.DurationExpr
+	21, // 31: frostdb.storage.v1alpha1.ExprDef.convert:type_name -> frostdb.storage.v1alpha1.ConvertExpr
+	20, // 32: frostdb.storage.v1alpha1.ExprDef.if:type_name -> frostdb.storage.v1alpha1.IfExpr
+	17, // 33: frostdb.storage.v1alpha1.BinaryExpr.left:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 34: frostdb.storage.v1alpha1.BinaryExpr.right:type_name -> frostdb.storage.v1alpha1.Expr
+	0,  // 35: frostdb.storage.v1alpha1.BinaryExpr.op:type_name -> frostdb.storage.v1alpha1.Op
+	17, // 36: frostdb.storage.v1alpha1.IfExpr.condition:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 37: frostdb.storage.v1alpha1.IfExpr.then:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 38: frostdb.storage.v1alpha1.IfExpr.else:type_name -> frostdb.storage.v1alpha1.Expr
+	17, // 39: frostdb.storage.v1alpha1.ConvertExpr.expr:type_name -> frostdb.storage.v1alpha1.Expr
+	1,  // 40: frostdb.storage.v1alpha1.ConvertExpr.type:type_name -> frostdb.storage.v1alpha1.Type
+	24, // 41: frostdb.storage.v1alpha1.Literal.content:type_name -> frostdb.storage.v1alpha1.LiteralContent
+	25, // 42: frostdb.storage.v1alpha1.LiteralContent.null_value:type_name -> frostdb.storage.v1alpha1.Null
+	17, // 43: frostdb.storage.v1alpha1.Alias.expr:type_name -> frostdb.storage.v1alpha1.Expr
+	3,  // 44: frostdb.storage.v1alpha1.AggregationFunction.type:type_name -> frostdb.storage.v1alpha1.AggregationFunction.Type
+	17, // 45: frostdb.storage.v1alpha1.AggregationFunction.expr:type_name -> frostdb.storage.v1alpha1.Expr
+	4,  // 46: frostdb.storage.v1alpha1.FrostDBService.Query:input_type -> frostdb.storage.v1alpha1.QueryRequest
+	5,  // 47: frostdb.storage.v1alpha1.FrostDBService.Query:output_type -> frostdb.storage.v1alpha1.QueryResponse
+	47, // [47:48] is the sub-list for method output_type
+	46, // [46:47] is the sub-list for method input_type
+	46, // [46:46] is the sub-list for extension type_name
+	46, // [46:46] is the sub-list for extension extendee
+	0,  // [0:46] is the sub-list for field type_name
 }
 
 func init() { file_frostdb_storage_v1alpha1_storage_proto_init() }
@@ -2503,7 +2695,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[12].Exporter = func(v any, i int) any {
-			switch v := v.(*Expr); i {
+			switch v := v.(*Join); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2515,7 +2707,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13].Exporter = func(v any, i int) any {
-			switch v := v.(*ExprDef); i {
+			switch v := v.(*Expr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2527,7 +2719,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14].Exporter = func(v any, i int) any {
-			switch v := v.(*BinaryExpr); i {
+			switch v := v.(*ExprDef); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2539,7 +2731,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[15].Exporter = func(v any, i int) any {
-			switch v := v.(*IfExpr); i {
+			switch v := v.(*BinaryExpr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2551,7 +2743,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[16].Exporter = func(v any, i int) any {
-			switch v := v.(*ConvertExpr); i {
+			switch v := v.(*IfExpr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2563,7 +2755,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[17].Exporter = func(v any, i int) any {
-			switch v := v.(*Column); i {
+			switch v := v.(*ConvertExpr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2575,7 +2767,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[18].Exporter = func(v any, i int) any {
-			switch v := v.(*Literal); i {
+			switch v := v.(*Column); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2587,7 +2779,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19].Exporter = func(v any, i int) any {
-			switch v := v.(*LiteralContent); i {
+			switch v := v.(*Literal); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2599,7 +2791,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20].Exporter = func(v any, i int) any {
-			switch v := v.(*Null); i {
+			switch v := v.(*LiteralContent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2611,7 +2803,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[21].Exporter = func(v any, i int) any {
-			switch v := v.(*Alias); i {
+			switch v := v.(*Null); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2623,7 +2815,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[22].Exporter = func(v any, i int) any {
-			switch v := v.(*DynamicColumn); i {
+			switch v := v.(*Alias); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2635,7 +2827,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[23].Exporter = func(v any, i int) any {
-			switch v := v.(*AggregationFunction); i {
+			switch v := v.(*DynamicColumn); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2647,6 +2839,18 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 			}
 		}
 		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*AggregationFunction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_storage_v1alpha1_storage_proto_msgTypes[25].Exporter = func(v any, i int) any {
 			switch v := v.(*DurationExpr); i {
 			case 0:
 				return &v.state
@@ -2667,8 +2871,9 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 		(*PlanNodeSpec_Distinct)(nil),
 		(*PlanNodeSpec_Aggregation)(nil),
 		(*PlanNodeSpec_Limit)(nil),
+		(*PlanNodeSpec_Join)(nil),
 	}
-	file_frostdb_storage_v1alpha1_storage_proto_msgTypes[13].OneofWrappers = []any{
+	file_frostdb_storage_v1alpha1_storage_proto_msgTypes[14].OneofWrappers = []any{
 		(*ExprDef_BinaryExpr)(nil),
 		(*ExprDef_Column)(nil),
 		(*ExprDef_Literal)(nil),
@@ -2679,7 +2884,7 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 		(*ExprDef_Convert)(nil),
 		(*ExprDef_If)(nil),
 	}
-	file_frostdb_storage_v1alpha1_storage_proto_msgTypes[19].OneofWrappers = []any{
+	file_frostdb_storage_v1alpha1_storage_proto_msgTypes[20].OneofWrappers = []any{
 		(*LiteralContent_NullValue)(nil),
 		(*LiteralContent_BoolValue)(nil),
 		(*LiteralContent_Int32Value)(nil),
@@ -2696,8 +2901,8 @@ func file_frostdb_storage_v1alpha1_storage_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_frostdb_storage_v1alpha1_storage_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   25,
+			NumEnums:      4,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -41,6 +41,18 @@ type TableConfig struct {
 	BlockReaderLimit uint64 `protobuf:"varint,4,opt,name=block_reader_limit,json=blockReaderLimit,proto3" json:"block_reader_limit,omitempty"`
 	// DisableWal disables the write ahead log for this table.
 	DisableWal bool `protobuf:"varint,5,opt,name=disable_wal,json=disableWal,proto3" json:"disable_wal,omitempty"`
+	// RetentionMs is the duration in milliseconds that persisted blocks of this table are kept in storage for. Blocks
+	// older than this are removed from storage by the retention loop of the database. Zero disables retention.
+	RetentionMs uint64 `protobuf:"varint,6,opt,name=retention_ms,json=retentionMs,proto3" json:"retention_ms,omitempty"`
+	// BlockCompactionWindowMs is the duration in milliseconds of the time windows persisted blocks of this table are
+	// compacted in. Blocks whose ULID timestamps fall into the same window are merged into a single block by the block
+	// compaction loop of the database once the window has passed. Zero disables block compaction.
+	BlockCompactionWindowMs uint64 `protobuf:"varint,7,opt,name=block_compaction_window_ms,json=blockCompactionWindowMs,proto3" json:"block_compaction_window_ms,omitempty"`
+	// Downsampling configures the downsampling of persisted blocks when they are compacted. Unset disables downsampling.
+	Downsampling *Downsampling `protobuf:"bytes,8,opt,name=downsampling,proto3" json:"downsampling,omitempty"`
+	// SchemaVersion is the version of the schema of the table. It starts at zero and is incremented every time the
+	// schema of the table is changed with DB.AlterTable.
+	SchemaVersion uint64 `protobuf:"varint,9,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
 }
 
 func (x *TableConfig) Reset() {
@@ -117,6 +129,34 @@ func (x *TableConfig) GetDisableWal() bool {
 	return false
 }
 
+func (x *TableConfig) GetRetentionMs() uint64 {
+	if x != nil {
+		return x.RetentionMs
+	}
+	return 0
+}
+
+func (x *TableConfig) GetBlockCompactionWindowMs() uint64 {
+	if x != nil {
+		return x.BlockCompactionWindowMs
+	}
+	return 0
+}
+
+func (x *TableConfig) GetDownsampling() *Downsampling {
+	if x != nil {
+		return x.Downsampling
+	}
+	return nil
+}
+
+func (x *TableConfig) GetSchemaVersion() uint64 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
 type isTableConfig_Schema interface {
 	isTableConfig_Schema()
 }
@@ -135,6 +175,66 @@ func (*TableConfig_DeprecatedSchema) isTableConfig_Schema() {}
 
 func (*TableConfig_SchemaV2) isTableConfig_Schema() {}
 
+// Downsampling is the configuration of the downsampling of persisted blocks.
+type Downsampling struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Column is the name of the int64 time column, in milliseconds, or timestamp column whose values are truncated to the
+	// resolution.
+	Column string `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	// ResolutionMs is the resolution in milliseconds rows are aggregated to. Rows that are equal on all sorting columns
+	// once their time has been truncated are merged into one, summing up their other numeric columns.
+	ResolutionMs uint64 `protobuf:"varint,2,opt,name=resolution_ms,json=resolutionMs,proto3" json:"resolution_ms,omitempty"`
+}
+
+func (x *Downsampling) Reset() {
+	*x = Downsampling{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Downsampling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Downsampling) ProtoMessage() {}
+
+func (x *Downsampling) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_table_v1alpha1_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Downsampling.ProtoReflect.Descriptor instead.
+func (*Downsampling) Descriptor() ([]byte, []int) {
+	return file_frostdb_table_v1alpha1_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Downsampling) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *Downsampling) GetResolutionMs() uint64 {
+	if x != nil {
+		return x.ResolutionMs
+	}
+	return 0
+}
+
 var File_frostdb_table_v1alpha1_config_proto protoreflect.FileDescriptor
 
 var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
@@ -146,7 +246,7 @@ var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
 	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x24, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x73, 0x63, 0x68,
 	0x65, 0x6d, 0x61, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x32, 0x2f, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x02, 0x0a, 0x0b, 0x54, 0x61,
+	0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xed, 0x03, 0x0a, 0x0b, 0x54, 0x61,
 	0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x4e, 0x0a, 0x11, 0x64, 0x65, 0x70,
 	0x72, 0x65, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73,
@@ -163,24 +263,42 @@ var file_frostdb_table_v1alpha1_config_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x62, 0x6c, 0x6f,
 	0x63, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1f, 0x0a,
 	0x0b, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x77, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x57, 0x61, 0x6c, 0x42, 0x08,
-	0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x42, 0xf6, 0x01, 0x0a, 0x1a, 0x63, 0x6f, 0x6d,
-	0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76,
-	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x2f,
-	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x54, 0x58, 0xaa,
-	0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x2e,
-	0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74,
-	0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0xe2, 0x02, 0x22, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c,
-	0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
-	0x3a, 0x3a, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x57, 0x61, 0x6c, 0x12, 0x21,
+	0x0a, 0x0c, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x73, 0x12, 0x3b, 0x0a, 0x1a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x6d, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x4d, 0x73, 0x12, 0x48,
+	0x0a, 0x0c, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x6f,
+	0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x64, 0x6f, 0x77, 0x6e,
+	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x08, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x22, 0x4b, 0x0a, 0x0c, 0x44, 0x6f, 0x77,
+	0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d,
+	0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x42, 0xf6, 0x01, 0x0a, 0x1a, 0x63, 0x6f, 0x6d, 0x2e, 0x66,
+	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x2f, 0x66, 0x72,
+	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x54, 0x58, 0xaa, 0x02, 0x16,
+	0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x56, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x5c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xe2,
+	0x02, 0x22, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x5c,
+	0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x18, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x3a, 0x3a,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -195,20 +313,22 @@ func file_frostdb_table_v1alpha1_config_proto_rawDescGZIP() []byte {
 	return file_frostdb_table_v1alpha1_config_proto_rawDescData
 }
 
-var file_frostdb_table_v1alpha1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_frostdb_table_v1alpha1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_frostdb_table_v1alpha1_config_proto_goTypes = []any{
 	(*TableConfig)(nil),     // 0: frostdb.table.v1alpha1.TableConfig
-	(*v1alpha1.Schema)(nil), // 1: frostdb.schema.v1alpha1.Schema
-	(*v1alpha2.Schema)(nil), // 2: frostdb.schema.v1alpha2.Schema
+	(*Downsampling)(nil),    // 1: frostdb.table.v1alpha1.Downsampling
+	(*v1alpha1.Schema)(nil), // 2: frostdb.schema.v1alpha1.Schema
+	(*v1alpha2.Schema)(nil), // 3: frostdb.schema.v1alpha2.Schema
 }
 var file_frostdb_table_v1alpha1_config_proto_depIdxs = []int32{
-	1, // 0: frostdb.table.v1alpha1.TableConfig.deprecated_schema:type_name -> frostdb.schema.v1alpha1.Schema
-	2, // 1: frostdb.table.v1alpha1.TableConfig.schema_v2:type_name -> frostdb.schema.v1alpha2.Schema
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2, // 0: frostdb.table.v1alpha1.TableConfig.deprecated_schema:type_name -> frostdb.schema.v1alpha1.Schema
+	3, // 1: frostdb.table.v1alpha1.TableConfig.schema_v2:type_name -> frostdb.schema.v1alpha2.Schema
+	1, // 2: frostdb.table.v1alpha1.TableConfig.downsampling:type_name -> frostdb.table.v1alpha1.Downsampling
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_frostdb_table_v1alpha1_config_proto_init() }
@@ -229,6 +349,18 @@ func file_frostdb_table_v1alpha1_config_proto_init() {
 				return nil
 			}
 		}
+		file_frostdb_table_v1alpha1_config_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Downsampling); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_frostdb_table_v1alpha1_config_proto_msgTypes[0].OneofWrappers = []any{
 		(*TableConfig_DeprecatedSchema)(nil),
@@ -240,7 +372,7 @@ func file_frostdb_table_v1alpha1_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_frostdb_table_v1alpha1_config_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
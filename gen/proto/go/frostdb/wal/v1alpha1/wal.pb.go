@@ -7,6 +7,7 @@
 package walv1alpha1
 
 import (
+	v1alpha11 "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/storage/v1alpha1"
 	v1alpha1 "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/table/v1alpha1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -21,6 +22,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Compression enum of the body of an arrow record.
+type Entry_Write_Compression int32
+
+const (
+	// No compression.
+	Entry_Write_COMPRESSION_NONE_UNSPECIFIED Entry_Write_Compression = 0
+	// ZSTD compression.
+	Entry_Write_COMPRESSION_ZSTD Entry_Write_Compression = 1
+)
+
+// Enum value maps for Entry_Write_Compression.
+var (
+	Entry_Write_Compression_name = map[int32]string{
+		0: "COMPRESSION_NONE_UNSPECIFIED",
+		1: "COMPRESSION_ZSTD",
+	}
+	Entry_Write_Compression_value = map[string]int32{
+		"COMPRESSION_NONE_UNSPECIFIED": 0,
+		"COMPRESSION_ZSTD":             1,
+	}
+)
+
+func (x Entry_Write_Compression) Enum() *Entry_Write_Compression {
+	p := new(Entry_Write_Compression)
+	*p = x
+	return p
+}
+
+func (x Entry_Write_Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Entry_Write_Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_frostdb_wal_v1alpha1_wal_proto_enumTypes[0].Descriptor()
+}
+
+func (Entry_Write_Compression) Type() protoreflect.EnumType {
+	return &file_frostdb_wal_v1alpha1_wal_proto_enumTypes[0]
+}
+
+func (x Entry_Write_Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Entry_Write_Compression.Descriptor instead.
+func (Entry_Write_Compression) EnumDescriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 0, 0}
+}
+
 // Record describes a single entry into the WAL.
 type Record struct {
 	state         protoimpl.MessageState
@@ -30,6 +80,9 @@ type Record struct {
 	// Data of the record. This is intentionally nested so the only thing in
 	// the entry can be a protobuf `oneof` and have forward compatilibity.
 	Entry *Entry `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// Time at which the record was logged in unix milliseconds. Records logged
+	// by older versions don't have it.
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
 
 func (x *Record) Reset() {
@@ -71,6 +124,13 @@ func (x *Record) GetEntry() *Entry {
 	return nil
 }
 
+func (x *Record) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
 // The data of a WAL Record. This is intentionally separate to allow using the
 // `oneof` feature in a forward-compatible way.
 type Entry struct {
@@ -86,6 +146,11 @@ type Entry struct {
 	//	*Entry_NewTableBlock_
 	//	*Entry_TableBlockPersisted_
 	//	*Entry_Snapshot_
+	//	*Entry_Delete_
+	//	*Entry_DropTable_
+	//	*Entry_TruncateTable_
+	//	*Entry_RenameTable_
+	//	*Entry_Transaction_
 	EntryType isEntry_EntryType `protobuf_oneof:"entry_type"`
 }
 
@@ -156,6 +221,41 @@ func (x *Entry) GetSnapshot() *Entry_Snapshot {
 	return nil
 }
 
+func (x *Entry) GetDelete() *Entry_Delete {
+	if x, ok := x.GetEntryType().(*Entry_Delete_); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+func (x *Entry) GetDropTable() *Entry_DropTable {
+	if x, ok := x.GetEntryType().(*Entry_DropTable_); ok {
+		return x.DropTable
+	}
+	return nil
+}
+
+func (x *Entry) GetTruncateTable() *Entry_TruncateTable {
+	if x, ok := x.GetEntryType().(*Entry_TruncateTable_); ok {
+		return x.TruncateTable
+	}
+	return nil
+}
+
+func (x *Entry) GetRenameTable() *Entry_RenameTable {
+	if x, ok := x.GetEntryType().(*Entry_RenameTable_); ok {
+		return x.RenameTable
+	}
+	return nil
+}
+
+func (x *Entry) GetTransaction() *Entry_Transaction {
+	if x, ok := x.GetEntryType().(*Entry_Transaction_); ok {
+		return x.Transaction
+	}
+	return nil
+}
+
 type isEntry_EntryType interface {
 	isEntry_EntryType()
 }
@@ -180,6 +280,32 @@ type Entry_Snapshot_ struct {
 	Snapshot *Entry_Snapshot `protobuf:"bytes,4,opt,name=snapshot,proto3,oneof"`
 }
 
+type Entry_Delete_ struct {
+	// Delete is set if the entry describes a delete.
+	Delete *Entry_Delete `protobuf:"bytes,5,opt,name=delete,proto3,oneof"`
+}
+
+type Entry_DropTable_ struct {
+	// DropTable is set if the entry describes a table drop.
+	DropTable *Entry_DropTable `protobuf:"bytes,6,opt,name=drop_table,json=dropTable,proto3,oneof"`
+}
+
+type Entry_TruncateTable_ struct {
+	// TruncateTable is set if the entry describes a table truncation.
+	TruncateTable *Entry_TruncateTable `protobuf:"bytes,7,opt,name=truncate_table,json=truncateTable,proto3,oneof"`
+}
+
+type Entry_RenameTable_ struct {
+	// RenameTable is set if the entry describes a table rename.
+	RenameTable *Entry_RenameTable `protobuf:"bytes,8,opt,name=rename_table,json=renameTable,proto3,oneof"`
+}
+
+type Entry_Transaction_ struct {
+	// Transaction is set if the entry describes writes to multiple tables
+	// committed in a single transaction.
+	Transaction *Entry_Transaction `protobuf:"bytes,9,opt,name=transaction,proto3,oneof"`
+}
+
 func (*Entry_Write_) isEntry_EntryType() {}
 
 func (*Entry_NewTableBlock_) isEntry_EntryType() {}
@@ -188,6 +314,16 @@ func (*Entry_TableBlockPersisted_) isEntry_EntryType() {}
 
 func (*Entry_Snapshot_) isEntry_EntryType() {}
 
+func (*Entry_Delete_) isEntry_EntryType() {}
+
+func (*Entry_DropTable_) isEntry_EntryType() {}
+
+func (*Entry_TruncateTable_) isEntry_EntryType() {}
+
+func (*Entry_RenameTable_) isEntry_EntryType() {}
+
+func (*Entry_Transaction_) isEntry_EntryType() {}
+
 // The write-type entry.
 type Entry_Write struct {
 	state         protoimpl.MessageState
@@ -200,6 +336,8 @@ type Entry_Write struct {
 	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 	// Arrow indicates that data is stored in arrow record format.
 	Arrow bool `protobuf:"varint,3,opt,name=arrow,proto3" json:"arrow,omitempty"`
+	// Compression of the body of the arrow record stored in data.
+	Compression Entry_Write_Compression `protobuf:"varint,4,opt,name=compression,proto3,enum=frostdb.wal.v1alpha1.Entry_Write_Compression" json:"compression,omitempty"`
 }
 
 func (x *Entry_Write) Reset() {
@@ -255,6 +393,13 @@ func (x *Entry_Write) GetArrow() bool {
 	return false
 }
 
+func (x *Entry_Write) GetCompression() Entry_Write_Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Entry_Write_COMPRESSION_NONE_UNSPECIFIED
+}
+
 // The new-table-block entry.
 type Entry_NewTableBlock struct {
 	state         protoimpl.MessageState
@@ -441,78 +586,425 @@ func (x *Entry_Snapshot) GetTx() uint64 {
 	return 0
 }
 
+// The delete entry.
+type Entry_Delete struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Table name of the delete.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	// Filter matching the rows to delete.
+	Filter *v1alpha11.Expr `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Block ID of the active block at the time of the delete. Blocks created
+	// before this block only contain rows written before the delete.
+	BlockId []byte `protobuf:"bytes,3,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+}
+
+func (x *Entry_Delete) Reset() {
+	*x = Entry_Delete{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry_Delete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry_Delete) ProtoMessage() {}
+
+func (x *Entry_Delete) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry_Delete.ProtoReflect.Descriptor instead.
+func (*Entry_Delete) Descriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 4}
+}
+
+func (x *Entry_Delete) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *Entry_Delete) GetFilter() *v1alpha11.Expr {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *Entry_Delete) GetBlockId() []byte {
+	if x != nil {
+		return x.BlockId
+	}
+	return nil
+}
+
+// The drop-table entry.
+type Entry_DropTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the dropped table.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+}
+
+func (x *Entry_DropTable) Reset() {
+	*x = Entry_DropTable{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry_DropTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry_DropTable) ProtoMessage() {}
+
+func (x *Entry_DropTable) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry_DropTable.ProtoReflect.Descriptor instead.
+func (*Entry_DropTable) Descriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 5}
+}
+
+func (x *Entry_DropTable) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+// The truncate-table entry.
+type Entry_TruncateTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the truncated table.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	// Block ID of the empty block that replaced the active block of the
+	// table. Blocks created before this block only contain truncated rows.
+	BlockId []byte `protobuf:"bytes,2,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+}
+
+func (x *Entry_TruncateTable) Reset() {
+	*x = Entry_TruncateTable{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry_TruncateTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry_TruncateTable) ProtoMessage() {}
+
+func (x *Entry_TruncateTable) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry_TruncateTable.ProtoReflect.Descriptor instead.
+func (*Entry_TruncateTable) Descriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 6}
+}
+
+func (x *Entry_TruncateTable) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *Entry_TruncateTable) GetBlockId() []byte {
+	if x != nil {
+		return x.BlockId
+	}
+	return nil
+}
+
+// The rename-table entry.
+type Entry_RenameTable struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the table before the rename.
+	TableName string `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	// Name of the table after the rename.
+	NewTableName string `protobuf:"bytes,2,opt,name=new_table_name,json=newTableName,proto3" json:"new_table_name,omitempty"`
+}
+
+func (x *Entry_RenameTable) Reset() {
+	*x = Entry_RenameTable{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry_RenameTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry_RenameTable) ProtoMessage() {}
+
+func (x *Entry_RenameTable) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry_RenameTable.ProtoReflect.Descriptor instead.
+func (*Entry_RenameTable) Descriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 7}
+}
+
+func (x *Entry_RenameTable) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *Entry_RenameTable) GetNewTableName() string {
+	if x != nil {
+		return x.NewTableName
+	}
+	return ""
+}
+
+// The transaction entry.
+type Entry_Transaction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Writes of the transaction. They are committed together, either all or
+	// none of them are recovered.
+	Writes []*Entry_Write `protobuf:"bytes,1,rep,name=writes,proto3" json:"writes,omitempty"`
+}
+
+func (x *Entry_Transaction) Reset() {
+	*x = Entry_Transaction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entry_Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry_Transaction) ProtoMessage() {}
+
+func (x *Entry_Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_frostdb_wal_v1alpha1_wal_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry_Transaction.ProtoReflect.Descriptor instead.
+func (*Entry_Transaction) Descriptor() ([]byte, []int) {
+	return file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP(), []int{1, 8}
+}
+
+func (x *Entry_Transaction) GetWrites() []*Entry_Write {
+	if x != nil {
+		return x.Writes
+	}
+	return nil
+}
+
 var File_frostdb_wal_v1alpha1_wal_proto protoreflect.FileDescriptor
 
 var file_frostdb_wal_v1alpha1_wal_proto_rawDesc = []byte{
 	0x0a, 0x1e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x77, 0x61, 0x6c, 0x2f, 0x76, 0x31,
 	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x77, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x14, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x1a, 0x23, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f,
-	0x74, 0x61, 0x62, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x41, 0x0a, 0x06, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x31, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77,
-	0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x22, 0xbd,
-	0x05, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x39, 0x0a, 0x05, 0x77, 0x72, 0x69, 0x74,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
-	0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x48, 0x00, 0x52, 0x05, 0x77, 0x72,
-	0x69, 0x74, 0x65, 0x12, 0x53, 0x0a, 0x0f, 0x6e, 0x65, 0x77, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x66,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x1a, 0x26, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f,
+	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2f, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x23,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x2f, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x5f, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x31, 0x0a,
+	0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x66,
 	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
-	0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x77, 0x54, 0x61, 0x62,
-	0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x0d, 0x6e, 0x65, 0x77, 0x54, 0x61,
-	0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x65, 0x0a, 0x15, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4a, 0x04,
+	0x08, 0x02, 0x10, 0x03, 0x22, 0xdc, 0x0c, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x39,
+	0x0a, 0x05, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x48, 0x00, 0x52, 0x05, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x53, 0x0a, 0x0f, 0x6e, 0x65, 0x77,
+	0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e,
+	0x4e, 0x65, 0x77, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x00, 0x52,
+	0x0d, 0x6e, 0x65, 0x77, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x65,
+	0x0a, 0x15, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x70, 0x65,
+	0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x48, 0x00,
+	0x52, 0x13, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x65, 0x72, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x64, 0x12, 0x42, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
+	0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52,
+	0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x3c, 0x0a, 0x06, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x48, 0x00, 0x52,
+	0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x64, 0x72, 0x6f, 0x70, 0x5f,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x72,
+	0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x48, 0x00, 0x52, 0x09, 0x64, 0x72, 0x6f, 0x70, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12,
+	0x52, 0x0a, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64,
 	0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50,
-	0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x13, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x64, 0x12,
-	0x42, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x24, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e,
-	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x1a, 0x50, 0x0a, 0x05, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
-	0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
-	0x14, 0x0a, 0x05, 0x61, 0x72, 0x72, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
-	0x61, 0x72, 0x72, 0x6f, 0x77, 0x1a, 0x92, 0x01, 0x0a, 0x0d, 0x4e, 0x65, 0x77, 0x54, 0x61, 0x62,
-	0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62,
-	0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
-	0x64, 0x12, 0x3b, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x74, 0x61, 0x62, 0x6c,
-	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4a, 0x04,
-	0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x1a, 0x68, 0x0a, 0x13, 0x54, 0x61,
-	0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
-	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65,
-	0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x6e,
-	0x65, 0x78, 0x74, 0x5f, 0x74, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x65,
-	0x78, 0x74, 0x54, 0x78, 0x1a, 0x1a, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74, 0x78,
-	0x42, 0x0c, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x42, 0xe5,
-	0x01, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x77,
-	0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x08, 0x57, 0x61, 0x6c,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x4d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73,
-	0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x77, 0x61,
-	0x6c, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x77, 0x61, 0x6c, 0x76, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x57, 0x58, 0xaa, 0x02, 0x14, 0x46,
-	0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x57, 0x61, 0x6c, 0x2e, 0x56, 0x31, 0x61, 0x6c, 0x70,
-	0x68, 0x61, 0x31, 0xca, 0x02, 0x14, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x57, 0x61,
-	0x6c, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xe2, 0x02, 0x20, 0x46, 0x72, 0x6f,
-	0x73, 0x74, 0x64, 0x62, 0x5c, 0x57, 0x61, 0x6c, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
-	0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x16,
-	0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x3a, 0x3a, 0x57, 0x61, 0x6c, 0x3a, 0x3a, 0x56, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x74, 0x72, 0x79, 0x2e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x48, 0x00, 0x52, 0x0d, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x4c, 0x0a, 0x0c, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x12, 0x4b, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48,
+	0x00, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0xe8,
+	0x01, 0x0a, 0x05, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x72, 0x72, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x72, 0x72, 0x6f,
+	0x77, 0x12, 0x4f, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2d, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62,
+	0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0x45, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e,
+	0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49,
+	0x4f, 0x4e, 0x5f, 0x5a, 0x53, 0x54, 0x44, 0x10, 0x01, 0x1a, 0x92, 0x01, 0x0a, 0x0d, 0x4e, 0x65,
+	0x77, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x3b, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54,
+	0x61, 0x62, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x1a, 0x68,
+	0x0a, 0x13, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x65, 0x72, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x74, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x6e, 0x65, 0x78, 0x74, 0x54, 0x78, 0x1a, 0x1a, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x02, 0x74, 0x78, 0x1a, 0x7a, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a,
+	0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x52, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
+	0x1a, 0x2a, 0x0a, 0x09, 0x44, 0x72, 0x6f, 0x70, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x1a, 0x49, 0x0a, 0x0d,
+	0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x1a, 0x52, 0x0a, 0x0b, 0x52, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x65, 0x77, 0x5f, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6e,
+	0x65, 0x77, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x1a, 0x48, 0x0a, 0x0b, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x06, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x72, 0x6f,
+	0x73, 0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x06, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x42, 0xe5, 0x01, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x2e, 0x66, 0x72, 0x6f, 0x73,
+	0x74, 0x64, 0x62, 0x2e, 0x77, 0x61, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x42, 0x08, 0x57, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x4d, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x6f, 0x6c, 0x61, 0x72, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x6c, 0x73, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2f, 0x67, 0x65,
+	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x66, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x2f, 0x77, 0x61, 0x6c, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b,
+	0x77, 0x61, 0x6c, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x46, 0x57,
+	0x58, 0xaa, 0x02, 0x14, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x2e, 0x57, 0x61, 0x6c, 0x2e,
+	0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x14, 0x46, 0x72, 0x6f, 0x73, 0x74,
+	0x64, 0x62, 0x5c, 0x57, 0x61, 0x6c, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xe2,
+	0x02, 0x20, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x5c, 0x57, 0x61, 0x6c, 0x5c, 0x56, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0xea, 0x02, 0x16, 0x46, 0x72, 0x6f, 0x73, 0x74, 0x64, 0x62, 0x3a, 0x3a, 0x57, 0x61,
+	0x6c, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -527,28 +1019,44 @@ func file_frostdb_wal_v1alpha1_wal_proto_rawDescGZIP() []byte {
 	return file_frostdb_wal_v1alpha1_wal_proto_rawDescData
 }
 
-var file_frostdb_wal_v1alpha1_wal_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_frostdb_wal_v1alpha1_wal_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_frostdb_wal_v1alpha1_wal_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_frostdb_wal_v1alpha1_wal_proto_goTypes = []any{
-	(*Record)(nil),                    // 0: frostdb.wal.v1alpha1.Record
-	(*Entry)(nil),                     // 1: frostdb.wal.v1alpha1.Entry
-	(*Entry_Write)(nil),               // 2: frostdb.wal.v1alpha1.Entry.Write
-	(*Entry_NewTableBlock)(nil),       // 3: frostdb.wal.v1alpha1.Entry.NewTableBlock
-	(*Entry_TableBlockPersisted)(nil), // 4: frostdb.wal.v1alpha1.Entry.TableBlockPersisted
-	(*Entry_Snapshot)(nil),            // 5: frostdb.wal.v1alpha1.Entry.Snapshot
-	(*v1alpha1.TableConfig)(nil),      // 6: frostdb.table.v1alpha1.TableConfig
+	(Entry_Write_Compression)(0),      // 0: frostdb.wal.v1alpha1.Entry.Write.Compression
+	(*Record)(nil),                    // 1: frostdb.wal.v1alpha1.Record
+	(*Entry)(nil),                     // 2: frostdb.wal.v1alpha1.Entry
+	(*Entry_Write)(nil),               // 3: frostdb.wal.v1alpha1.Entry.Write
+	(*Entry_NewTableBlock)(nil),       // 4: frostdb.wal.v1alpha1.Entry.NewTableBlock
+	(*Entry_TableBlockPersisted)(nil), // 5: frostdb.wal.v1alpha1.Entry.TableBlockPersisted
+	(*Entry_Snapshot)(nil),            // 6: frostdb.wal.v1alpha1.Entry.Snapshot
+	(*Entry_Delete)(nil),              // 7: frostdb.wal.v1alpha1.Entry.Delete
+	(*Entry_DropTable)(nil),           // 8: frostdb.wal.v1alpha1.Entry.DropTable
+	(*Entry_TruncateTable)(nil),       // 9: frostdb.wal.v1alpha1.Entry.TruncateTable
+	(*Entry_RenameTable)(nil),         // 10: frostdb.wal.v1alpha1.Entry.RenameTable
+	(*Entry_Transaction)(nil),         // 11: frostdb.wal.v1alpha1.Entry.Transaction
+	(*v1alpha1.TableConfig)(nil),      // 12: frostdb.table.v1alpha1.TableConfig
+	(*v1alpha11.Expr)(nil),            // 13: frostdb.storage.v1alpha1.Expr
 }
 var file_frostdb_wal_v1alpha1_wal_proto_depIdxs = []int32{
-	1, // 0: frostdb.wal.v1alpha1.Record.entry:type_name -> frostdb.wal.v1alpha1.Entry
-	2, // 1: frostdb.wal.v1alpha1.Entry.write:type_name -> frostdb.wal.v1alpha1.Entry.Write
-	3, // 2: frostdb.wal.v1alpha1.Entry.new_table_block:type_name -> frostdb.wal.v1alpha1.Entry.NewTableBlock
-	4, // 3: frostdb.wal.v1alpha1.Entry.table_block_persisted:type_name -> frostdb.wal.v1alpha1.Entry.TableBlockPersisted
-	5, // 4: frostdb.wal.v1alpha1.Entry.snapshot:type_name -> frostdb.wal.v1alpha1.Entry.Snapshot
-	6, // 5: frostdb.wal.v1alpha1.Entry.NewTableBlock.config:type_name -> frostdb.table.v1alpha1.TableConfig
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	2,  // 0: frostdb.wal.v1alpha1.Record.entry:type_name -> frostdb.wal.v1alpha1.Entry
+	3,  // 1: frostdb.wal.v1alpha1.Entry.write:type_name -> frostdb.wal.v1alpha1.Entry.Write
+	4,  // 2: frostdb.wal.v1alpha1.Entry.new_table_block:type_name -> frostdb.wal.v1alpha1.Entry.NewTableBlock
+	5,  // 3: frostdb.wal.v1alpha1.Entry.table_block_persisted:type_name -> frostdb.wal.v1alpha1.Entry.TableBlockPersisted
+	6,  // 4: frostdb.wal.v1alpha1.Entry.snapshot:type_name -> frostdb.wal.v1alpha1.Entry.Snapshot
+	7,  // 5: frostdb.wal.v1alpha1.Entry.delete:type_name -> frostdb.wal.v1alpha1.Entry.Delete
+	8,  // 6: frostdb.wal.v1alpha1.Entry.drop_table:type_name -> frostdb.wal.v1alpha1.Entry.DropTable
+	9,  // 7: frostdb.wal.v1alpha1.Entry.truncate_table:type_name -> frostdb.wal.v1alpha1.Entry.TruncateTable
+	10, // 8: frostdb.wal.v1alpha1.Entry.rename_table:type_name -> frostdb.wal.v1alpha1.Entry.RenameTable
+	11, // 9: frostdb.wal.v1alpha1.Entry.transaction:type_name -> frostdb.wal.v1alpha1.Entry.Transaction
+	0,  // 10: frostdb.wal.v1alpha1.Entry.Write.compression:type_name -> frostdb.wal.v1alpha1.Entry.Write.Compression
+	12, // 11: frostdb.wal.v1alpha1.Entry.NewTableBlock.config:type_name -> frostdb.table.v1alpha1.TableConfig
+	13, // 12: frostdb.wal.v1alpha1.Entry.Delete.filter:type_name -> frostdb.storage.v1alpha1.Expr
+	3,  // 13: frostdb.wal.v1alpha1.Entry.Transaction.writes:type_name -> frostdb.wal.v1alpha1.Entry.Write
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_frostdb_wal_v1alpha1_wal_proto_init() }
@@ -629,25 +1137,91 @@ func file_frostdb_wal_v1alpha1_wal_proto_init() {
 				return nil
 			}
 		}
+		file_frostdb_wal_v1alpha1_wal_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*Entry_Delete); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_wal_v1alpha1_wal_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Entry_DropTable); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_wal_v1alpha1_wal_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Entry_TruncateTable); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_wal_v1alpha1_wal_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Entry_RenameTable); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frostdb_wal_v1alpha1_wal_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Entry_Transaction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_frostdb_wal_v1alpha1_wal_proto_msgTypes[1].OneofWrappers = []any{
 		(*Entry_Write_)(nil),
 		(*Entry_NewTableBlock_)(nil),
 		(*Entry_TableBlockPersisted_)(nil),
 		(*Entry_Snapshot_)(nil),
+		(*Entry_Delete_)(nil),
+		(*Entry_DropTable_)(nil),
+		(*Entry_TruncateTable_)(nil),
+		(*Entry_RenameTable_)(nil),
+		(*Entry_Transaction_)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_frostdb_wal_v1alpha1_wal_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_frostdb_wal_v1alpha1_wal_proto_goTypes,
 		DependencyIndexes: file_frostdb_wal_v1alpha1_wal_proto_depIdxs,
+		EnumInfos:         file_frostdb_wal_v1alpha1_wal_proto_enumTypes,
 		MessageInfos:      file_frostdb_wal_v1alpha1_wal_proto_msgTypes,
 	}.Build()
 	File_frostdb_wal_v1alpha1_wal_proto = out.File
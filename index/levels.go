@@ -57,6 +57,10 @@ func NewFileCompaction(dir string, maxSize int64, compact Compaction, logger log
 	return f, nil
 }
 
+func newFileCompactionLevel(options LevelOptions) (Level, error) {
+	return NewFileCompaction(options.Dir, options.MaxSize, options.Compact, options.Logger)
+}
+
 func (f *FileCompaction) MaxSize() int64 { return f.maxSize }
 
 // Snapshot takes a snapshot of the current level. It ignores the parts and just hard links the files into the snapshot directory.
@@ -170,6 +174,11 @@ func (f *FileCompaction) Compact(compact []parts.Part, options ...parts.Option)
 		return nil, 0, 0, err
 	}
 
+	if buf.NumRows() == 0 {
+		// All the rows of the compacted parts were deleted.
+		return nil, preCompactionSize, 0, nil
+	}
+
 	f.parts.Add(1)
 	return []parts.Part{parts.NewParquetPart(compact[0].TX(), buf, append(options, parts.WithRelease(f.parts.Done))...)}, preCompactionSize, accountant.n, nil
 }
@@ -199,8 +208,8 @@ func (f *FileCompaction) Reset() {
 	}
 }
 
-// recovery the level from the given directory.
-func (f *FileCompaction) recover(options ...parts.Option) ([]parts.Part, error) {
+// Recover recovers the level from its directory.
+func (f *FileCompaction) Recover(options ...parts.Option) ([]parts.Part, error) {
 	defer func() {
 		_, err := f.createIndexFile(len(f.indexFiles))
 		if err != nil {
@@ -253,6 +262,11 @@ func (f *FileCompaction) recover(options ...parts.Option) ([]parts.Part, error)
 					return err
 				}
 
+				if buf.NumRows() == 0 {
+					// All the rows of the compacted parts were deleted.
+					continue
+				}
+
 				var tx int
 				txstr, ok := buf.ParquetFile().Lookup(ParquetCompactionTXKey)
 				if !ok {
@@ -302,7 +316,18 @@ type inMemoryLevel struct {
 	maxSize int64
 }
 
+func newInMemoryLevel(options LevelOptions) (Level, error) {
+	return &inMemoryLevel{
+		maxSize: options.MaxSize,
+		compact: options.Compact,
+	}, nil
+}
+
 func (l *inMemoryLevel) MaxSize() int64 { return l.maxSize }
+
+// Recover returns no parts since in-memory levels don't outlive the LSM.
+func (l *inMemoryLevel) Recover(...parts.Option) ([]parts.Part, error) { return nil, nil }
+
 func (l *inMemoryLevel) Snapshot(snapshot []parts.Part, writer func(parts.Part) error, _ string) error {
 	for _, part := range snapshot {
 		if err := writer(part); err != nil {
@@ -328,6 +353,11 @@ func (l *inMemoryLevel) Compact(toCompact []parts.Part, options ...parts.Option)
 		return nil, 0, 0, err
 	}
 
+	if buf.NumRows() == 0 {
+		// All the rows of the compacted parts were deleted.
+		return nil, preCompactionSize, 0, nil
+	}
+
 	postCompactionSize := int64(b.Len())
 	return []parts.Part{parts.NewParquetPart(toCompact[0].TX(), buf, options...)}, preCompactionSize, postCompactionSize, nil
 }
@@ -1,10 +1,12 @@
 package index
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -34,6 +36,33 @@ const (
 	CompactionTypeParquetMemory
 )
 
+// CompactionStrategy determines which parts are merged when a level of the LSM
+// is compacted into the next level.
+type CompactionStrategy int
+
+const (
+	// CompactionStrategyFull merges all the parts of the level into a new part
+	// of the next level. The parts of the next level are not rewritten. This
+	// is the default.
+	CompactionStrategyFull CompactionStrategy = iota
+
+	// CompactionStrategyLeveled merges all the parts of the level together with
+	// the parts of the next level that overlap with them, so that the parts of
+	// the next level don't overlap. Reads of the next level scan fewer
+	// overlapping parts at the cost of rewriting parts of the next level.
+	CompactionStrategyLeveled
+
+	// CompactionStrategySizeTiered merges all the parts of the level into a new
+	// part of the next level, and then merges the parts of the next level of
+	// similar size whenever there are at least sizeTieredMinParts of them. The
+	// number of parts of the next level grows logarithmically with its size.
+	CompactionStrategySizeTiered
+)
+
+// sizeTieredMinParts is the number of parts of similar size that are merged
+// together by CompactionStrategySizeTiered.
+const sizeTieredMinParts = 4
+
 // LSM is a log-structured merge-tree like index. It is implemented as a single linked list of parts.
 //
 // Arrow records are always added to the L0 list. When a list reaches it's configured max size it is compacted
@@ -50,9 +79,14 @@ type LSM struct {
 	dir           string
 	maxTXRecoverd []uint64
 	levels        []Level
+	strategies    []CompactionStrategy
 	partList      *Node
 	sizes         []atomic.Int64
 
+	// compactedTx is the newest txn of the parts that were merged by
+	// compactions.
+	compactedTx atomic.Uint64
+
 	// Options
 	logger    log.Logger
 	metrics   *LSMMetrics
@@ -64,6 +98,12 @@ type LSMMetrics struct {
 	Compactions        *prometheus.CounterVec
 	LevelSize          *prometheus.GaugeVec
 	CompactionDuration prometheus.Observer
+	// CompactionBytesWritten is the size of the parts written into each level
+	// by compactions, and CompactionBytesRewritten the size of the parts of
+	// each level that compactions rewrote into the same level. The write
+	// amplification of a level is written / (written - rewritten).
+	CompactionBytesWritten   *prometheus.CounterVec
+	CompactionBytesRewritten *prometheus.CounterVec
 }
 
 // LevelConfig is the configuration for a level in the LSM.
@@ -73,15 +113,47 @@ type LevelConfig struct {
 	MaxSize int64
 	Type    CompactionType
 	Compact Compaction
+	// NewLevel creates a custom Level for the level. If set, it takes
+	// precedence over Type.
+	NewLevel NewLevelFunc
+	// Strategy is the strategy used to compact the level into the next level.
+	// Strategies other than CompactionStrategyFull release individual parts of
+	// the next level, so they can't be used with CompactionTypeParquetDisk,
+	// which only frees its storage once all the parts of a level are merged.
+	// Custom levels have to free the storage of parts as they are released.
+	Strategy CompactionStrategy
 }
 
+// Level compacts the parts of a level of the LSM into parts of the next level,
+// which it then holds.
 type Level interface {
 	Compact(parts []parts.Part, options ...parts.Option) ([]parts.Part, int64, int64, error)
+	// Snapshot writes the given parts of the level into a snapshot with the
+	// writer, or stores them in dir. Index files (see IndexFileExtension)
+	// stored in dir are restored into the directory of the level before it is
+	// recovered when the snapshot is loaded.
 	Snapshot(parts []parts.Part, writer func(parts.Part) error, dir string) error
+	// Recover returns the parts the level held when the LSM was last closed.
+	// It is called once, when the LSM is created, with the options to create
+	// the parts with.
+	Recover(options ...parts.Option) ([]parts.Part, error)
 	MaxSize() int64
 	Reset()
 }
 
+// LevelOptions are the options a Level is created with.
+type LevelOptions struct {
+	// Dir is the directory in which the level can store its data. It is
+	// specific to the level.
+	Dir     string
+	MaxSize int64
+	Compact Compaction
+	Logger  log.Logger
+}
+
+// NewLevelFunc creates a Level with the given options.
+type NewLevelFunc func(options LevelOptions) (Level, error)
+
 type LSMOption func(*LSM)
 
 func LSMWithLogger(logger log.Logger) LSMOption {
@@ -113,6 +185,16 @@ func NewLSMMetrics(reg prometheus.Registerer) *LSMMetrics {
 			Help:                        "Total compaction duration",
 			NativeHistogramBucketFactor: 1.1,
 		}),
+
+		CompactionBytesWritten: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "frostdb_lsm_compaction_written_bytes_total",
+			Help: "The total size in bytes of the parts written into the level by compactions.",
+		}, []string{"level"}),
+
+		CompactionBytesRewritten: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "frostdb_lsm_compaction_rewritten_bytes_total",
+			Help: "The total size in bytes of the parts of the level rewritten into the level by compactions.",
+		}, []string{"level"}),
 	}
 }
 
@@ -123,11 +205,19 @@ func NewLSM(dir string, schema *dynparquet.Schema, levels []*LevelConfig, waterm
 	if err := validateLevels(levels); err != nil {
 		return nil, err
 	}
+	strategies := make([]CompactionStrategy, len(levels))
+	for i, lvl := range levels {
+		if lvl.Strategy == CompactionStrategyLeveled && schema == nil {
+			return nil, fmt.Errorf("level %d uses leveled compaction, which requires a schema", lvl.Level)
+		}
+		strategies[i] = lvl.Strategy
+	}
 
 	lsm := &LSM{
 		schema:        schema,
 		dir:           dir,
 		maxTXRecoverd: make([]uint64, len(levels)),
+		strategies:    strategies,
 		partList:      NewList(L0),
 		sizes:         make([]atomic.Int64, len(levels)),
 		compacting:    sync.Mutex{},
@@ -210,28 +300,36 @@ func configureLSMLevels(dir string, levels []*LevelConfig, logger log.Logger) ([
 	// This allows us to throw away parts that were compacted into a higher level but for some reason weren't successfully removed.
 	for i := len(levels) - 1; i >= 0; i-- {
 		lvl := levels[i]
-		switch lvl.Type {
-		case CompactionTypeParquetMemory:
-			settings[i] = &inMemoryLevel{
-				maxSize: lvl.MaxSize,
-				compact: lvl.Compact,
-			}
-		case CompactionTypeParquetDisk:
-			fileCompaction, err := NewFileCompaction(filepath.Join(dir, fmt.Sprintf("L%v", i+1)), lvl.MaxSize, lvl.Compact, logger) // TODO: it would be nice to not need to inject the compact function here.
-			if err != nil {
-				return nil, nil, err
-			}
-			parts, err := fileCompaction.recover(parts.WithCompactionLevel(i + 1))
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to recover level %v parts: %w", i+1, err)
-			}
-			recovered = append(recovered, parts...)
-			settings[i] = fileCompaction
-		default:
-			if i != len(levels)-1 { // Compaction type should not be set for last level
-				panic(fmt.Sprintf("unknown compaction type: %v", lvl.Type))
+		newLevel := lvl.NewLevel
+		if newLevel == nil {
+			switch lvl.Type {
+			case CompactionTypeParquetMemory:
+				newLevel = newInMemoryLevel
+			case CompactionTypeParquetDisk:
+				newLevel = newFileCompactionLevel
+			default:
+				if i != len(levels)-1 { // Compaction type should not be set for last level
+					panic(fmt.Sprintf("unknown compaction type: %v", lvl.Type))
+				}
+				continue
 			}
 		}
+
+		l, err := newLevel(LevelOptions{
+			Dir:     filepath.Join(dir, fmt.Sprintf("L%v", i+1)),
+			MaxSize: lvl.MaxSize,
+			Compact: lvl.Compact, // TODO: it would be nice to not need to inject the compact function here.
+			Logger:  logger,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create level %v: %w", i+1, err)
+		}
+		parts, err := l.Recover(parts.WithCompactionLevel(i + 1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to recover level %v parts: %w", i+1, err)
+		}
+		recovered = append(recovered, parts...)
+		settings[i] = l
 	}
 
 	return settings, recovered, nil
@@ -246,6 +344,26 @@ func (l *LSM) Size() int64 {
 	return size
 }
 
+// CompactedTx returns the newest txn of the parts that were merged by
+// compactions. Compacted parts take the txn of the newest part they were
+// merged from, so reads at earlier txns don't see all the rows that were
+// visible to them before the compaction.
+func (l *LSM) CompactedTx() uint64 {
+	return l.compactedTx.Load()
+}
+
+// advanceCompactedTx raises the compacted txn to the newest txn of the given
+// merged parts.
+func (l *LSM) advanceCompactedTx(merged []parts.Part) {
+	for _, part := range merged {
+		for tx := l.compactedTx.Load(); part.TX() > tx; tx = l.compactedTx.Load() {
+			if l.compactedTx.CompareAndSwap(tx, part.TX()) {
+				break
+			}
+		}
+	}
+}
+
 // LevelSize returns the size of a specific level in bytes.
 func (l *LSM) LevelSize(t SentinelType) int64 {
 	return l.sizes[t].Load()
@@ -309,12 +427,21 @@ func validateLevels(levels []*LevelConfig) error {
 		if int(l.Level) != i {
 			return fmt.Errorf("level %d is not in order", l.Level)
 		}
+		if l.Strategy < CompactionStrategyFull || l.Strategy > CompactionStrategySizeTiered {
+			return fmt.Errorf("level %d has an unknown compaction strategy %d", l.Level, l.Strategy)
+		}
+		if l.Strategy != CompactionStrategyFull && l.NewLevel == nil && l.Type == CompactionTypeParquetDisk {
+			return fmt.Errorf("level %d is compacted to disk, which only supports the full compaction strategy", l.Level)
+		}
 
 		switch i {
 		case len(levels) - 1:
 			if l.Compact != nil {
 				return fmt.Errorf("level %d is the last level and should not have a compact function", l.Level)
 			}
+			if l.Strategy != CompactionStrategyFull {
+				return fmt.Errorf("level %d is the last level and should not have a compaction strategy", l.Level)
+			}
 		default:
 			if l.Compact == nil {
 				return fmt.Errorf("level %d is not the last level and should have a compact function", l.Level)
@@ -371,6 +498,9 @@ func (l *LSM) InsertPart(part parts.Part) {
 	if tx := part.TX(); tx > l.maxTXRecoverd[level] {
 		l.maxTXRecoverd[level] = tx
 	}
+	if level != L0 {
+		l.advanceCompactedTx([]parts.Part{part})
+	}
 
 	// Insert the part into the correct level, but do not do this if parts with newer TXs have already been inserted.
 	l.findLevel(level).Insert(part)
@@ -399,6 +529,14 @@ func (l *LSM) Iterate(iter func(node *Node) bool) {
 }
 
 func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter logicalplan.Expr, tx uint64, callback func(context.Context, any) error) error {
+	return l.ScanWithTx(ctx, filter, tx, func(ctx context.Context, _ uint64, v any) error {
+		return callback(ctx, v)
+	})
+}
+
+// ScanWithTx is like Scan, but additionally passes the transaction of the part
+// each value was read from to the callback.
+func (l *LSM) ScanWithTx(ctx context.Context, filter logicalplan.Expr, tx uint64, callback func(ctx context.Context, partTx uint64, v any) error) error {
 	l.RLock()
 	defer l.RUnlock()
 
@@ -419,7 +557,7 @@ func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter l
 
 		if r := node.part.Record(); r != nil {
 			r.Retain()
-			if err := callback(ctx, r); err != nil {
+			if err := callback(ctx, node.part.TX(), r); err != nil {
 				iterError = err
 				return false
 			}
@@ -442,7 +580,7 @@ func (l *LSM) Scan(ctx context.Context, _ string, _ *dynparquet.Schema, filter l
 
 			if mayContainUsefulData {
 				node.part.Retain() // Create another reference to this part
-				if err := callback(ctx, &releaseableRowGroup{DynamicRowGroup: rg, release: node.part.Release}); err != nil {
+				if err := callback(ctx, node.part.TX(), &releaseableRowGroup{DynamicRowGroup: rg, release: node.part.Release}); err != nil {
 					iterError = err
 					return false
 				}
@@ -589,39 +727,75 @@ func (l *LSM) merge(level SentinelType) error {
 		return nil
 	}
 
-	var size int64
-	var compactedSize int64
-	var compacted []parts.Part
-	var err error
 	mergeList := make([]parts.Part, 0, len(nodeList))
 	for _, node := range nodeList {
 		mergeList = append(mergeList, node.part)
 	}
+
+	// Leveled compactions rewrite the parts of the next level that overlap
+	// with the merged parts. The parts of the next level that are kept are
+	// linked to new nodes, since the old nodes point to the rewritten ones.
+	var rewrite []parts.Part
+	if l.strategies[level] == CompactionStrategyLeveled {
+		nextNodes, end := levelNodes(next)
+		rewriteNodes, err := l.overlappingNodes(mergeList, nextNodes)
+		if err != nil {
+			return fmt.Errorf("find overlapping parts: %w", err)
+		}
+		if len(rewriteNodes) > 0 {
+			rewritten := make(map[*Node]struct{}, len(rewriteNodes))
+			for _, node := range rewriteNodes {
+				rewritten[node] = struct{}{}
+				rewrite = append(rewrite, node.part)
+			}
+			next = end
+			for i := len(nextNodes) - 1; i >= 0; i-- {
+				if _, ok := rewritten[nextNodes[i]]; ok {
+					continue
+				}
+				node := &Node{part: nextNodes[i].part}
+				node.next.Store(next)
+				next = node
+			}
+		}
+	}
+
+	compactList := mergeList
+	var rewrittenSize int64
+	if len(rewrite) > 0 {
+		for _, p := range rewrite {
+			rewrittenSize += p.Size()
+		}
+		// The compacted parts take the txn of the first part.
+		compactList = append(slices.Clone(mergeList), rewrite...)
+		slices.SortStableFunc(compactList, compareTXDesc)
+	}
+
 	s := &Node{
 		sentinel: level + 1,
 	}
-	compacted, size, compactedSize, err = l.levels[level].Compact(mergeList, parts.WithCompactionLevel(int(level)+1))
+	compacted, size, compactedSize, err := l.levels[level].Compact(compactList, parts.WithCompactionLevel(int(level)+1))
 	if err != nil {
 		return err
 	}
+	l.advanceCompactedTx(compactList)
 
-	// Create new list for the compacted parts.
-	compactedList := &Node{
-		part: compacted[0],
-	}
-	node := compactedList
-	for _, p := range compacted[1:] {
+	// Create new list for the compacted parts. There are no compacted parts
+	// if all the rows of the merged parts were deleted.
+	node := s
+	for _, p := range compacted {
 		node.next.Store(&Node{
 			part: p,
 		})
 		node = node.next.Load()
 	}
-	s.next.Store(compactedList)
 	if next != nil {
 		node.next.Store(next)
 	}
-	l.sizes[level+1].Add(int64(compactedSize))
+	l.sizes[level+1].Add(compactedSize - rewrittenSize)
 	l.metrics.LevelSize.WithLabelValues(SentinelType(level + 1).String()).Set(float64(l.sizes[level+1].Load()))
+	l.metrics.CompactionBytesWritten.WithLabelValues(SentinelType(level + 1).String()).Add(float64(compactedSize))
+	l.metrics.CompactionBytesRewritten.WithLabelValues(SentinelType(level + 1).String()).Add(float64(rewrittenSize))
 
 	// Replace the compacted list with the new list
 	// find the node that points to the first node in our compacted list.
@@ -630,12 +804,12 @@ func (l *LSM) merge(level SentinelType) error {
 		// This can happen at most once in the scenario where a new part is added to the L0 list while we are trying to replace it.
 		node = l.findNode(nodeList[0])
 	}
-	l.sizes[level].Add(-int64(size))
+	l.sizes[level].Add(-(size - rewrittenSize))
 	l.metrics.LevelSize.WithLabelValues(level.String()).Set(float64(l.sizes[level].Load()))
 
 	// release the old parts
 	l.Lock()
-	for _, part := range mergeList {
+	for _, part := range compactList {
 		part.Release()
 	}
 	l.Unlock()
@@ -645,9 +819,209 @@ func (l *LSM) merge(level SentinelType) error {
 		l.levels[level-1].Reset()
 	}
 
+	if l.strategies[level] == CompactionStrategySizeTiered {
+		return l.mergeTiers(level + 1)
+	}
+	return nil
+}
+
+// mergeTiers merges the parts of similar size of the given level into a single
+// part of the level, for as long as there are at least sizeTieredMinParts of
+// them.
+func (l *LSM) mergeTiers(level SentinelType) error {
+	for {
+		sentinel := l.findLevel(level)
+		nodes, end := levelNodes(sentinel.next.Load())
+		tier := sizeTier(nodes)
+		if tier == nil {
+			return nil
+		}
+		l.metrics.Compactions.WithLabelValues(level.String()).Inc()
+
+		inTier := make(map[*Node]struct{}, len(tier))
+		tierParts := make([]parts.Part, 0, len(tier))
+		var rewrittenSize int64
+		for _, node := range tier {
+			inTier[node] = struct{}{}
+			tierParts = append(tierParts, node.part)
+			rewrittenSize += node.part.Size()
+		}
+		// The compacted parts take the txn of the first part.
+		slices.SortStableFunc(tierParts, compareTXDesc)
+		compacted, _, compactedSize, err := l.levels[level-1].Compact(tierParts, parts.WithCompactionLevel(int(level)))
+		if err != nil {
+			return err
+		}
+		l.advanceCompactedTx(tierParts)
+
+		// Link the compacted parts in place of the first part of the tier,
+		// building the new list from its end.
+		first := slices.IndexFunc(nodes, func(node *Node) bool {
+			_, ok := inTier[node]
+			return ok
+		})
+		next := end
+		for i := len(nodes) - 1; i >= 0; i-- {
+			if i == first {
+				for j := len(compacted) - 1; j >= 0; j-- {
+					node := &Node{part: compacted[j]}
+					node.next.Store(next)
+					next = node
+				}
+				continue
+			}
+			if _, ok := inTier[nodes[i]]; ok {
+				continue
+			}
+			node := &Node{part: nodes[i].part}
+			node.next.Store(next)
+			next = node
+		}
+		if !sentinel.next.CompareAndSwap(nodes[0], next) {
+			return fmt.Errorf("level %v was modified during compaction", level)
+		}
+
+		size := l.sizes[level].Add(compactedSize - rewrittenSize)
+		l.metrics.LevelSize.WithLabelValues(level.String()).Set(float64(size))
+		l.metrics.CompactionBytesWritten.WithLabelValues(level.String()).Add(float64(compactedSize))
+		l.metrics.CompactionBytesRewritten.WithLabelValues(level.String()).Add(float64(rewrittenSize))
+
+		l.Lock()
+		for _, part := range tierParts {
+			part.Release()
+		}
+		l.Unlock()
+	}
+}
+
+// sizeTier returns the nodes of the smallest parts of similar size, that is
+// within a factor of two of the smallest of them, if there are at least
+// sizeTieredMinParts of them.
+func sizeTier(nodes []*Node) []*Node {
+	sorted := slices.Clone(nodes)
+	slices.SortStableFunc(sorted, func(a, b *Node) int {
+		return cmp.Compare(a.part.Size(), b.part.Size())
+	})
+	for i := range sorted {
+		j := i + 1
+		for j < len(sorted) && sorted[j].part.Size() <= 2*sorted[i].part.Size() {
+			j++
+		}
+		if j-i >= sizeTieredMinParts {
+			return sorted[i:j]
+		}
+	}
 	return nil
 }
 
+// overlappingNodes returns the nodes of a level that have to be rewritten
+// together with the given parts merged into the level so that the parts of the
+// level don't overlap: the parts that overlap with other parts of the level,
+// e.g. because the level was compacted with another strategy, and the parts
+// that overlap with the range of the parts merged into the level.
+func (l *LSM) overlappingNodes(merge []parts.Part, nodes []*Node) ([]*Node, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	type rowRange struct {
+		least, most *dynparquet.DynamicRow
+	}
+	partRange := func(p parts.Part) (rowRange, error) {
+		least, err := p.Least()
+		if err != nil {
+			return rowRange{}, err
+		}
+		most, err := p.Most()
+		if err != nil {
+			return rowRange{}, err
+		}
+		return rowRange{least: least, most: most}, nil
+	}
+
+	ranges := make([]rowRange, len(nodes))
+	byPart := make(map[parts.Part]int, len(nodes))
+	levelParts := make([]parts.Part, 0, len(nodes))
+	for i, node := range nodes {
+		r, err := partRange(node.part)
+		if err != nil {
+			return nil, err
+		}
+		ranges[i] = r
+		byPart[node.part] = i
+		levelParts = append(levelParts, node.part)
+	}
+	_, overlapping, err := parts.FindMaximumNonOverlappingSet(l.schema, levelParts)
+	if err != nil {
+		return nil, err
+	}
+
+	// merged is the range of rows of the parts that are merged.
+	var merged rowRange
+	extend := func(r rowRange) {
+		if merged.least == nil || l.schema.Cmp(r.least, merged.least) < 0 {
+			merged.least = r.least
+		}
+		if merged.most == nil || l.schema.Cmp(r.most, merged.most) > 0 {
+			merged.most = r.most
+		}
+	}
+	for _, p := range merge {
+		r, err := partRange(p)
+		if err != nil {
+			return nil, err
+		}
+		extend(r)
+	}
+	rewrite := make([]bool, len(nodes))
+	for _, p := range overlapping {
+		i := byPart[p]
+		rewrite[i] = true
+		extend(ranges[i])
+	}
+
+	// Rewritten parts extend the merged range, which can make it overlap with
+	// more parts.
+	for found := true; found; {
+		found = false
+		for i, r := range ranges {
+			if rewrite[i] {
+				continue
+			}
+			if l.schema.Cmp(r.least, merged.most) < 0 && l.schema.Cmp(merged.least, r.most) < 0 {
+				rewrite[i] = true
+				extend(r)
+				found = true
+			}
+		}
+	}
+
+	var result []*Node
+	for i, node := range nodes {
+		if rewrite[i] {
+			result = append(result, node)
+		}
+	}
+	return result, nil
+}
+
+// levelNodes returns the nodes of the parts of a level starting at first, and
+// the node following them, which is the sentinel of the next level or nil.
+func levelNodes(first *Node) ([]*Node, *Node) {
+	var nodes []*Node
+	node := first
+	for node != nil && node.part != nil {
+		nodes = append(nodes, node)
+		node = node.next.Load()
+	}
+	return nodes, node
+}
+
+// compareTXDesc orders parts by descending txn.
+func compareTXDesc(a, b parts.Part) int {
+	return cmp.Compare(b.TX(), a.TX())
+}
+
 // compact is a cascading compaction routine. It will start at the lowest level and compact until the next level is either the max level or the next level does not exceed the max size.
 // compact can not be run concurrently.
 func (l *LSM) compact(ignoreSizes bool) error {
@@ -2,17 +2,20 @@ package index
 
 import (
 	"context"
-	"errors"
 	"io"
 	"math"
 	"math/rand"
+	"path/filepath"
 	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/util"
 	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/youscentia/ydb-frostdb/dynparquet"
@@ -45,15 +48,8 @@ func compactParts(w io.Writer, compact []parts.Part, _ ...parquet.WriterOption)
 		rows := merged.Rows()
 		defer rows.Close()
 
-		buf := make([]parquet.Row, merged.NumRows())
-		if _, err := rows.ReadRows(buf); err != nil && !errors.Is(err, io.EOF) {
-			return err
-		}
-		if _, err := writer.WriteRows(buf); err != nil && !errors.Is(err, io.EOF) {
-			return err
-		}
-
-		return nil
+		_, err = parquet.CopyRows(writer, rows)
+		return err
 	}()
 	if err != nil {
 		return 0, err
@@ -252,3 +248,199 @@ func Test_LSM_InOrderInsert(t *testing.T) {
 		return 0
 	}))
 }
+
+// countingLevel is a custom in-memory level that counts the calls to it and
+// recovers the given parts.
+type countingLevel struct {
+	Level
+	compactions int
+	snapshots   int
+	recover     []parts.Part
+}
+
+func (l *countingLevel) Compact(compact []parts.Part, options ...parts.Option) ([]parts.Part, int64, int64, error) {
+	l.compactions++
+	return l.Level.Compact(compact, options...)
+}
+
+func (l *countingLevel) Snapshot(snapshot []parts.Part, writer func(parts.Part) error, dir string) error {
+	l.snapshots++
+	return l.Level.Snapshot(snapshot, writer, dir)
+}
+
+func (l *countingLevel) Recover(...parts.Option) ([]parts.Part, error) {
+	return l.recover, nil
+}
+
+func Test_LSM_CustomLevel(t *testing.T) {
+	t.Parallel()
+	samples := dynparquet.NewTestSamples()
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+
+	newLSM := func(lvl *countingLevel) *LSM {
+		lsm, err := NewLSM("test", nil, []*LevelConfig{
+			{Level: L0, MaxSize: 1024 * 1024 * 1024, Compact: compactParts, NewLevel: func(options LevelOptions) (Level, error) {
+				require.Equal(t, filepath.Join("test", "L1"), options.Dir)
+				inner, err := newInMemoryLevel(options)
+				if err != nil {
+					return nil, err
+				}
+				lvl.Level = inner
+				return lvl, nil
+			}},
+			{Level: L1, MaxSize: 1024 * 1024 * 1024},
+		},
+			func() uint64 { return math.MaxUint64 },
+		)
+		require.NoError(t, err)
+		return lsm
+	}
+
+	lvl := &countingLevel{}
+	lsm := newLSM(lvl)
+	lsm.Add(1, r)
+	lsm.Add(2, r)
+	require.NoError(t, lsm.EnsureCompaction())
+	require.Equal(t, 1, lvl.compactions)
+	check(t, lsm, 0, 1)
+
+	var snapshot []parts.Part
+	require.NoError(t, lsm.Snapshot(2, func(p parts.Part) error {
+		snapshot = append(snapshot, p)
+		return nil
+	}, t.TempDir()))
+	require.Equal(t, 1, lvl.snapshots)
+	require.Len(t, snapshot, 1)
+
+	// Recovered parts are inserted into the level after the custom one.
+	lsm = newLSM(&countingLevel{recover: []parts.Part{
+		parts.NewArrowPart(1, r, uint64(util.TotalRecordSize(r)), nil, parts.WithCompactionLevel(int(L1))),
+	}})
+	check(t, lsm, 1, 0)
+	require.Zero(t, lsm.LevelSize(L0))
+	require.NotZero(t, lsm.LevelSize(L1))
+}
+
+// exampleTypeRecord returns a record of the test samples for each of the given
+// example types, which are the first sorting column of the sample schema.
+func exampleTypeRecord(t *testing.T, exampleTypes ...string) arrow.Record {
+	t.Helper()
+	var samples dynparquet.Samples
+	for _, exampleType := range exampleTypes {
+		for _, sample := range dynparquet.NewTestSamples() {
+			sample.ExampleType = exampleType
+			samples = append(samples, sample)
+		}
+	}
+	r, err := samples.ToRecord()
+	require.NoError(t, err)
+	return r
+}
+
+func Test_LSM_CompactionStrategy(t *testing.T) {
+	t.Parallel()
+	newLSM := func(t *testing.T, strategy CompactionStrategy, metrics *LSMMetrics) *LSM {
+		lsm, err := NewLSM("test", dynparquet.NewSampleSchema(), []*LevelConfig{
+			{Level: L0, MaxSize: 1024 * 1024 * 1024, Type: CompactionTypeParquetMemory, Compact: compactParts, Strategy: strategy},
+			{Level: L1, MaxSize: 1024 * 1024 * 1024},
+		},
+			func() uint64 { return math.MaxUint64 },
+			LSMWithMetrics(metrics),
+		)
+		require.NoError(t, err)
+		return lsm
+	}
+	levelParts := func(lsm *LSM, level SentinelType) int {
+		nodes, _ := levelNodes(lsm.findLevel(level).next.Load())
+		return len(nodes)
+	}
+	rows := func(t *testing.T, lsm *LSM) int64 {
+		var n int64
+		require.NoError(t, lsm.Scan(context.Background(), "", nil, nil, math.MaxUint64, func(_ context.Context, v any) error {
+			switch v := v.(type) {
+			case arrow.Record:
+				n += v.NumRows()
+				v.Release()
+			case ReleaseableRowGroup:
+				n += v.NumRows()
+				v.Release()
+			}
+			return nil
+		}))
+		return n
+	}
+
+	t.Run("leveled", func(t *testing.T) {
+		metrics := NewLSMMetrics(prometheus.NewRegistry())
+		lsm := newLSM(t, CompactionStrategyLeveled, metrics)
+		rewritten := metrics.CompactionBytesRewritten.WithLabelValues(L1.String())
+
+		lsm.Add(1, exampleTypeRecord(t, "a"))
+		require.NoError(t, lsm.merge(L0))
+		lsm.Add(2, exampleTypeRecord(t, "c"))
+		require.NoError(t, lsm.merge(L0))
+		require.Equal(t, 2, levelParts(lsm, L1))
+		require.Zero(t, testutil.ToFloat64(rewritten))
+
+		// Only the overlapping part is rewritten.
+		lsm.Add(3, exampleTypeRecord(t, "a"))
+		require.NoError(t, lsm.merge(L0))
+		require.Equal(t, 2, levelParts(lsm, L1))
+		require.NotZero(t, testutil.ToFloat64(rewritten))
+
+		lsm.Add(4, exampleTypeRecord(t, "b"))
+		require.NoError(t, lsm.merge(L0))
+		require.Equal(t, 3, levelParts(lsm, L1))
+
+		// The range of the merged part covers all the parts of the level.
+		lsm.Add(5, exampleTypeRecord(t, "a", "c"))
+		require.NoError(t, lsm.merge(L0))
+		require.Equal(t, 1, levelParts(lsm, L1))
+		require.Zero(t, lsm.LevelSize(L0))
+		require.Equal(t, lsm.findLevel(L1).next.Load().part.Size(), lsm.LevelSize(L1))
+		require.Equal(t, int64(18), rows(t, lsm))
+	})
+
+	t.Run("size-tiered", func(t *testing.T) {
+		metrics := NewLSMMetrics(prometheus.NewRegistry())
+		lsm := newLSM(t, CompactionStrategySizeTiered, metrics)
+		rewritten := metrics.CompactionBytesRewritten.WithLabelValues(L1.String())
+
+		for tx := uint64(1); tx < sizeTieredMinParts; tx++ {
+			lsm.Add(tx, exampleTypeRecord(t, "a"))
+			require.NoError(t, lsm.merge(L0))
+		}
+		require.Equal(t, sizeTieredMinParts-1, levelParts(lsm, L1))
+		require.Zero(t, testutil.ToFloat64(rewritten))
+
+		lsm.Add(sizeTieredMinParts, exampleTypeRecord(t, "a"))
+		require.NoError(t, lsm.merge(L0))
+		require.Equal(t, 1, levelParts(lsm, L1))
+		require.NotZero(t, testutil.ToFloat64(rewritten))
+		require.Equal(t, lsm.findLevel(L1).next.Load().part.Size(), lsm.LevelSize(L1))
+		require.Equal(t, int64(3*sizeTieredMinParts), rows(t, lsm))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, levels := range map[string][]*LevelConfig{
+			"disk": {
+				{Level: L0, MaxSize: 1, Type: CompactionTypeParquetDisk, Compact: compactParts, Strategy: CompactionStrategyLeveled},
+				{Level: L1, MaxSize: 1},
+			},
+			"last level": {
+				{Level: L0, MaxSize: 1, Type: CompactionTypeParquetMemory, Compact: compactParts},
+				{Level: L1, MaxSize: 1, Strategy: CompactionStrategySizeTiered},
+			},
+		} {
+			_, err := NewLSM("test", dynparquet.NewSampleSchema(), levels, func() uint64 { return math.MaxUint64 })
+			require.Error(t, err, name)
+		}
+
+		_, err := NewLSM("test", nil, []*LevelConfig{
+			{Level: L0, MaxSize: 1, Type: CompactionTypeParquetMemory, Compact: compactParts, Strategy: CompactionStrategyLeveled},
+			{Level: L1, MaxSize: 1},
+		}, func() uint64 { return math.MaxUint64 })
+		require.Error(t, err)
+	})
+}
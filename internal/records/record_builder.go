@@ -1,15 +1,18 @@
 package records
 
 import (
+	"errors"
 	"reflect"
 	"regexp"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/google/uuid"
 
@@ -61,6 +64,8 @@ type Record struct {
 //	                   zstd | ZSTD compression.
 //	               rle_dict | Dictionary run-length encoding.
 //	                  plain | Plain encoding.
+//	                decimal | Stores int64 fields as decimals. Use decimal(n) where n is the precision, the field holds the unscaled value.
+//	                  scale | Number of digits after the decimal point of decimal fields. Use scale(n) where n is an integer.
 //
 // Example tagged Sample struct
 //
@@ -81,10 +86,17 @@ type Record struct {
 //		Labels map[string]string `frostdb:"labels"`
 //	}
 //
+// # Column types
+//
+// Fields of type int64, uint64, float64, float32, bool, string, []byte and
+// time.Time are supported. time.Time is stored as a timestamp with nanosecond
+// precision and []byte as binary.
+//
 // # Repeated columns
 //
-// Fields of type []int64, []float64, []bool, and []string are supported. These
-// are represented as arrow.LIST.
+// Fields of type []int64, []float64, []float32, []bool, []string, [][]byte and
+// []time.Time are supported. These are represented as arrow.LIST. Slices of
+// slices, like [][]int64, are represented as nested lists.
 //
 // Generated schema for the repeated columns applies all supported tags. By
 // default repeated fields are nullable. You can safely pass nil slices for
@@ -116,6 +128,8 @@ func NewBuild[T any](mem memory.Allocator) *Build[T] {
 			nullFirst  bool
 			sortOrder  int
 			direction  schemapb.SortingColumn_Direction
+			precision  int
+			scale      int
 
 			encoding    schemapb.StorageLayout_Encoding
 			compression schemapb.StorageLayout_Compression
@@ -137,6 +151,10 @@ func NewBuild[T any](mem memory.Allocator) *Build[T] {
 					}
 				case "pre_hash":
 					preHash = true
+				case "decimal":
+					precision, _ = strconv.Atoi(value)
+				case "scale":
+					scale, _ = strconv.Atoi(value)
 				case "plain":
 					encoding = schemapb.StorageLayout_ENCODING_PLAIN_UNSPECIFIED
 				case "rle_dict":
@@ -193,18 +211,43 @@ func NewBuild[T any](mem memory.Allocator) *Build[T] {
 			case isUUIDSlice(fty):
 				fr.typ = schemapb.StorageLayout_TYPE_STRING
 				fr.build = newUUIDSliceField(mem, name)
+			case isBytes(fty):
+				typ, styp = baseType(fty, dictionary)
+				fr.typ = styp
+				fr.nullable = nullable
+				fr.build = newFieldBuild(typ, mem, name, nullable)
 			default:
-				typ, styp = baseType(fty.Elem(), dictionary)
+				elem, depth := fty.Elem(), 1
+				for elem.Kind() == reflect.Slice && !isBytes(elem) {
+					elem = elem.Elem()
+					depth++
+				}
+				typ, styp = baseType(elem, dictionary)
+				if precision != 0 {
+					typ, styp = decimalType(elem, precision, scale)
+				}
 				fr.typ = styp
+				fr.precision = int32(precision)
+				fr.scale = int32(scale)
 				fr.repeated = true
+				if depth > 1 {
+					fr.listDepth = uint32(depth)
+				}
 				// Repeated columns are always nullable
 				fr.nullable = true
-				typ = arrow.ListOf(typ)
+				for i := 0; i < depth; i++ {
+					typ = arrow.ListOf(typ)
+				}
 				fr.build = newFieldBuild(typ, mem, name, true)
 			}
-		case reflect.Int64, reflect.Float64, reflect.Bool, reflect.String, reflect.Uint64:
+		case reflect.Int64, reflect.Float64, reflect.Float32, reflect.Bool, reflect.String, reflect.Uint64, reflect.Struct:
 			typ, styp = baseType(fty, dictionary)
+			if precision != 0 {
+				typ, styp = decimalType(fty, precision, scale)
+			}
 			fr.typ = styp
+			fr.precision = int32(precision)
+			fr.scale = int32(scale)
 			fr.nullable = nullable
 			fr.build = newFieldBuild(typ, mem, name, nullable)
 		default:
@@ -326,6 +369,9 @@ func (b Build[T]) Schema(name string) (s *schemapb.Schema) {
 				Compression: f.compression,
 				Nullable:    f.nullable,
 				Repeated:    f.repeated,
+				Precision:   f.precision,
+				Scale:       f.scale,
+				ListDepth:   f.listDepth,
 			},
 		})
 		if f.sort {
@@ -513,6 +559,21 @@ func baseType(fty reflect.Type, dictionary bool) (typ arrow.DataType, sty schema
 	case reflect.Uint64:
 		typ = arrow.PrimitiveTypes.Uint64
 		sty = schemapb.StorageLayout_TYPE_UINT64
+	case reflect.Float32:
+		typ = arrow.PrimitiveTypes.Float32
+		sty = schemapb.StorageLayout_TYPE_FLOAT
+	case reflect.Slice:
+		if !isBytes(fty) {
+			panic("frostdb/dynschema: " + fty.String() + " is npt supported")
+		}
+		typ = arrow.BinaryTypes.Binary
+		sty = schemapb.StorageLayout_TYPE_BINARY
+	case reflect.Struct:
+		if fty != timeType {
+			panic("frostdb/dynschema: " + fty.String() + " is npt supported")
+		}
+		typ = arrow.FixedWidthTypes.Timestamp_ns
+		sty = schemapb.StorageLayout_TYPE_TIMESTAMP
 	default:
 		panic("frostdb/dynschema: " + fty.String() + " is npt supported")
 	}
@@ -525,6 +586,21 @@ func baseType(fty reflect.Type, dictionary bool) (typ arrow.DataType, sty schema
 	return
 }
 
+// decimalType returns the type of decimal fields, they hold the unscaled
+// value as an int64.
+func decimalType(fty reflect.Type, precision, scale int) (arrow.DataType, schemapb.StorageLayout_Type) {
+	for fty.Kind() == reflect.Ptr {
+		fty = fty.Elem()
+	}
+	if fty.Kind() != reflect.Int64 {
+		panic("frostdb/dynschema: decimal " + fty.String() + " is npt supported")
+	}
+	return &arrow.Decimal128Type{
+		Precision: int32(precision),
+		Scale:     int32(scale),
+	}, schemapb.StorageLayout_TYPE_DECIMAL
+}
+
 func fieldName(f reflect.StructField) (name, tag string) {
 	name, tag, _ = strings.Cut(f.Tag.Get(TagName), ",")
 	if name == "" {
@@ -618,6 +694,54 @@ func newFieldBuild(dt arrow.DataType, mem memory.Allocator, name string, nullabl
 			}
 			return e.Append(v.Float())
 		}
+	case *array.Float32Builder:
+		f.buildFunc = func(v reflect.Value) error {
+			if nullable {
+				if v.IsNil() {
+					e.AppendNull()
+					return nil
+				}
+				v = v.Elem()
+			}
+			e.Append(float32(v.Float()))
+			return nil
+		}
+	case *array.TimestampBuilder:
+		f.buildFunc = func(v reflect.Value) error {
+			if nullable {
+				if v.IsNil() {
+					e.AppendNull()
+					return nil
+				}
+				v = v.Elem()
+			}
+			e.Append(arrow.Timestamp(v.Interface().(time.Time).UnixNano()))
+			return nil
+		}
+	case *array.Decimal128Builder:
+		f.buildFunc = func(v reflect.Value) error {
+			if nullable {
+				if v.IsNil() {
+					e.AppendNull()
+					return nil
+				}
+				v = v.Elem()
+			}
+			e.Append(decimal128.FromI64(v.Int()))
+			return nil
+		}
+	case *array.BinaryBuilder:
+		f.buildFunc = func(v reflect.Value) error {
+			if nullable {
+				if v.IsNil() {
+					e.AppendNull()
+					return nil
+				}
+				v = v.Elem()
+			}
+			e.Append(v.Bytes())
+			return nil
+		}
 	case *array.BooleanBuilder:
 		f.buildFunc = func(v reflect.Value) error {
 			if nullable {
@@ -651,6 +775,9 @@ func newFieldBuild(dt arrow.DataType, mem memory.Allocator, name string, nullabl
 				}
 				v = v.Elem()
 			}
+			if v.Kind() == reflect.Slice {
+				return e.Append(v.Bytes())
+			}
 			return e.AppendString(v.Interface().(string))
 		}
 	case *array.ListBuilder:
@@ -751,6 +878,10 @@ func newFieldBuild(dt arrow.DataType, mem memory.Allocator, name string, nullabl
 					return nil
 				})
 			}
+		default:
+			f.buildFunc = func(v reflect.Value) error {
+				return appendValue(e, v)
+			}
 		}
 	default:
 		panic("frostdb:dynschema: unsupported array builder " + b.Type().String())
@@ -758,6 +889,51 @@ func newFieldBuild(dt arrow.DataType, mem memory.Allocator, name string, nullabl
 	return
 }
 
+// appendValue appends v to the builder, it is used for the types of lists
+// that don't have a specialized builder, like nested lists.
+func appendValue(b array.Builder, v reflect.Value) error {
+	switch e := b.(type) {
+	case *array.ListBuilder:
+		if v.IsNil() {
+			e.AppendNull()
+			return nil
+		}
+		e.Append(true)
+		e.ValueBuilder().Reserve(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := appendValue(e.ValueBuilder(), v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case *array.Int64Builder:
+		e.Append(v.Int())
+	case *array.Uint64Builder:
+		e.Append(v.Uint())
+	case *array.Float64Builder:
+		e.Append(v.Float())
+	case *array.Float32Builder:
+		e.Append(float32(v.Float()))
+	case *array.BooleanBuilder:
+		e.Append(v.Bool())
+	case *array.StringBuilder:
+		e.Append(v.String())
+	case *array.BinaryBuilder:
+		e.Append(v.Bytes())
+	case *array.TimestampBuilder:
+		e.Append(arrow.Timestamp(v.Interface().(time.Time).UnixNano()))
+	case *array.Decimal128Builder:
+		e.Append(decimal128.FromI64(v.Int()))
+	case *array.BinaryDictionaryBuilder:
+		if v.Kind() == reflect.Slice {
+			return e.Append(v.Bytes())
+		}
+		return e.AppendString(v.String())
+	default:
+		return errors.New("frostdb/dynschema: unsupported list builder " + b.Type().String())
+	}
+	return nil
+}
+
 func applyString(v reflect.Value, apply func(string) error) error {
 	return listApply[string](v, func(v reflect.Value) string {
 		return v.Interface().(string)
@@ -864,6 +1040,9 @@ type fieldRecord struct {
 	encoding    schemapb.StorageLayout_Encoding
 	compression schemapb.StorageLayout_Compression
 	typ         schemapb.StorageLayout_Type
+	precision   int32
+	scale       int32
+	listDepth   uint32
 	build       fieldBuilder
 }
 
@@ -880,12 +1059,19 @@ func walkTag(tag string, f func(key, value string)) {
 	walkTag(tag, f)
 }
 
-var uuidSliceType = reflect.TypeOf([]uuid.UUID{})
+var (
+	uuidSliceType = reflect.TypeOf([]uuid.UUID{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
 
 func isUUIDSlice(typ reflect.Type) bool {
 	return typ.AssignableTo(uuidSliceType)
 }
 
+func isBytes(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
 var (
 	matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
 	matchAllCap   = regexp.MustCompile("([a-z0-9])([A-Z])")
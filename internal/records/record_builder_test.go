@@ -2,6 +2,7 @@ package records_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
@@ -146,6 +147,95 @@ func TestBuild(t *testing.T) {
 	})
 }
 
+func TestBuild_types(t *testing.T) {
+	type Types struct {
+		Timestamp time.Time
+		Hash      []byte
+		Gauge     float32
+		Price     *int64 `frostdb:",decimal(10),scale(2)"`
+		Gauges    []float32
+		Buckets   [][]int64
+	}
+
+	b := records.NewBuild[Types](memory.NewGoAllocator())
+	defer b.Release()
+
+	wantSchema := `{
+  "name": "types",
+  "columns": [
+    {
+      "name": "timestamp",
+      "storageLayout": {
+        "type": "TYPE_TIMESTAMP"
+      }
+    },
+    {
+      "name": "hash",
+      "storageLayout": {
+        "type": "TYPE_BINARY"
+      }
+    },
+    {
+      "name": "gauge",
+      "storageLayout": {
+        "type": "TYPE_FLOAT"
+      }
+    },
+    {
+      "name": "price",
+      "storageLayout": {
+        "type": "TYPE_DECIMAL",
+        "nullable": true,
+        "precision": 10,
+        "scale": 2
+      }
+    },
+    {
+      "name": "gauges",
+      "storageLayout": {
+        "type": "TYPE_FLOAT",
+        "nullable": true,
+        "repeated": true
+      }
+    },
+    {
+      "name": "buckets",
+      "storageLayout": {
+        "type": "TYPE_INT64",
+        "nullable": true,
+        "repeated": true,
+        "listDepth": 2
+      }
+    }
+  ]
+}`
+	m := protojson.MarshalOptions{Multiline: true}
+	d, _ := m.Marshal(b.Schema("types"))
+	require.JSONEq(t, wantSchema, string(d))
+
+	err := b.Append(
+		Types{Timestamp: time.Unix(0, 1).UTC()},
+		Types{
+			Timestamp: time.Unix(1, 0).UTC(),
+			Hash:      []byte("hash"),
+			Gauge:     1.5,
+			Price:     point[int64](1234),
+			Gauges:    []float32{1, 2.5},
+			Buckets:   [][]int64{{1, 2}, nil, {3}},
+		},
+	)
+	require.Nil(t, err)
+	r := b.NewRecord()
+	defer r.Release()
+
+	want := `[{"buckets":null,"gauge":0,"gauges":null,"hash":"","price":null,"timestamp":"1970-01-01 00:00:00.000000001Z"}
+,{"buckets":[[1,2],null,[3]],"gauge":1.5,"gauges":[1,2.5],"hash":"aGFzaA==","price":"12.34","timestamp":"1970-01-01 00:00:01Z"}
+]`
+	got, err := r.MarshalJSON()
+	require.Nil(t, err)
+	require.JSONEq(t, want, string(got))
+}
+
 func TestBuild_pointer_base_types(t *testing.T) {
 	type PointerBase struct {
 		Int     *int64
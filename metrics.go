@@ -88,6 +88,8 @@ type globalMetrics struct {
 			walRepairsLostRecords *prometheus.CounterVec
 			walCloseTimeouts      *prometheus.CounterVec
 			walQueueSize          *prometheus.GaugeVec
+			syncDuration          *prometheus.HistogramVec
+			groupCommitSize       *prometheus.HistogramVec
 		}
 	}
 	tableMetrics struct {
@@ -99,10 +101,18 @@ type globalMetrics struct {
 		rowInsertSize        *prometheus.HistogramVec
 		lastCompletedBlockTx *prometheus.GaugeVec
 		numParts             *prometheus.GaugeVec
+		blocksReclaimed      *prometheus.CounterVec
+		blockBytesReclaimed  *prometheus.CounterVec
+		replicationsFailed   *prometheus.CounterVec
+		replicationsDropped  *prometheus.CounterVec
+		blocksCompacted      *prometheus.CounterVec
+		compactionsFailed    *prometheus.CounterVec
 		indexMetrics         struct {
 			compactions        *prometheus.CounterVec
 			levelSize          *prometheus.GaugeVec
 			compactionDuration *prometheus.HistogramVec
+			bytesWritten       *prometheus.CounterVec
+			bytesRewritten     *prometheus.CounterVec
 		}
 	}
 }
@@ -228,6 +238,16 @@ func makeAndRegisterGlobalMetrics(unwrappedReg prometheus.Registerer) globalMetr
 				Name: "queue_size",
 				Help: "The number of unprocessed requests in the WAL queue",
 			}, makeLabelsForDBMetric())
+			m.dbMetrics.fileWalMetrics.syncDuration = promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:                        "sync_duration_seconds",
+				Help:                        "Time synchronous writes waited for their record to be written and fsynced",
+				NativeHistogramBucketFactor: 1.1,
+			}, makeLabelsForDBMetric())
+			m.dbMetrics.fileWalMetrics.groupCommitSize = promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "group_commit_records",
+				Help:    "The number of records written with a single fsync that synchronous writes waited for",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+			}, makeLabelsForDBMetric())
 		}
 	}
 
@@ -267,6 +287,30 @@ func makeAndRegisterGlobalMetrics(unwrappedReg prometheus.Registerer) globalMetr
 			Name: "num_parts",
 			Help: "Number of parts currently active.",
 		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.blocksReclaimed = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "blocks_reclaimed_total",
+			Help: "Number of persisted table blocks that have been removed from storage due to retention.",
+		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.blockBytesReclaimed = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "block_bytes_reclaimed_total",
+			Help: "Number of bytes of persisted table blocks that have been removed from storage due to retention.",
+		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.replicationsFailed = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "block_replications_failed_total",
+			Help: "Number of uploads of table blocks to secondary sinks that have failed.",
+		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.replicationsDropped = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "block_replications_dropped_total",
+			Help: "Number of failed uploads of table blocks to secondary sinks that have been given up on without retrying them successfully.",
+		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.blocksCompacted = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "blocks_compacted_total",
+			Help: "Number of persisted table blocks that have been replaced by compacted blocks.",
+		}, makeLabelsForTablesMetrics())
+		m.tableMetrics.compactionsFailed = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "block_compactions_failed_total",
+			Help: "Number of compactions of persisted table blocks that have failed.",
+		}, makeLabelsForTablesMetrics())
 
 		// LSM metrics.
 		{
@@ -286,6 +330,16 @@ func makeAndRegisterGlobalMetrics(unwrappedReg prometheus.Registerer) globalMetr
 				Help:                        "Total compaction duration",
 				NativeHistogramBucketFactor: 1.1,
 			}, makeLabelsForTablesMetrics())
+
+			m.tableMetrics.indexMetrics.bytesWritten = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "compaction_written_bytes_total",
+				Help: "The total size in bytes of the parts written into the level by compactions.",
+			}, makeLabelsForTablesMetrics("level"))
+
+			m.tableMetrics.indexMetrics.bytesRewritten = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "compaction_rewritten_bytes_total",
+				Help: "The total size in bytes of the parts of the level rewritten into the level by compactions.",
+			}, makeLabelsForTablesMetrics("level"))
 		}
 	}
 	return m
@@ -319,6 +373,12 @@ type tableMetrics struct {
 	rowInsertSize        prometheus.Observer
 	lastCompletedBlockTx prometheus.Gauge
 	numParts             prometheus.Gauge
+	blocksReclaimed      prometheus.Counter
+	blockBytesReclaimed  prometheus.Counter
+	replicationsFailed   prometheus.Counter
+	replicationsDropped  prometheus.Counter
+	blocksCompacted      prometheus.Counter
+	compactionsFailed    prometheus.Counter
 
 	indexMetrics index.LSMMetrics
 }
@@ -333,10 +393,18 @@ func (p tableMetricsProvider) metricsForTable(tableName string) tableMetrics {
 		rowInsertSize:        p.m.tableMetrics.rowInsertSize.WithLabelValues(p.dbName, tableName),
 		lastCompletedBlockTx: p.m.tableMetrics.lastCompletedBlockTx.WithLabelValues(p.dbName, tableName),
 		numParts:             p.m.tableMetrics.numParts.WithLabelValues(p.dbName, tableName),
+		blocksReclaimed:      p.m.tableMetrics.blocksReclaimed.WithLabelValues(p.dbName, tableName),
+		blockBytesReclaimed:  p.m.tableMetrics.blockBytesReclaimed.WithLabelValues(p.dbName, tableName),
+		replicationsFailed:   p.m.tableMetrics.replicationsFailed.WithLabelValues(p.dbName, tableName),
+		replicationsDropped:  p.m.tableMetrics.replicationsDropped.WithLabelValues(p.dbName, tableName),
+		blocksCompacted:      p.m.tableMetrics.blocksCompacted.WithLabelValues(p.dbName, tableName),
+		compactionsFailed:    p.m.tableMetrics.compactionsFailed.WithLabelValues(p.dbName, tableName),
 		indexMetrics: index.LSMMetrics{
-			Compactions:        p.m.tableMetrics.indexMetrics.compactions.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
-			LevelSize:          p.m.tableMetrics.indexMetrics.levelSize.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
-			CompactionDuration: p.m.tableMetrics.indexMetrics.compactionDuration.WithLabelValues(p.dbName, tableName),
+			Compactions:              p.m.tableMetrics.indexMetrics.compactions.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
+			LevelSize:                p.m.tableMetrics.indexMetrics.levelSize.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
+			CompactionDuration:       p.m.tableMetrics.indexMetrics.compactionDuration.WithLabelValues(p.dbName, tableName),
+			CompactionBytesWritten:   p.m.tableMetrics.indexMetrics.bytesWritten.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
+			CompactionBytesRewritten: p.m.tableMetrics.indexMetrics.bytesRewritten.MustCurryWith(prometheus.Labels{"db": p.dbName, "table": tableName}),
 		},
 	}
 }
@@ -363,5 +431,7 @@ func (m globalMetrics) metricsForFileWAL(dbName string) *filewal.Metrics {
 		WalRepairsLostRecords: m.dbMetrics.fileWalMetrics.walRepairsLostRecords.WithLabelValues(dbName),
 		WalCloseTimeouts:      m.dbMetrics.fileWalMetrics.walCloseTimeouts.WithLabelValues(dbName),
 		WalQueueSize:          m.dbMetrics.fileWalMetrics.walQueueSize.WithLabelValues(dbName),
+		SyncDuration:          m.dbMetrics.fileWalMetrics.syncDuration.WithLabelValues(dbName),
+		GroupCommitSize:       m.dbMetrics.fileWalMetrics.groupCommitSize.WithLabelValues(dbName),
 	}
 }
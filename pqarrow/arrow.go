@@ -10,6 +10,7 @@ import (
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/parquet-go/parquet-go"
 
@@ -714,7 +715,7 @@ func (c *ParquetConverter) writeColumnToArray(
 	dictionaryOnly bool,
 	w writer.ValueWriter,
 ) error {
-	repeated := n.Repeated()
+	repeated := n.Repeated() || !n.Leaf()
 	if !repeated && dictionaryOnly {
 		// Check all the page indexes of the column chunk. If they are
 		// trustworthy and there is only one value contained in the column
@@ -1064,6 +1065,33 @@ func copyArrToBuilder(builder builder.ColumnBuilder, arr arrow.Array, toCopy int
 				b.UnsafeAppend(arr.Value(i))
 			}
 		}
+	case *array.Float32:
+		b := builder.(*array.Float32Builder)
+		for i := 0; i < toCopy; i++ {
+			if arr.IsNull(i) {
+				b.UnsafeAppendBoolToBitmap(false)
+			} else {
+				b.UnsafeAppend(arr.Value(i))
+			}
+		}
+	case *array.Timestamp:
+		b := builder.(*array.TimestampBuilder)
+		for i := 0; i < toCopy; i++ {
+			if arr.IsNull(i) {
+				b.UnsafeAppendBoolToBitmap(false)
+			} else {
+				b.UnsafeAppend(arr.Value(i))
+			}
+		}
+	case *array.Decimal128:
+		b := builder.(*array.Decimal128Builder)
+		for i := 0; i < toCopy; i++ {
+			if arr.IsNull(i) {
+				b.UnsafeAppendBoolToBitmap(false)
+			} else {
+				b.UnsafeAppend(arr.Value(i))
+			}
+		}
 	case *array.Dictionary:
 		b := builder.(*array.BinaryDictionaryBuilder)
 		switch dict := arr.Dictionary().(type) {
@@ -1113,6 +1141,12 @@ func repeatLastValue(
 		repeatUint64Array(builder.(*array.Uint64Builder), arr, count)
 	case *array.Float64:
 		repeatFloat64Array(builder.(*array.Float64Builder), arr, count)
+	case *array.Float32:
+		repeatFloat32Array(builder.(*array.Float32Builder), arr, count)
+	case *array.Timestamp:
+		repeatTimestampArray(builder.(*array.TimestampBuilder), arr, count)
+	case *array.Decimal128:
+		repeatDecimal128Array(builder.(*array.Decimal128Builder), arr, count)
 	case *array.Dictionary:
 		repeatDictionaryArray(builder.(array.DictionaryBuilder), arr, count)
 	default:
@@ -1186,6 +1220,45 @@ func repeatFloat64Array(
 	b.AppendValues(vals, nil)
 }
 
+func repeatFloat32Array(
+	b *array.Float32Builder,
+	arr *array.Float32,
+	count int,
+) {
+	val := arr.Value(arr.Len() - 1)
+	vals := make([]float32, count)
+	for i := 0; i < count; i++ {
+		vals[i] = val
+	}
+	b.AppendValues(vals, nil)
+}
+
+func repeatTimestampArray(
+	b *array.TimestampBuilder,
+	arr *array.Timestamp,
+	count int,
+) {
+	val := arr.Value(arr.Len() - 1)
+	vals := make([]arrow.Timestamp, count)
+	for i := 0; i < count; i++ {
+		vals[i] = val
+	}
+	b.AppendValues(vals, nil)
+}
+
+func repeatDecimal128Array(
+	b *array.Decimal128Builder,
+	arr *array.Decimal128,
+	count int,
+) {
+	val := arr.Value(arr.Len() - 1)
+	vals := make([]decimal128.Num, count)
+	for i := 0; i < count; i++ {
+		vals[i] = val
+	}
+	b.AppendValues(vals, nil)
+}
+
 func repeatDictionaryArray(b array.DictionaryBuilder, arr *array.Dictionary, count int) {
 	switch db := b.(type) {
 	case *array.BinaryDictionaryBuilder:
@@ -14,6 +14,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"golang.org/x/sync/errgroup"
 
@@ -436,6 +437,10 @@ func newMultiColSorter(
 			ms.comparisons[i] = newOrderedSorter[uint64](e, cmp.Compare)
 		case *array.Float64:
 			ms.comparisons[i] = newOrderedSorter[float64](e, cmp.Compare)
+		case *array.Float32:
+			ms.comparisons[i] = newOrderedSorter[float32](e, cmp.Compare)
+		case *array.Decimal128:
+			ms.comparisons[i] = newOrderedSorter[decimal128.Num](e, decimal128.Num.Cmp)
 		case *array.String:
 			ms.comparisons[i] = newOrderedSorter[string](e, cmp.Compare)
 		case *array.Binary:
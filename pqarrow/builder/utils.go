@@ -45,6 +45,12 @@ func RollbackPrevious(cb ColumnBuilder) error {
 		b.Resize(b.Len() - 1)
 	case *array.BinaryDictionaryBuilder:
 		b.Resize(b.Len() - 1)
+	case *array.Float32Builder:
+		b.Resize(b.Len() - 1)
+	case *array.TimestampBuilder:
+		b.Resize(b.Len() - 1)
+	case *array.Decimal128Builder:
+		b.Resize(b.Len() - 1)
 	default:
 		return fmt.Errorf("unsupported type for RollbackPrevious %T", b)
 	}
@@ -70,6 +76,12 @@ func AppendValue(cb ColumnBuilder, arr arrow.Array, i int) error {
 		b.Append(arr.(*array.Int32).Value(i))
 	case *array.Float64Builder:
 		b.Append(arr.(*array.Float64).Value(i))
+	case *array.Float32Builder:
+		b.Append(arr.(*array.Float32).Value(i))
+	case *array.TimestampBuilder:
+		b.Append(arr.(*array.Timestamp).Value(i))
+	case *array.Decimal128Builder:
+		b.Append(arr.(*array.Decimal128).Value(i))
 	case *array.Uint64Builder:
 		b.Append(arr.(*array.Uint64).Value(i))
 	case *array.StringBuilder:
@@ -155,6 +167,18 @@ func buildList(vb any, b ListLikeBuilder, arr arrow.Array, i int) error {
 				}
 			}
 		}
+	case *array.List, *array.Float32, *array.Timestamp, *array.Decimal128:
+		valueBuilder, ok := vb.(ColumnBuilder)
+		if !ok {
+			return fmt.Errorf("unsupported type for ListLikeBuilder: %T", vb)
+		}
+
+		b.Append(true)
+		for j := 0; j < v.Len(); j++ {
+			if err := AppendValue(valueBuilder, v, j); err != nil {
+				return err
+			}
+		}
 	case *array.Struct:
 		structBuilder, ok := vb.(*array.StructBuilder)
 		if !ok {
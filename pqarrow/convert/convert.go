@@ -83,6 +83,19 @@ func ParquetNodeToType(n parquet.Node) (arrow.DataType, error) {
 			default:
 				return nil, errors.New("unsupported int bit width")
 			}
+		case lt.Timestamp != nil:
+			if lt.Timestamp.Unit.Nanos == nil {
+				return nil, errors.New("unsupported timestamp unit: " + n.Type().String())
+			}
+			dt = arrow.FixedWidthTypes.Timestamp_ns
+		case lt.Decimal != nil:
+			if t.Kind() != parquet.Int64 {
+				return nil, errors.New("unsupported decimal physical type: " + n.Type().String())
+			}
+			dt = &arrow.Decimal128Type{
+				Precision: lt.Decimal.Precision,
+				Scale:     lt.Decimal.Scale,
+			}
 		default:
 			return nil, errors.New("unsupported logical type: " + n.Type().String())
 		}
@@ -90,6 +103,10 @@ func ParquetNodeToType(n parquet.Node) (arrow.DataType, error) {
 		dt = &arrow.BooleanType{}
 	case t.Kind() == parquet.Double:
 		dt = &arrow.Float64Type{}
+	case t.Kind() == parquet.Float:
+		dt = &arrow.Float32Type{}
+	case t.Kind() == parquet.ByteArray:
+		dt = &arrow.BinaryType{}
 	default:
 		return nil, errors.New("unsupported type: " + n.Type().String())
 	}
@@ -103,6 +120,10 @@ func ParquetNodeToType(n parquet.Node) (arrow.DataType, error) {
 
 // GetWriter create a value writer from a parquet node.
 func GetWriter(offset int, n parquet.Node) (writer.NewWriterFunc, error) {
+	if !n.Leaf() && hasListFields(n) {
+		return listWriter(offset, n)
+	}
+
 	dt, err := ParquetNodeToType(n)
 	if err != nil {
 		return nil, err
@@ -115,35 +136,78 @@ func GetWriter(offset int, n parquet.Node) (writer.NewWriterFunc, error) {
 		dt = typ.Elem()
 	}
 
-	var wr writer.NewWriterFunc
+	wr, err := valueWriter(offset, dt)
+	if err != nil {
+		return nil, errors.New("unsupported type: " + n.Type().String())
+	}
+
+	if n.Repeated() || list {
+		// TODO(asubiotto): We should use arrow.ListOfNonNullable if
+		// n.Optional(). The problem is that it doesn't seem like the arrow
+		// builder stores the nullability (NewArray always uses ListOf).
+		return writer.NewListValueWriter(wr), nil
+	}
+	return wr, nil
+}
+
+func valueWriter(offset int, dt arrow.DataType) (writer.NewWriterFunc, error) {
 	switch dt.(type) {
 	case *arrow.BinaryType:
-		wr = writer.NewBinaryValueWriter
+		return writer.NewBinaryValueWriter, nil
 	case *arrow.Int64Type:
-		wr = writer.NewInt64ValueWriter
+		return writer.NewInt64ValueWriter, nil
 	case *arrow.Uint64Type:
-		wr = writer.NewUint64ValueWriter
+		return writer.NewUint64ValueWriter, nil
 	case *arrow.MapType:
-		wr = writer.NewMapWriter
+		return writer.NewMapWriter, nil
 	case *arrow.StructType:
-		wr = writer.NewStructWriterFromOffset(offset)
+		return writer.NewStructWriterFromOffset(offset), nil
 	case *arrow.BooleanType:
-		wr = writer.NewBooleanValueWriter
+		return writer.NewBooleanValueWriter, nil
 	case *arrow.Float64Type:
-		wr = writer.NewFloat64ValueWriter
+		return writer.NewFloat64ValueWriter, nil
+	case *arrow.Float32Type:
+		return writer.NewFloat32ValueWriter, nil
+	case *arrow.TimestampType:
+		return writer.NewTimestampValueWriter, nil
+	case *arrow.Decimal128Type:
+		return writer.NewDecimal128ValueWriter, nil
 	case *arrow.DictionaryType:
-		wr = writer.NewDictionaryValueWriter
+		return writer.NewDictionaryValueWriter, nil
 	default:
-		return nil, errors.New("unsupported type: " + n.Type().String())
+		return nil, errors.New("unsupported type: " + dt.String())
 	}
+}
 
-	if n.Repeated() || list {
-		// TODO(asubiotto): We should use arrow.ListOfNonNullable if
-		// n.Optional(). The problem is that it doesn't seem like the arrow
-		// builder stores the nullability (NewArray always uses ListOf).
-		return writer.NewListValueWriter(wr), nil
+// listWriter returns a value writer for (nested) LIST groups. The definition
+// levels of the lists are collected on the way down to their leaf.
+func listWriter(offset int, n parquet.Node) (writer.NewWriterFunc, error) {
+	var levels []writer.ListLevel
+	def := 0
+	for !n.Leaf() {
+		if !hasListFields(n) {
+			return nil, errors.New("unsupported list element type: " + n.Type().String())
+		}
+		if n.Optional() {
+			def++
+		}
+		level := writer.ListLevel{NonNullDef: def}
+		// The repeated list group.
+		def++
+		level.ElemDef = def
+		levels = append(levels, level)
+		n = n.Fields()[0].Fields()[0]
 	}
-	return wr, nil
+
+	dt, err := ParquetNodeToType(n)
+	if err != nil {
+		return nil, err
+	}
+	wr, err := valueWriter(offset, dt)
+	if err != nil {
+		return nil, err
+	}
+	return writer.NewNestedListValueWriter(levels, wr), nil
 }
 
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#maps
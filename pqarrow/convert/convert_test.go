@@ -31,6 +31,22 @@ func TestParquetNodeToType(t *testing.T) {
 			parquetNode: parquet.Leaf(parquet.BooleanType),
 			arrowType:   &arrow.BooleanType{},
 		},
+		{
+			parquetNode: parquet.Leaf(parquet.FloatType),
+			arrowType:   &arrow.Float32Type{},
+		},
+		{
+			parquetNode: parquet.Leaf(parquet.ByteArrayType),
+			arrowType:   &arrow.BinaryType{},
+		},
+		{
+			parquetNode: parquet.Timestamp(parquet.Nanosecond),
+			arrowType:   arrow.FixedWidthTypes.Timestamp_ns,
+		},
+		{
+			parquetNode: parquet.Decimal(2, 10, parquet.Int64Type),
+			arrowType:   &arrow.Decimal128Type{Precision: 10, Scale: 2},
+		},
 		{
 			parquetNode: parquet.Group{},
 			arrowType:   &arrow.StructType{},
@@ -68,21 +84,13 @@ func TestParquetNodeToType(t *testing.T) {
 			parquetNode: parquet.Leaf(parquet.Int96Type),
 			msg:         "unsupported type: INT96",
 		},
-		{
-			parquetNode: parquet.Leaf(parquet.FloatType),
-			msg:         "unsupported type: FLOAT",
-		},
-		{
-			parquetNode: parquet.Leaf(parquet.ByteArrayType),
-			msg:         "unsupported type: BYTE_ARRAY",
-		},
 		{
 			parquetNode: parquet.Leaf(parquet.FixedLenByteArrayType(8)),
 			msg:         "unsupported type: FIXED_LEN_BYTE_ARRAY(8)",
 		},
 		{
 			parquetNode: parquet.Decimal(0, 9, parquet.Int32Type),
-			msg:         "unsupported logical type: DECIMAL(9,0)",
+			msg:         "unsupported decimal physical type: DECIMAL(9,0)",
 		},
 		{
 			parquetNode: parquet.UUID(),
@@ -112,7 +120,7 @@ func TestParquetNodeToType(t *testing.T) {
 		},
 		{
 			parquetNode: parquet.Timestamp(parquet.Millisecond),
-			msg:         "unsupported logical type: TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS)",
+			msg:         "unsupported timestamp unit: TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS)",
 		},
 		// nullType is unexported by parquet-go/parquet-go.
 	}
@@ -25,6 +25,18 @@ func ArrowScalarToParquetValue(sc scalar.Scalar) (parquet.Value, error) {
 		return parquet.ValueOf(s.Value), nil
 	case *scalar.Uint64:
 		return parquet.ValueOf(s.Value), nil
+	case *scalar.Float64:
+		return parquet.ValueOf(s.Value), nil
+	case *scalar.Float32:
+		return parquet.ValueOf(s.Value), nil
+	case *scalar.Timestamp:
+		return parquet.Int64Value(int64(s.Value)), nil
+	case *scalar.Decimal128:
+		// The scale of the column isn't known here, it is converted by the
+		// comparison.
+		return parquet.DoubleValue(s.Value.ToFloat64(s.Type.(*arrow.Decimal128Type).Scale)), nil
+	case *scalar.Binary:
+		return parquet.ByteArrayValue(s.Data()), nil
 	case *scalar.FixedSizeBinary:
 		width := s.Type.(*arrow.FixedSizeBinaryType).ByteWidth
 		v := [16]byte{}
@@ -102,6 +114,14 @@ func recordToRows(w dynparquet.ParquetWriter, record arrow.Record, recordStart,
 		column := record.Column(idx[0])
 		switch a := column.(type) {
 		case *array.List:
+			if _, ok := a.DataType().(*arrow.ListType).Elem().(*arrow.ListType); ok {
+				ls, err := writeNestedList(f, i, recordStart, a)
+				if err != nil {
+					return err
+				}
+				writers[i] = ls
+				continue
+			}
 			ls, err := writeList(def, i, recordStart, a)
 			if err != nil {
 				return err
@@ -119,6 +139,12 @@ func recordToRows(w dynparquet.ParquetWriter, record arrow.Record, recordStart,
 			writers[i] = writeString(def, i, recordStart, a)
 		case *array.Binary:
 			writers[i] = writeBinary(def, i, recordStart, a)
+		case *array.Float32:
+			writers[i] = writeFloat32(def, i, recordStart, a)
+		case *array.Timestamp:
+			writers[i] = writeTimestamp(def, i, recordStart, a)
+		case *array.Decimal128:
+			writers[i] = writeDecimal128(def, i, recordStart, a)
 		default:
 			writers[i] = writeGeneral(def, i, recordStart, a)
 		}
@@ -190,6 +216,47 @@ func writeInt32(def, column, startIdx int, a *array.Int32) arrowToParquet {
 	}
 }
 
+func writeFloat32(def, column, startIdx int, a *array.Float32) arrowToParquet {
+	return func(w parquet.Row, row int) parquet.Row {
+		if a.IsNull(row + startIdx) {
+			return append(w,
+				parquet.Value{}.Level(0, 0, column),
+			)
+		}
+		return append(w,
+			parquet.FloatValue(a.Value(row+startIdx)).Level(0, def, column),
+		)
+	}
+}
+
+func writeTimestamp(def, column, startIdx int, a *array.Timestamp) arrowToParquet {
+	return func(w parquet.Row, row int) parquet.Row {
+		if a.IsNull(row + startIdx) {
+			return append(w,
+				parquet.Value{}.Level(0, 0, column),
+			)
+		}
+		return append(w,
+			parquet.Int64Value(int64(a.Value(row+startIdx))).Level(0, def, column),
+		)
+	}
+}
+
+// writeDecimal128 writes decimals as their unscaled int64 value, decimal
+// columns have a precision of at most 18 digits.
+func writeDecimal128(def, column, startIdx int, a *array.Decimal128) arrowToParquet {
+	return func(w parquet.Row, row int) parquet.Row {
+		if a.IsNull(row + startIdx) {
+			return append(w,
+				parquet.Value{}.Level(0, 0, column),
+			)
+		}
+		return append(w,
+			parquet.Int64Value(int64(a.Value(row+startIdx).LowBits())).Level(0, def, column),
+		)
+	}
+}
+
 func writeBinary(def, column, startIdx int, a *array.Binary) arrowToParquet {
 	return func(w parquet.Row, row int) parquet.Row {
 		if a.IsNull(row + startIdx) {
@@ -240,61 +307,82 @@ func writeDictionary(def, column, startIdx int, a *array.Dictionary) arrowToParq
 }
 
 func writeList(def, column, startIdx int, a *array.List) (arrowToParquet, error) {
-	var lw arrowToParquet
-	switch e := a.ListValues().(type) {
+	value, err := listValue(a.ListValues())
+	if err != nil {
+		return nil, err
+	}
+	lw := writeListOf(def, column, startIdx, a, value)
+	return func(w parquet.Row, row int) parquet.Row {
+		if a.IsNull(row + startIdx) {
+			return append(w,
+				parquet.Value{}.Level(0, 0, column),
+			)
+		}
+		return lw(w, row)
+	}, nil
+}
+
+// listValue returns a function converting the values of a list to parquet
+// values.
+func listValue(values arrow.Array) (func(idx int) parquet.Value, error) {
+	switch e := values.(type) {
 	case *array.Int32:
 		// WHile this is not base type. To avoid breaking things I have left it here.
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.Int32Value(e.Value(idx))
-		})
+		}, nil
 	case *array.Int64:
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.Int64Value(e.Value(idx))
-		})
+		}, nil
 	case *array.Boolean:
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.BooleanValue(e.Value(idx))
-		})
+		}, nil
 	case *array.Float64:
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.DoubleValue(e.Value(idx))
-		})
+		}, nil
+	case *array.Float32:
+		return func(idx int) parquet.Value {
+			return parquet.FloatValue(e.Value(idx))
+		}, nil
+	case *array.Timestamp:
+		return func(idx int) parquet.Value {
+			return parquet.Int64Value(int64(e.Value(idx)))
+		}, nil
+	case *array.Decimal128:
+		return func(idx int) parquet.Value {
+			return parquet.Int64Value(int64(e.Value(idx).LowBits()))
+		}, nil
 	case *array.String:
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.ByteArrayValue([]byte(e.Value(idx)))
-		})
+		}, nil
 	case *array.Binary:
-		lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+		return func(idx int) parquet.Value {
 			return parquet.ByteArrayValue([]byte(e.Value(idx)))
-		})
+		}, nil
 	case *array.Dictionary:
 		switch d := e.Dictionary().(type) {
 		case *array.Binary:
-			lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+			return func(idx int) parquet.Value {
 				return parquet.ByteArrayValue(
 					d.Value(e.GetValueIndex(idx)),
 				)
-			})
+			}, nil
 		case *array.String:
-			lw = writeListOf(def, column, startIdx, a, func(idx int) parquet.Value {
+			return func(idx int) parquet.Value {
 				return parquet.ByteArrayValue(
 					[]byte(d.Value(e.GetValueIndex(idx))),
 				)
-			})
+			}, nil
 		default:
 			return nil, fmt.Errorf("list dictionary not of expected type: %T", d)
 		}
 	default:
 		return nil, fmt.Errorf("list not of expected type: %T", e)
 	}
-	return func(w parquet.Row, row int) parquet.Row {
-		if a.IsNull(row + startIdx) {
-			return append(w,
-				parquet.Value{}.Level(0, 0, column),
-			)
-		}
-		return lw(w, row)
-	}, nil
 }
 
 func writeListOf(def, column, startIdx int, a *array.List, value func(idx int) parquet.Value) arrowToParquet {
@@ -311,6 +399,83 @@ func writeListOf(def, column, startIdx int, a *array.List, value func(idx int) p
 	}
 }
 
+// listLevels are the definition levels of a list in a nested list column.
+type listLevels struct {
+	// null and empty are the definition levels of null and empty lists.
+	null, empty int
+}
+
+// writeNestedList writes lists stored as nested LIST groups. The list levels
+// are walked recursively, repeating values at the level of their list.
+func writeNestedList(f parquet.Field, column, startIdx int, a *array.List) (arrowToParquet, error) {
+	var levels []listLevels
+	def := 0
+	var n parquet.Node = f
+	for !n.Leaf() {
+		if len(n.Fields()) != 1 || len(n.Fields()[0].Fields()) != 1 {
+			return nil, fmt.Errorf("column %s is not a nested list", f.Name())
+		}
+		l := listLevels{null: def}
+		if n.Optional() {
+			def++
+		}
+		l.empty = def
+		if !n.Optional() {
+			// Required lists can't be null, they are written as empty lists.
+			l.null = def
+		}
+		// The repeated list group.
+		def++
+		levels = append(levels, l)
+		n = n.Fields()[0].Fields()[0]
+	}
+	leafDef := def
+	if n.Optional() {
+		leafDef++
+	}
+
+	values := arrow.Array(a)
+	for range levels {
+		list, ok := values.(*array.List)
+		if !ok {
+			return nil, fmt.Errorf("column %s has %d list levels, got %s", f.Name(), len(levels), a.DataType())
+		}
+		values = list.ListValues()
+	}
+	value, err := listValue(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var write func(w parquet.Row, arr arrow.Array, idx, depth, rep int) parquet.Row
+	write = func(w parquet.Row, arr arrow.Array, idx, depth, rep int) parquet.Row {
+		if depth == len(levels) {
+			if arr.IsNull(idx) {
+				return append(w, parquet.Value{}.Level(rep, leafDef-1, column))
+			}
+			return append(w, value(idx).Level(rep, leafDef, column))
+		}
+		if arr.IsNull(idx) {
+			return append(w, parquet.Value{}.Level(rep, levels[depth].null, column))
+		}
+		list := arr.(*array.List)
+		start, end := list.ValueOffsets(idx)
+		if start == end {
+			return append(w, parquet.Value{}.Level(rep, levels[depth].empty, column))
+		}
+		for k := start; k < end; k++ {
+			if k != start {
+				rep = depth + 1
+			}
+			w = write(w, list.ListValues(), int(k), depth+1, rep)
+		}
+		return w
+	}
+	return func(w parquet.Row, row int) parquet.Row {
+		return write(w, a, row+startIdx, 0, 0)
+	}, nil
+}
+
 func writeNull(column int) arrowToParquet {
 	return func(w parquet.Row, _ int) parquet.Row {
 		return append(w, parquet.Value{}.Level(0, 0, column))
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
 	"github.com/parquet-go/parquet-go"
 
 	"github.com/youscentia/ydb-frostdb/pqarrow/builder"
@@ -157,6 +159,146 @@ func (w *repeatedValueWriter) Write(values []parquet.Value) {
 	}
 }
 
+// ListLevel describes the definition levels of a list in a nested list
+// column.
+type ListLevel struct {
+	// NonNullDef is the definition level from which the list is not null.
+	NonNullDef int
+	// ElemDef is the definition level from which the list has an element.
+	ElemDef int
+}
+
+type nestedListValueWriter struct {
+	builders []*builder.ListBuilder
+	levels   []ListLevel
+	values   ValueWriter
+}
+
+// NewNestedListValueWriter returns a writer for columns stored as nested
+// LIST groups, one level per list. The values are written to the builder of
+// the innermost list using the given value writer.
+func NewNestedListValueWriter(levels []ListLevel, newValueWriter NewWriterFunc) NewWriterFunc {
+	return func(b builder.ColumnBuilder, numValues int) ValueWriter {
+		builders := make([]*builder.ListBuilder, len(levels))
+		for i := range levels {
+			builders[i] = b.(*builder.ListBuilder)
+			b = builders[i].ValueBuilder()
+		}
+		return &nestedListValueWriter{
+			builders: builders,
+			levels:   levels,
+			values:   newValueWriter(b, numValues),
+		}
+	}
+}
+
+func (w *nestedListValueWriter) Write(values []parquet.Value) {
+	// Values are written to the value builder in runs, the offsets of the
+	// lists are taken from its length so runs are flushed before appending
+	// to any list.
+	start, end := 0, 0
+	flush := func() {
+		if start < end {
+			w.values.Write(values[start:end])
+		}
+		start = end
+	}
+	for i, v := range values {
+		rep, def := v.RepetitionLevel(), v.DefinitionLevel()
+		// The list at the repetition level gets a new element, the lists
+		// below it start at this value.
+		for l := max(rep, 1); l <= len(w.levels); l++ {
+			level := w.levels[l-1]
+			if rep < l {
+				flush()
+				if def < level.NonNullDef {
+					w.builders[l-1].AppendNull()
+					break
+				}
+				w.builders[l-1].Append(true)
+			}
+			if def < level.ElemDef {
+				break
+			}
+			if l == len(w.levels) {
+				if end != i {
+					flush()
+					start = i
+				}
+				end = i + 1
+			}
+		}
+	}
+	flush()
+}
+
+type timestampValueWriter struct {
+	b *array.TimestampBuilder
+}
+
+func NewTimestampValueWriter(b builder.ColumnBuilder, numValues int) ValueWriter {
+	res := &timestampValueWriter{
+		b: b.(*array.TimestampBuilder),
+	}
+	res.b.Reserve(numValues)
+	return res
+}
+
+func (w *timestampValueWriter) Write(values []parquet.Value) {
+	for _, v := range values {
+		if v.IsNull() {
+			w.b.AppendNull()
+		} else {
+			w.b.Append(arrow.Timestamp(v.Int64()))
+		}
+	}
+}
+
+type float32ValueWriter struct {
+	b *array.Float32Builder
+}
+
+func NewFloat32ValueWriter(b builder.ColumnBuilder, numValues int) ValueWriter {
+	res := &float32ValueWriter{
+		b: b.(*array.Float32Builder),
+	}
+	res.b.Reserve(numValues)
+	return res
+}
+
+func (w *float32ValueWriter) Write(values []parquet.Value) {
+	for _, v := range values {
+		if v.IsNull() {
+			w.b.AppendNull()
+		} else {
+			w.b.Append(v.Float())
+		}
+	}
+}
+
+type decimal128ValueWriter struct {
+	b *array.Decimal128Builder
+}
+
+// NewDecimal128ValueWriter returns a writer for decimals stored as int64.
+func NewDecimal128ValueWriter(b builder.ColumnBuilder, numValues int) ValueWriter {
+	res := &decimal128ValueWriter{
+		b: b.(*array.Decimal128Builder),
+	}
+	res.b.Reserve(numValues)
+	return res
+}
+
+func (w *decimal128ValueWriter) Write(values []parquet.Value) {
+	for _, v := range values {
+		if v.IsNull() {
+			w.b.AppendNull()
+		} else {
+			w.b.Append(decimal128.FromI64(v.Int64()))
+		}
+	}
+}
+
 type float64ValueWriter struct {
 	b   *array.Float64Builder
 	buf []float64
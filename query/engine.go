@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"slices"
 
 	"go.opentelemetry.io/otel/trace/noop"
 
@@ -19,6 +20,8 @@ type Builder interface {
 	Distinct(expr ...logicalplan.Expr) Builder
 	Project(projections ...logicalplan.Expr) Builder
 	Limit(expr logicalplan.Expr) Builder
+	OrderBy(exprs ...*logicalplan.SortExpr) Builder
+	Join(right Builder, joinType logicalplan.JoinType, leftKeys, rightKeys []logicalplan.Expr) Builder
 	Execute(ctx context.Context, callback func(ctx context.Context, r arrow.Record) error) error
 	Explain(ctx context.Context) (string, error)
 	Sample(size, limitInBytes int64) Builder
@@ -29,6 +32,8 @@ type LocalEngine struct {
 	tracer        trace.Tracer
 	tableProvider logicalplan.TableProvider
 	execOpts      []physicalplan.Option
+	workers       *physicalplan.WorkerLimiter
+	readTx        uint64
 }
 
 type Option func(*LocalEngine)
@@ -45,6 +50,26 @@ func WithPhysicalplanOptions(opts ...physicalplan.Option) Option {
 	}
 }
 
+// WithWorkerLimiter makes all queries of the engine acquire their workers from
+// the given limiter, so concurrent queries queue once all workers are in use.
+// The limiter can be shared by multiple engines.
+func WithWorkerLimiter(l *physicalplan.WorkerLimiter) Option {
+	return func(e *LocalEngine) {
+		e.workers = l
+	}
+}
+
+// WithReadTx makes all queries of the engine read their tables at the given
+// txn, such as one returned by DB.HighWatermark, rather than at the latest
+// one. Queries of engines with the same txn see the same data, as long as the
+// data of the txn hasn't been compacted away since. Zero reads at the latest
+// txn.
+func WithReadTx(tx uint64) Option {
+	return func(e *LocalEngine) {
+		e.readTx = tx
+	}
+}
+
 func NewEngine(
 	pool memory.Allocator,
 	tableProvider logicalplan.TableProvider,
@@ -59,6 +84,12 @@ func NewEngine(
 	for _, option := range options {
 		option(e)
 	}
+	if e.workers != nil {
+		e.execOpts = append(slices.Clip(e.execOpts), physicalplan.WithWorkerLimiter(e.workers))
+	}
+	if e.readTx != 0 {
+		e.execOpts = append(slices.Clip(e.execOpts), physicalplan.WithReadTx(e.readTx))
+	}
 
 	return e
 }
@@ -79,6 +110,18 @@ func (e *LocalEngine) ScanTable(name string) Builder {
 	}
 }
 
+// FromPlan returns a Builder executing the given logical plan, such as a plan
+// created by sqlparse.Parser.Parse. The plan's scans read from the table
+// provider they were planned with rather than the engine's.
+func (e *LocalEngine) FromPlan(plan *logicalplan.LogicalPlan) Builder {
+	return LocalQueryBuilder{
+		pool:        e.pool,
+		tracer:      e.tracer,
+		planBuilder: logicalplan.FromPlan(plan),
+		execOpts:    e.execOpts,
+	}
+}
+
 func (e *LocalEngine) ScanSchema(name string) Builder {
 	return LocalQueryBuilder{
 		pool:        e.pool,
@@ -144,6 +187,40 @@ func (b LocalQueryBuilder) Limit(
 	}
 }
 
+func (b LocalQueryBuilder) OrderBy(
+	exprs ...*logicalplan.SortExpr,
+) Builder {
+	return LocalQueryBuilder{
+		pool:        b.pool,
+		tracer:      b.tracer,
+		planBuilder: b.planBuilder.OrderBy(exprs...),
+		execOpts:    b.execOpts,
+	}
+}
+
+// Join joins the rows of the query with the rows of the right query for which
+// each of the leftKeys is equal to the corresponding rightKeys. The right
+// query must have been created by a LocalEngine as well.
+func (b LocalQueryBuilder) Join(
+	right Builder,
+	joinType logicalplan.JoinType,
+	leftKeys, rightKeys []logicalplan.Expr,
+) Builder {
+	// A right query of another engine has no logical plan to join with, which
+	// fails validation when the query is built.
+	var rightPlanBuilder logicalplan.Builder
+	if r, ok := right.(LocalQueryBuilder); ok {
+		rightPlanBuilder = r.planBuilder
+	}
+
+	return LocalQueryBuilder{
+		pool:        b.pool,
+		tracer:      b.tracer,
+		planBuilder: b.planBuilder.Join(rightPlanBuilder, joinType, leftKeys, rightKeys),
+		execOpts:    b.execOpts,
+	}
+}
+
 func (b LocalQueryBuilder) Sample(
 	size, limitInBytes int64,
 ) Builder {
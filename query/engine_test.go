@@ -2,16 +2,20 @@ package query
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/youscentia/ydb-frostdb/dynparquet"
 	schemapb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha1"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	"github.com/youscentia/ydb-frostdb/query/physicalplan"
 )
 
 func TestUniqueAggregation(t *testing.T) {
@@ -237,3 +241,439 @@ func TestIsNullProjection(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ran)
 }
+
+func TestOrderByLimit(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+		Name: "test",
+		Columns: []*schemapb.Column{{
+			Name: "series",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}, {
+			Name: "value",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	rb := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{{
+		Name: "series",
+		Type: arrow.PrimitiveTypes.Int64,
+	}, {
+		Name: "value",
+		Type: arrow.PrimitiveTypes.Int64,
+	}}, nil))
+	defer rb.Release()
+
+	records := make([]arrow.Record, 0, 2)
+	for _, values := range [][2][]int64{
+		{{1, 2, 3, 4}, {10, 40, 5, 1}},
+		{{1, 2, 3, 4}, {50, 5, 20, 1}},
+	} {
+		rb.Field(0).(*array.Int64Builder).AppendValues(values[0], nil)
+		rb.Field(1).(*array.Int64Builder).AppendValues(values[1], nil)
+		r := rb.NewRecord()
+		defer r.Release()
+		records = append(records, r)
+	}
+
+	builder := NewEngine(mem, &FakeTableProvider{
+		Tables: map[string]logicalplan.TableReader{
+			"test": &FakeTableReader{
+				FrostdbSchema: schema,
+				Records:       records,
+			},
+		},
+	}).ScanTable("test").
+		Aggregate(
+			[]*logicalplan.AggregationFunction{logicalplan.Sum(logicalplan.Col("value"))},
+			[]logicalplan.Expr{logicalplan.Col("series")},
+		).
+		OrderBy(logicalplan.Desc(logicalplan.Sum(logicalplan.Col("value")))).
+		Limit(logicalplan.Literal(int64(2)))
+
+	explain, err := builder.Explain(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, explain, "TopK(2)")
+	require.NotContains(t, explain, "Limit")
+
+	ran := false
+	err = builder.Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+		require.Equal(t, []int64{1, 2}, r.Column(0).(*array.Int64).Int64Values())
+		require.Equal(t, []int64{60, 45}, r.Column(1).(*array.Int64).Int64Values())
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, ran)
+}
+
+func TestAggregationConcurrency(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+		Name: "test",
+		Columns: []*schemapb.Column{{
+			Name: "series",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}, {
+			Name: "value",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	rb := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{{
+		Name: "series",
+		Type: arrow.PrimitiveTypes.Int64,
+	}, {
+		Name: "value",
+		Type: arrow.PrimitiveTypes.Int64,
+	}}, nil))
+	defer rb.Release()
+
+	records := make([]arrow.Record, 0, 2)
+	for _, values := range [][2][]int64{
+		{{1, 2, 1}, {10, 20, 30}},
+		{{2, 3}, {5, 1}},
+	} {
+		rb.Field(0).(*array.Int64Builder).AppendValues(values[0], nil)
+		rb.Field(1).(*array.Int64Builder).AppendValues(values[1], nil)
+		r := rb.NewRecord()
+		defer r.Release()
+		records = append(records, r)
+	}
+	provider := &FakeTableProvider{
+		Tables: map[string]logicalplan.TableReader{
+			"test": &FakeTableReader{
+				FrostdbSchema: schema,
+				Records:       records,
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "concurrency 1",
+			opts: []Option{WithPhysicalplanOptions(physicalplan.WithConcurrency(1))},
+		},
+		{
+			name: "concurrency 4",
+			opts: []Option{WithPhysicalplanOptions(physicalplan.WithConcurrency(4))},
+		},
+		{
+			name: "single worker",
+			opts: []Option{WithWorkerLimiter(physicalplan.NewWorkerLimiter(1))},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows := map[int64][2]int64{}
+			err := NewEngine(mem, provider, tc.opts...).ScanTable("test").
+				Aggregate(
+					[]*logicalplan.AggregationFunction{
+						logicalplan.Sum(logicalplan.Col("value")),
+						logicalplan.Count(logicalplan.Col("value")),
+					},
+					[]logicalplan.Expr{logicalplan.Col("series")},
+				).
+				Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+					require.Equal(t, []string{"series", "sum(value)", "count(value)"}, []string{
+						r.ColumnName(0), r.ColumnName(1), r.ColumnName(2),
+					})
+					for i := 0; i < int(r.NumRows()); i++ {
+						rows[r.Column(0).(*array.Int64).Value(i)] = [2]int64{
+							r.Column(1).(*array.Int64).Value(i),
+							r.Column(2).(*array.Int64).Value(i),
+						}
+					}
+					return nil
+				})
+			require.NoError(t, err)
+			require.Equal(t, map[int64][2]int64{
+				1: {40, 2},
+				2: {25, 2},
+				3: {1, 1},
+			}, rows)
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var records []arrow.Record
+	defer func() {
+		for _, r := range records {
+			r.Release()
+		}
+	}()
+	newTable := func(columns []*schemapb.Column, fields []arrow.Field, build func(b *array.RecordBuilder)) *FakeTableReader {
+		schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+			Name:    "test",
+			Columns: columns,
+		})
+		require.NoError(t, err)
+
+		rb := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+		defer rb.Release()
+		build(rb)
+		r := rb.NewRecord()
+		records = append(records, r)
+		return &FakeTableReader{
+			FrostdbSchema: schema,
+			Records:       []arrow.Record{r},
+		}
+	}
+
+	samples := newTable([]*schemapb.Column{{
+		Name:          "function_id",
+		StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64, Nullable: true},
+	}, {
+		Name:          "value",
+		StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+	}}, []arrow.Field{
+		{Name: "function_id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, func(b *array.RecordBuilder) {
+		b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 1, 0}, []bool{true, true, true, true, false})
+		b.Field(1).(*array.Int64Builder).AppendValues([]int64{10, 20, 30, 40, 50}, nil)
+	})
+	functions := newTable([]*schemapb.Column{{
+		Name:          "id",
+		StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+	}, {
+		Name:          "name",
+		StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_STRING},
+	}}, []arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, func(b *array.RecordBuilder) {
+		b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 2}, nil)
+		b.Field(1).(*array.StringBuilder).AppendValues([]string{"main", "foo", "bar"}, nil)
+	})
+	noFunctions := &FakeTableReader{FrostdbSchema: functions.FrostdbSchema}
+
+	engine := NewEngine(mem, &FakeTableProvider{
+		Tables: map[string]logicalplan.TableReader{
+			"samples":      samples,
+			"functions":    functions,
+			"no_functions": noFunctions,
+		},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		joinType logicalplan.JoinType
+		right    string
+		expected []string
+	}{
+		{
+			name:     "inner",
+			joinType: logicalplan.JoinTypeInner,
+			right:    "functions",
+			expected: []string{"1 10 1 main", "2 20 2 foo", "2 20 2 bar", "1 40 1 main"},
+		},
+		{
+			name:     "left",
+			joinType: logicalplan.JoinTypeLeft,
+			right:    "functions",
+			expected: []string{"1 10 1 main", "2 20 2 foo", "2 20 2 bar", "3 30 (null) (null)", "1 40 1 main", "(null) 50 (null) (null)"},
+		},
+		{
+			name:     "inner",
+			joinType: logicalplan.JoinTypeInner,
+			right:    "no_functions",
+		},
+		{
+			// The right columns are null rather than missing if the right
+			// input has no rows.
+			name:     "left",
+			joinType: logicalplan.JoinTypeLeft,
+			right:    "no_functions",
+			expected: []string{"1 10 (null) (null)", "2 20 (null) (null)", "3 30 (null) (null)", "1 40 (null) (null)", "(null) 50 (null) (null)"},
+		},
+	} {
+		t.Run(tc.name+"/"+tc.right, func(t *testing.T) {
+			builder := engine.ScanTable("samples").Join(
+				engine.ScanTable(tc.right),
+				tc.joinType,
+				[]logicalplan.Expr{logicalplan.Col("function_id")},
+				[]logicalplan.Expr{logicalplan.Col("id")},
+			)
+
+			explain, err := builder.Explain(context.Background())
+			require.NoError(t, err)
+			require.Contains(t, explain, "HashJoin["+tc.name+"] (function_id=id)")
+
+			var rows []string
+			require.NoError(t, builder.Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+				require.Equal(t, int64(4), r.NumCols())
+				require.Equal(t, []string{"function_id", "value", "id", "name"}, []string{
+					r.ColumnName(0), r.ColumnName(1), r.ColumnName(2), r.ColumnName(3),
+				})
+				for i := 0; i < int(r.NumRows()); i++ {
+					row := make([]string, 0, r.NumCols())
+					for _, col := range r.Columns() {
+						row = append(row, col.ValueStr(i))
+					}
+					rows = append(rows, strings.Join(row, " "))
+				}
+				return nil
+			}))
+			require.ElementsMatch(t, tc.expected, rows)
+		})
+	}
+}
+
+func TestJoinConflictingColumns(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+		Name: "test",
+		Columns: []*schemapb.Column{{
+			Name:          "id",
+			StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+		}, {
+			Name:          "value",
+			StorageLayout: &schemapb.StorageLayout{Type: schemapb.StorageLayout_TYPE_INT64},
+		}},
+	})
+	require.NoError(t, err)
+
+	rb := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil))
+	defer rb.Release()
+	rb.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	rb.Field(1).(*array.Int64Builder).AppendValues([]int64{10, 20}, nil)
+	r := rb.NewRecord()
+	defer r.Release()
+
+	engine := NewEngine(mem, &FakeTableProvider{
+		Tables: map[string]logicalplan.TableReader{
+			"test": &FakeTableReader{
+				FrostdbSchema: schema,
+				Records:       []arrow.Record{r},
+			},
+		},
+	})
+
+	// The right id is dropped since it is the key compared to the left id,
+	// but the right value would silently replace or be replaced by the left
+	// value.
+	err = engine.ScanTable("test").Join(
+		engine.ScanTable("test"),
+		logicalplan.JoinTypeInner,
+		[]logicalplan.Expr{logicalplan.Col("id")},
+		[]logicalplan.Expr{logicalplan.Col("id")},
+	).Execute(context.Background(), func(_ context.Context, _ arrow.Record) error {
+		return nil
+	})
+	require.ErrorContains(t, err, `column "value" of the right input conflicts`)
+
+	var rows []string
+	require.NoError(t, engine.ScanTable("test").Join(
+		engine.ScanTable("test").Project(logicalplan.Col("id"), logicalplan.Col("value").Alias("right_value")),
+		logicalplan.JoinTypeInner,
+		[]logicalplan.Expr{logicalplan.Col("id")},
+		[]logicalplan.Expr{logicalplan.Col("id")},
+	).Execute(context.Background(), func(_ context.Context, r arrow.Record) error {
+		require.Equal(t, []string{"id", "value", "right_value"}, []string{
+			r.ColumnName(0), r.ColumnName(1), r.ColumnName(2),
+		})
+		for i := 0; i < int(r.NumRows()); i++ {
+			rows = append(rows, r.Column(0).ValueStr(i)+" "+r.Column(1).ValueStr(i)+" "+r.Column(2).ValueStr(i))
+		}
+		return nil
+	}))
+	require.ElementsMatch(t, []string{"1 10 10", "2 20 20"}, rows)
+}
+
+func TestWorkerLimiter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+		Name: "test",
+		Columns: []*schemapb.Column{{
+			Name: "value",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_INT64,
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	rb := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{{
+		Name: "value",
+		Type: arrow.PrimitiveTypes.Int64,
+	}}, nil))
+	defer rb.Release()
+	rb.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	r := rb.NewRecord()
+	defer r.Release()
+
+	provider := &FakeTableProvider{
+		Tables: map[string]logicalplan.TableReader{
+			"test": &FakeTableReader{
+				FrostdbSchema: schema,
+				Records:       []arrow.Record{r},
+			},
+		},
+	}
+	limiter := physicalplan.NewWorkerLimiter(1)
+
+	// The concurrency of queries is capped to the workers of the limiter.
+	explain, err := NewEngine(mem, provider, WithWorkerLimiter(limiter)).ScanTable("test").Explain(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, explain, "[concurrent]")
+
+	// Queries of different engines sharing a limiter wait for each other.
+	first := make(chan struct{})
+	unblock := make(chan struct{})
+	errg := errgroup.Group{}
+	errg.Go(func() error {
+		return NewEngine(mem, provider, WithWorkerLimiter(limiter)).ScanTable("test").
+			Execute(context.Background(), func(_ context.Context, _ arrow.Record) error {
+				close(first)
+				<-unblock
+				return nil
+			})
+	})
+	<-first
+
+	second := make(chan struct{})
+	errg.Go(func() error {
+		defer close(second)
+		return NewEngine(mem, provider, WithWorkerLimiter(limiter)).ScanTable("test").
+			Execute(context.Background(), func(_ context.Context, _ arrow.Record) error {
+				return nil
+			})
+	})
+	select {
+	case <-second:
+		t.Fatal("second query executed while the first held all workers")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(unblock)
+	require.NoError(t, errg.Wait())
+	<-second
+}
@@ -3,6 +3,7 @@ package expr
 import (
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/parquet-go/parquet-go"
 
@@ -89,6 +90,12 @@ func BinaryScalarOperation(left parquet.ColumnChunk, right parquet.Value, operat
 	if err != nil {
 		return true, err
 	}
+	right, ok := convertValue(right, left.Type())
+	if !ok {
+		// The value can't be represented exactly in the type of the column,
+		// let the execution engine compare it.
+		return true, nil
+	}
 	numNulls := NullCount(leftColumnIndex)
 	fullOfNulls := numNulls == left.NumValues()
 	if operator == logicalplan.OpEq {
@@ -177,6 +184,68 @@ func BinaryScalarOperation(left parquet.ColumnChunk, right parquet.Value, operat
 	}
 }
 
+// maxExactFloat is the largest integer from which not all integers can be
+// represented by a float64.
+const maxExactFloat = 1 << 53
+
+// convertValue converts a numeric value to the physical representation of
+// the given column type so they can be compared. Decimal columns are compared
+// with their unscaled values. It returns false if the value can't be converted
+// exactly.
+func convertValue(v parquet.Value, t parquet.Type) (parquet.Value, bool) {
+	if v.IsNull() || t == nil {
+		return v, true
+	}
+
+	var (
+		f       float64
+		integer bool
+	)
+	switch v.Kind() {
+	case parquet.Int32, parquet.Int64:
+		f, integer = float64(v.Int64()), true
+	case parquet.Float:
+		f = float64(v.Float())
+	case parquet.Double:
+		f = v.Double()
+	default:
+		return v, true
+	}
+
+	if lt := t.LogicalType(); lt != nil && lt.Decimal != nil {
+		unscaled := f * math.Pow10(int(lt.Decimal.Scale))
+		if unscaled != math.Trunc(unscaled) || math.Abs(unscaled) >= maxExactFloat {
+			return v, false
+		}
+		return parquet.Int64Value(int64(unscaled)), true
+	}
+
+	switch t.Kind() {
+	case v.Kind():
+		return v, true
+	case parquet.Float:
+		if float64(float32(f)) != f {
+			return v, false
+		}
+		return parquet.FloatValue(float32(f)), true
+	case parquet.Double:
+		if integer && math.Abs(f) >= maxExactFloat {
+			return v, false
+		}
+		return parquet.DoubleValue(f), true
+	case parquet.Int64:
+		if integer {
+			return parquet.Int64Value(v.Int64()), true
+		}
+		if f != math.Trunc(f) || math.Abs(f) >= maxExactFloat {
+			return v, false
+		}
+		return parquet.Int64Value(int64(f)), true
+	default:
+		return v, true
+	}
+}
+
 // Min returns the minimum value found in the column chunk across all pages.
 func Min(columnIndex parquet.ColumnIndex) parquet.Value {
 	minV := columnIndex.MinValue(0)
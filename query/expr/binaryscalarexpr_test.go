@@ -10,11 +10,12 @@ import (
 )
 
 type FakeColumnChunk struct {
+	typ       parquet.Type
 	index     *FakeColumnIndex
 	numValues int64
 }
 
-func (f *FakeColumnChunk) Type() parquet.Type                        { return nil }
+func (f *FakeColumnChunk) Type() parquet.Type                        { return f.typ }
 func (f *FakeColumnChunk) Column() int                               { return 0 }
 func (f *FakeColumnChunk) Pages() parquet.Pages                      { return nil }
 func (f *FakeColumnChunk) ColumnIndex() (parquet.ColumnIndex, error) { return f.index, nil }
@@ -191,6 +192,7 @@ func TestBinaryScalarOperation(t *testing.T) {
 				maxV = parquet.ValueOf(nil)
 			}
 			fakeChunk := &FakeColumnChunk{
+				typ: parquet.Int64Type,
 				index: &FakeColumnIndex{
 					numPages:  1,
 					min:       minV,
@@ -211,3 +213,97 @@ func TestBinaryScalarOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestBinaryScalarOperationConvert(t *testing.T) {
+	decimal := parquet.Decimal(2, 10, parquet.Int64Type).Type()
+	for _, tc := range []struct {
+		name  string
+		typ   parquet.Type
+		min   parquet.Value
+		max   parquet.Value
+		right parquet.Value
+		op    logicalplan.Op
+		// expectSatisfies is true if the predicate should be satisfied by the
+		// column chunk.
+		expectSatisfies bool
+	}{
+		{
+			name:            "FloatGtDouble",
+			typ:             parquet.FloatType,
+			min:             parquet.FloatValue(1),
+			max:             parquet.FloatValue(2.5),
+			right:           parquet.DoubleValue(2.5),
+			op:              logicalplan.OpGt,
+			expectSatisfies: false,
+		},
+		{
+			name:            "FloatEqInt",
+			typ:             parquet.FloatType,
+			min:             parquet.FloatValue(1),
+			max:             parquet.FloatValue(2.5),
+			right:           parquet.Int64Value(2),
+			op:              logicalplan.OpEq,
+			expectSatisfies: true,
+		},
+		{
+			name:            "FloatInexactDouble",
+			typ:             parquet.FloatType,
+			min:             parquet.FloatValue(1),
+			max:             parquet.FloatValue(2.5),
+			right:           parquet.DoubleValue(0.1),
+			op:              logicalplan.OpLt,
+			expectSatisfies: true,
+		},
+		{
+			name:            "DoubleLtInt",
+			typ:             parquet.DoubleType,
+			min:             parquet.DoubleValue(1),
+			max:             parquet.DoubleValue(2.5),
+			right:           parquet.Int64Value(1),
+			op:              logicalplan.OpLt,
+			expectSatisfies: false,
+		},
+		{
+			name:            "DecimalGtInt",
+			typ:             decimal,
+			min:             parquet.Int64Value(150),
+			max:             parquet.Int64Value(350),
+			right:           parquet.Int64Value(3),
+			op:              logicalplan.OpGt,
+			expectSatisfies: true,
+		},
+		{
+			name:            "DecimalGtDouble",
+			typ:             decimal,
+			min:             parquet.Int64Value(150),
+			max:             parquet.Int64Value(350),
+			right:           parquet.DoubleValue(3.5),
+			op:              logicalplan.OpGt,
+			expectSatisfies: false,
+		},
+		{
+			name:            "DecimalBeyondScale",
+			typ:             decimal,
+			min:             parquet.Int64Value(150),
+			max:             parquet.Int64Value(350),
+			right:           parquet.DoubleValue(3.499),
+			op:              logicalplan.OpGt,
+			expectSatisfies: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeChunk := &FakeColumnChunk{
+				typ: tc.typ,
+				index: &FakeColumnIndex{
+					numPages: 1,
+					min:      tc.min,
+					max:      tc.max,
+				},
+				numValues: 10,
+			}
+			res, err := BinaryScalarOperation(fakeChunk, tc.right, tc.op)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectSatisfies, res)
+		})
+	}
+}
@@ -44,6 +44,7 @@ func newFakeParticulate(columnNames []string, maxValues []int64) fakeParticulate
 			maxV = parquet.ValueOf(v)
 		}
 		columnChunks[i] = &FakeColumnChunk{
+			typ: parquet.Int64Type,
 			index: &FakeColumnIndex{
 				numPages:  1,
 				min:       parquet.Value{},
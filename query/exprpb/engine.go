@@ -137,7 +137,31 @@ func (qb ProtoQueryBuilder) planFromProto(plan *pb.PlanNode) (logicalplan.Builde
 			aggExprs = append(aggExprs, expr.(*logicalplan.AggregationFunction))
 		}
 
-		b.Aggregate(aggExprs, groupExprs)
+		b = b.Aggregate(aggExprs, groupExprs)
+	case plan.GetSpec().GetJoin() != nil:
+		join := plan.GetSpec().GetJoin()
+		var joinType logicalplan.JoinType
+		switch join.GetType() {
+		case pb.Join_TYPE_INNER:
+			joinType = logicalplan.JoinTypeInner
+		case pb.Join_TYPE_LEFT:
+			joinType = logicalplan.JoinTypeLeft
+		default:
+			return b, fmt.Errorf("unsupported join type: %s", join.GetType())
+		}
+		right, err := qb.planFromProto(join.GetRight())
+		if err != nil {
+			return b, fmt.Errorf("failed to convert right side of join from proto: %v", err)
+		}
+		leftKeys, err := ExprsFromProtos(join.GetLeftKeys())
+		if err != nil {
+			return b, fmt.Errorf("failed to convert exprs from proto: %v", err)
+		}
+		rightKeys, err := ExprsFromProtos(join.GetRightKeys())
+		if err != nil {
+			return b, fmt.Errorf("failed to convert exprs from proto: %v", err)
+		}
+		b = b.Join(right, joinType, leftKeys, rightKeys)
 	}
 
 	return b, nil
@@ -11,6 +11,7 @@ import (
 	"github.com/youscentia/ydb-frostdb/dynparquet"
 	pb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/storage/v1alpha1"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	"github.com/youscentia/ydb-frostdb/query/physicalplan"
 )
 
 func TestPlan(t *testing.T) {
@@ -45,6 +46,14 @@ func TestPlan(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `SchemaScan [concurrent] - Synchronizer`, explain)
 
+	builder, err = NewEngine(memory.NewGoAllocator(), &mockTableProvider{
+		schema: dynparquet.NewSampleSchema(),
+	}, WithPhysicalplanOptions(physicalplan.WithConcurrency(1))).FromProto(protoPlan)
+	require.NoError(t, err)
+	explain, err = builder.Explain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, `SchemaScan`, explain)
+
 	// next plan
 
 	protoPlan = &pb.PlanNode{
@@ -90,6 +99,65 @@ func TestPlan(t *testing.T) {
 	explain, err = builder.Explain(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, `TableScan [concurrent] - Projection (foo) - Synchronizer`, explain)
+
+	// next plan
+
+	column := func(name string) *pb.Expr {
+		return &pb.Expr{
+			Def: &pb.ExprDef{
+				Content: &pb.ExprDef_Column{
+					Column: &pb.Column{
+						Name: name,
+					},
+				},
+			},
+		}
+	}
+	tableScan := func(table string) *pb.PlanNode {
+		return &pb.PlanNode{
+			Spec: &pb.PlanNodeSpec{
+				Spec: &pb.PlanNodeSpec_TableScan{
+					TableScan: &pb.TableScan{
+						Base: &pb.ScanBase{
+							Table: table,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	protoPlan = &pb.PlanNode{
+		Spec: &pb.PlanNodeSpec{
+			Spec: &pb.PlanNodeSpec_Join{
+				Join: &pb.Join{
+					Type:      pb.Join_TYPE_LEFT,
+					Right:     tableScan("baz"),
+					LeftKeys:  []*pb.Expr{column("labels.label1")},
+					RightKeys: []*pb.Expr{column("labels.label2")},
+				},
+			},
+		},
+		Next: tableScan("bar"),
+	}
+
+	builder, err = engine.FromProto(protoPlan)
+	require.NoError(t, err)
+
+	require.Equal(t, logicalplan.JoinTypeLeft, builder.LogicalPlan.Join.Type)
+	require.Equal(t, "bar", builder.LogicalPlan.Input.TableScan.TableName)
+	require.Equal(t, "baz", builder.LogicalPlan.Join.Right.TableScan.TableName)
+	require.Equal(t, []logicalplan.Expr{&logicalplan.Column{ColumnName: "labels.label1"}}, builder.LogicalPlan.Join.LeftKeys)
+	require.Equal(t, []logicalplan.Expr{&logicalplan.Column{ColumnName: "labels.label2"}}, builder.LogicalPlan.Join.RightKeys)
+
+	err = builder.Execute(context.Background(), func(_ context.Context, _ arrow.Record) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	explain, err = builder.Explain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, `TableScan [concurrent] - HashJoin[left] (labels.label1=labels.label2) Build: (TableScan [concurrent] - Synchronizer) - Synchronizer`, explain)
 }
 
 type mockTableReader struct {
@@ -12,6 +12,12 @@ type Builder struct {
 	err  error
 }
 
+// FromPlan returns a Builder that continues building on top of the given
+// plan.
+func FromPlan(plan *LogicalPlan) Builder {
+	return Builder{plan: plan}
+}
+
 func (b Builder) Scan(
 	provider TableProvider,
 	tableName string,
@@ -149,6 +155,47 @@ func (b Builder) Limit(expr Expr) Builder {
 	}
 }
 
+// OrderBy sorts the rows of the input by the given expressions. Rows that
+// compare equal by the first expression are ordered by the second one and so
+// on.
+func (b Builder) OrderBy(exprs ...*SortExpr) Builder {
+	if len(exprs) == 0 {
+		return b
+	}
+
+	return Builder{
+		err: b.err,
+		plan: &LogicalPlan{
+			Input: b.plan,
+			Sort: &Sort{
+				Exprs: exprs,
+			},
+		},
+	}
+}
+
+// Join joins the rows of the plan with the rows of the right plan for which
+// each of the leftKeys is equal to the corresponding rightKeys.
+func (b Builder) Join(right Builder, joinType JoinType, leftKeys, rightKeys []Expr) Builder {
+	err := b.err
+	if err == nil {
+		err = right.err
+	}
+
+	return Builder{
+		err: err,
+		plan: &LogicalPlan{
+			Input: b.plan,
+			Join: &Join{
+				Type:      joinType,
+				Right:     right.plan,
+				LeftKeys:  leftKeys,
+				RightKeys: rightKeys,
+			},
+		},
+	}
+}
+
 func (b Builder) Aggregate(
 	aggExpr []*AggregationFunction,
 	groupExprs []Expr,
@@ -610,7 +610,14 @@ func (e *LiteralExpr) Computed() bool {
 	return false
 }
 
+// Literal returns a literal expression of the given value. time.Time values
+// are nanosecond timestamps.
 func Literal(v interface{}) *LiteralExpr {
+	if t, ok := v.(time.Time); ok {
+		return &LiteralExpr{
+			Value: scalar.NewTimestampScalar(arrow.Timestamp(t.UnixNano()), arrow.FixedWidthTypes.Timestamp_ns),
+		}
+	}
 	return &LiteralExpr{
 		Value: scalar.MakeScalar(v),
 	}
@@ -671,6 +678,9 @@ func (f *AggregationFunction) Clone() Expr {
 }
 
 func (f *AggregationFunction) DataType(l ExprTypeFinder) (arrow.DataType, error) {
+	if f.Func == AggFuncCount {
+		return arrow.PrimitiveTypes.Int64, nil
+	}
 	return f.Expr.DataType(l)
 }
 
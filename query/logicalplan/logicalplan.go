@@ -2,6 +2,7 @@ package logicalplan
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -26,6 +27,8 @@ type LogicalPlan struct {
 	Aggregation *Aggregation
 	Limit       *Limit
 	Sample      *Sample
+	Sort        *Sort
+	Join        *Join
 }
 
 // Callback is a function that is called throughout a chain of operators
@@ -92,6 +95,14 @@ func (plan *LogicalPlan) string(indent int) string {
 		res = plan.Aggregation.String()
 	case plan.Distinct != nil:
 		res = plan.Distinct.String()
+	case plan.Limit != nil:
+		res = plan.Limit.String()
+	case plan.Sample != nil:
+		res = plan.Sample.String()
+	case plan.Sort != nil:
+		res = plan.Sort.String()
+	case plan.Join != nil:
+		res = plan.Join.String()
 	default:
 		res = "Unknown LogicalPlan"
 	}
@@ -100,6 +111,9 @@ func (plan *LogicalPlan) string(indent int) string {
 	if plan.Input != nil {
 		res += "\n" + plan.Input.string(indent+1)
 	}
+	if plan.Join != nil && plan.Join.Right != nil {
+		res += "\n" + plan.Join.Right.string(indent+1)
+	}
 	return res
 }
 
@@ -148,6 +162,14 @@ func (plan *LogicalPlan) DataTypeForExpr(expr Expr) (arrow.DataType, error) {
 			return agg.Expr.DataType(plan.Input)
 		}
 
+		// The output columns of the aggregations can be referred to by
+		// their name.
+		for _, agg := range plan.Aggregation.AggExprs {
+			if agg.Name() == expr.Name() {
+				return agg.DataType(plan.Input)
+			}
+		}
+
 		t, err := expr.DataType(plan.Input)
 		if err != nil {
 			return nil, fmt.Errorf("data type for expr %v within Aggregation: %w", expr, err)
@@ -167,6 +189,26 @@ func (plan *LogicalPlan) DataTypeForExpr(expr Expr) (arrow.DataType, error) {
 			return nil, fmt.Errorf("data type for expr %v within Sample: %w", expr, err)
 		}
 
+		return t, nil
+	case plan.Sort != nil:
+		t, err := expr.DataType(plan.Input)
+		if err != nil {
+			return nil, fmt.Errorf("data type for expr %v within Sort: %w", expr, err)
+		}
+
+		return t, nil
+	case plan.Join != nil:
+		// Columns of the left input take precedence over columns of the
+		// right input with the same name.
+		t, err := expr.DataType(plan.Input)
+		if err == nil {
+			return t, nil
+		}
+		t, rightErr := expr.DataType(plan.Join.Right)
+		if rightErr != nil {
+			return nil, fmt.Errorf("data type for expr %v within Join: %w", expr, errors.Join(err, rightErr))
+		}
+
 		return t, nil
 	default:
 		return nil, fmt.Errorf("unknown logical plan")
@@ -219,6 +261,8 @@ func (plan *LogicalPlan) Accept(visitor PlanVisitor) bool {
 }
 
 type TableReader interface {
+	// View calls fn with the txn to read the table at. It is the txn set on
+	// the context with WithReadTx, if any, and the latest one otherwise.
 	View(ctx context.Context, fn func(ctx context.Context, tx uint64) error) error
 	Iterator(
 		ctx context.Context,
@@ -240,6 +284,20 @@ type TableProvider interface {
 	GetTable(name string) (TableReader, error)
 }
 
+type readTxKey struct{}
+
+// WithReadTx returns a context that makes TableReaders read at the given txn
+// rather than at the latest one.
+func WithReadTx(ctx context.Context, tx uint64) context.Context {
+	return context.WithValue(ctx, readTxKey{}, tx)
+}
+
+// ReadTx returns the txn set on the context with WithReadTx, if any.
+func ReadTx(ctx context.Context) (uint64, bool) {
+	tx, ok := ctx.Value(readTxKey{}).(uint64)
+	return tx, ok
+}
+
 type TableScan struct {
 	TableProvider TableProvider
 	TableName     string
@@ -261,6 +319,9 @@ type TableScan struct {
 
 	// ReadMode indicates the mode to use when reading.
 	ReadMode ReadMode
+
+	// ReadTx is the txn to read the table at, zero to read at the latest one.
+	ReadTx uint64
 }
 
 func (scan *TableScan) DataTypeForExpr(expr Expr) (arrow.DataType, error) {
@@ -363,6 +424,9 @@ type SchemaScan struct {
 
 	// ReadMode indicates the mode to use when reading.
 	ReadMode ReadMode
+
+	// ReadTx is the txn to read the table at, zero to read at the latest one.
+	ReadTx uint64
 }
 
 func (s *SchemaScan) String() string {
@@ -431,3 +495,119 @@ type Sample struct {
 func (s *Sample) String() string {
 	return "Sample" + " Expr: " + fmt.Sprint(s.Expr)
 }
+
+// SortDirection is the direction in which a SortExpr orders its rows.
+type SortDirection int
+
+const (
+	// Ascending orders rows from the smallest to the largest value.
+	Ascending SortDirection = iota
+	// Descending orders rows from the largest to the smallest value.
+	Descending
+)
+
+func (d SortDirection) String() string {
+	switch d {
+	case Ascending:
+		return "asc"
+	case Descending:
+		return "desc"
+	default:
+		return "unknown"
+	}
+}
+
+// SortExpr describes an expression to order rows by, the direction of the
+// ordering and where rows for which the expression is null are placed.
+type SortExpr struct {
+	Expr       Expr
+	Direction  SortDirection
+	NullsFirst bool
+}
+
+// Asc returns a SortExpr ordering rows by expr in ascending order.
+func Asc(expr Expr) *SortExpr {
+	return &SortExpr{Expr: expr, Direction: Ascending}
+}
+
+// Desc returns a SortExpr ordering rows by expr in descending order.
+func Desc(expr Expr) *SortExpr {
+	return &SortExpr{Expr: expr, Direction: Descending}
+}
+
+func (e *SortExpr) String() string {
+	nulls := "nulls last"
+	if e.NullsFirst {
+		nulls = "nulls first"
+	}
+	return fmt.Sprintf("%s %s %s", e.Expr, e.Direction, nulls)
+}
+
+type Sort struct {
+	Exprs []*SortExpr
+}
+
+func (s *Sort) String() string {
+	return "Sort" + " Exprs: " + fmt.Sprint(s.Exprs)
+}
+
+// JoinType is the type of a Join.
+type JoinType int
+
+const (
+	// JoinTypeInner only emits the rows of the left input that have a
+	// matching row in the right input.
+	JoinTypeInner JoinType = iota
+	// JoinTypeLeft emits all rows of the left input. Rows without a matching
+	// row in the right input have all right columns set to null.
+	JoinTypeLeft
+)
+
+func (t JoinType) String() string {
+	switch t {
+	case JoinTypeInner:
+		return "inner"
+	case JoinTypeLeft:
+		return "left"
+	default:
+		return "unknown"
+	}
+}
+
+// Join combines each row of the plan's input (the left side) with each row of
+// the Right plan for which all LeftKeys are equal to the corresponding
+// RightKeys. Rows with a null key never match. The output contains the
+// columns of the left input followed by the columns of the right input,
+// except for right key columns with the same name as the left key column they
+// are compared to, which are dropped. Any other right column with the same
+// name as a left column fails the join, alias it in the right plan to keep
+// it.
+type Join struct {
+	Type      JoinType
+	Right     *LogicalPlan
+	LeftKeys  []Expr
+	RightKeys []Expr
+}
+
+// SharedKey returns whether the column with the given name is both a left key
+// and the right key it is compared to. The right column is dropped from the
+// output of the join rather than conflicting with the left column.
+func (j *Join) SharedKey(name string) bool {
+	for i := range j.LeftKeys {
+		left, ok := j.LeftKeys[i].(*Column)
+		if !ok || left.ColumnName != name {
+			continue
+		}
+		if right, ok := j.RightKeys[i].(*Column); ok && right.ColumnName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Join) String() string {
+	return "Join" +
+		" Type: " + j.Type.String() +
+		" LeftKeys: " + fmt.Sprint(j.LeftKeys) +
+		" RightKeys: " + fmt.Sprint(j.RightKeys)
+}
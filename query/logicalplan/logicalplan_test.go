@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
 
@@ -69,6 +70,35 @@ func TestInputSchemaGetter(t *testing.T) {
 	require.Equal(t, schema, plan.InputSchema())
 }
 
+func TestCountDataType(t *testing.T) {
+	schema, err := dynparquet.SchemaFromDefinition(dynparquet.SampleDefinitionWithFloat())
+	require.NoError(t, err)
+
+	plan, err := (&Builder{}).
+		Scan(&mockTableProvider{schema}, "table1").
+		Aggregate(
+			[]*AggregationFunction{Count(Col("floatvalue"))},
+			[]Expr{Col("stacktrace")},
+		).
+		Project(Col("stacktrace"), Count(Col("floatvalue")).Alias("n")).
+		Build()
+	require.NoError(t, err)
+
+	// The count of a float column is an int64, no matter if it is
+	// resolved through the aggregation or through an alias above it.
+	typ, err := plan.Input.DataTypeForExpr(Count(Col("floatvalue")))
+	require.NoError(t, err)
+	require.Equal(t, arrow.PrimitiveTypes.Int64, typ)
+
+	typ, err = plan.DataTypeForExpr(Col("n"))
+	require.NoError(t, err)
+	require.Equal(t, arrow.PrimitiveTypes.Int64, typ)
+
+	typ, err = Count(Col("floatvalue")).Alias("n").DataType(plan.Input)
+	require.NoError(t, err)
+	require.Equal(t, arrow.PrimitiveTypes.Int64, typ)
+}
+
 func Test_ExprClone(t *testing.T) {
 	expr := Col("labels.test").Eq(Literal("abc"))
 	expr2 := expr.Clone()
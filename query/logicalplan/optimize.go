@@ -65,6 +65,31 @@ func (p *PhysicalProjectionPushDown) optimize(plan *LogicalPlan, columnsUsedExpr
 		}
 		p.defaultProjections = []Expr{}
 		columnsUsedExprs = append(columnsUsedExprs, DynCol(hashedMatch))
+	case plan.Sort != nil:
+		// A sort only needs its columns to be read in addition to the ones
+		// used by a projecting plan further up. Without one, all columns are
+		// read anyway.
+		if columnsUsedExprs != nil {
+			for _, expr := range plan.Sort.Exprs {
+				columnsUsedExprs = append(columnsUsedExprs, expr.Expr.ColumnsUsedExprs()...)
+			}
+		}
+	case plan.Join != nil:
+		// Columns used further up may come from either side of the join, so
+		// both sides read them in addition to their own join keys. Without a
+		// projecting plan further up, all columns are read anyway.
+		var rightColumnsUsedExprs []Expr
+		if columnsUsedExprs != nil {
+			rightColumnsUsedExprs = append([]Expr{}, columnsUsedExprs...)
+			for _, key := range plan.Join.RightKeys {
+				rightColumnsUsedExprs = append(rightColumnsUsedExprs, key.ColumnsUsedExprs()...)
+			}
+			for _, key := range plan.Join.LeftKeys {
+				columnsUsedExprs = append(columnsUsedExprs, key.ColumnsUsedExprs()...)
+			}
+		}
+		right := &PhysicalProjectionPushDown{defaultProjections: p.defaultProjections}
+		right.optimize(plan.Join.Right, rightColumnsUsedExprs)
 	}
 
 	if plan.Input != nil {
@@ -97,6 +122,12 @@ func (p *FilterPushDown) optimize(plan *LogicalPlan, exprs []Expr) {
 		}
 	case plan.Filter != nil:
 		exprs = append(exprs, plan.Filter.Expr)
+	case plan.Join != nil:
+		// Filters further up may apply to columns of the right side of the
+		// join, or to the nulls a left join emits, so they aren't pushed
+		// through it.
+		exprs = nil
+		p.optimize(plan.Join.Right, nil)
 	}
 
 	if plan.Input != nil {
@@ -146,6 +177,9 @@ func (p *DistinctPushDown) optimize(plan *LogicalPlan, distinctColumns []Expr) {
 			// to reset it in this case.
 			distinctColumns = []Expr{}
 		}
+	case plan.Join != nil:
+		distinctColumns = []Expr{}
+		p.optimize(plan.Join.Right, nil)
 	default:
 		// reset distinct columns
 		distinctColumns = []Expr{}
@@ -181,6 +215,9 @@ func (p *AggFuncPushDown) optimize(plan *LogicalPlan, filterExpr Expr) {
 			// TODO(asubiotto): Should we make this less specific?
 			filterExpr = plan.Aggregation.AggExprs[0]
 		}
+	case plan.Join != nil:
+		filterExpr = nil
+		p.optimize(plan.Join.Right, nil)
 	default:
 		// If we find anything other than a table scan after a global
 		// aggregation, bail out by setting the filterExpr to nil.
@@ -7,7 +7,7 @@ import (
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/scalar"
-	"github.com/parquet-go/parquet-go/format"
+	"github.com/parquet-go/parquet-go"
 )
 
 // PlanValidationError is the error representing a logical plan that is not valid.
@@ -79,6 +79,10 @@ func Validate(plan *LogicalPlan) error {
 			err = nil
 		case plan.Aggregation != nil:
 			err = ValidateAggregation(plan)
+		case plan.Sort != nil:
+			err = ValidateSort(plan)
+		case plan.Join != nil:
+			err = ValidateJoin(plan)
 		}
 	}
 
@@ -125,10 +129,16 @@ func ValidateSingleFieldSet(plan *LogicalPlan) *PlanValidationError {
 	if plan.Sample != nil {
 		fieldsSet = append(fieldsSet, 7)
 	}
+	if plan.Sort != nil {
+		fieldsSet = append(fieldsSet, 8)
+	}
+	if plan.Join != nil {
+		fieldsSet = append(fieldsSet, 9)
+	}
 
 	if len(fieldsSet) != 1 {
 		fieldsFound := make([]string, 0)
-		fields := []string{"SchemaScan", "TableScan", "Filter", "Distinct", "Projection", "Aggregation", "Limit", "Sample"}
+		fields := []string{"SchemaScan", "TableScan", "Filter", "Distinct", "Projection", "Aggregation", "Limit", "Sample", "Sort", "Join"}
 		for _, i := range fieldsSet {
 			fieldsFound = append(fieldsFound, fields[i])
 		}
@@ -301,6 +311,174 @@ func ValidateAggregationExpr(plan *LogicalPlan) *ExprValidationError {
 	return nil
 }
 
+// ValidateSort validates the logical plan's sort step.
+func ValidateSort(plan *LogicalPlan) *PlanValidationError {
+	if len(plan.Sort.Exprs) == 0 {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid sort: at least one expression is required",
+		}
+	}
+
+	for _, e := range plan.Sort.Exprs {
+		if e == nil || e.Expr == nil {
+			return &PlanValidationError{
+				plan:    plan,
+				message: "invalid sort: expression cannot be nil",
+			}
+		}
+	}
+
+	if plan.Input == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid sort: an input is required",
+		}
+	}
+
+	// Sorting by an expression that doesn't resolve against the input would
+	// silently leave the rows unsorted.
+	for _, e := range plan.Sort.Exprs {
+		if _, err := e.Expr.DataType(plan.Input); err != nil {
+			return &PlanValidationError{
+				plan:    plan,
+				message: "invalid sort",
+				children: []*ExprValidationError{{
+					expr:    e.Expr,
+					message: fmt.Errorf("sort expression doesn't match the input: %w", err).Error(),
+				}},
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateJoin validates the logical plan's join step, including its right
+// plan.
+func ValidateJoin(plan *LogicalPlan) *PlanValidationError {
+	join := plan.Join
+	if join.Type != JoinTypeInner && join.Type != JoinTypeLeft {
+		return &PlanValidationError{
+			plan:    plan,
+			message: fmt.Sprintf("invalid join: unsupported join type %d", join.Type),
+		}
+	}
+
+	if plan.Input == nil || join.Right == nil {
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid join: both a left and a right input are required",
+		}
+	}
+
+	if len(join.LeftKeys) == 0 || len(join.LeftKeys) != len(join.RightKeys) {
+		return &PlanValidationError{
+			plan: plan,
+			message: fmt.Sprintf(
+				"invalid join: expected the same non-zero number of left and right keys, found %d left and %d right keys",
+				len(join.LeftKeys), len(join.RightKeys),
+			),
+		}
+	}
+
+	for i := range join.LeftKeys {
+		if err := validateJoinKeys(plan, join.LeftKeys[i], join.RightKeys[i]); err != nil {
+			return &PlanValidationError{
+				plan:     plan,
+				message:  "invalid join",
+				children: []*ExprValidationError{err},
+			}
+		}
+	}
+
+	if err := Validate(join.Right); err != nil {
+		rightErr, ok := err.(*PlanValidationError)
+		if !ok {
+			// if we are here it is a bug in the code
+			panic(fmt.Sprintf("Unexpected error: %v expected a PlanValidationError", err))
+		}
+		return &PlanValidationError{
+			plan:    plan,
+			message: "invalid join: invalid right input",
+			input:   rightErr,
+		}
+	}
+
+	return nil
+}
+
+// validateJoinKeys validates that a pair of join keys are columns of
+// comparable types.
+func validateJoinKeys(plan *LogicalPlan, left, right Expr) *ExprValidationError {
+	for _, key := range []Expr{left, right} {
+		if _, ok := key.(*Column); !ok {
+			return &ExprValidationError{
+				expr:    key,
+				message: "join key must be a column",
+			}
+		}
+	}
+
+	leftType, err := left.DataType(plan.Input)
+	if err != nil {
+		return &ExprValidationError{
+			expr:    left,
+			message: fmt.Errorf("get type of left join key: %w", err).Error(),
+		}
+	}
+	rightType, err := right.DataType(plan.Join.Right)
+	if err != nil {
+		return &ExprValidationError{
+			expr:    right,
+			message: fmt.Errorf("get type of right join key: %w", err).Error(),
+		}
+	}
+
+	leftKind, rightKind := joinKeyKindOf(leftType), joinKeyKindOf(rightType)
+	if leftKind == joinKeyKindUnsupported {
+		return &ExprValidationError{
+			expr:    left,
+			message: fmt.Sprintf("join key type %s is not supported", leftType),
+		}
+	}
+	if leftKind != rightKind {
+		return &ExprValidationError{
+			expr:    right,
+			message: fmt.Sprintf("join key type %s is not comparable to %s", rightType, leftType),
+		}
+	}
+
+	return nil
+}
+
+// joinKeyKind classifies the types of join keys.
+type joinKeyKind int
+
+const (
+	joinKeyKindUnsupported joinKeyKind = iota
+	joinKeyKindBinary
+	joinKeyKindInteger
+	joinKeyKindBoolean
+)
+
+// joinKeyKindOf returns the kind of values of the given type. Join keys of the
+// same kind can be compared with each other.
+func joinKeyKindOf(t arrow.DataType) joinKeyKind {
+	switch t := t.(type) {
+	case *arrow.StringType, *arrow.BinaryType:
+		return joinKeyKindBinary
+	case *arrow.Int64Type, *arrow.Uint64Type:
+		return joinKeyKindInteger
+	case *arrow.BooleanType:
+		return joinKeyKindBoolean
+	case *arrow.DictionaryType:
+		return joinKeyKindOf(t.ValueType)
+	default:
+		return joinKeyKindUnsupported
+	}
+}
+
 // ValidateInput validates that the current logical plans input is valid.
 // It returns nil if the plan has no input.
 func ValidateInput(plan *LogicalPlan) *PlanValidationError {
@@ -370,7 +548,7 @@ func ValidateFilterBinaryExpr(plan *LogicalPlan, expr *BinaryExpr) *ExprValidati
 				// ensure that the column type is compatible with the literal being compared to it
 				t := column.StorageLayout.Type()
 				literalExpr := rightLiteralFinder.result.(*LiteralExpr)
-				if err := ValidateComparingTypes(t.LogicalType(), literalExpr.Value); err != nil {
+				if err := ValidateComparingTypes(t, literalExpr.Value); err != nil {
 					err.expr = expr
 					return err
 				}
@@ -382,8 +560,17 @@ func ValidateFilterBinaryExpr(plan *LogicalPlan, expr *BinaryExpr) *ExprValidati
 }
 
 // ValidateComparingTypes validates if the types being compared by a binary expression are compatible.
-func ValidateComparingTypes(columnType *format.LogicalType, literal scalar.Scalar) *ExprValidationError {
+func ValidateComparingTypes(t parquet.Type, literal scalar.Scalar) *ExprValidationError {
+	columnType := t.LogicalType()
 	switch {
+	// float and binary columns don't have a logical type
+	case columnType == nil && (t.Kind() == parquet.Float || t.Kind() == parquet.Double):
+		if _, ok := literal.(*scalar.String); ok {
+			return &ExprValidationError{
+				message: "incompatible types: numeric column cannot be compared with string literal",
+			}
+		}
+	case columnType == nil && t.Kind() == parquet.ByteArray:
 	// if the columns logical type is nil, it may be of type bool
 	case columnType == nil:
 		switch t := literal.(type) {
@@ -408,6 +595,15 @@ func ValidateComparingTypes(columnType *format.LogicalType, literal scalar.Scala
 				message: "incompatible types: string column cannot be compared with numeric literal",
 			}
 		}
+	// timestamp columns are compared with timestamps or nanoseconds since the epoch
+	case columnType.Timestamp != nil:
+		switch literal.(type) {
+		case *scalar.Timestamp, *scalar.Int64, *scalar.Uint64, *scalar.Float64, *scalar.Null:
+		default:
+			return &ExprValidationError{
+				message: fmt.Sprintf("incompatible types: timestamp column cannot be compared with %v", literal.DataType()),
+			}
+		}
 	// if the column is a numeric type, it shouldn't be compared to a string
 	case columnType.Integer != nil:
 		switch literal.(type) {
@@ -3,10 +3,12 @@ package logicalplan
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/youscentia/ydb-frostdb/dynparquet"
+	schemapb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha1"
 )
 
 func TestOnlyOneFieldCanBeSet(t *testing.T) {
@@ -169,6 +171,45 @@ func TestFilterBinaryExprColMustMatchLiteralType(t *testing.T) {
 	require.True(t, strings.HasPrefix(exprErr.message, "incompatible types"))
 }
 
+func TestFilterBinaryExprTimestampColMustMatchLiteralType(t *testing.T) {
+	schema, err := dynparquet.SchemaFromDefinition(&schemapb.Schema{
+		Name: "test",
+		Columns: []*schemapb.Column{{
+			Name: "ts",
+			StorageLayout: &schemapb.StorageLayout{
+				Type: schemapb.StorageLayout_TYPE_TIMESTAMP,
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	for _, literal := range []Expr{Literal(int64(1)), Literal(1.5), Literal(time.Unix(0, 1))} {
+		_, err := (&Builder{}).
+			Scan(&mockTableProvider{schema}, "table1").
+			Filter(&BinaryExpr{
+				Left:  Col("ts"),
+				Op:    OpGt,
+				Right: literal,
+			}).
+			Build()
+		require.NoError(t, err)
+	}
+
+	_, err = (&Builder{}).
+		Scan(&mockTableProvider{schema}, "table1").
+		Filter(&BinaryExpr{
+			Left:  Col("ts"),
+			Op:    OpGt,
+			Right: Literal("garbage"),
+		}).
+		Build()
+	require.NotNil(t, err)
+	planErr, ok := err.(*PlanValidationError)
+	require.True(t, ok)
+	require.Len(t, planErr.children, 1)
+	require.True(t, strings.HasPrefix(planErr.children[0].message, "incompatible types: timestamp column"))
+}
+
 func TestFilterAndExprEvaluatesEachAndedRule(t *testing.T) {
 	_, err := (&Builder{}).
 		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
@@ -205,3 +246,42 @@ func TestFilterAndExprEvaluatesEachAndedRule(t *testing.T) {
 	rightErr := exprErr.children[1]
 	require.True(t, strings.HasPrefix(rightErr.message, "left side of binary expression must be a column"))
 }
+
+func TestJoinKeysMustBeComparable(t *testing.T) {
+	right := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table2")
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Join(right, JoinTypeInner, []Expr{Col("example_type")}, []Expr{Col("timestamp")}).
+		Build()
+
+	require.NotNil(t, err)
+	planErr, ok := err.(*PlanValidationError)
+	require.True(t, ok)
+	require.True(t, strings.HasPrefix(planErr.message, "invalid join"))
+	require.Len(t, planErr.children, 1)
+	require.Contains(t, planErr.children[0].message, "is not comparable to")
+}
+
+func TestSortExprMustMatchInput(t *testing.T) {
+	_, err := (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		OrderBy(Asc(Col("timestamp")), Desc(Col("no_such_column"))).
+		Build()
+
+	require.NotNil(t, err)
+	planErr, ok := err.(*PlanValidationError)
+	require.True(t, ok)
+	require.True(t, strings.HasPrefix(planErr.message, "invalid sort"))
+	require.Len(t, planErr.children, 1)
+	require.Contains(t, planErr.children[0].message, "no_such_column")
+
+	// Concrete columns of dynamic columns and the outputs of aggregations
+	// resolve.
+	_, err = (&Builder{}).
+		Scan(&mockTableProvider{dynparquet.NewSampleSchema()}, "table1").
+		Aggregate([]*AggregationFunction{Count(Col("value"))}, []Expr{Col("labels.label1")}).
+		OrderBy(Asc(Col("labels.label1")), Desc(Col("count(value)"))).
+		Build()
+	require.NoError(t, err)
+}
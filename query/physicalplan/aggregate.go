@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"hash/maphash"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -109,6 +110,69 @@ type Aggregation struct {
 	arrays     []builder.ColumnBuilder // TODO: These can actually live outside this struct and be shared. Only at the very end will they be read by each column and then aggregated separately.
 }
 
+// groupByDuration returns the bucket size if the given group by expression
+// buckets the timestamp column by a duration.
+func groupByDuration(expr logicalplan.Expr) (time.Duration, bool) {
+	d, ok := expr.(*logicalplan.DurationExpr)
+	if !ok {
+		return 0, false
+	}
+	return d.Value(), true
+}
+
+// bucketTimestamps returns a new array where every timestamp is floored to the
+// start of the bucket of size d it falls into. Int64 timestamps are
+// interpreted as nanoseconds, timestamp arrays use the unit of their type.
+func bucketTimestamps(pool memory.Allocator, arr arrow.Array, d time.Duration) (arrow.Array, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("invalid duration for time bucketing: %s", d)
+	}
+
+	switch arr := arr.(type) {
+	case *array.Int64:
+		b := array.NewInt64Builder(pool)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.UnsafeAppend(floorToBucket(arr.Value(i), int64(d)))
+		}
+		return b.NewArray(), nil
+	case *array.Timestamp:
+		typ := arr.DataType().(*arrow.TimestampType)
+		bucket := int64(d / typ.Unit.Multiplier())
+		if bucket <= 0 {
+			return nil, fmt.Errorf("duration %s is smaller than the timestamp unit %s", d, typ.Unit)
+		}
+		b := array.NewTimestampBuilder(pool, typ)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.UnsafeAppend(arrow.Timestamp(floorToBucket(int64(arr.Value(i)), bucket)))
+		}
+		return b.NewArray(), nil
+	default:
+		return nil, fmt.Errorf("unsupported type for time bucketing: %s", arr.DataType())
+	}
+}
+
+// floorToBucket rounds v down to the nearest multiple of bucket, also for
+// negative values.
+func floorToBucket(v, bucket int64) int64 {
+	mod := v % bucket
+	if mod < 0 {
+		mod += bucket
+	}
+	return v - mod
+}
+
 type AggregationFunction interface {
 	Aggregate(pool memory.Allocator, arrs []arrow.Array) (arrow.Array, error)
 }
@@ -132,6 +196,15 @@ type HashAggregate struct {
 	// aggregates are the collection of all the hash aggregates for this hash aggregation. This is useful when a single hash aggregate cannot fit
 	// into a single record and needs to be split into multiple records.
 	aggregates []*hashAggregate
+	// aggregations are the aggregations the hash aggregation was created
+	// with, the state is reset to them after it has been spilled.
+	aggregations []Aggregation
+
+	// spill is set if the hash aggregation spills its state once the memory
+	// budget of the query is exceeded. The final stage writes its state to
+	// spilled and merges it partition by partition when finishing.
+	spill   *spillOptions
+	spilled *spillFiles
 }
 
 type hashtuple struct {
@@ -165,16 +238,6 @@ func NewHashAggregate(
 	seed maphash.Seed,
 	finalStage bool,
 ) *HashAggregate {
-	dynamic := []Aggregation{}
-	static := []Aggregation{}
-	for _, agg := range aggregations {
-		if agg.dynamic {
-			dynamic = append(dynamic, agg)
-		} else {
-			static = append(static, agg)
-		}
-	}
-
 	return &HashAggregate{
 		pool:   pool,
 		tracer: tracer,
@@ -187,23 +250,54 @@ func NewHashAggregate(
 		groupByFieldHashes: make([]hashCombiner, 0, 10),
 		groupByArrays:      make([]arrow.Array, 0, 10),
 		hashToAggregate:    map[uint64]hashtuple{},
-		aggregates: []*hashAggregate{ // initialize a single hash aggregate; we expect this array to only every grow during very large aggregations.
-			{
-				dynamicAggregations:          dynamic,
-				dynamicAggregationsConverted: make(map[string]struct{}),
-				aggregations:                 static,
-				concreteAggregations:         len(static),
-				groupByCols:                  map[string]builder.ColumnBuilder{},
-				colOrdering:                  []string{},
-			},
-		},
+		// initialize a single hash aggregate; we expect this array to only every grow during very large aggregations.
+		aggregates:   []*hashAggregate{newHashAggregate(aggregations)},
+		aggregations: aggregations,
+	}
+}
+
+func newHashAggregate(aggregations []Aggregation) *hashAggregate {
+	dynamic := []Aggregation{}
+	static := []Aggregation{}
+	for _, agg := range aggregations {
+		if agg.dynamic {
+			dynamic = append(dynamic, agg)
+		} else {
+			static = append(static, agg)
+		}
+	}
+
+	return &hashAggregate{
+		dynamicAggregations:          dynamic,
+		dynamicAggregationsConverted: make(map[string]struct{}),
+		aggregations:                 static,
+		concreteAggregations:         len(static),
+		groupByCols:                  map[string]builder.ColumnBuilder{},
+		colOrdering:                  []string{},
 	}
 }
 
+// spillTo makes the hash aggregation spill its state once the memory budget
+// of the given options is exceeded. Stages other than the final one don't
+// write their state to disk but pass their partial aggregations on early, as
+// the final stage merges them anyway.
+func (a *HashAggregate) spillTo(opts *spillOptions) {
+	a.spill = opts
+	a.pool = &budgetAllocator{Allocator: a.pool, budget: opts.budget}
+}
+
 func (a *HashAggregate) Close() {
 	for _, arr := range a.groupByArrays {
 		arr.Release()
 	}
+	a.releaseState()
+	if a.spilled != nil {
+		_ = a.spilled.close()
+	}
+	a.next.Close()
+}
+
+func (a *HashAggregate) releaseState() {
 	for _, aggregate := range a.aggregates {
 		for _, aggregation := range aggregate.aggregations {
 			for _, bldr := range aggregation.arrays {
@@ -214,7 +308,14 @@ func (a *HashAggregate) Close() {
 			bldr.Release()
 		}
 	}
-	a.next.Close()
+}
+
+// resetState releases the state of the hash aggregation and starts over.
+func (a *HashAggregate) resetState() {
+	a.releaseState()
+	a.spill.budget.grow(-int64(len(a.hashToAggregate)) * mapEntrySize)
+	a.hashToAggregate = map[uint64]hashtuple{}
+	a.aggregates = []*hashAggregate{newHashAggregate(a.aggregations)}
 }
 
 func (a *HashAggregate) SetNext(next PhysicalPlan) {
@@ -260,7 +361,17 @@ func (u *uint64HashCombine) hashCombine(rhs uint64) uint64 {
 	return hashCombine(u.value, rhs)
 }
 
-func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
+func (a *HashAggregate) Callback(ctx context.Context, r arrow.Record) error {
+	if err := a.aggregateRecord(ctx, r); err != nil {
+		return err
+	}
+	if a.spill != nil && a.spill.budget.exceeded() {
+		return a.spillState(ctx)
+	}
+	return nil
+}
+
+func (a *HashAggregate) aggregateRecord(_ context.Context, r arrow.Record) error {
 	// Generates high volume of spans. Comment out if needed during development.
 	// ctx, span := a.tracer.Start(ctx, "HashAggregate/Callback")
 	// defer span.End()
@@ -272,6 +383,9 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 	groupByFields := a.groupByFields
 	groupByFieldHashes := a.groupByFieldHashes
 	groupByArrays := a.groupByArrays
+	// groupByBucketed tracks which group by arrays were bucketed by time and
+	// therefore can't use pre-computed hashes of the original values.
+	groupByBucketed := make([]bool, 0, len(a.groupByColumnMatchers))
 
 	defer func() {
 		groupByFields = groupByFields[:0]
@@ -287,8 +401,19 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 		field := r.Schema().Field(i)
 		for _, matcher := range a.groupByColumnMatchers {
 			if matcher.MatchColumn(field.Name) {
+				arr := r.Column(i)
+				d, bucketed := groupByDuration(matcher)
+				if bucketed {
+					bucketedArr, err := bucketTimestamps(a.pool, arr, d)
+					if err != nil {
+						return err
+					}
+					defer bucketedArr.Release()
+					arr = bucketedArr
+				}
 				groupByFields = append(groupByFields, field)
-				groupByArrays = append(groupByArrays, r.Column(i))
+				groupByArrays = append(groupByArrays, arr)
+				groupByBucketed = append(groupByBucketed, bucketed)
 
 				if a.finalStage { // in the final stage expect the hashes to already exist, so only need to combine them as normal hashes
 					groupByFieldHashes = append(groupByFieldHashes,
@@ -384,7 +509,7 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 	colHashes := make([][]uint64, len(groupByArrays))
 	for i, arr := range groupByArrays {
 		col := dynparquet.FindHashedColumn(groupByFields[i].Name, fields)
-		if col != -1 {
+		if col != -1 && !groupByBucketed[i] {
 			vals := make([]uint64, 0, numRows)
 			for _, v := range r.Column(col).(*array.Int64).Int64Values() {
 				vals = append(vals, uint64(v))
@@ -421,6 +546,9 @@ func (a *HashAggregate) Callback(_ context.Context, r arrow.Record) error {
 			}
 			a.hashToAggregate[hash] = tuple
 			aggregate.rowCount++
+			if a.spill != nil {
+				a.spill.budget.grow(mapEntrySize)
+			}
 
 			// insert new row into columns grouped by and create new aggregate array to append to.
 			if err := a.updateGroupByCols(i, groupByArrays, groupByFields); err != nil {
@@ -529,18 +657,102 @@ func (a *HashAggregate) Finish(ctx context.Context) error {
 	span.SetAttributes(attribute.Bool("finalStage", a.finalStage))
 	defer span.End()
 
-	totalRows := 0
-	for i, aggregate := range a.aggregates {
-		if err := a.finishAggregate(ctx, i, aggregate); err != nil {
+	var (
+		totalRows int
+		err       error
+	)
+	if a.spilled != nil {
+		// Spill what is still in memory as well, so that every group is
+		// merged from a single partition.
+		if err := a.spillState(ctx); err != nil {
 			return err
 		}
-		totalRows += aggregate.rowCount
+		totalRows, err = a.mergeSpilled(ctx)
+		span.SetAttributes(attribute.Bool("spilled", true))
+	} else {
+		totalRows, err = a.finishAggregates(ctx, a.next.Callback, false)
+	}
+	if err != nil {
+		return err
 	}
 	span.SetAttributes(attribute.Int64("rows", int64(totalRows)))
 	return a.next.Finish(ctx)
 }
 
-func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggregate *hashAggregate) error {
+// finishAggregates computes the aggregations of all groups and calls emit
+// with the results. If spilling is set, the results are in the format the
+// hash aggregation consumes, so they can be merged again later. It returns
+// the number of groups.
+func (a *HashAggregate) finishAggregates(
+	ctx context.Context,
+	emit func(context.Context, arrow.Record) error,
+	spilling bool,
+) (int, error) {
+	totalRows := 0
+	for i, aggregate := range a.aggregates {
+		if err := a.finishAggregate(ctx, i, aggregate, emit, spilling); err != nil {
+			return 0, err
+		}
+		totalRows += aggregate.rowCount
+	}
+	return totalRows, nil
+}
+
+// spillState releases the state of the hash aggregation from memory. The final
+// stage writes its partial aggregations to disk partitioned by group, other
+// stages pass them on to the final stage.
+func (a *HashAggregate) spillState(ctx context.Context) error {
+	if !a.finalStage {
+		if _, err := a.finishAggregates(ctx, a.next.Callback, false); err != nil {
+			return err
+		}
+		a.resetState()
+		return nil
+	}
+
+	if a.spilled == nil {
+		spilled, err := newSpillFiles(a.pool, a.spill)
+		if err != nil {
+			return err
+		}
+		a.spilled = spilled
+	}
+	if _, err := a.finishAggregates(ctx, func(ctx context.Context, r arrow.Record) error {
+		return a.spilled.write(ctx, r, hashRows(a.hashSeed, r, a.groupByColumnMatchers))
+	}, true); err != nil {
+		return fmt.Errorf("spill aggregations: %w", err)
+	}
+	a.resetState()
+	return nil
+}
+
+// mergeSpilled aggregates the spilled partial aggregations one partition at a
+// time and emits the results. It returns the number of groups.
+func (a *HashAggregate) mergeSpilled(ctx context.Context) (int, error) {
+	totalRows := 0
+	for p := 0; p < spillPartitionCount; p++ {
+		if err := a.spilled.read(p, func(r arrow.Record) error {
+			return a.aggregateRecord(ctx, r)
+		}); err != nil {
+			return 0, fmt.Errorf("merge spilled aggregations: %w", err)
+		}
+		rows, err := a.finishAggregates(ctx, a.next.Callback, false)
+		if err != nil {
+			return 0, err
+		}
+		totalRows += rows
+		a.resetState()
+	}
+	return totalRows, a.spilled.close()
+}
+
+func (a *HashAggregate) finishAggregate(
+	ctx context.Context,
+	aggIdx int,
+	aggregate *hashAggregate,
+	emit func(context.Context, arrow.Record) error,
+	spilling bool,
+) error {
 	numCols := len(aggregate.groupByCols) + len(aggregate.aggregations)
 	numRows := aggregate.rowCount
 
@@ -612,8 +824,14 @@ func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggrega
 		}
 		groupByArrays = append(groupByArrays, aggregateArray)
 
+		name := aggregation.resultName
+		if spilling && aggregation.dynamic {
+			// Dynamic aggregations are matched by the name of the column
+			// they aggregate.
+			name = aggregation.expr.Name()
+		}
 		aggregateFields = append(aggregateFields, arrow.Field{
-			Name: aggregation.resultName,
+			Name: name,
 			Type: aggregateArray.DataType(),
 		})
 	}
@@ -624,7 +842,7 @@ func (a *HashAggregate) finishAggregate(ctx context.Context, aggIdx int, aggrega
 		int64(numRows),
 	)
 	defer r.Release()
-	err := a.next.Callback(ctx, r)
+	err := emit(ctx, r)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ import (
 	"hash/maphash"
 	"math/rand"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel/trace/noop"
 
@@ -116,3 +117,97 @@ func Test_Aggregate_ArrayOverflow(t *testing.T) {
 	require.NoError(t, agg.Finish(ctx))
 	require.Equal(t, int64(n*rows), totalRows)
 }
+
+func TestAggregateDurationBuckets(t *testing.T) {
+	ctx := context.Background()
+	pool := memory.NewGoAllocator()
+
+	groupBy := []logicalplan.Expr{logicalplan.Duration(10 * time.Second)}
+	aggregation := Aggregation{
+		expr:       logicalplan.Col("value"),
+		resultName: "sum(value)",
+		function:   logicalplan.AggFuncSum,
+	}
+
+	for _, tc := range []struct {
+		name string
+		agg  PhysicalPlan
+	}{
+		{
+			name: "hash",
+			agg: NewHashAggregate(
+				pool,
+				noop.NewTracerProvider().Tracer(""),
+				[]Aggregation{aggregation},
+				groupBy,
+				maphash.MakeSeed(),
+				false,
+			),
+		},
+		{
+			name: "ordered",
+			agg: NewOrderedAggregate(
+				pool,
+				noop.NewTracerProvider().Tracer(""),
+				aggregation,
+				groupBy,
+				false,
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := map[int64]int64{}
+			tc.agg.SetNext(&OutputPlan{
+				callback: func(_ context.Context, r arrow.Record) error {
+					timestamps := r.Column(0).(*array.Int64)
+					sums := r.Column(int(r.NumCols()) - 1).(*array.Int64)
+					for i := 0; i < int(r.NumRows()); i++ {
+						result[timestamps.Value(i)] += sums.Value(i)
+					}
+					return nil
+				},
+			})
+
+			tsBuilder := array.NewInt64Builder(pool)
+			defer tsBuilder.Release()
+			valBuilder := array.NewInt64Builder(pool)
+			defer valBuilder.Release()
+			for _, ts := range []time.Duration{
+				-1 * time.Second,
+				1 * time.Second,
+				9 * time.Second,
+				10 * time.Second,
+				19 * time.Second,
+				25 * time.Second,
+			} {
+				tsBuilder.Append(int64(ts))
+				valBuilder.Append(1)
+			}
+			ts := tsBuilder.NewArray()
+			defer ts.Release()
+			vals := valBuilder.NewArray()
+			defer vals.Release()
+
+			r := array.NewRecord(
+				arrow.NewSchema([]arrow.Field{
+					{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64},
+					{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+				}, nil),
+				[]arrow.Array{ts, vals},
+				int64(ts.Len()),
+			)
+			defer r.Release()
+
+			require.NoError(t, tc.agg.Callback(ctx, r))
+			require.NoError(t, tc.agg.Finish(ctx))
+			tc.agg.Close()
+
+			require.Equal(t, map[int64]int64{
+				int64(-10 * time.Second): 1,
+				0:                        2,
+				int64(10 * time.Second):  2,
+				int64(20 * time.Second):  1,
+			}, result)
+		})
+	}
+}
@@ -111,6 +111,16 @@ func BinaryScalarOperation(left arrow.Array, right scalar.Scalar, operator logic
 	switch leftType.(type) {
 	case *arrow.ListType:
 		panic("TODO: list comparisons unimplemented")
+	case *arrow.TimestampType:
+		// Timestamps aren't implicitly cast by the compute functions, integers
+		// are compared as nanoseconds.
+		if right.IsValid() && !arrow.TypeEqual(leftType, right.DataType()) {
+			casted, err := right.CastTo(leftType)
+			if err != nil {
+				return nil, fmt.Errorf("cast %s to %s: %w", right.DataType(), leftType, err)
+			}
+			right = casted
+		}
 	}
 
 	return ArrayScalarCompute(operator.ArrowString(), left, right)
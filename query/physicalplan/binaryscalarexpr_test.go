@@ -126,3 +126,28 @@ func Test_ArrayScalarCompute_Leak(t *testing.T) {
 	_, err := ArrayScalarCompute("equal", arr, s)
 	require.NoError(t, err)
 }
+
+func TestBinaryScalarOperationTimestamp(t *testing.T) {
+	allocator := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer allocator.AssertSize(t, 0)
+
+	ab := array.NewTimestampBuilder(allocator, arrow.FixedWidthTypes.Timestamp_ns.(*arrow.TimestampType))
+	defer ab.Release()
+
+	ab.AppendValues([]arrow.Timestamp{1, 2, 3}, nil)
+	arr := ab.NewArray()
+	defer arr.Release()
+
+	for _, s := range []scalar.Scalar{
+		scalar.NewInt64Scalar(1),
+		scalar.NewFloat64Scalar(1),
+		scalar.NewTimestampScalar(1, arrow.FixedWidthTypes.Timestamp_ns),
+	} {
+		bm, err := BinaryScalarOperation(arr, s, logicalplan.OpGt)
+		require.NoError(t, err)
+		require.Equal(t, []uint32{1, 2}, bm.ToArray())
+	}
+
+	_, err := BinaryScalarOperation(arr, scalar.NewStringScalar("not-a-time"), logicalplan.OpGt)
+	require.ErrorContains(t, err, "cast utf8 to timestamp")
+}
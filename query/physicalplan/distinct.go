@@ -10,10 +10,8 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
-	"github.com/apache/arrow-go/v18/arrow/scalar"
 	"go.opentelemetry.io/otel/trace"
 
-	"github.com/youscentia/ydb-frostdb/dynparquet"
 	"github.com/youscentia/ydb-frostdb/pqarrow/builder"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
 )
@@ -27,6 +25,17 @@ type Distinction struct {
 
 	mtx  *sync.RWMutex
 	seen map[uint64]struct{}
+
+	// spill is set if the distinction spills its state to disk once the
+	// memory budget of the query is exceeded. Once it has spilled, the hashes
+	// of the rows emitted so far are in spilledSeen. Rows received afterwards
+	// may have been emitted already, so they are kept in pending and written
+	// to spilledRows whenever the budget is exceeded again. They are
+	// deduplicated partition by partition when the distinction finishes.
+	spill       *spillOptions
+	spilledSeen *spillFiles
+	spilledRows *spillFiles
+	pending     []arrow.Record
 }
 
 func (d *Distinction) Draw() *Diagram {
@@ -59,11 +68,30 @@ func (d *Distinction) SetNext(plan PhysicalPlan) {
 	d.next = plan
 }
 
+// spillTo makes the distinction spill its state to disk once the memory
+// budget of the given options is exceeded.
+func (d *Distinction) spillTo(opts *spillOptions) {
+	d.spill = opts
+	d.pool = &budgetAllocator{Allocator: d.pool, budget: opts.budget}
+}
+
 func (d *Distinction) Finish(ctx context.Context) error {
+	if d.spilledSeen != nil {
+		// Spill the pending rows as well, so that every row is deduplicated
+		// within a single partition.
+		if err := d.spillPending(ctx); err != nil {
+			return err
+		}
+		if err := d.mergeSpilled(ctx); err != nil {
+			return err
+		}
+	}
 	return d.next.Finish(ctx)
 }
 
 func (d *Distinction) Close() {
+	d.releasePending()
+	d.closeSpilled()
 	d.next.Close()
 }
 
@@ -72,20 +100,51 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 	// ctx, span := d.tracer.Start(ctx, "Distinction/Callback")
 	// defer span.End()
 
-	distinctFields := make([]arrow.Field, 0, 10)
-	distinctFieldHashes := make([]uint64, 0, 10)
-	distinctArrays := make([]arrow.Array, 0, 10)
-
-	for i := 0; i < r.Schema().NumFields(); i++ {
-		field := r.Schema().Field(i)
-		for _, col := range d.columns {
-			if col.MatchColumn(field.Name) {
-				distinctFields = append(distinctFields, field)
-				distinctFieldHashes = append(distinctFieldHashes, scalar.Hash(d.hashSeed, scalar.NewStringScalar(field.Name)))
-				distinctArrays = append(distinctArrays, r.Column(i))
-			}
+	if d.spilledSeen != nil {
+		if err := d.buffer(r); err != nil {
+			return err
 		}
+	} else if err := d.distinct(ctx, r); err != nil {
+		return err
 	}
+	if d.spill != nil && d.spill.budget.exceeded() {
+		if d.spilledSeen == nil {
+			return d.spillSeen(ctx)
+		}
+		return d.spillPending(ctx)
+	}
+	return nil
+}
+
+// distinct emits the rows of the record that haven't been seen yet.
+func (d *Distinction) distinct(ctx context.Context, r arrow.Record) error {
+	distinctRecord, err := d.dedupe(r)
+	if err != nil || distinctRecord == nil {
+		return err
+	}
+	defer distinctRecord.Release()
+	return d.next.Callback(ctx, distinctRecord)
+}
+
+// buffer keeps the rows of the record that haven't been seen since the
+// distinction spilled in memory. They are emitted when the distinction
+// finishes, once they have been deduplicated against the spilled rows.
+func (d *Distinction) buffer(r arrow.Record) error {
+	distinctRecord, err := d.dedupe(r)
+	if err != nil || distinctRecord == nil {
+		return err
+	}
+	d.mtx.Lock()
+	d.pending = append(d.pending, distinctRecord)
+	d.mtx.Unlock()
+	return nil
+}
+
+// dedupe returns the distinct columns of the rows of the record that haven't
+// been seen yet and marks them as seen. It returns nil if all rows have been
+// seen.
+func (d *Distinction) dedupe(r arrow.Record) (arrow.Record, error) {
+	distinctFields, distinctArrays := d.distinctColumns(r)
 
 	resBuilders := make([]builder.ColumnBuilder, 0, len(distinctArrays))
 	defer func() {
@@ -98,29 +157,7 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 	}
 	rows := int64(0)
 
-	numRows := int(r.NumRows())
-
-	colHashes := make([][]uint64, len(distinctFields))
-	for i, arr := range distinctArrays {
-		colHashes[i] = dynparquet.HashArray(arr)
-	}
-
-	for i := 0; i < numRows; i++ {
-		hash := uint64(0)
-		for j := range colHashes {
-			if colHashes[j][i] == 0 {
-				continue
-			}
-
-			hash = hashCombine(
-				hash,
-				hashCombine(
-					distinctFieldHashes[j],
-					colHashes[j][i],
-				),
-			)
-		}
-
+	for i, hash := range hashRows(d.hashSeed, r, d.columns) {
 		d.mtx.RLock()
 		if _, ok := d.seen[hash]; ok {
 			d.mtx.RUnlock()
@@ -131,7 +168,7 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 		for j, arr := range distinctArrays {
 			err := builder.AppendValue(resBuilders[j], arr, i)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
@@ -139,12 +176,15 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 		d.mtx.Lock()
 		d.seen[hash] = struct{}{}
 		d.mtx.Unlock()
+		if d.spill != nil {
+			d.spill.budget.grow(mapEntrySize)
+		}
 	}
 
 	if rows == 0 {
 		// No need to call anything further down the chain, no new values were
 		// seen so we can skip.
-		return nil
+		return nil, nil
 	}
 
 	resArrays := make([]arrow.Array, 0, len(resBuilders))
@@ -159,12 +199,139 @@ func (d *Distinction) Callback(ctx context.Context, r arrow.Record) error {
 
 	schema := arrow.NewSchema(distinctFields, nil)
 
-	distinctRecord := array.NewRecord(
+	return array.NewRecord(
 		schema,
 		resArrays,
 		rows,
+	), nil
+}
+
+// distinctColumns returns the fields and columns of the record that the
+// distinction is computed on.
+func (d *Distinction) distinctColumns(r arrow.Record) ([]arrow.Field, []arrow.Array) {
+	distinctFields := make([]arrow.Field, 0, 10)
+	distinctArrays := make([]arrow.Array, 0, 10)
+
+	for i := 0; i < r.Schema().NumFields(); i++ {
+		field := r.Schema().Field(i)
+		for _, col := range d.columns {
+			if col.MatchColumn(field.Name) {
+				distinctFields = append(distinctFields, field)
+				distinctArrays = append(distinctArrays, r.Column(i))
+			}
+		}
+	}
+	return distinctFields, distinctArrays
+}
+
+// spillSeen writes the hashes of the rows seen so far to disk and releases
+// them from memory. Rows received afterwards are kept in pending until the
+// budget is exceeded again.
+func (d *Distinction) spillSeen(ctx context.Context) error {
+	var err error
+	d.spilledSeen, err = newSpillFiles(d.pool, d.spill)
+	if err != nil {
+		return err
+	}
+	d.spilledRows, err = newSpillFiles(d.pool, d.spill)
+	if err != nil {
+		return err
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	hashes := make([]uint64, 0, len(d.seen))
+	for hash := range d.seen {
+		hashes = append(hashes, hash)
+	}
+	d.spill.budget.grow(-int64(len(d.seen)) * mapEntrySize)
+	d.seen = make(map[uint64]struct{})
+
+	b := array.NewUint64Builder(d.pool)
+	defer b.Release()
+	b.AppendValues(hashes, nil)
+	arr := b.NewArray()
+	defer arr.Release()
+
+	r := array.NewRecord(
+		arrow.NewSchema([]arrow.Field{{Name: "hash", Type: arrow.PrimitiveTypes.Uint64}}, nil),
+		[]arrow.Array{arr},
+		int64(len(hashes)),
 	)
+	defer r.Release()
+	return d.spilledSeen.write(ctx, r, hashes)
+}
 
-	defer distinctRecord.Release()
-	return d.next.Callback(ctx, distinctRecord)
+// spillPending writes the pending rows to disk and releases them and their
+// hashes from memory.
+func (d *Distinction) spillPending(ctx context.Context) error {
+	d.mtx.Lock()
+	pending := d.pending
+	d.pending = nil
+	d.spill.budget.grow(-int64(len(d.seen)) * mapEntrySize)
+	d.seen = make(map[uint64]struct{})
+	d.mtx.Unlock()
+
+	defer func() {
+		for _, r := range pending {
+			r.Release()
+		}
+	}()
+	for _, r := range pending {
+		if err := d.spilledRows.write(ctx, r, hashRows(d.hashSeed, r, d.columns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Distinction) releasePending() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, r := range d.pending {
+		r.Release()
+	}
+	d.pending = nil
+}
+
+// mergeSpilled emits the spilled rows that haven't been seen yet, one
+// partition at a time.
+func (d *Distinction) mergeSpilled(ctx context.Context) error {
+	defer d.closeSpilled()
+
+	for p := 0; p < spillPartitionCount; p++ {
+		if err := d.spilledSeen.read(p, func(r arrow.Record) error {
+			d.mtx.Lock()
+			defer d.mtx.Unlock()
+			hashes := r.Column(0).(*array.Uint64).Uint64Values()
+			for _, hash := range hashes {
+				d.seen[hash] = struct{}{}
+			}
+			d.spill.budget.grow(int64(len(hashes)) * mapEntrySize)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := d.spilledRows.read(p, func(r arrow.Record) error {
+			return d.distinct(ctx, r)
+		}); err != nil {
+			return err
+		}
+
+		d.mtx.Lock()
+		d.spill.budget.grow(-int64(len(d.seen)) * mapEntrySize)
+		d.seen = make(map[uint64]struct{})
+		d.mtx.Unlock()
+	}
+	return nil
+}
+
+func (d *Distinction) closeSpilled() {
+	if d.spilledSeen != nil {
+		_ = d.spilledSeen.close()
+	}
+	if d.spilledRows != nil {
+		_ = d.spilledRows.close()
+	}
 }
@@ -227,6 +227,12 @@ func booleanExpr(expr logicalplan.Expr) (BooleanExpression, error) {
 	}
 }
 
+// NewBooleanExpr returns a BooleanExpression that evaluates the given filter
+// expression against records.
+func NewBooleanExpr(expr logicalplan.Expr) (BooleanExpression, error) {
+	return booleanExpr(expr)
+}
+
 func Filter(pool memory.Allocator, tracer trace.Tracer, filterExpr logicalplan.Expr) (*PredicateFilter, error) {
 	expr, err := booleanExpr(filterExpr)
 	if err != nil {
@@ -283,7 +289,38 @@ func filter(pool memory.Allocator, filterExpr BooleanExpression, ar arrow.Record
 		return nil, true, nil
 	}
 
-	indicesToKeep := bitmap.ToArray()
+	r, err := takeRows(pool, ar, bitmap.ToArray())
+	if err != nil {
+		return nil, true, err
+	}
+	return r, false, nil
+}
+
+// Exclude returns a record containing the rows of ar that do not match
+// filterExpr. The returned record must be released by the caller. If no rows
+// match, ar itself is retained and returned.
+func Exclude(pool memory.Allocator, filterExpr BooleanExpression, ar arrow.Record) (arrow.Record, error) {
+	bitmap, err := filterExpr.Eval(ar)
+	if err != nil {
+		return nil, err
+	}
+
+	if bitmap.IsEmpty() {
+		ar.Retain()
+		return ar, nil
+	}
+
+	bitmap.Flip(0, uint64(ar.NumRows()))
+	if bitmap.IsEmpty() {
+		return ar.NewSlice(0, 0), nil
+	}
+
+	return takeRows(pool, ar, bitmap.ToArray())
+}
+
+// takeRows returns a new record containing the rows of ar at the given sorted
+// indices.
+func takeRows(pool memory.Allocator, ar arrow.Record, indicesToKeep []uint32) (arrow.Record, error) {
 	ranges := buildIndexRanges(indicesToKeep)
 
 	totalRows := int64(0)
@@ -313,13 +350,13 @@ func filter(pool memory.Allocator, filterExpr BooleanExpression, ar arrow.Record
 
 		c, err := array.Concatenate(colRanges, pool)
 		if err != nil {
-			return nil, true, err
+			return nil, err
 		}
 
 		cols = append(cols, c)
 	}
 
-	return array.NewRecord(ar.Schema(), cols, totalRows), false, nil
+	return array.NewRecord(ar.Schema(), cols, totalRows), nil
 }
 
 type IndexRange struct {
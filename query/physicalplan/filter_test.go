@@ -3,7 +3,10 @@ package physicalplan
 import (
 	"testing"
 
+	"github.com/RoaringBitmap/roaring"
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -79,3 +82,40 @@ func TestAndExprShortCircuits(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, result.IsEmpty())
 }
+
+func TestExclude(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	b.AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	arr := b.NewArray()
+	defer arr.Release()
+	r := array.NewRecord(
+		arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64}}, nil),
+		[]arrow.Array{arr},
+		5,
+	)
+	defer r.Release()
+
+	for _, tc := range []struct {
+		name     string
+		matching []uint32
+		expected []int64
+	}{
+		{name: "none", expected: []int64{1, 2, 3, 4, 5}},
+		{name: "some", matching: []uint32{0, 2, 3}, expected: []int64{2, 5}},
+		{name: "all", matching: []uint32{0, 1, 2, 3, 4}, expected: []int64{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expr := mockExpression{evalFn: func(_ arrow.Record) (*Bitmap, error) {
+				return roaring.BitmapOf(tc.matching...), nil
+			}}
+			excluded, err := Exclude(mem, expr, r)
+			require.NoError(t, err)
+			defer excluded.Release()
+			require.Equal(t, tc.expected, excluded.Column(0).(*array.Int64).Int64Values())
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package physicalplan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/parquet-go/parquet-go"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	"github.com/youscentia/ydb-frostdb/pqarrow"
+	"github.com/youscentia/ydb-frostdb/pqarrow/arrowutils"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+// HashJoin is the probe side of a hash join. It joins the records it receives
+// from the left input with the rows of the right input, which are collected
+// into a hash table keyed by the join keys the first time a record is probed.
+// Multiple HashJoins probing concurrently share the same JoinBuild.
+type HashJoin struct {
+	pool   memory.Allocator
+	tracer trace.Tracer
+	next   PhysicalPlan
+
+	build *JoinBuild
+}
+
+func NewHashJoin(pool memory.Allocator, tracer trace.Tracer, build *JoinBuild) *HashJoin {
+	return &HashJoin{
+		pool:   pool,
+		tracer: tracer,
+		build:  build,
+	}
+}
+
+func (j *HashJoin) SetNext(next PhysicalPlan) { j.next = next }
+
+func (j *HashJoin) Draw() *Diagram {
+	var child *Diagram
+	if j.next != nil {
+		child = j.next.Draw()
+	}
+
+	keys := make([]string, 0, len(j.build.join.LeftKeys))
+	for i := range j.build.join.LeftKeys {
+		keys = append(keys, fmt.Sprintf("%s=%s", j.build.join.LeftKeys[i], j.build.join.RightKeys[i]))
+	}
+
+	details := fmt.Sprintf("HashJoin[%s] (%s) Build: (%s)", j.build.join.Type, strings.Join(keys, ","), j.build.plan.DrawString())
+	return &Diagram{Details: details, Child: child}
+}
+
+func (j *HashJoin) Close() {
+	j.build.release()
+	j.next.Close()
+}
+
+func (j *HashJoin) Finish(ctx context.Context) error {
+	return j.next.Finish(ctx)
+}
+
+func (j *HashJoin) Callback(ctx context.Context, r arrow.Record) error {
+	if r.NumRows() == 0 {
+		return nil
+	}
+
+	if err := j.build.execute(ctx); err != nil {
+		return err
+	}
+
+	if j.build.record == nil {
+		// The right input of an inner join has no rows, so no row matches.
+		return nil
+	}
+
+	keys := keyColumns(r, j.build.join.LeftKeys)
+	hashes, err := hashKeys(keys, int(r.NumRows()))
+	if err != nil {
+		return err
+	}
+
+	leftIndices := array.NewInt32Builder(j.pool)
+	defer leftIndices.Release()
+	rightIndices := array.NewInt32Builder(j.pool)
+	defer rightIndices.Release()
+	for i := 0; i < int(r.NumRows()); i++ {
+		matched := false
+		if !anyNull(keys, i) {
+			for _, row := range j.build.table[hashes.combined[i]] {
+				if !j.build.keysEqual(keys, hashes.columns, i, int(row)) {
+					continue
+				}
+				leftIndices.Append(int32(i))
+				rightIndices.Append(row)
+				matched = true
+			}
+		}
+		if !matched && j.build.join.Type == logicalplan.JoinTypeLeft {
+			leftIndices.Append(int32(i))
+			rightIndices.Append(j.build.nullRow)
+		}
+	}
+
+	if leftIndices.Len() == 0 {
+		return nil
+	}
+
+	out, err := j.join(ctx, r, leftIndices.NewInt32Array(), rightIndices.NewInt32Array())
+	if err != nil {
+		return err
+	}
+	defer out.Release()
+
+	return j.next.Callback(ctx, out)
+}
+
+// join returns a record with the rows at the given indices of the left
+// record followed by the columns of the right rows at the given indices.
+// Right key columns with the same name as their left key are dropped, any
+// other right column with the same name as a left column is an error.
+func (j *HashJoin) join(ctx context.Context, left arrow.Record, leftIndices, rightIndices *array.Int32) (arrow.Record, error) {
+	defer leftIndices.Release()
+	defer rightIndices.Release()
+
+	ctx = compute.WithAllocator(ctx, j.pool)
+	leftTaken, err := arrowutils.Take(ctx, left, leftIndices)
+	if err != nil {
+		return nil, fmt.Errorf("take left rows: %w", err)
+	}
+	defer leftTaken.Release()
+
+	fields := append([]arrow.Field{}, left.Schema().Fields()...)
+	columns := append([]arrow.Array{}, leftTaken.Columns()...)
+
+	right := j.build.record
+	rightFields := make([]arrow.Field, 0, right.NumCols())
+	rightColumns := make([]arrow.Array, 0, right.NumCols())
+	for i, field := range right.Schema().Fields() {
+		if left.Schema().HasField(field.Name) {
+			if j.build.join.SharedKey(field.Name) {
+				continue
+			}
+			return nil, fmt.Errorf("join: column %q of the right input conflicts with a column of the left input, alias it in the right plan", field.Name)
+		}
+		rightFields = append(rightFields, field)
+		rightColumns = append(rightColumns, right.Column(i))
+	}
+	if len(rightColumns) > 0 {
+		rightRecord := array.NewRecord(arrow.NewSchema(rightFields, nil), rightColumns, right.NumRows())
+		defer rightRecord.Release()
+		rightTaken, err := arrowutils.Take(ctx, rightRecord, rightIndices)
+		if err != nil {
+			return nil, fmt.Errorf("take right rows: %w", err)
+		}
+		defer rightTaken.Release()
+
+		fields = append(fields, rightFields...)
+		columns = append(columns, rightTaken.Columns()...)
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), columns, int64(leftIndices.Len())), nil
+}
+
+// JoinBuild is the build side of a hash join. It executes the plan of the
+// right input of the join once and holds a hash table of its rows.
+type JoinBuild struct {
+	pool memory.Allocator
+	join *logicalplan.Join
+	plan *OutputPlan
+
+	once sync.Once
+	err  error
+
+	// record holds all rows of the right input. For left joins, it has an
+	// additional row of nulls at index nullRow that unmatched left rows are
+	// joined with, even if the right input has no rows.
+	record  arrow.Record
+	nullRow int32
+	keys    []arrow.Array
+	hashes  keyHashes
+	table   map[uint64][]int32
+
+	// probes is the number of HashJoins that have not been closed yet.
+	probes atomic.Int64
+}
+
+// NewJoinBuild returns the build side of the given join, which reads the right
+// input from the given plan and is probed by the given number of HashJoins.
+func NewJoinBuild(pool memory.Allocator, join *logicalplan.Join, plan *OutputPlan, probes int) *JoinBuild {
+	b := &JoinBuild{
+		pool: pool,
+		join: join,
+		plan: plan,
+	}
+	b.probes.Store(int64(probes))
+	return b
+}
+
+func (b *JoinBuild) execute(ctx context.Context) error {
+	b.once.Do(func() {
+		b.err = b.build(ctx)
+	})
+	return b.err
+}
+
+func (b *JoinBuild) build(ctx context.Context) error {
+	var (
+		records []arrow.Record
+		rows    int64
+	)
+	defer func() {
+		for _, r := range records {
+			r.Release()
+		}
+	}()
+	if err := b.plan.Execute(ctx, b.pool, func(_ context.Context, r arrow.Record) error {
+		if r.NumRows() == 0 {
+			return nil
+		}
+		r.Retain()
+		records = append(records, r)
+		rows += r.NumRows()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("build join: %w", err)
+	}
+
+	if b.join.Type == logicalplan.JoinTypeLeft {
+		// Unmatched left rows are joined with a row of nulls. If the right
+		// input has no rows, the schema of the row is resolved from its plan
+		// so the joined records have the same columns either way.
+		var schema *arrow.Schema
+		if len(records) > 0 {
+			schema = records[0].Schema()
+		} else {
+			var err error
+			schema, err = planSchema(ctx, b.join.Right)
+			if err != nil {
+				return fmt.Errorf("resolve schema of empty join build: %w", err)
+			}
+		}
+		records = append(records, nullRecord(b.pool, schema))
+		rows++
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	record, err := concatRecords(b.pool, records, rows)
+	if err != nil {
+		return fmt.Errorf("concat join build records: %w", err)
+	}
+	b.record = record
+	b.nullRow = int32(rows - 1)
+
+	numRows := int(record.NumRows())
+	if b.join.Type == logicalplan.JoinTypeLeft {
+		numRows--
+	}
+	b.keys = keyColumns(record, b.join.RightKeys)
+	b.hashes, err = hashKeys(b.keys, int(record.NumRows()))
+	if err != nil {
+		return err
+	}
+
+	b.table = make(map[uint64][]int32, numRows)
+	for i := 0; i < numRows; i++ {
+		if anyNull(b.keys, i) {
+			// Null keys never match.
+			continue
+		}
+		hash := b.hashes.combined[i]
+		b.table[hash] = append(b.table[hash], int32(i))
+	}
+	return nil
+}
+
+// keysEqual returns whether the left keys at index i are equal to the right
+// keys at index j.
+func (b *JoinBuild) keysEqual(left []arrow.Array, leftHashes [][]uint64, i, j int) bool {
+	for k := range left {
+		if leftHashes[k][i] != b.hashes.columns[k][j] {
+			return false
+		}
+		// Integers and booleans are hashed to their value, so only binary
+		// values can collide.
+		leftValue, ok := binaryValue(left[k], i)
+		if !ok {
+			continue
+		}
+		rightValue, _ := binaryValue(b.keys[k], j)
+		if !bytes.Equal(leftValue, rightValue) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *JoinBuild) release() {
+	if b.probes.Add(-1) > 0 {
+		return
+	}
+	if b.record != nil {
+		b.record.Release()
+		b.record = nil
+	}
+}
+
+// keyHashes are the hashes of the join keys of a record.
+type keyHashes struct {
+	// columns are the hashes of each key column.
+	columns [][]uint64
+	// combined are the hashes of all key columns of each row combined.
+	combined []uint64
+}
+
+// keyColumns returns the columns of the record that match the given keys.
+// Keys that don't match a column are represented by a null array, so they
+// never match.
+func keyColumns(r arrow.Record, keys []logicalplan.Expr) []arrow.Array {
+	columns := make([]arrow.Array, 0, len(keys))
+	for _, key := range keys {
+		var column arrow.Array
+		for i, field := range r.Schema().Fields() {
+			if key.MatchColumn(field.Name) {
+				column = r.Column(i)
+				break
+			}
+		}
+		if column == nil {
+			column = arrowutils.MakeVirtualNullArray(arrow.Null, int(r.NumRows()))
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func hashKeys(keys []arrow.Array, rows int) (keyHashes, error) {
+	h := keyHashes{
+		columns:  make([][]uint64, 0, len(keys)),
+		combined: make([]uint64, rows),
+	}
+	for _, key := range keys {
+		var hashes []uint64
+		switch key := key.(type) {
+		case arrowutils.VirtualNullArray:
+			hashes = make([]uint64, rows)
+		case *array.String, *array.Binary, *array.Int64, *array.Uint64, *array.Boolean:
+			hashes = dynparquet.HashArray(key)
+		case *array.Dictionary:
+			switch key.Dictionary().(type) {
+			case *array.String, *array.Binary:
+				hashes = dynparquet.HashArray(key)
+			default:
+				return keyHashes{}, fmt.Errorf("unsupported join key type %s", key.DataType())
+			}
+		default:
+			return keyHashes{}, fmt.Errorf("unsupported join key type %s", key.DataType())
+		}
+		for i, hash := range hashes {
+			h.combined[i] = hashCombine(h.combined[i], hash)
+		}
+		h.columns = append(h.columns, hashes)
+	}
+	return h, nil
+}
+
+func anyNull(keys []arrow.Array, i int) bool {
+	for _, key := range keys {
+		if key.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// binaryValue returns the value at index i of a binary-like array.
+func binaryValue(arr arrow.Array, i int) ([]byte, bool) {
+	switch arr := arr.(type) {
+	case *array.Binary:
+		return arr.Value(i), true
+	case *array.String:
+		return []byte(arr.Value(i)), true
+	case *array.Dictionary:
+		return binaryValue(arr.Dictionary(), arr.GetValueIndex(i))
+	default:
+		return nil, false
+	}
+}
+
+// planSchema returns the schema of the records the given plan produces. It is
+// used for the right input of a join that produced no records, so only the
+// plans of join inputs that read a table, optionally filtered, limited, sorted
+// or projected, are supported. Dynamic columns are only part of the schema if
+// they are projected explicitly.
+func planSchema(ctx context.Context, plan *logicalplan.LogicalPlan) (*arrow.Schema, error) {
+	switch {
+	case plan.TableScan != nil:
+		table, err := plan.TableScan.TableProvider.GetTable(plan.TableScan.TableName)
+		if err != nil {
+			return nil, err
+		}
+		schema := table.Schema()
+		group := parquet.Group{}
+		for _, col := range schema.Columns() {
+			if !col.Dynamic {
+				group[col.Name] = col.StorageLayout
+			}
+		}
+		return pqarrow.ParquetSchemaToArrowSchema(ctx, parquet.NewSchema(schema.Name(), group), schema, logicalplan.IterOptions{
+			PhysicalProjection: plan.TableScan.PhysicalProjection,
+			Filter:             plan.TableScan.Filter,
+			DistinctColumns:    plan.TableScan.Distinct,
+		})
+	case plan.Filter != nil, plan.Limit != nil, plan.Sort != nil:
+		return planSchema(ctx, plan.Input)
+	case plan.Projection != nil:
+		input, err := planSchema(ctx, plan.Input)
+		if err != nil {
+			return nil, err
+		}
+		fields := make([]arrow.Field, 0, len(plan.Projection.Exprs))
+		for _, expr := range plan.Projection.Exprs {
+			if _, ok := expr.(*logicalplan.AllExpr); ok {
+				// Wildcards aren't planned, the scan projects the columns.
+				return input, nil
+			}
+			if indices := input.FieldIndices(expr.Name()); len(indices) > 0 {
+				fields = append(fields, input.Field(indices[0]))
+				continue
+			}
+			t, err := expr.DataType(plan.Input)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, arrow.Field{Name: expr.Name(), Type: t, Nullable: true})
+		}
+		return arrow.NewSchema(fields, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported plan: %s", plan)
+	}
+}
+
+// nullRecord returns a record with the given schema and a single row of
+// nulls.
+func nullRecord(pool memory.Allocator, schema *arrow.Schema) arrow.Record {
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	for _, field := range schema.Fields() {
+		columns = append(columns, arrowutils.MakeNullArray(pool, field.Type, 1))
+	}
+	r := array.NewRecord(schema, columns, 1)
+	for _, c := range columns {
+		c.Release()
+	}
+	return r
+}
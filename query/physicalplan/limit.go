@@ -23,26 +23,33 @@ type Limiter struct {
 }
 
 func Limit(pool memory.Allocator, tracer trace.Tracer, expr logicalplan.Expr) (*Limiter, error) {
+	count, err := limitCount(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		pool:   pool,
+		tracer: tracer,
+		count:  count,
+	}, nil
+}
+
+// limitCount returns the number of rows the given limit expression allows.
+func limitCount(expr logicalplan.Expr) (uint64, error) {
 	literal, ok := expr.(*logicalplan.LiteralExpr)
 	if !ok {
-		return nil, fmt.Errorf("expected literal expression, got %T", expr)
+		return 0, fmt.Errorf("expected literal expression, got %T", expr)
 	}
 
-	var count uint64
 	switch v := literal.Value.(type) {
 	case *scalar.Uint64:
-		count = v.Value
+		return v.Value, nil
 	case *scalar.Int64:
-		count = uint64(v.Value)
+		return uint64(v.Value), nil
 	default:
-		return nil, fmt.Errorf("expected limit count type, got %T", v)
+		return 0, fmt.Errorf("expected limit count type, got %T", v)
 	}
-
-	return &Limiter{
-		pool:   pool,
-		tracer: tracer,
-		count:  count,
-	}, nil
 }
 
 func (l *Limiter) SetNext(next PhysicalPlan) { l.next = next }
@@ -179,7 +179,18 @@ func (a *OrderedAggregate) Callback(_ context.Context, r arrow.Record) error {
 		field := r.Schema().Field(i)
 		for _, matcher := range a.groupByColumnMatchers {
 			if matcher.MatchColumn(field.Name) {
-				a.scratch.groupByMap[field.Name] = groupColInfo{field: field, arr: r.Column(i)}
+				arr := r.Column(i)
+				if d, ok := groupByDuration(matcher); ok {
+					// Flooring timestamps preserves their order, so the
+					// bucketed column is still ordered.
+					bucketed, err := bucketTimestamps(a.pool, arr, d)
+					if err != nil {
+						return err
+					}
+					defer bucketed.Release()
+					arr = bucketed
+				}
+				a.scratch.groupByMap[field.Name] = groupColInfo{field: field, arr: arr}
 				if _, ok := a.groupBuilders[field.Name]; !ok {
 					a.groupColOrdering = append(a.groupColOrdering, field)
 					b := builder.NewBuilder(a.pool, field.Type)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"hash/maphash"
+	"os"
 	"runtime"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -16,8 +17,11 @@ import (
 	"github.com/youscentia/ydb-frostdb/dynparquet"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
 	"github.com/youscentia/ydb-frostdb/recovery"
+	"github.com/youscentia/ydb-frostdb/vfs"
+	vfsadapters "github.com/youscentia/ydb-frostdb/vfs/adapters"
 )
 
+// concurrencyHardcoded is the default number of parallel pipelines of a query.
 // TODO: Make this smarter.
 var concurrencyHardcoded = runtime.GOMAXPROCS(0)
 
@@ -57,6 +61,11 @@ func (f PostPlanVisitorFunc) PostVisit(plan *logicalplan.LogicalPlan) bool {
 type OutputPlan struct {
 	callback func(ctx context.Context, r arrow.Record) error
 	scan     ScanPhysicalPlan
+
+	// workers limits the workers the plan executes with, the plan acquires a
+	// worker for each of its concurrent pipelines.
+	workers     *WorkerLimiter
+	concurrency int
 }
 
 func (e *OutputPlan) Draw() *Diagram {
@@ -90,6 +99,13 @@ func (e *OutputPlan) SetNext(_ PhysicalPlan) {
 
 func (e *OutputPlan) Execute(ctx context.Context, pool memory.Allocator, callback func(ctx context.Context, r arrow.Record) error) error {
 	e.callback = callback
+	if e.workers != nil {
+		release, err := e.workers.acquire(ctx, e.concurrency)
+		if err != nil {
+			return fmt.Errorf("acquire workers: %w", err)
+		}
+		defer release()
+	}
 	return e.scan.Execute(ctx, pool)
 }
 
@@ -138,6 +154,9 @@ func (s *TableScan) Execute(ctx context.Context, pool memory.Allocator) error {
 		logicalplan.WithReadMode(s.options.ReadMode),
 	}
 
+	if s.options.ReadTx != 0 {
+		ctx = logicalplan.WithReadTx(ctx, s.options.ReadTx)
+	}
 	errg, _ := errgroup.WithContext(ctx)
 	errg.Go(recovery.Do(func() error {
 		return table.View(ctx, func(ctx context.Context, tx uint64) error {
@@ -202,6 +221,9 @@ func (s *SchemaScan) Execute(ctx context.Context, pool memory.Allocator) error {
 		logicalplan.WithReadMode(s.options.ReadMode),
 	}
 
+	if s.options.ReadTx != 0 {
+		ctx = logicalplan.WithReadTx(ctx, s.options.ReadTx)
+	}
 	errg, _ := errgroup.WithContext(ctx)
 	errg.Go(recovery.Do(func() error {
 		return table.View(ctx, func(ctx context.Context, tx uint64) error {
@@ -260,22 +282,114 @@ type execOptions struct {
 	orderedAggregations bool
 	overrideInput       []PhysicalPlan
 	readMode            logicalplan.ReadMode
+	readTx              uint64
+	concurrency         int
+	workers             *WorkerLimiter
+
+	memoryBudget int64
+	spillFS      vfs.FileSystem
+	spillDir     string
+	// budget is the memory budget shared by all operators of the query,
+	// including the ones of the right input of joins.
+	budget *memoryBudget
 }
 
 type Option func(o *execOptions)
 
+// joinBuildOptions returns the options to plan the right input of a join
+// with. The input of the plan is not overridden for the right input. The
+// right input executes within the workers of the query, so it doesn't acquire
+// workers of its own.
+func (o execOptions) joinBuildOptions() []Option {
+	opts := []Option{WithReadMode(o.readMode), WithReadTx(o.readTx), WithConcurrency(o.concurrency)}
+	if o.orderedAggregations {
+		opts = append(opts, WithOrderedAggregations())
+	}
+	if o.budget != nil {
+		opts = append(opts,
+			WithSpillDir(o.spillFS, o.spillDir),
+			func(opts *execOptions) {
+				opts.memoryBudget = o.memoryBudget
+				opts.budget = o.budget
+			},
+		)
+	}
+	return opts
+}
+
+// spillOptions returns the options hash aggregations and distinct operators
+// spill their state with, or nil if the memory of the query isn't limited.
+func (o *execOptions) spillOptions() *spillOptions {
+	if o.memoryBudget <= 0 {
+		return nil
+	}
+	if o.budget == nil {
+		o.budget = &memoryBudget{limit: o.memoryBudget}
+	}
+	if o.spillFS == nil {
+		return &spillOptions{budget: o.budget, fs: vfsadapters.NewOSAdapter(), dir: os.TempDir()}
+	}
+	return &spillOptions{budget: o.budget, fs: o.spillFS, dir: o.spillDir}
+}
+
 func WithReadMode(m logicalplan.ReadMode) Option {
 	return func(o *execOptions) {
 		o.readMode = m
 	}
 }
 
+// WithReadTx makes the table scans of queries read at the given txn rather
+// than at the latest one. Zero reads at the latest txn.
+func WithReadTx(tx uint64) Option {
+	return func(o *execOptions) {
+		o.readTx = tx
+	}
+}
+
 func WithOrderedAggregations() Option {
 	return func(o *execOptions) {
 		o.orderedAggregations = true
 	}
 }
 
+// WithConcurrency sets the number of parallel pipelines a query executes
+// with. It defaults to GOMAXPROCS.
+func WithConcurrency(n int) Option {
+	return func(o *execOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithWorkerLimiter makes queries acquire a worker of the given limiter for
+// each of their parallel pipelines before executing. The concurrency of a
+// query is capped to the number of workers of the limiter.
+func WithWorkerLimiter(l *WorkerLimiter) Option {
+	return func(o *execOptions) {
+		o.workers = l
+	}
+}
+
+// WithMemoryBudget limits the memory that the hash aggregations and distinct
+// operators of a query hold in total. Once the budget is exceeded, they spill
+// their state to disk and merge it again when finishing, which makes queries
+// slower instead of failing them. The budget is not a hard limit, it should
+// be set well below the limit of the allocator used to execute the query.
+func WithMemoryBudget(bytes int64) Option {
+	return func(o *execOptions) {
+		o.memoryBudget = bytes
+	}
+}
+
+// WithSpillDir sets the file system and directory that operators spill their
+// state to once the memory budget of the query is exceeded. By default, state
+// is spilled to the temporary directory of the OS.
+func WithSpillDir(fs vfs.FileSystem, dir string) Option {
+	return func(o *execOptions) {
+		o.spillFS = fs
+		o.spillDir = dir
+	}
+}
+
 // WithOverrideInput can be used to provide an input stage on top of which the
 // Build function can build the physical plan.
 func WithOverrideInput(input []PhysicalPlan) Option {
@@ -300,8 +414,19 @@ func Build(
 		o(&execOpts)
 	}
 	prev := execOpts.overrideInput
+	spill := execOpts.spillOptions()
 
-	outputPlan := &OutputPlan{}
+	if execOpts.concurrency <= 0 {
+		execOpts.concurrency = concurrencyHardcoded
+	}
+	if execOpts.workers != nil {
+		execOpts.concurrency = min(execOpts.concurrency, execOpts.workers.Workers())
+	}
+
+	outputPlan := &OutputPlan{
+		workers:     execOpts.workers,
+		concurrency: execOpts.concurrency,
+	}
 	oInfo := &planOrderingInfo{
 		state: planOrderingInfoStateInit,
 	}
@@ -319,11 +444,12 @@ func Build(
 			// Create noop operators since we don't know what to push the scan
 			// results to. In a following node visit, these noops will have
 			// SetNext called on them and push to the correct operator.
-			plans := make([]PhysicalPlan, concurrencyHardcoded)
+			plans := make([]PhysicalPlan, execOpts.concurrency)
 			for i := range plans {
 				plans[i] = &noopOperator{}
 			}
 			plan.SchemaScan.ReadMode = execOpts.readMode
+			plan.SchemaScan.ReadTx = execOpts.readTx
 			outputPlan.scan = &SchemaScan{
 				tracer:  tracer,
 				options: plan.SchemaScan,
@@ -334,11 +460,12 @@ func Build(
 			// Create noop operators since we don't know what to push the scan
 			// results to. In a following node visit, these noops will have
 			// SetNext called on them and push to the correct operator.
-			plans := make([]PhysicalPlan, concurrencyHardcoded)
+			plans := make([]PhysicalPlan, execOpts.concurrency)
 			for i := range plans {
 				plans[i] = &noopOperator{}
 			}
 			plan.TableScan.ReadMode = execOpts.readMode
+			plan.TableScan.ReadTx = execOpts.readTx
 			outputPlan.scan = &TableScan{
 				tracer:  tracer,
 				options: plan.TableScan,
@@ -370,6 +497,9 @@ func Build(
 			}
 			for i := 0; i < len(prev); i++ {
 				d := Distinct(pool, tracer, plan.Distinct.Exprs)
+				if spill != nil {
+					d.spillTo(spill)
+				}
 				prev[i].SetNext(d)
 				prev[i] = d
 				if sync != nil {
@@ -380,11 +510,27 @@ func Build(
 				// Plan a distinct operator to run a distinct on all the
 				// synchronized distincts.
 				d := Distinct(pool, tracer, plan.Distinct.Exprs)
+				if spill != nil {
+					d.spillTo(spill)
+				}
 				sync.SetNext(d)
 				prev = prev[0:1]
 				prev[0] = d
 			}
 		case plan.Limit != nil:
+			if sorter, ok := prev[0].(*Sorter); ok && len(prev) == 1 {
+				// A limit directly following a sort is fused into it, so the
+				// sort only needs to keep the top rows around.
+				count, err := limitCount(plan.Limit.Expr)
+				if err != nil {
+					visitErr = err
+					return false
+				}
+				if count > 0 {
+					sorter.SetLimit(count)
+					return true
+				}
+			}
 			var sync *Synchronizer
 			if len(prev) > 1 {
 				// These limit operators need to be synchronized.
@@ -414,6 +560,44 @@ func Build(
 				prev = prev[0:1]
 				prev[0] = d
 			}
+		case plan.Sort != nil:
+			// Sorting requires all rows, so synchronize all inputs into a
+			// single sort operator.
+			if len(prev) > 1 {
+				sync := Synchronize(len(prev))
+				for i := range prev {
+					prev[i].SetNext(sync)
+				}
+				s := Sort(pool, tracer, plan.Sort.Exprs)
+				sync.SetNext(s)
+				prev = prev[0:1]
+				prev[0] = s
+			} else {
+				s := Sort(pool, tracer, plan.Sort.Exprs)
+				prev[0].SetNext(s)
+				prev[0] = s
+			}
+		case plan.Join != nil:
+			// The right input is planned independently and executed by the
+			// build side of the join, which all the concurrent probes share.
+			right, err := Build(
+				ctx,
+				pool,
+				tracer,
+				plan.Join.Right.InputSchema(),
+				plan.Join.Right,
+				execOpts.joinBuildOptions()...,
+			)
+			if err != nil {
+				visitErr = fmt.Errorf("build right input of join: %w", err)
+				return false
+			}
+			build := NewJoinBuild(pool, plan.Join, right, len(prev))
+			for i := range prev {
+				j := NewHashJoin(pool, tracer, build)
+				prev[i].SetNext(j)
+				prev[i] = j
+			}
 		case plan.Filter != nil:
 			// Create a filter for each previous plan.
 			// Can be multiple filters or just a single
@@ -446,29 +630,38 @@ func Build(
 			}
 			seed := maphash.MakeSeed()
 			for i := 0; i < len(prev); i++ {
-				a, err := Aggregate(pool, tracer, plan.Aggregation, sync == nil, ordered, seed)
+				a, err := Aggregate(pool, tracer, plan.Aggregation, false, ordered, seed)
 				if err != nil {
 					visitErr = err
 					return false
 				}
+				if h, ok := a.(*HashAggregate); ok && spill != nil {
+					h.spillTo(spill)
+				}
 				prev[i].SetNext(a)
 				prev[i] = a
 				if sync != nil {
 					a.SetNext(sync)
 				}
 			}
+			// Plan an aggregate operator to run an aggregation on all the
+			// aggregations. It is planned even for a single pipeline, as only
+			// the final stage produces the result columns.
+			a, err := Aggregate(pool, tracer, plan.Aggregation, true, ordered, seed)
+			if err != nil {
+				visitErr = err
+				return false
+			}
+			if h, ok := a.(*HashAggregate); ok && spill != nil {
+				h.spillTo(spill)
+			}
 			if sync != nil {
-				// Plan an aggregate operator to run an aggregation on all the
-				// aggregations.
-				a, err := Aggregate(pool, tracer, plan.Aggregation, true, ordered, seed)
-				if err != nil {
-					visitErr = err
-					return false
-				}
 				sync.SetNext(a)
-				prev = prev[0:1]
-				prev[0] = a
+			} else {
+				prev[0].SetNext(a)
 			}
+			prev = prev[0:1]
+			prev[0] = a
 			if ordered {
 				oInfo.nodeMaintainsOrdering()
 			}
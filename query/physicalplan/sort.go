@@ -0,0 +1,254 @@
+package physicalplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/youscentia/ydb-frostdb/pqarrow/arrowutils"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+// Sorter buffers all the records it receives and emits them as a single
+// sorted record once its input has finished. If a limit is set, only the top
+// limit rows are emitted and the buffered records are regularly compacted to
+// those rows, so memory usage is bounded by the limit rather than the input.
+type Sorter struct {
+	pool   memory.Allocator
+	tracer trace.Tracer
+	next   PhysicalPlan
+
+	exprs []*logicalplan.SortExpr
+	// limit is the number of rows to emit. Zero means all rows are emitted.
+	limit uint64
+
+	records []arrow.Record
+	rows    int64
+}
+
+func Sort(pool memory.Allocator, tracer trace.Tracer, exprs []*logicalplan.SortExpr) *Sorter {
+	return &Sorter{
+		pool:   pool,
+		tracer: tracer,
+		exprs:  exprs,
+	}
+}
+
+// SetLimit turns the Sorter into a TopK operator that only emits the first
+// limit rows. If a limit is already set, the lower of both is used.
+func (s *Sorter) SetLimit(limit uint64) {
+	if s.limit == 0 || limit < s.limit {
+		s.limit = limit
+	}
+}
+
+func (s *Sorter) SetNext(next PhysicalPlan) { s.next = next }
+
+func (s *Sorter) Draw() *Diagram {
+	var child *Diagram
+	if s.next != nil {
+		child = s.next.Draw()
+	}
+
+	exprs := make([]string, 0, len(s.exprs))
+	for _, e := range s.exprs {
+		exprs = append(exprs, e.String())
+	}
+
+	details := fmt.Sprintf("Sort (%s)", strings.Join(exprs, ","))
+	if s.limit > 0 {
+		details = fmt.Sprintf("TopK(%d) (%s)", s.limit, strings.Join(exprs, ","))
+	}
+	return &Diagram{Details: details, Child: child}
+}
+
+func (s *Sorter) Close() {
+	s.release()
+	s.next.Close()
+}
+
+func (s *Sorter) release() {
+	for _, r := range s.records {
+		r.Release()
+	}
+	s.records = s.records[:0]
+	s.rows = 0
+}
+
+func (s *Sorter) Callback(ctx context.Context, r arrow.Record) error {
+	if r.NumRows() == 0 {
+		return nil
+	}
+
+	r.Retain()
+	s.records = append(s.records, r)
+	s.rows += r.NumRows()
+
+	// Once enough rows to fill the top k twice have been buffered, reduce
+	// them to the top k so far.
+	if s.limit > 0 && s.rows >= 2*int64(s.limit) && len(s.records) > 1 {
+		sorted, err := s.sort(ctx)
+		if err != nil {
+			return err
+		}
+		s.release()
+		s.records = append(s.records, sorted)
+		s.rows = sorted.NumRows()
+	}
+
+	return nil
+}
+
+func (s *Sorter) Finish(ctx context.Context) error {
+	if len(s.records) > 0 {
+		sorted, err := s.sort(ctx)
+		if err != nil {
+			return err
+		}
+		s.release()
+		defer sorted.Release()
+
+		if err := s.next.Callback(ctx, sorted); err != nil {
+			return err
+		}
+	}
+
+	return s.next.Finish(ctx)
+}
+
+// sort returns a new record containing the sorted (and limited, if a limit is
+// set) rows of all the buffered records. The buffered records are left
+// untouched.
+func (s *Sorter) sort(ctx context.Context) (arrow.Record, error) {
+	r, err := concatRecords(s.pool, s.records, s.rows)
+	if err != nil {
+		return nil, fmt.Errorf("concat records: %w", err)
+	}
+	defer r.Release()
+
+	sortingCols := s.sortingColumns(r.Schema())
+	if len(sortingCols) == 0 {
+		// None of the sort columns are present, so every row compares
+		// equal and the input order is as good as any.
+		if s.limit > 0 && uint64(r.NumRows()) > s.limit {
+			return r.NewSlice(0, int64(s.limit)), nil
+		}
+		r.Retain()
+		return r, nil
+	}
+
+	indices, err := arrowutils.SortRecord(r, sortingCols)
+	if err != nil {
+		return nil, fmt.Errorf("sort record: %w", err)
+	}
+	defer indices.Release()
+
+	if s.limit > 0 && uint64(indices.Len()) > s.limit {
+		limited := array.NewSlice(indices, 0, int64(s.limit)).(*array.Int32)
+		defer limited.Release()
+		indices = limited
+	}
+
+	return arrowutils.Take(compute.WithAllocator(ctx, s.pool), r, indices)
+}
+
+// sortingColumns resolves the sort expressions to the columns of the given
+// schema. A dynamic column expression may resolve to multiple columns, which
+// are then sorted by in schema order. The expressions are validated against
+// the schema of the table by the logical plan, so an expression that doesn't
+// match any column refers to a column without any values in the input, e.g. a
+// dynamic column, and is skipped as all of its values would be null.
+func (s *Sorter) sortingColumns(schema *arrow.Schema) []arrowutils.SortingColumn {
+	cols := make([]arrowutils.SortingColumn, 0, len(s.exprs))
+	for _, e := range s.exprs {
+		direction := arrowutils.Ascending
+		if e.Direction == logicalplan.Descending {
+			direction = arrowutils.Descending
+		}
+		for i, field := range schema.Fields() {
+			if e.Expr.MatchColumn(field.Name) {
+				cols = append(cols, arrowutils.SortingColumn{
+					Index:      i,
+					Direction:  direction,
+					NullsFirst: e.NullsFirst,
+				})
+			}
+		}
+	}
+	return cols
+}
+
+// concatRecords concatenates the given records, which have the given number
+// of rows in total, into a single record. The records may have differing
+// schemas due to dynamic columns, in which case columns missing from a record
+// are filled with nulls.
+func concatRecords(pool memory.Allocator, records []arrow.Record, rows int64) (arrow.Record, error) {
+	if len(records) == 1 {
+		records[0].Retain()
+		return records[0], nil
+	}
+
+	schema := records[0].Schema()
+	for _, r := range records[1:] {
+		if !r.Schema().Equal(schema) {
+			schema = mergeSchemas(records)
+			break
+		}
+	}
+
+	columns := make([]arrow.Array, schema.NumFields())
+	defer func() {
+		for _, c := range columns {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+	for i, field := range schema.Fields() {
+		arrs := make([]arrow.Array, 0, len(records))
+		var nulls []arrow.Array
+		for _, r := range records {
+			if indices := r.Schema().FieldIndices(field.Name); len(indices) > 0 {
+				arrs = append(arrs, r.Column(indices[0]))
+				continue
+			}
+			null := arrowutils.MakeNullArray(pool, field.Type, int(r.NumRows()))
+			nulls = append(nulls, null)
+			arrs = append(arrs, null)
+		}
+
+		col, err := array.Concatenate(arrs, pool)
+		for _, null := range nulls {
+			null.Release()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("concatenate column %s: %w", field.Name, err)
+		}
+		columns[i] = col
+	}
+
+	return array.NewRecord(schema, columns, rows), nil
+}
+
+// mergeSchemas returns a schema containing the fields of all the given
+// records, in the order they are first seen.
+func mergeSchemas(records []arrow.Record) *arrow.Schema {
+	seen := make(map[string]struct{})
+	fields := make([]arrow.Field, 0, records[0].Schema().NumFields())
+	for _, r := range records {
+		for _, field := range r.Schema().Fields() {
+			if _, ok := seen[field.Name]; ok {
+				continue
+			}
+			seen[field.Name] = struct{}{}
+			fields = append(fields, field)
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
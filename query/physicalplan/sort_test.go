@@ -0,0 +1,131 @@
+package physicalplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+type collectPlan struct {
+	values [][]int64
+	names  [][]string
+}
+
+func (c *collectPlan) Callback(_ context.Context, r arrow.Record) error {
+	names := make([]string, 0, r.NumCols())
+	values := make([]int64, 0, r.NumRows())
+	for i := 0; i < int(r.NumCols()); i++ {
+		names = append(names, r.ColumnName(i))
+	}
+	col := r.Column(0).(*array.Int64)
+	for i := 0; i < col.Len(); i++ {
+		if col.IsNull(i) {
+			values = append(values, -1)
+			continue
+		}
+		values = append(values, col.Value(i))
+	}
+	c.names = append(c.names, names)
+	c.values = append(c.values, values)
+	return nil
+}
+
+func (c *collectPlan) Finish(_ context.Context) error { return nil }
+func (c *collectPlan) SetNext(_ PhysicalPlan)         {}
+func (c *collectPlan) Draw() *Diagram                 { return &Diagram{} }
+func (c *collectPlan) Close()                         {}
+
+func TestSort(t *testing.T) {
+	// newRecord returns a record with an int64 "value" column and, if label
+	// is set, a "labels.<label>" string column.
+	newRecord := func(mem memory.Allocator, label string, values ...int64) arrow.Record {
+		fields := []arrow.Field{{Name: "value", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}
+		if label != "" {
+			fields = append(fields, arrow.Field{Name: "labels." + label, Type: arrow.BinaryTypes.String})
+		}
+		b := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+		defer b.Release()
+		for _, v := range values {
+			if v < 0 {
+				b.Field(0).AppendNull()
+			} else {
+				b.Field(0).(*array.Int64Builder).Append(v)
+			}
+			if label != "" {
+				b.Field(1).(*array.StringBuilder).Append(label)
+			}
+		}
+		return b.NewRecord()
+	}
+
+	for _, tc := range []struct {
+		name     string
+		exprs    []*logicalplan.SortExpr
+		limit    uint64
+		expected []int64
+	}{
+		{
+			name:     "asc",
+			exprs:    []*logicalplan.SortExpr{logicalplan.Asc(logicalplan.Col("value"))},
+			expected: []int64{1, 2, 3, 4, 5, 6, -1},
+		},
+		{
+			name:     "desc",
+			exprs:    []*logicalplan.SortExpr{logicalplan.Desc(logicalplan.Col("value"))},
+			expected: []int64{6, 5, 4, 3, 2, 1, -1},
+		},
+		{
+			name: "nulls first",
+			exprs: []*logicalplan.SortExpr{{
+				Expr:       logicalplan.Col("value"),
+				Direction:  logicalplan.Descending,
+				NullsFirst: true,
+			}},
+			expected: []int64{-1, 6, 5, 4, 3, 2, 1},
+		},
+		{
+			name:     "topk",
+			exprs:    []*logicalplan.SortExpr{logicalplan.Desc(logicalplan.Col("value"))},
+			limit:    2,
+			expected: []int64{6, 5},
+		},
+		{
+			name:     "dynamic column",
+			exprs:    []*logicalplan.SortExpr{logicalplan.Asc(logicalplan.DynCol("labels")), logicalplan.Asc(logicalplan.Col("value"))},
+			expected: []int64{4, 6, -1, 1, 3, 2, 5},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			s := Sort(mem, noop.NewTracerProvider().Tracer(""), tc.exprs)
+			s.SetLimit(tc.limit)
+			out := &collectPlan{}
+			s.SetNext(out)
+			defer s.Close()
+
+			ctx := context.Background()
+			for _, r := range []arrow.Record{
+				newRecord(mem, "", 5, 2),
+				newRecord(mem, "a", 4, -1, 6),
+				newRecord(mem, "b", 3, 1),
+			} {
+				require.NoError(t, s.Callback(ctx, r))
+				r.Release()
+			}
+			require.NoError(t, s.Finish(ctx))
+
+			require.Len(t, out.values, 1)
+			require.Equal(t, tc.expected, out.values[0])
+			require.Equal(t, []string{"value", "labels.a", "labels.b"}, out.names[0])
+		})
+	}
+}
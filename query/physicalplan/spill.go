@@ -0,0 +1,255 @@
+package physicalplan
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/maphash"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	"github.com/youscentia/ydb-frostdb/pqarrow/arrowutils"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	"github.com/youscentia/ydb-frostdb/vfs"
+)
+
+// spillPartitionCount is the number of partitions spilled state is split
+// into. Partitions are merged one at a time, so merging spilled state needs
+// roughly 1/spillPartitionCount of the memory the whole state would need.
+const spillPartitionCount = 16
+
+// mapEntrySize approximates the number of bytes an entry of the hash tables
+// of operators holds. These are not allocated through the arrow allocator, so
+// they are accounted for separately.
+const mapEntrySize = 48
+
+// memoryBudget is the memory budget of a query that is shared by all its
+// operators that are able to spill their state to disk.
+type memoryBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+func (b *memoryBudget) grow(n int64) {
+	b.used.Add(n)
+}
+
+func (b *memoryBudget) exceeded() bool {
+	return b.used.Load() > b.limit
+}
+
+// budgetAllocator accounts all memory allocated through it to a memory
+// budget.
+type budgetAllocator struct {
+	memory.Allocator
+	budget *memoryBudget
+}
+
+func (a *budgetAllocator) Allocate(size int) []byte {
+	a.budget.grow(int64(size))
+	return a.Allocator.Allocate(size)
+}
+
+func (a *budgetAllocator) Reallocate(size int, b []byte) []byte {
+	a.budget.grow(int64(size - len(b)))
+	return a.Allocator.Reallocate(size, b)
+}
+
+func (a *budgetAllocator) Free(b []byte) {
+	a.budget.grow(-int64(len(b)))
+	a.Allocator.Free(b)
+}
+
+// spillOptions configure operators to spill their state to disk once the
+// memory budget of the query is exceeded.
+type spillOptions struct {
+	budget *memoryBudget
+	fs     vfs.FileSystem
+	dir    string
+}
+
+// spillFiles holds records spilled to disk split into partitions. Every
+// record is written as a separate IPC stream, so the records of a partition
+// don't need to share a schema.
+type spillFiles struct {
+	pool       memory.Allocator
+	fs         vfs.FileSystem
+	dir        string
+	partitions [spillPartitionCount]spillPartition
+}
+
+type spillPartition struct {
+	file    vfs.File
+	w       *bufio.Writer
+	streams int
+}
+
+func newSpillFiles(pool memory.Allocator, opts *spillOptions) (*spillFiles, error) {
+	dir := filepath.Join(opts.dir, ulid.Make().String())
+	if err := opts.fs.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create spill directory: %w", err)
+	}
+	return &spillFiles{
+		pool: pool,
+		fs:   opts.fs,
+		dir:  dir,
+	}, nil
+}
+
+func (s *spillFiles) path(partition int) string {
+	return filepath.Join(s.dir, strconv.Itoa(partition))
+}
+
+// write splits the rows of the record into partitions by the given hashes
+// of the rows and appends them to the files of their partitions.
+func (s *spillFiles) write(ctx context.Context, r arrow.Record, hashes []uint64) error {
+	var indices [spillPartitionCount][]int32
+	for i, hash := range hashes {
+		p := hash % spillPartitionCount
+		indices[p] = append(indices[p], int32(i))
+	}
+
+	ctx = compute.WithAllocator(ctx, s.pool)
+	for p := range indices {
+		if len(indices[p]) == 0 {
+			continue
+		}
+		if len(indices[p]) == int(r.NumRows()) {
+			return s.append(p, r)
+		}
+		if err := s.writeRows(ctx, p, r, indices[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *spillFiles) writeRows(ctx context.Context, partition int, r arrow.Record, rows []int32) error {
+	b := array.NewInt32Builder(s.pool)
+	defer b.Release()
+	b.AppendValues(rows, nil)
+	indices := b.NewInt32Array()
+	defer indices.Release()
+
+	taken, err := arrowutils.Take(ctx, r, indices)
+	if err != nil {
+		return fmt.Errorf("take spilled rows: %w", err)
+	}
+	defer taken.Release()
+	return s.append(partition, taken)
+}
+
+func (s *spillFiles) append(partition int, r arrow.Record) error {
+	p := &s.partitions[partition]
+	if p.file == nil {
+		f, err := s.fs.OpenFile(s.path(partition), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return fmt.Errorf("create spill file: %w", err)
+		}
+		p.file = f
+		p.w = bufio.NewWriter(f)
+	}
+
+	w := ipc.NewWriter(p.w, ipc.WithSchema(r.Schema()), ipc.WithAllocator(s.pool))
+	if err := w.Write(r); err != nil {
+		return fmt.Errorf("write spill file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("write spill file: %w", err)
+	}
+	p.streams++
+	return nil
+}
+
+// read calls fn with every record spilled to the given partition and removes
+// the partition's file afterwards. The records are only valid during the call.
+func (s *spillFiles) read(partition int, fn func(arrow.Record) error) error {
+	p := &s.partitions[partition]
+	if p.streams == 0 {
+		return nil
+	}
+	if err := p.w.Flush(); err != nil {
+		return fmt.Errorf("flush spill file: %w", err)
+	}
+	if err := p.file.Close(); err != nil {
+		return fmt.Errorf("close spill file: %w", err)
+	}
+	streams := p.streams
+	*p = spillPartition{}
+
+	f, err := s.fs.OpenFile(s.path(partition), os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("open spill file: %w", err)
+	}
+	defer f.Close()
+
+	for i := 0; i < streams; i++ {
+		if err := s.readStream(f, fn); err != nil {
+			return err
+		}
+	}
+	return s.fs.RemoveAll(s.path(partition))
+}
+
+func (s *spillFiles) readStream(f vfs.File, fn func(arrow.Record) error) error {
+	rdr, err := ipc.NewReader(f, ipc.WithAllocator(s.pool))
+	if err != nil {
+		return fmt.Errorf("read spill file: %w", err)
+	}
+	defer rdr.Release()
+
+	for rdr.Next() {
+		if err := fn(rdr.Record()); err != nil {
+			return err
+		}
+	}
+	if err := rdr.Err(); err != nil {
+		return fmt.Errorf("read spill file: %w", err)
+	}
+	return nil
+}
+
+// close closes all open files and removes the spilled state from disk.
+func (s *spillFiles) close() error {
+	for i := range s.partitions {
+		if f := s.partitions[i].file; f != nil {
+			_ = f.Close()
+		}
+		s.partitions[i] = spillPartition{}
+	}
+	return s.fs.RemoveAll(s.dir)
+}
+
+// hashRows returns the hashes of the values of the columns of the record that
+// match the given expressions for each row. Null values don't contribute to
+// the hash, so a row with a null value hashes the same as a row without the
+// column.
+func hashRows(seed maphash.Seed, r arrow.Record, columns []logicalplan.Expr) []uint64 {
+	hashes := make([]uint64, r.NumRows())
+	for i, field := range r.Schema().Fields() {
+		for _, col := range columns {
+			if !col.MatchColumn(field.Name) {
+				continue
+			}
+			fieldHash := scalar.Hash(seed, scalar.NewStringScalar(field.Name))
+			for j, hash := range dynparquet.HashArray(r.Column(i)) {
+				if hash == 0 {
+					continue
+				}
+				hashes[j] = hashCombine(hashes[j], hashCombine(fieldHash, hash))
+			}
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,219 @@
+package physicalplan
+
+import (
+	"context"
+	"hash/maphash"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	vfsadapters "github.com/youscentia/ydb-frostdb/vfs/adapters"
+)
+
+func testSpillOptions(t *testing.T) *spillOptions {
+	return &spillOptions{
+		// Every operator exceeds the budget as soon as it holds any state.
+		budget: &memoryBudget{limit: 1},
+		fs:     vfsadapters.NewOSAdapter(),
+		dir:    t.TempDir(),
+	}
+}
+
+func stringInt64Record(t *testing.T, pool memory.Allocator, names []string, strs []string, ints []int64) arrow.Record {
+	t.Helper()
+
+	sb := array.NewStringBuilder(pool)
+	defer sb.Release()
+	sb.AppendValues(strs, nil)
+	s := sb.NewArray()
+	defer s.Release()
+
+	ib := array.NewInt64Builder(pool)
+	defer ib.Release()
+	ib.AppendValues(ints, nil)
+	i := ib.NewArray()
+	defer i.Release()
+
+	return array.NewRecord(
+		arrow.NewSchema([]arrow.Field{
+			{Name: names[0], Type: arrow.BinaryTypes.String},
+			{Name: names[1], Type: arrow.PrimitiveTypes.Int64},
+		}, nil),
+		[]arrow.Array{s, i},
+		int64(len(strs)),
+	)
+}
+
+func TestHashAggregateSpill(t *testing.T) {
+	ctx := context.Background()
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	spill := testSpillOptions(t)
+	agg := NewHashAggregate(
+		pool,
+		noop.NewTracerProvider().Tracer(""),
+		[]Aggregation{{
+			expr:       logicalplan.Col("value"),
+			resultName: "sum(value)",
+			function:   logicalplan.AggFuncSum,
+		}},
+		[]logicalplan.Expr{logicalplan.Col("name")},
+		maphash.MakeSeed(),
+		true,
+	)
+	agg.spillTo(spill)
+
+	result := map[string]int64{}
+	agg.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			require.Equal(t, "name", r.Schema().Field(0).Name)
+			require.Equal(t, "sum(value)", r.Schema().Field(1).Name)
+			names := r.Column(0).(*array.String)
+			sums := r.Column(1).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				_, ok := result[names.Value(i)]
+				require.False(t, ok, "group %s emitted twice", names.Value(i))
+				result[names.Value(i)] = sums.Value(i)
+			}
+			return nil
+		},
+	})
+
+	// The input of the final stage are the partial aggregations of the
+	// previous stages.
+	for _, in := range []struct {
+		names []string
+		sums  []int64
+	}{
+		{names: []string{"a", "b", "c"}, sums: []int64{1, 2, 3}},
+		{names: []string{"b", "c", "d"}, sums: []int64{4, 5, 6}},
+		{names: []string{"a", "d", "e"}, sums: []int64{7, 8, 9}},
+	} {
+		r := stringInt64Record(t, pool, []string{"name", "sum(value)"}, in.names, in.sums)
+		require.NoError(t, agg.Callback(ctx, r))
+		r.Release()
+		require.NotNil(t, agg.spilled)
+		// Spilling releases the state from the budget.
+		require.Zero(t, spill.budget.used.Load())
+	}
+
+	require.NoError(t, agg.Finish(ctx))
+	agg.Close()
+
+	require.Equal(t, map[string]int64{"a": 8, "b": 6, "c": 8, "d": 14, "e": 9}, result)
+	entries, err := spill.fs.ReadDir(spill.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestDistinctionSpill(t *testing.T) {
+	ctx := context.Background()
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	spill := testSpillOptions(t)
+	d := Distinct(pool, noop.NewTracerProvider().Tracer(""), []logicalplan.Expr{logicalplan.Col("name"), logicalplan.Col("value")})
+	d.spillTo(spill)
+
+	type row struct {
+		name  string
+		value int64
+	}
+	var result []row
+	d.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			names := r.Column(0).(*array.String)
+			values := r.Column(1).(*array.Int64)
+			for i := 0; i < int(r.NumRows()); i++ {
+				result = append(result, row{name: names.Value(i), value: values.Value(i)})
+			}
+			return nil
+		},
+	})
+
+	for _, in := range []struct {
+		names  []string
+		values []int64
+	}{
+		// The distinction spills after the first record.
+		{names: []string{"a", "b", "a"}, values: []int64{1, 1, 1}},
+		{names: []string{"a", "b", "c"}, values: []int64{1, 2, 1}},
+		{names: []string{"c", "b", "a"}, values: []int64{1, 2, 2}},
+	} {
+		r := stringInt64Record(t, pool, []string{"name", "value"}, in.names, in.values)
+		require.NoError(t, d.Callback(ctx, r))
+		r.Release()
+	}
+	require.NotNil(t, d.spilledRows)
+
+	require.NoError(t, d.Finish(ctx))
+	d.Close()
+
+	require.ElementsMatch(t, []row{
+		{name: "a", value: 1},
+		{name: "b", value: 1},
+		{name: "b", value: 2},
+		{name: "c", value: 1},
+		{name: "a", value: 2},
+	}, result)
+	entries, err := spill.fs.ReadDir(spill.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func spilledStreams(s *spillFiles) int {
+	streams := 0
+	for _, p := range s.partitions {
+		streams += p.streams
+	}
+	return streams
+}
+
+func TestDistinctionSpillReleasesBudget(t *testing.T) {
+	ctx := context.Background()
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	spill := testSpillOptions(t)
+	d := Distinct(pool, noop.NewTracerProvider().Tracer(""), []logicalplan.Expr{logicalplan.Col("name"), logicalplan.Col("value")})
+	d.spillTo(spill)
+
+	var result []string
+	d.SetNext(&OutputPlan{
+		callback: func(_ context.Context, r arrow.Record) error {
+			names := r.Column(0).(*array.String)
+			for i := 0; i < int(r.NumRows()); i++ {
+				result = append(result, names.Value(i))
+			}
+			return nil
+		},
+	})
+
+	r := stringInt64Record(t, pool, []string{"name", "value"}, []string{"a", "b"}, []int64{1, 1})
+	require.NoError(t, d.Callback(ctx, r))
+	r.Release()
+	require.NotNil(t, d.spilledSeen)
+	require.Zero(t, spill.budget.used.Load())
+
+	// Once the state has been spilled, the next record fits into the budget
+	// again and stays in memory.
+	spill.budget.limit = 1 << 20
+	r = stringInt64Record(t, pool, []string{"name", "value"}, []string{"b", "c", "c"}, []int64{1, 1, 1})
+	require.NoError(t, d.Callback(ctx, r))
+	r.Release()
+	require.Len(t, d.pending, 1)
+	require.Zero(t, spilledStreams(d.spilledRows))
+	require.Equal(t, []string{"a", "b"}, result)
+
+	require.NoError(t, d.Finish(ctx))
+	d.Close()
+
+	require.ElementsMatch(t, []string{"a", "b", "c"}, result)
+	require.Zero(t, spill.budget.used.Load())
+}
@@ -0,0 +1,42 @@
+package physicalplan
+
+import (
+	"context"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// WorkerLimiter limits the number of workers that queries execute with in
+// total. A query acquires a worker for each of its parallel pipelines before
+// it starts executing and waits until enough workers are available, so
+// concurrent queries queue instead of each running as many goroutines as they
+// like. A WorkerLimiter can be shared by multiple engines.
+type WorkerLimiter struct {
+	workers int
+	sem     *semaphore.Weighted
+}
+
+// NewWorkerLimiter returns a limiter of the given number of workers.
+func NewWorkerLimiter(workers int) *WorkerLimiter {
+	if workers < 1 {
+		workers = 1
+	}
+	return &WorkerLimiter{
+		workers: workers,
+		sem:     semaphore.NewWeighted(int64(workers)),
+	}
+}
+
+// Workers returns the number of workers of the limiter.
+func (l *WorkerLimiter) Workers() int {
+	return l.workers
+}
+
+// acquire waits until n workers are available and returns a function to
+// release them again.
+func (l *WorkerLimiter) acquire(ctx context.Context, n int) (func(), error) {
+	if err := l.sem.Acquire(ctx, int64(n)); err != nil {
+		return nil, err
+	}
+	return func() { l.sem.Release(int64(n)) }, nil
+}
@@ -0,0 +1,174 @@
+package frostdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+)
+
+// ReplicationPolicy determines how blocks are persisted when a database has
+// more than one sink.
+type ReplicationPolicy int
+
+const (
+	// ReplicateToAll requires a block to be uploaded to all sinks. If any
+	// upload fails, the block is removed from the sinks it was already
+	// uploaded to and persisting the block fails.
+	ReplicateToAll ReplicationPolicy = iota
+	// ReplicateBestEffort only requires a block to be uploaded to the
+	// primary sink, which is the first one configured and must be a
+	// ReadableSink. Failed uploads to secondary sinks are queued and retried
+	// periodically (see WithReplicationRetryInterval) with the data of the
+	// block read back from the primary sink.
+	//
+	// The queue of failed uploads is only held in memory. Uploads that are
+	// still queued when the database is closed, or that are pushed out of
+	// the queue once it is full, are given up on: they are logged as errors
+	// and counted by the block_replications_dropped_total metric, and the
+	// secondary sink misses the block until it is replicated by other means.
+	ReplicateBestEffort
+)
+
+// DefaultReplicationRetryInterval is the default interval at which databases
+// retry uploading blocks to the secondary sinks that failed to receive them.
+const DefaultReplicationRetryInterval = time.Minute
+
+// maxPendingReplications is the maximum number of failed uploads a database
+// keeps around to retry. Once the limit is reached the oldest ones are
+// dropped.
+const maxPendingReplications = 64
+
+// pendingReplication is an upload of a block to a secondary sink that failed.
+// The data of the block is read back from the primary sink when retrying.
+type pendingReplication struct {
+	table *Table
+	sink  DataSink
+	block ulid.ULID
+	name  string
+}
+
+type replicationQueue struct {
+	mtx     sync.Mutex
+	pending []pendingReplication
+}
+
+func (q *replicationQueue) add(logger log.Logger, r pendingReplication) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if len(q.pending) >= maxPendingReplications {
+		q.pending[0].drop(logger, "too many pending replications, giving up on block")
+		q.pending = q.pending[1:]
+	}
+	q.pending = append(q.pending, r)
+}
+
+// take removes all pending replications from the queue and returns them.
+func (q *replicationQueue) take() []pendingReplication {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	pending := q.pending
+	q.pending = nil
+	return pending
+}
+
+// len returns the number of pending replications.
+func (q *replicationQueue) len() int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return len(q.pending)
+}
+
+// drop gives up on the replication. The secondary sink misses the block.
+func (r pendingReplication) drop(logger log.Logger, msg string) {
+	level.Error(logger).Log(
+		"msg", msg,
+		"block", r.name,
+		"sink", r.sink.String(),
+	)
+	r.table.metrics.replicationsDropped.Inc()
+}
+
+func (r pendingReplication) upload(ctx context.Context, primary ReadableSink) error {
+	data, err := primary.ReadBlock(ctx, filepath.Join(r.table.db.name, r.table.Name()), r.block)
+	if err != nil {
+		return fmt.Errorf("read block from %s: %w", primary, err)
+	}
+	if err := r.sink.Upload(ctx, r.name, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return r.table.addToCatalog(ctx, r.sink, r.block)
+}
+
+// startReplication starts a goroutine that periodically retries the uploads
+// to secondary sinks that failed. It is a no-op unless the database
+// replicates blocks on a best effort basis to multiple sinks and the retry
+// interval is enabled.
+func (db *DB) startReplication() {
+	interval := db.columnStore.replicationRetryInterval
+	if interval <= 0 || len(db.sinks) < 2 || db.columnStore.replicationPolicy != ReplicateBestEffort {
+		return
+	}
+	// New makes sure the primary sink is readable under this policy.
+	primary := db.sinks[0].(ReadableSink)
+
+	var ctx context.Context
+	ctx, db.replicationDone = context.WithCancel(context.Background())
+	db.replicationWg.Add(1)
+	go func() {
+		defer db.replicationWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				db.retryReplications(ctx, primary)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopReplication stops the replication goroutine, if any, and waits for it
+// to exit. The replications that are still pending are dropped, as the queue
+// isn't persisted.
+func (db *DB) stopReplication() {
+	if db.replicationDone != nil {
+		db.replicationDone()
+		db.replicationWg.Wait()
+	}
+	for _, r := range db.replication.take() {
+		r.drop(db.logger, "database closed with pending replication, giving up on block")
+	}
+}
+
+// retryReplications retries all pending replications with the data of the
+// blocks read back from the primary sink. Uploads that fail again are queued
+// for the next attempt.
+func (db *DB) retryReplications(ctx context.Context, primary ReadableSink) {
+	for _, r := range db.replication.take() {
+		if ctx.Err() != nil {
+			db.replication.add(db.logger, r)
+			continue
+		}
+		if err := r.upload(ctx, primary); err != nil {
+			level.Warn(db.logger).Log(
+				"msg", "failed to replicate block",
+				"block", r.name,
+				"sink", r.sink.String(),
+				"err", err,
+			)
+			r.table.metrics.replicationsFailed.Inc()
+			db.replication.add(db.logger, r)
+			continue
+		}
+		level.Debug(db.logger).Log("msg", "replicated block", "block", r.name, "sink", r.sink.String())
+	}
+}
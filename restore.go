@@ -0,0 +1,303 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+
+	snapshotpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/snapshot/v1alpha1"
+	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/vfs"
+	"github.com/youscentia/ydb-frostdb/wal"
+)
+
+// restorePath is the directory in the storage path of a column store in which
+// restores are staged before they are moved to the databases directory.
+const restorePath = "restore"
+
+// errRestoreTargetReached stops WAL replays at the target of a restore.
+var errRestoreTargetReached = errors.New("restore target reached")
+
+// errRestorePersistedBlocks is returned by restores that would miss the blocks
+// persisted to the sinks of the column store before the target.
+var errRestorePersistedBlocks = errors.New("persisted blocks are not restored")
+
+// RestoreTarget is the point in time a database is restored to (see
+// RestoreDB).
+type RestoreTarget struct {
+	// Tx restores the database as of the given txn, i.e. with the writes and
+	// deletes up to and including it.
+	Tx uint64
+	// Time restores the database as of the last txn logged at or before the
+	// given time. It is only used if Tx is zero. Records logged by versions
+	// that didn't record the time are considered to be logged before it.
+	Time time.Time
+}
+
+// RestoreDB restores the database with the given name as it was at the target
+// into a new database named "<name>-restore-<tx>" and opens it. The latest
+// valid snapshot at or before the target txn is loaded and the WAL is replayed
+// up to exactly the target txn. The database being restored is only read from,
+// so it can be open while it is restored.
+//
+// The column store must have a WAL and a storage path. Only the data in the
+// snapshots and the WAL of the database is restored, blocks the database
+// persisted to its sinks are not, so the restore fails if the column store has
+// sinks and a table of the database persisted a block before the target.
+func (s *ColumnStore) RestoreDB(ctx context.Context, name string, target RestoreTarget, opts ...DBOption) (*DB, error) {
+	if !s.enableWAL || s.storagePath == "" {
+		return nil, errors.New("restoring a database requires a WAL and a storage path")
+	}
+	if !validateName(name) {
+		return nil, errors.New("invalid database name")
+	}
+	if target.Tx == 0 && target.Time.IsZero() {
+		return nil, errors.New("restore target must have a txn or a time")
+	}
+	srcDir := filepath.Join(s.DatabasesDir(), name)
+	if _, err := s.fs.Stat(srcDir); err != nil {
+		return nil, fmt.Errorf("database %s: %w", name, err)
+	}
+
+	// Stage the restore outside the databases directory so a failed restore
+	// is not opened as a database.
+	stageDir := filepath.Join(s.storagePath, restorePath, ulid.Make().String())
+	defer func() {
+		if err := s.fs.RemoveAll(stageDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to remove restore staging directory", "dir", stageDir, "err", err)
+		}
+	}()
+	dbDir := filepath.Join(stageDir, "db")
+
+	// Copy the WAL before the snapshots. The WAL is only truncated after a
+	// snapshot is taken, so the copied snapshots cover the start of the
+	// copied WAL. The copy is not modified, the WAL of the restored
+	// database is written from its records.
+	srcWALDir := filepath.Join(stageDir, walPath)
+	if err := copyDir(s.fs, filepath.Join(srcDir, walPath), srcWALDir); err != nil {
+		return nil, fmt.Errorf("copy WAL: %w", err)
+	}
+	srcWAL, err := wal.Open(s.logger, srcWALDir, s.fs)
+	if err != nil {
+		return nil, fmt.Errorf("open WAL: %w", err)
+	}
+	tx, err := s.stageRestore(ctx, srcWAL, srcDir, dbDir, target)
+	// The WAL only closes its store if it was started.
+	srcWAL.RunAsync()
+	if closeErr := srcWAL.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	restoredName := fmt.Sprintf("%s-restore-%d", name, tx)
+	restoredDir := filepath.Join(s.DatabasesDir(), restoredName)
+	s.mtx.RLock()
+	_, ok := s.dbs[restoredName]
+	s.mtx.RUnlock()
+	if _, err := s.fs.Stat(restoredDir); ok || err == nil {
+		return nil, fmt.Errorf("restored database %s already exists", restoredName)
+	}
+	if err := s.fs.Rename(dbDir, restoredDir); err != nil {
+		return nil, fmt.Errorf("move restored database: %w", err)
+	}
+
+	level.Info(s.logger).Log("msg", "restoring database", "db", name, "restored_db", restoredName, "tx", tx)
+	db, err := s.DB(ctx, restoredName, opts...)
+	if err != nil {
+		if err := s.fs.RemoveAll(restoredDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to remove restored database", "db", restoredName, "err", err)
+		}
+		return nil, fmt.Errorf("open restored database: %w", err)
+	}
+	return db, nil
+}
+
+// stageRestore resolves the target txn of a restore and stages the storage of
+// the restored database in dbDir: the latest valid snapshot at or before the
+// target txn, and a WAL with the records of srcWAL after the snapshot up to the
+// target txn. It returns the target txn.
+func (s *ColumnStore) stageRestore(ctx context.Context, srcWAL *wal.FileWAL, srcDir, dbDir string, target RestoreTarget) (uint64, error) {
+	firstIndex, err := srcWAL.FirstIndex()
+	if err != nil {
+		return 0, fmt.Errorf("read WAL first index: %w", err)
+	}
+	lastIndex, err := srcWAL.LastIndex()
+	if err != nil {
+		return 0, fmt.Errorf("read WAL last index: %w", err)
+	}
+
+	tx := target.Tx
+	if tx == 0 {
+		// Find the last txn logged at or before the target time.
+		if firstIndex == 0 {
+			return 0, errors.New("WAL is empty, the restore target time cannot be resolved")
+		}
+		tx = firstIndex - 1
+		if err := srcWAL.Replay(firstIndex, func(recordTx uint64, record *walpb.Record) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if record.Timestamp > target.Time.UnixMilli() {
+				return errRestoreTargetReached
+			}
+			tx = recordTx
+			return nil
+		}); err != nil && !errors.Is(err, errRestoreTargetReached) {
+			return 0, fmt.Errorf("replay WAL: %w", err)
+		}
+	}
+	if tx == 0 {
+		return 0, errors.New("no transactions before the restore target")
+	}
+
+	srcSnapshotsDir := filepath.Join(srcDir, snapshotsPath)
+	snapshotTx := uint64(0)
+	if err := snapshotsDo(ctx, s.fs, srcSnapshotsDir, func(parsedTx uint64, entry os.DirEntry) (bool, error) {
+		if parsedTx > tx {
+			return true, nil
+		}
+		footer, err := readSnapshotFooter(s.fs, filepath.Join(srcSnapshotsDir, entry.Name(), snapshotFileName(parsedTx)))
+		if err != nil {
+			level.Debug(s.logger).Log("msg", "skipping invalid snapshot in restore", "tx", parsedTx, "err", err)
+			return true, nil
+		}
+		if err := s.checkRestoreSnapshot(footer); err != nil {
+			return false, err
+		}
+		if err := copyDir(s.fs, filepath.Join(srcSnapshotsDir, entry.Name()), filepath.Join(dbDir, snapshotsPath, entry.Name())); err != nil {
+			return false, fmt.Errorf("copy snapshot: %w", err)
+		}
+		snapshotTx = parsedTx
+		return false, nil
+	}); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	if tx == snapshotTx {
+		// The snapshot holds the database at the target txn.
+		return tx, nil
+	}
+	if tx > lastIndex {
+		return 0, fmt.Errorf("restore target txn %d is after the last WAL record %d", tx, lastIndex)
+	}
+	if firstIndex == 0 || firstIndex > snapshotTx+1 {
+		return 0, fmt.Errorf("WAL records between snapshot txn %d and restore target txn %d are missing", snapshotTx, tx)
+	}
+
+	w, err := wal.Open(s.logger, filepath.Join(dbDir, walPath), s.fs)
+	if err != nil {
+		return 0, fmt.Errorf("open restored WAL: %w", err)
+	}
+	if err := w.Reset(snapshotTx + 1); err != nil {
+		return 0, fmt.Errorf("reset restored WAL: %w", err)
+	}
+	w.RunAsync()
+	err = srcWAL.Replay(snapshotTx+1, func(recordTx uint64, record *walpb.Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if recordTx > tx {
+			return errRestoreTargetReached
+		}
+		if persisted := record.Entry.GetTableBlockPersisted(); persisted != nil && len(s.sinks) > 0 {
+			return fmt.Errorf("table %s persisted a block at txn %d before the restore target: %w", persisted.TableName, recordTx, errRestorePersistedBlocks)
+		}
+		return w.Log(recordTx, record)
+	})
+	if errors.Is(err, errRestoreTargetReached) {
+		err = nil
+	}
+	if err == nil {
+		err = w.WaitDurable(tx)
+	}
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("write restored WAL: %w", err)
+	}
+	return tx, nil
+}
+
+// checkRestoreSnapshot returns an error if the column store has sinks and a
+// table of the snapshot rotated a block before it, which the restored database
+// wouldn't read.
+func (s *ColumnStore) checkRestoreSnapshot(footer *snapshotpb.FooterData) error {
+	if len(s.sinks) == 0 {
+		return nil
+	}
+	for _, table := range footer.TableMetadata {
+		if table.ActiveBlock.GetPrevTx() != 0 {
+			return fmt.Errorf("table %s persisted a block before the restore target: %w", table.Name, errRestorePersistedBlocks)
+		}
+	}
+	return nil
+}
+
+// readSnapshotFooter validates the checksum of the snapshot file at the given
+// path and returns its footer.
+func readSnapshotFooter(fs vfs.FileSystem, path string) (*snapshotpb.FooterData, error) {
+	f, err := fs.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// readFooter validates the checksum.
+	return readFooter(f, info.Size())
+}
+
+// copyDir recursively copies the directory src to dst.
+func copyDir(fs vfs.FileSystem, src, dst string) error {
+	entries, err := fs.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(dst, dirPerms); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := copyDir(fs, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFile(fs, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(fs vfs.FileSystem, src, dst string) error {
+	in, err := fs.OpenFile(src, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePerms)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,126 @@
+package frostdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log/level"
+
+	tablepb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/table/v1alpha1"
+)
+
+// DefaultRetentionInterval is the default interval at which databases remove
+// the persisted blocks that fall outside the retention of their tables.
+const DefaultRetentionInterval = 5 * time.Minute
+
+// maxRetentionMs is the longest retention in milliseconds that can be
+// represented as a time.Duration.
+const maxRetentionMs = math.MaxInt64 / uint64(time.Millisecond)
+
+// validateRetention returns an error if the retention of the given table
+// config would overflow when converted to a time.Duration, which would move
+// the cutoff of the retention loop into the future and remove every block.
+func validateRetention(config *tablepb.TableConfig) error {
+	if config.RetentionMs > maxRetentionMs {
+		return fmt.Errorf("retention of %dms exceeds the maximum of %dms", config.RetentionMs, maxRetentionMs)
+	}
+	return nil
+}
+
+// startRetention starts a goroutine that periodically enforces the retention
+// of the database's tables. It is a no-op if the database has no sinks or the
+// retention interval is disabled.
+func (db *DB) startRetention() {
+	interval := db.columnStore.retentionInterval
+	if interval <= 0 || len(db.sinks) == 0 {
+		return
+	}
+
+	var ctx context.Context
+	ctx, db.retentionDone = context.WithCancel(context.Background())
+	db.retentionWg.Add(1)
+	go func() {
+		defer db.retentionWg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := db.enforceRetention(ctx, time.Now()); err != nil {
+					level.Error(db.logger).Log("msg", "failed to enforce retention", "err", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopRetention stops the retention goroutine, if any, and waits for it to
+// exit.
+func (db *DB) stopRetention() {
+	if db.retentionDone != nil {
+		db.retentionDone()
+		db.retentionWg.Wait()
+	}
+}
+
+// enforceRetention removes the persisted blocks of the tables with a retention
+// set whose ULID timestamp is older than the table's retention at the given
+// time from all sinks that support it. All tables are processed even if
+// removing the blocks of one fails, in which case the first error is
+// returned.
+func (db *DB) enforceRetention(ctx context.Context, now time.Time) error {
+	db.mtx.RLock()
+	tables := make([]*Table, 0, len(db.tables))
+	for _, table := range db.tables {
+		tables = append(tables, table)
+	}
+	db.mtx.RUnlock()
+
+	var firstErr error
+	for _, table := range tables {
+		config := table.config.Load()
+		if config == nil || config.RetentionMs == 0 {
+			continue
+		}
+		before := now.Add(-time.Duration(config.RetentionMs) * time.Millisecond)
+
+		for _, sink := range db.sinks {
+			rs, ok := sink.(RetentionSink)
+			if !ok {
+				continue
+			}
+
+			blocks, bytes, err := rs.DeleteBlocksBefore(ctx, filepath.Join(db.name, table.Name()), before)
+			// Account for blocks removed before a failure as well.
+			table.metrics.blocksReclaimed.Add(float64(blocks))
+			table.metrics.blockBytesReclaimed.Add(float64(bytes))
+			if err != nil {
+				level.Warn(db.logger).Log(
+					"msg", "failed to remove expired blocks",
+					"table", table.Name(),
+					"sink", sink.String(),
+					"err", err,
+				)
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if blocks > 0 {
+				level.Debug(db.logger).Log(
+					"msg", "removed expired blocks",
+					"table", table.Name(),
+					"sink", sink.String(),
+					"blocks", blocks,
+					"bytes", bytes,
+				)
+			}
+		}
+	}
+	return firstErr
+}
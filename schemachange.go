@@ -0,0 +1,510 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log/level"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	schemapb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha1"
+	schemav2pb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/schema/v1alpha2"
+	tablepb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/table/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/pqarrow"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+// SchemaChange is a change of the schema definition of a table applied by
+// DB.AlterTable. The definition is a *schemapb.Schema or a *schemav2pb.Schema,
+// depending on the schema the table was created with.
+type SchemaChange func(def proto.Message) error
+
+// AddColumn adds a column to the schema. The column is a *schemapb.Column for
+// schemapb schemas and a *schemav2pb.Node for schemav2pb schemas. It must be
+// nullable or repeated, as the rows written before it was added have no value
+// for it.
+func AddColumn(column proto.Message) SchemaChange {
+	return func(def proto.Message) error {
+		switch def := def.(type) {
+		case *schemapb.Schema:
+			col, ok := column.(*schemapb.Column)
+			if !ok {
+				return fmt.Errorf("column of type %T cannot be added to a schema of type %T", column, def)
+			}
+			for _, c := range def.Columns {
+				if c.Name == col.Name {
+					return fmt.Errorf("column %q already exists", col.Name)
+				}
+			}
+			def.Columns = append(def.Columns, proto.Clone(col).(*schemapb.Column))
+		case *schemav2pb.Schema:
+			node, ok := column.(*schemav2pb.Node)
+			if !ok {
+				return fmt.Errorf("column of type %T cannot be added to a schema of type %T", column, def)
+			}
+			name := nodeName(node)
+			for _, n := range def.Root.Nodes {
+				if nodeName(n) == name {
+					return fmt.Errorf("column %q already exists", name)
+				}
+			}
+			def.Root.Nodes = append(def.Root.Nodes, proto.Clone(node).(*schemav2pb.Node))
+		default:
+			return fmt.Errorf("unsupported schema type: %T", def)
+		}
+		return nil
+	}
+}
+
+// DropColumn drops the column with the given name from the schema. For
+// schemav2pb schemas the name is the name of a top-level node. Sorting columns
+// cannot be dropped. The values of the column are not read anymore and are
+// removed from persisted blocks when they are compacted.
+func DropColumn(name string) SchemaChange {
+	return func(def proto.Message) error {
+		switch def := def.(type) {
+		case *schemapb.Schema:
+			for i, c := range def.Columns {
+				if c.Name == name {
+					def.Columns = append(def.Columns[:i], def.Columns[i+1:]...)
+					return nil
+				}
+			}
+		case *schemav2pb.Schema:
+			for i, n := range def.Root.Nodes {
+				if nodeName(n) == name {
+					def.Root.Nodes = append(def.Root.Nodes[:i], def.Root.Nodes[i+1:]...)
+					return nil
+				}
+			}
+		default:
+			return fmt.Errorf("unsupported schema type: %T", def)
+		}
+		return fmt.Errorf("column %q not found", name)
+	}
+}
+
+// SetColumnEncoding changes the encoding of the column with the given name.
+// For schemav2pb schemas the name is the name of a leaf node. The encoding of
+// schemapb schemas is set to the encoding with the same value.
+func SetColumnEncoding(name string, encoding schemav2pb.StorageLayout_Encoding) SchemaChange {
+	return changeStorageLayout(name,
+		func(l *schemapb.StorageLayout) { l.Encoding = schemapb.StorageLayout_Encoding(encoding) },
+		func(l *schemav2pb.StorageLayout) { l.Encoding = encoding },
+	)
+}
+
+// SetColumnCompression changes the compression of the column with the given
+// name. For schemav2pb schemas the name is the name of a leaf node. The
+// compression of schemapb schemas is set to the compression with the same
+// value.
+func SetColumnCompression(name string, compression schemav2pb.StorageLayout_Compression) SchemaChange {
+	return changeStorageLayout(name,
+		func(l *schemapb.StorageLayout) { l.Compression = schemapb.StorageLayout_Compression(compression) },
+		func(l *schemav2pb.StorageLayout) { l.Compression = compression },
+	)
+}
+
+func changeStorageLayout(name string, v1 func(*schemapb.StorageLayout), v2 func(*schemav2pb.StorageLayout)) SchemaChange {
+	return func(def proto.Message) error {
+		switch def := def.(type) {
+		case *schemapb.Schema:
+			for _, c := range def.Columns {
+				if c.Name == name {
+					if c.StorageLayout == nil {
+						c.StorageLayout = &schemapb.StorageLayout{}
+					}
+					v1(c.StorageLayout)
+					return nil
+				}
+			}
+		case *schemav2pb.Schema:
+			if leaf := findLeaf(def.Root.Nodes, name); leaf != nil {
+				if leaf.StorageLayout == nil {
+					leaf.StorageLayout = &schemav2pb.StorageLayout{}
+				}
+				v2(leaf.StorageLayout)
+				return nil
+			}
+		default:
+			return fmt.Errorf("unsupported schema type: %T", def)
+		}
+		return fmt.Errorf("column %q not found", name)
+	}
+}
+
+// AddSortingColumn appends a sorting column to the sorting columns of the
+// schema. The sorting column is a *schemapb.SortingColumn for schemapb schemas
+// and a *schemav2pb.SortingColumn for schemav2pb schemas. Rows written before
+// it was added are sorted as if their value for it was null.
+func AddSortingColumn(column proto.Message) SchemaChange {
+	return func(def proto.Message) error {
+		switch def := def.(type) {
+		case *schemapb.Schema:
+			col, ok := column.(*schemapb.SortingColumn)
+			if !ok {
+				return fmt.Errorf("sorting column of type %T cannot be added to a schema of type %T", column, def)
+			}
+			def.SortingColumns = append(def.SortingColumns, proto.Clone(col).(*schemapb.SortingColumn))
+		case *schemav2pb.Schema:
+			col, ok := column.(*schemav2pb.SortingColumn)
+			if !ok {
+				return fmt.Errorf("sorting column of type %T cannot be added to a schema of type %T", column, def)
+			}
+			def.SortingColumns = append(def.SortingColumns, proto.Clone(col).(*schemav2pb.SortingColumn))
+		default:
+			return fmt.Errorf("unsupported schema type: %T", def)
+		}
+		return nil
+	}
+}
+
+func nodeName(node *schemav2pb.Node) string {
+	if leaf := node.GetLeaf(); leaf != nil {
+		return leaf.Name
+	}
+	return node.GetGroup().GetName()
+}
+
+func findLeaf(nodes []*schemav2pb.Node, name string) *schemav2pb.Leaf {
+	for _, node := range nodes {
+		if leaf := node.GetLeaf(); leaf != nil && leaf.Name == name {
+			return leaf
+		}
+		if group := node.GetGroup(); group != nil {
+			if leaf := findLeaf(group.Nodes, name); leaf != nil {
+				return leaf
+			}
+		}
+	}
+	return nil
+}
+
+// AlterTable changes the schema of the table with the given name. The changes
+// are applied in order and the resulting schema is validated: columns that
+// are kept can only change their encoding and compression, and sorting
+// columns can only be added after the existing ones. The schema version of the
+// table config is incremented and the active block is rotated, so every block
+// is written with a single version of the schema. Blocks and persisted files
+// written with previous versions are read with the columns that were added
+// since filled with nulls, and without the columns that were dropped.
+func (db *DB) AlterTable(ctx context.Context, name string, changes ...SchemaChange) error {
+	if db.leader != nil {
+		return ErrFollower
+	}
+	if len(changes) == 0 {
+		return errors.New("no schema changes")
+	}
+
+	table, err := db.GetTable(name)
+	if err != nil {
+		return err
+	}
+	tx, version, err := table.alterSchema(changes)
+	if err != nil {
+		return err
+	}
+	level.Info(db.logger).Log("msg", "altered table schema", "table", name, "schema_version", version, "tx", tx)
+
+	return db.waitDurable(ctx, table.wal, tx)
+}
+
+// alterSchema applies the changes to the schema of the table and rotates the
+// active block. It returns the txn the new block was created at and the new
+// schema version.
+func (t *Table) alterSchema(changes []SchemaChange) (uint64, uint64, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.closing {
+		return 0, 0, ErrTableClosing
+	}
+
+	prevConfig := t.config.Load()
+	config := proto.Clone(prevConfig).(*tablepb.TableConfig)
+	var def proto.Message
+	switch schema := config.Schema.(type) {
+	case *tablepb.TableConfig_DeprecatedSchema:
+		def = schema.DeprecatedSchema
+	case *tablepb.TableConfig_SchemaV2:
+		def = schema.SchemaV2
+	default:
+		return 0, 0, ErrNoSchema
+	}
+	for _, change := range changes {
+		if err := change(def); err != nil {
+			return 0, 0, fmt.Errorf("change schema: %w", err)
+		}
+	}
+
+	prevSchema := t.schema.Load()
+	schema, err := schemaFromChangedDefinition(def)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := validateSchemaChange(prevSchema, schema); err != nil {
+		return 0, 0, err
+	}
+	if err := validateDownsampling(schema, config.Downsampling); err != nil {
+		return 0, 0, err
+	}
+	config.SchemaVersion++
+
+	// The writes to the replaced block are persisted with the new schema.
+	block := t.active
+	block.pendingWritersWg.Wait()
+
+	tx, _, commit := t.db.begin()
+	defer commit()
+
+	id := generateULID()
+	for id.Time() == block.ulid.Time() { // Ensure the new block has a different timestamp.
+		time.Sleep(time.Millisecond)
+		id = generateULID()
+	}
+	// The new block is logged with the new config, which changes the schema
+	// when the log is replayed.
+	t.config.Store(config)
+	t.schema.Store(schema)
+	if err := t.newTableBlock(t.active.minTx, tx, id); err != nil {
+		t.config.Store(prevConfig)
+		t.schema.Store(prevSchema)
+		return tx, 0, err
+	}
+	t.metrics.blockRotated.Inc()
+	t.metrics.numParts.Set(float64(0))
+
+	t.pendingBlocks[block] = struct{}{}
+	go t.writeBlock(block, tx, t.db.columnStore.manualBlockRotation)
+	return tx, config.SchemaVersion, nil
+}
+
+// schemaFromChangedDefinition returns the schema of a changed definition.
+// Definitions that are invalid, e.g. with an encoding that cannot be applied
+// to the type of a column, make the schema constructors panic.
+func schemaFromChangedDefinition(def proto.Message) (schema *dynparquet.Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid schema: %v", r)
+		}
+	}()
+	schema, err = dynparquet.SchemaFromDefinition(def)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// validateSchemaChange validates that the data written with the previous
+// schema can be read with the next schema.
+func validateSchemaChange(prev, next *dynparquet.Schema) error {
+	if len(next.Columns()) == 0 {
+		return errors.New("schema must have at least one column")
+	}
+	if next.UniquePrimaryIndex != prev.UniquePrimaryIndex {
+		return errors.New("the unique primary index of a schema cannot be changed")
+	}
+	if proto.Equal(prev.Definition(), next.Definition()) {
+		return errors.New("schema is unchanged")
+	}
+
+	for _, col := range next.Columns() {
+		prevCol, ok := prev.ColumnByName(col.Name)
+		if !ok {
+			if !col.StorageLayout.Optional() && !col.StorageLayout.Repeated() {
+				return fmt.Errorf("added column %q must be nullable", col.Name)
+			}
+			continue
+		}
+		if col.Dynamic != prevCol.Dynamic || col.PreHash != prevCol.PreHash {
+			return fmt.Errorf("column %q cannot be changed between dynamic and prehashed", col.Name)
+		}
+		if col.StorageLayout.Type().String() != prevCol.StorageLayout.Type().String() ||
+			col.StorageLayout.Optional() != prevCol.StorageLayout.Optional() ||
+			col.StorageLayout.Repeated() != prevCol.StorageLayout.Repeated() {
+			return fmt.Errorf("the type of column %q cannot be changed", col.Name)
+		}
+	}
+
+	prevSorting := prev.SortingColumns()
+	nextSorting := next.SortingColumns()
+	if len(nextSorting) < len(prevSorting) {
+		return errors.New("sorting columns cannot be removed")
+	}
+	seen := make(map[string]struct{}, len(nextSorting))
+	for i, col := range nextSorting {
+		if i < len(prevSorting) && (col.ColumnName() != prevSorting[i].ColumnName() ||
+			col.Descending() != prevSorting[i].Descending() ||
+			col.NullsFirst() != prevSorting[i].NullsFirst()) {
+			return fmt.Errorf("sorting column %q cannot be changed, sorting columns can only be added at the end", prevSorting[i].ColumnName())
+		}
+		if _, ok := next.ColumnByName(col.ColumnName()); !ok {
+			return fmt.Errorf("sorting column %q not found", col.ColumnName())
+		}
+		if _, ok := seen[col.ColumnName()]; ok {
+			return fmt.Errorf("column %q is already a sorting column", col.ColumnName())
+		}
+		seen[col.ColumnName()] = struct{}{}
+	}
+	return nil
+}
+
+// updateConfig replaces the config of the table with the given config when
+// the table is looked up with DB.Table. The schema of the table and its
+// version are kept, the schema is only changed with DB.AlterTable, so that
+// looking up an altered table with the config it was created with keeps
+// working.
+func (t *Table) updateConfig(config *tablepb.TableConfig) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	current := t.config.Load()
+	if !proto.Equal(current.GetDeprecatedSchema(), config.GetDeprecatedSchema()) ||
+		!proto.Equal(current.GetSchemaV2(), config.GetSchemaV2()) ||
+		config.SchemaVersion != current.SchemaVersion {
+		config = proto.Clone(config).(*tablepb.TableConfig)
+		config.Schema = current.Schema
+		config.SchemaVersion = current.SchemaVersion
+	}
+	t.config.Store(config)
+}
+
+// schemaAdapter adapts the records read from a table whose schema was altered
+// to the current schema of the table. Records of blocks and persisted files
+// written with previous versions of the schema lack the columns that were
+// added since, which are filled with nulls, and contain the columns that were
+// dropped since, which are removed.
+type schemaAdapter struct {
+	schema *dynparquet.Schema
+	// fields are the fields of the non-dynamic columns of the schema that are
+	// read.
+	fields []arrow.Field
+	// index is the index of the fields by name.
+	index map[string]int
+	// computed are the names of the computed distinct columns that are read.
+	computed map[string]struct{}
+}
+
+// newSchemaAdapter returns the schema adapter for the reads of the table with
+// the given options, or nil if the schema of the table was never altered.
+func (t *Table) newSchemaAdapter(ctx context.Context, iterOpts logicalplan.IterOptions) (*schemaAdapter, error) {
+	config := t.config.Load()
+	schema := t.schema.Load()
+	if config == nil || schema == nil || config.SchemaVersion == 0 {
+		return nil, nil
+	}
+
+	if len(iterOpts.DistinctColumns) > 0 && len(iterOpts.PhysicalProjection) == 0 {
+		// Only the distinct columns are read.
+		iterOpts.PhysicalProjection = iterOpts.DistinctColumns
+	}
+	// The fields of the columns are converted like the fields of the parquet
+	// files written with the schema.
+	arrowSchema, err := pqarrow.ParquetSchemaToArrowSchema(ctx, schema.ParquetSchema(), schema, iterOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &schemaAdapter{
+		schema:   schema,
+		index:    map[string]int{},
+		computed: map[string]struct{}{},
+	}
+	for _, expr := range iterOpts.DistinctColumns {
+		if expr.Computed() {
+			a.computed[expr.Name()] = struct{}{}
+		}
+	}
+	for _, field := range arrowSchema.Fields() {
+		if _, ok := a.computed[field.Name]; ok {
+			continue
+		}
+		if _, ok := schema.FindDynamicColumn(field.Name); ok {
+			continue
+		}
+		a.index[field.Name] = len(a.fields)
+		a.fields = append(a.fields, field)
+	}
+	return a, nil
+}
+
+// adapt returns the record adapted to the schema. The returned record must be
+// released.
+func (a *schemaAdapter) adapt(pool memory.Allocator, r arrow.Record) arrow.Record {
+	fields := make([]arrow.Field, 0, r.Schema().NumFields()+len(a.fields))
+	cols := make([]arrow.Array, 0, r.Schema().NumFields()+len(a.fields))
+	changed := false
+
+	var nulls []arrow.Array
+	// next is the index of the next field of the schema to add to the record.
+	// The fields of the schema are added in the order of the schema, each
+	// from the column of the record with its name, or as a column of nulls if
+	// it was added to the schema after the record was written.
+	next := 0
+	addFields := func(end int) {
+		for ; next < end; next++ {
+			field := a.fields[next]
+			if indices := r.Schema().FieldIndices(field.Name); len(indices) > 0 {
+				fields = append(fields, r.Schema().Field(indices[0]))
+				cols = append(cols, r.Column(indices[0]))
+				continue
+			}
+			null := array.MakeArrayOfNull(pool, field.Type, int(r.NumRows()))
+			nulls = append(nulls, null)
+			field.Nullable = true
+			fields = append(fields, field)
+			cols = append(cols, null)
+			changed = true
+		}
+	}
+
+	for i, field := range r.Schema().Fields() {
+		if j, ok := a.index[field.Name]; ok {
+			if j >= next {
+				addFields(j + 1)
+			}
+			continue
+		}
+		if _, ok := a.computed[field.Name]; !ok && !a.schema.ContainsField(field.Name) {
+			// The column was dropped from the schema.
+			changed = true
+			continue
+		}
+		// Dynamic and computed columns are kept where they are in the record.
+		fields = append(fields, field)
+		cols = append(cols, r.Column(i))
+	}
+	addFields(len(a.fields))
+
+	if !changed {
+		for i, field := range fields {
+			if field.Name != r.Schema().Field(i).Name {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		r.Retain()
+		return r
+	}
+	adapted := array.NewRecord(arrow.NewSchema(fields, nil), cols, r.NumRows())
+	for _, null := range nulls {
+		null.Release()
+	}
+	return adapted
+}
+
+// callback returns a callback that adapts the records to the schema before
+// passing them to the given callback.
+func (a *schemaAdapter) callback(pool memory.Allocator, callback logicalplan.Callback) logicalplan.Callback {
+	return func(ctx context.Context, r arrow.Record) error {
+		r = a.adapt(pool, r)
+		defer r.Release()
+		return callback(ctx, r)
+	}
+}
@@ -0,0 +1,693 @@
+// Package flightsql implements an Arrow Flight SQL server on top of a FrostDB
+// column store, so that Flight SQL clients such as ADBC drivers and BI tools
+// can query it.
+package flightsql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	fsql "github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql/schema_ref"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/parquet-go/parquet-go"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	frostdb "github.com/youscentia/ydb-frostdb"
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	"github.com/youscentia/ydb-frostdb/pqarrow/arrowutils"
+	"github.com/youscentia/ydb-frostdb/pqarrow/convert"
+	"github.com/youscentia/ydb-frostdb/query"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	"github.com/youscentia/ydb-frostdb/query/physicalplan"
+	"github.com/youscentia/ydb-frostdb/sqlparse"
+)
+
+// tableType is the Flight SQL table type of all FrostDB tables.
+const tableType = "TABLE"
+
+// streamBuffer is the number of records of a statement that are buffered
+// while the client reads them.
+const streamBuffer = 4
+
+// Server is a Flight SQL server. The databases of the column store are
+// exposed as catalogs, and statements are executed against a single database.
+type Server struct {
+	fsql.BaseServer
+
+	logger   log.Logger
+	tracer   trace.Tracer
+	pool     memory.Allocator
+	store    *frostdb.ColumnStore
+	database string
+	parser   *sqlparse.Parser
+	execOpts []physicalplan.Option
+}
+
+type Option func(*Server)
+
+func WithLogger(logger log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+func WithTracer(tracer trace.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// WithAllocator sets the allocator used to execute queries.
+func WithAllocator(pool memory.Allocator) Option {
+	return func(s *Server) {
+		s.pool = pool
+	}
+}
+
+func WithPhysicalplanOptions(opts ...physicalplan.Option) Option {
+	return func(s *Server) {
+		s.execOpts = opts
+	}
+}
+
+// New returns a new Server exposing the databases of the given column store.
+// Statements are executed against the given database.
+func New(store *frostdb.ColumnStore, database string, options ...Option) *Server {
+	s := &Server{
+		logger:   log.NewNopLogger(),
+		tracer:   noop.NewTracerProvider().Tracer(""),
+		pool:     memory.DefaultAllocator,
+		store:    store,
+		database: database,
+		parser:   sqlparse.NewParser(),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.Alloc = s.pool
+	return s
+}
+
+// Register registers the server with the given Flight server.
+func (s *Server) Register(srv flight.Server) {
+	srv.RegisterFlightService(fsql.NewFlightServerWithAllocator(s, s.pool))
+}
+
+// GetFlightInfoStatement validates the statement and returns a ticket to
+// execute it with. The schema of the result is resolved from the plan of the
+// statement, the same way DoGetStatement does before executing it.
+func (s *Server) GetFlightInfoStatement(
+	ctx context.Context,
+	cmd fsql.StatementQuery,
+	desc *flight.FlightDescriptor,
+) (*flight.FlightInfo, error) {
+	if len(cmd.GetTransactionId()) > 0 {
+		return nil, status.Error(codes.Unimplemented, "transactions are not supported")
+	}
+
+	db, err := s.store.GetDB(s.database)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	plan, explain, err := s.parser.Parse(db.TableProvider(), cmd.GetQuery())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	schema := explainSchema
+	if !explain {
+		engine := query.NewEngine(s.pool, db.TableProvider(), query.WithTracer(s.tracer))
+		schema, err = (&schemaResolver{engine: engine}).planSchema(ctx, plan)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, status.FromContextError(ctxErr).Err()
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	ticket, err := fsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		FlightDescriptor: desc,
+		Schema:           flight.SerializeSchema(schema, s.pool),
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// DoGetStatement executes the statement of the ticket and streams its
+// records. Records of a FrostDB query may have different schemas due to
+// dynamic columns, but a Flight stream has a single schema, so the schema is
+// resolved from the plan before executing it and every record is conformed
+// to it. For EXPLAIN statements, a single record containing the plan is sent.
+func (s *Server) DoGetStatement(
+	ctx context.Context,
+	ticket fsql.StatementQueryTicket,
+) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	ctx, span := s.tracer.Start(ctx, "Server/DoGetStatement")
+	defer span.End()
+
+	db, err := s.store.GetDB(s.database)
+	if err != nil {
+		return nil, nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	sql := string(ticket.GetStatementHandle())
+	plan, explain, err := s.parser.Parse(db.TableProvider(), sql)
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	engine := query.NewEngine(
+		s.pool,
+		db.TableProvider(),
+		query.WithTracer(s.tracer),
+		query.WithPhysicalplanOptions(s.execOpts...),
+	)
+	builder := engine.FromPlan(plan)
+
+	if explain {
+		explanation, err := builder.Explain(ctx)
+		if err != nil {
+			return nil, nil, status.Error(codes.Internal, err.Error())
+		}
+		return s.explainResult(explanation)
+	}
+
+	schema, err := (&schemaResolver{engine: engine}).planSchema(ctx, plan)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// The stream context is canceled once the client is gone or the stream
+	// failed, after which the records are no longer read.
+	chunks := make(chan flight.StreamChunk, streamBuffer)
+	go func() {
+		defer close(chunks)
+		err := builder.Execute(ctx, func(ctx context.Context, r arrow.Record) error {
+			conformed, err := conformRecord(ctx, s.pool, r, schema)
+			if err != nil {
+				return err
+			}
+			select {
+			case chunks <- flight.StreamChunk{Data: conformed}:
+				return nil
+			case <-ctx.Done():
+				conformed.Release()
+				return ctx.Err()
+			}
+		})
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		level.Warn(s.logger).Log("msg", "statement execution failed", "statement", sql, "err", err)
+		select {
+		case chunks <- flight.StreamChunk{Err: status.Error(codes.Internal, err.Error())}:
+		case <-ctx.Done():
+		}
+	}()
+	return schema, chunks, nil
+}
+
+// explainSchema is the schema of the result of EXPLAIN statements.
+var explainSchema = arrow.NewSchema([]arrow.Field{{Name: "plan", Type: arrow.BinaryTypes.String}}, nil)
+
+func (s *Server) explainResult(explanation string) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	schema := explainSchema
+	b := array.NewStringBuilder(s.pool)
+	defer b.Release()
+	b.Append(explanation)
+	plan := b.NewArray()
+	defer plan.Release()
+
+	chunks := make(chan flight.StreamChunk, 1)
+	chunks <- flight.StreamChunk{Data: array.NewRecord(schema, []arrow.Array{plan}, 1)}
+	close(chunks)
+	return schema, chunks, nil
+}
+
+func (s *Server) GetFlightInfoCatalogs(
+	_ context.Context,
+	desc *flight.FlightDescriptor,
+) (*flight.FlightInfo, error) {
+	return s.flightInfoForCommand(desc, schema_ref.Catalogs), nil
+}
+
+// DoGetCatalogs returns the names of all databases.
+func (s *Server) DoGetCatalogs(context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	names := s.store.DBs()
+	slices.Sort(names)
+
+	b := array.NewRecordBuilder(s.pool, schema_ref.Catalogs)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).AppendValues(names, nil)
+
+	chunks := make(chan flight.StreamChunk, 1)
+	chunks <- flight.StreamChunk{Data: b.NewRecord()}
+	close(chunks)
+	return schema_ref.Catalogs, chunks, nil
+}
+
+func (s *Server) GetFlightInfoTables(
+	_ context.Context,
+	cmd fsql.GetTables,
+	desc *flight.FlightDescriptor,
+) (*flight.FlightInfo, error) {
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+	return s.flightInfoForCommand(desc, schema), nil
+}
+
+// DoGetTables returns the tables of all databases matching the filters of the
+// command. FrostDB has no database schemas, so tables only match a schema
+// filter pattern that matches the empty string. If requested, the schema of
+// each table contains its concrete columns; the columns of dynamic columns
+// depend on the data and are only known once the table is queried.
+func (s *Server) DoGetTables(
+	_ context.Context,
+	cmd fsql.GetTables,
+) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+
+	tableName, err := likeMatcher(cmd.GetTableNameFilterPattern())
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	dbSchema, err := likeMatcher(cmd.GetDBSchemaFilterPattern())
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	b := array.NewRecordBuilder(s.pool, schema)
+	defer b.Release()
+
+	if dbSchema("") && (len(cmd.GetTableTypes()) == 0 || slices.Contains(cmd.GetTableTypes(), tableType)) {
+		databases := s.store.DBs()
+		slices.Sort(databases)
+		for _, database := range databases {
+			if cmd.GetCatalog() != nil && *cmd.GetCatalog() != database {
+				continue
+			}
+			db, err := s.store.GetDB(database)
+			if err != nil {
+				// The database was dropped concurrently.
+				continue
+			}
+
+			tables := db.TableNames()
+			slices.Sort(tables)
+			for _, name := range tables {
+				if !tableName(name) {
+					continue
+				}
+
+				if cmd.GetIncludeSchema() {
+					table, err := db.GetTable(name)
+					if err != nil {
+						// The table was dropped concurrently.
+						continue
+					}
+					tableSchema, err := arrowSchema(table)
+					if err != nil {
+						return nil, nil, status.Error(codes.Internal, fmt.Sprintf("schema of table %q: %v", name, err))
+					}
+					b.Field(4).(*array.BinaryBuilder).Append(flight.SerializeSchema(tableSchema, s.pool))
+				}
+
+				b.Field(0).(*array.StringBuilder).Append(database)
+				b.Field(1).(*array.StringBuilder).AppendNull()
+				b.Field(2).(*array.StringBuilder).Append(name)
+				b.Field(3).(*array.StringBuilder).Append(tableType)
+			}
+		}
+	}
+
+	chunks := make(chan flight.StreamChunk, 1)
+	chunks <- flight.StreamChunk{Data: b.NewRecord()}
+	close(chunks)
+	return schema, chunks, nil
+}
+
+func (s *Server) flightInfoForCommand(desc *flight.FlightDescriptor, schema *arrow.Schema) *flight.FlightInfo {
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		FlightDescriptor: desc,
+		Schema:           flight.SerializeSchema(schema, s.pool),
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}
+}
+
+// arrowSchema returns the Arrow schema of the concrete columns of the table.
+func arrowSchema(table *frostdb.Table) (*arrow.Schema, error) {
+	columns := table.Schema().Columns()
+	fields := make([]arrow.Field, 0, len(columns))
+	for _, col := range columns {
+		if col.Dynamic {
+			continue
+		}
+		typ, err := convert.ParquetNodeToType(col.StorageLayout)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", col.Name, err)
+		}
+		fields = append(fields, arrow.Field{
+			Name:     col.Name,
+			Type:     typ,
+			Nullable: col.StorageLayout.Optional(),
+		})
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// likeMatcher returns a function reporting whether a string matches the given
+// SQL LIKE pattern, in which % matches any sequence of characters and _ any
+// single character. A nil pattern matches everything.
+func likeMatcher(pattern *string) (func(string) bool, error) {
+	if pattern == nil {
+		return func(string) bool { return true }, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range *pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter pattern %q: %w", *pattern, err)
+	}
+	return re.MatchString, nil
+}
+
+// schemaResolver resolves the schema of the records of a logical plan
+// before it is executed. Dynamic columns are expanded to the concrete columns
+// found in their table by a schema scan.
+type schemaResolver struct {
+	engine *query.LocalEngine
+	// columns holds the concrete column names of the scanned tables.
+	columns map[string][]string
+}
+
+// planSchema returns the schema of the records of the plan. All fields are
+// nullable, as the columns of dynamic columns and outer joins may be missing
+// from records.
+func (r *schemaResolver) planSchema(ctx context.Context, plan *logicalplan.LogicalPlan) (*arrow.Schema, error) {
+	fields, err := r.fields(ctx, plan)
+	if err != nil {
+		return nil, fmt.Errorf("resolve schema: %w", err)
+	}
+	for i := range fields {
+		fields[i].Nullable = true
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func (r *schemaResolver) fields(ctx context.Context, plan *logicalplan.LogicalPlan) ([]arrow.Field, error) {
+	switch {
+	case plan.TableScan != nil:
+		return r.tableFields(ctx, plan.TableScan.TableProvider, plan.TableScan.TableName)
+	case plan.SchemaScan != nil:
+		return []arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil
+	case plan.Projection != nil:
+		return r.exprFields(ctx, plan.Input, plan.Projection.Exprs)
+	case plan.Distinct != nil:
+		return r.exprFields(ctx, plan.Input, plan.Distinct.Exprs)
+	case plan.Aggregation != nil:
+		fields, err := r.exprFields(ctx, plan.Input, plan.Aggregation.GroupExprs)
+		if err != nil {
+			return nil, err
+		}
+		for _, agg := range plan.Aggregation.AggExprs {
+			t, err := plan.DataTypeForExpr(agg)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, arrow.Field{Name: agg.Name(), Type: t})
+		}
+		return fields, nil
+	case plan.Join != nil:
+		fields, err := r.fields(ctx, plan.Input)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.fields(ctx, plan.Join.Right)
+		if err != nil {
+			return nil, err
+		}
+		// Right key columns with the same name as their left key are
+		// dropped, other columns with the same name conflict.
+		for _, field := range right {
+			if !slices.ContainsFunc(fields, func(f arrow.Field) bool { return f.Name == field.Name }) {
+				fields = append(fields, field)
+				continue
+			}
+			if !plan.Join.SharedKey(field.Name) {
+				return nil, fmt.Errorf("join: column %q of the right input conflicts with a column of the left input, alias it in the right plan", field.Name)
+			}
+		}
+		return fields, nil
+	case plan.Input != nil:
+		// Filters, limits, samples and sorts keep the columns of their input.
+		return r.fields(ctx, plan.Input)
+	default:
+		return nil, fmt.Errorf("unsupported plan: %v", plan)
+	}
+}
+
+// exprFields returns the fields of the given expressions evaluated on the
+// records of input.
+func (r *schemaResolver) exprFields(ctx context.Context, input *logicalplan.LogicalPlan, exprs []logicalplan.Expr) ([]arrow.Field, error) {
+	var (
+		fields      []arrow.Field
+		inputFields []arrow.Field
+	)
+	for _, expr := range exprs {
+		switch expr.(type) {
+		case *logicalplan.AllExpr, *logicalplan.DynamicColumn:
+			if inputFields == nil {
+				var err error
+				inputFields, err = r.fields(ctx, input)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for _, field := range inputFields {
+				if expr.MatchColumn(field.Name) {
+					fields = append(fields, field)
+				}
+			}
+		default:
+			name := expr.Name()
+			t, err := expr.DataType(input)
+			if err != nil {
+				return nil, err
+			}
+			if alias, ok := expr.(*logicalplan.AliasExpr); ok {
+				expr = alias.Expr
+			}
+			if col, ok := expr.(*logicalplan.Column); ok && isStringColumn(input.InputSchema(), col.ColumnName) {
+				t = stringType(t)
+			}
+			fields = append(fields, arrow.Field{Name: name, Type: t})
+		}
+	}
+	return fields, nil
+}
+
+// tableFields returns the fields of all columns of the table.
+func (r *schemaResolver) tableFields(ctx context.Context, provider logicalplan.TableProvider, name string) ([]arrow.Field, error) {
+	table, err := provider.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []arrow.Field
+	for _, col := range table.Schema().Columns() {
+		t, err := convert.ParquetNodeToType(col.StorageLayout)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", col.Name, err)
+		}
+		if isString(col.StorageLayout) {
+			t = stringType(t)
+		}
+		if !col.Dynamic {
+			fields = append(fields, arrow.Field{Name: col.Name, Type: t})
+			continue
+		}
+
+		columns, err := r.tableColumns(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range columns {
+			if strings.HasPrefix(column, col.Name+".") {
+				fields = append(fields, arrow.Field{Name: column, Type: t})
+			}
+		}
+	}
+	return fields, nil
+}
+
+// tableColumns returns the sorted names of the concrete columns of the table.
+func (r *schemaResolver) tableColumns(ctx context.Context, name string) ([]string, error) {
+	if columns, ok := r.columns[name]; ok {
+		return columns, nil
+	}
+
+	var columns []string
+	if err := r.engine.ScanSchema(name).
+		Distinct(logicalplan.Col("name")).
+		Execute(ctx, func(_ context.Context, rec arrow.Record) error {
+			for i := 0; i < int(rec.NumRows()); i++ {
+				columns = append(columns, rec.Column(0).ValueStr(i))
+			}
+			return nil
+		}); err != nil {
+		return nil, fmt.Errorf("scan columns of table %s: %w", name, err)
+	}
+	slices.Sort(columns)
+	columns = slices.Compact(columns)
+
+	if r.columns == nil {
+		r.columns = make(map[string][]string)
+	}
+	r.columns[name] = columns
+	return columns, nil
+}
+
+// isStringColumn returns whether the column of the schema with the given
+// name holds strings.
+func isStringColumn(schema *dynparquet.Schema, name string) bool {
+	if schema == nil {
+		return false
+	}
+	if col, ok := schema.FindColumn(name); ok {
+		return isString(col.StorageLayout)
+	}
+	if col, ok := schema.FindDynamicColumnForConcreteColumn(name); ok {
+		return isString(col.StorageLayout)
+	}
+	return false
+}
+
+func isString(n parquet.Node) bool {
+	if n.Leaf() {
+		lt := n.Type().LogicalType()
+		return lt != nil && lt.UTF8 != nil
+	}
+	// Lists of strings.
+	for len(n.Fields()) == 1 {
+		n = n.Fields()[0]
+	}
+	return n.Leaf() && isString(n)
+}
+
+// stringType returns t with its binary values replaced by strings. String
+// columns are read as binary from parquet, but are strings in memory.
+func stringType(t arrow.DataType) arrow.DataType {
+	switch t := t.(type) {
+	case *arrow.BinaryType:
+		return arrow.BinaryTypes.String
+	case *arrow.DictionaryType:
+		return &arrow.DictionaryType{IndexType: t.IndexType, ValueType: stringType(t.ValueType), Ordered: t.Ordered}
+	case *arrow.ListType:
+		return arrow.ListOf(stringType(t.Elem()))
+	default:
+		return t
+	}
+}
+
+// conformRecord returns a record with the given schema containing the rows
+// of r. Columns missing from r are filled with nulls, columns of a different
+// type are cast to the schema's type, and columns missing from the schema,
+// such as dynamic columns written after it was resolved, are dropped.
+func conformRecord(ctx context.Context, pool memory.Allocator, r arrow.Record, schema *arrow.Schema) (arrow.Record, error) {
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, c := range columns {
+			c.Release()
+		}
+	}()
+	for _, field := range schema.Fields() {
+		indices := r.Schema().FieldIndices(field.Name)
+		if len(indices) == 0 {
+			columns = append(columns, arrowutils.MakeNullArray(pool, field.Type, int(r.NumRows())))
+			continue
+		}
+
+		column := r.Column(indices[0])
+		if arrow.TypeEqual(column.DataType(), field.Type) {
+			column.Retain()
+			columns = append(columns, column)
+			continue
+		}
+
+		cast, err := castArray(ctx, pool, column, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("cast column %q from %s to %s: %w", field.Name, column.DataType(), field.Type, err)
+		}
+		columns = append(columns, cast)
+	}
+	return array.NewRecord(schema, columns, r.NumRows()), nil
+}
+
+// castArray casts the array to the given type. Dictionaries are cast by
+// casting their indices and values, which the compute functions don't
+// support.
+func castArray(ctx context.Context, pool memory.Allocator, arr arrow.Array, to arrow.DataType) (arrow.Array, error) {
+	ctx = compute.WithAllocator(ctx, pool)
+	dict, ok := arr.(*array.Dictionary)
+	dictType, isDict := to.(*arrow.DictionaryType)
+	if !ok || !isDict {
+		return compute.CastArray(ctx, arr, compute.SafeCastOptions(to))
+	}
+
+	indices, err := compute.CastArray(ctx, dict.Indices(), compute.SafeCastOptions(dictType.IndexType))
+	if err != nil {
+		return nil, err
+	}
+	defer indices.Release()
+	values, err := compute.CastArray(ctx, dict.Dictionary(), compute.SafeCastOptions(dictType.ValueType))
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+	return array.NewDictionaryArray(dictType, indices, values), nil
+}
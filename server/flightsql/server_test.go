@@ -0,0 +1,270 @@
+package flightsql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	fsql "github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	frostdb "github.com/youscentia/ydb-frostdb"
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+)
+
+func setupServer(t *testing.T) (*frostdb.Table, *fsql.Client) {
+	t.Helper()
+
+	c, err := frostdb.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, c.Close()) })
+
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("samples", frostdb.NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+	_, err = c.DB(context.Background(), "other")
+	require.NoError(t, err)
+
+	r, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+
+	srv := flight.NewServerWithMiddleware(nil)
+	New(c, "test").Register(srv)
+	require.NoError(t, srv.Init("localhost:0"))
+	go func() {
+		_ = srv.Serve()
+	}()
+	t.Cleanup(srv.Shutdown)
+
+	client, err := fsql.NewClient(
+		srv.Addr().String(),
+		nil,
+		nil,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return table, client
+}
+
+// readAll reads all records of the first endpoint of the flight.
+func readAll(t *testing.T, client *fsql.Client, info *flight.FlightInfo) []arrow.Record {
+	t.Helper()
+
+	rdr, err := client.DoGet(context.Background(), info.Endpoint[0].Ticket)
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	var records []arrow.Record
+	for rdr.Next() {
+		r := rdr.Record()
+		r.Retain()
+		records = append(records, r)
+	}
+	require.NoError(t, rdr.Err())
+	t.Cleanup(func() {
+		for _, r := range records {
+			r.Release()
+		}
+	})
+	return records
+}
+
+func TestGetCatalogs(t *testing.T) {
+	_, client := setupServer(t)
+
+	info, err := client.GetCatalogs(context.Background())
+	require.NoError(t, err)
+	records := readAll(t, client, info)
+	require.Len(t, records, 1)
+
+	catalogs := records[0].Column(0).(*array.String)
+	require.Equal(t, []string{"other", "test"}, []string{catalogs.Value(0), catalogs.Value(1)})
+}
+
+func TestGetTables(t *testing.T) {
+	_, client := setupServer(t)
+
+	pattern := "sam%"
+	info, err := client.GetTables(context.Background(), &fsql.GetTablesOpts{
+		TableNameFilterPattern: &pattern,
+		IncludeSchema:          true,
+	})
+	require.NoError(t, err)
+	records := readAll(t, client, info)
+	require.Len(t, records, 1)
+	r := records[0]
+	require.Equal(t, int64(1), r.NumRows())
+	require.Equal(t, "test", r.Column(0).(*array.String).Value(0))
+	require.True(t, r.Column(1).IsNull(0))
+	require.Equal(t, "samples", r.Column(2).(*array.String).Value(0))
+	require.Equal(t, "TABLE", r.Column(3).(*array.String).Value(0))
+
+	schema, err := flight.DeserializeSchema(r.Column(4).(*array.Binary).Value(0), memory.DefaultAllocator)
+	require.NoError(t, err)
+	require.True(t, schema.HasField("example_type"))
+	require.True(t, schema.HasField("timestamp"))
+	require.False(t, schema.HasField("labels"))
+
+	pattern = "missing"
+	info, err = client.GetTables(context.Background(), &fsql.GetTablesOpts{
+		TableNameFilterPattern: &pattern,
+	})
+	require.NoError(t, err)
+	records = readAll(t, client, info)
+	require.Len(t, records, 1)
+	require.Equal(t, int64(0), records[0].NumRows())
+}
+
+func TestExecute(t *testing.T) {
+	_, client := setupServer(t)
+
+	info, err := client.Execute(
+		context.Background(),
+		"SELECT labels.namespace, sum(value) AS total FROM samples GROUP BY labels.namespace ORDER BY total DESC",
+	)
+	require.NoError(t, err)
+	records := readAll(t, client, info)
+
+	var (
+		namespaces []string
+		totals     []int64
+	)
+	for _, r := range records {
+		require.Equal(t, "labels.namespace", r.Schema().Field(0).Name)
+		require.Equal(t, "total", r.Schema().Field(1).Name)
+		namespace := r.Column(0).(*array.Dictionary)
+		dict := namespace.Dictionary().(*array.String)
+		for i := 0; i < int(r.NumRows()); i++ {
+			if namespace.IsNull(i) {
+				namespaces = append(namespaces, "")
+			} else {
+				namespaces = append(namespaces, dict.Value(namespace.GetValueIndex(i)))
+			}
+			totals = append(totals, r.Column(1).(*array.Int64).Value(i))
+		}
+	}
+	require.Equal(t, []string{"default", ""}, namespaces)
+	require.Equal(t, []int64{6, 5}, totals)
+
+	info, err = client.Execute(context.Background(), "EXPLAIN SELECT value FROM samples")
+	require.NoError(t, err)
+	schema, err := flight.DeserializeSchema(info.Schema, memory.DefaultAllocator)
+	require.NoError(t, err)
+	require.True(t, explainSchema.Equal(schema))
+	records = readAll(t, client, info)
+	require.Len(t, records, 1)
+	require.Contains(t, records[0].Column(0).(*array.String).Value(0), "TableScan")
+}
+
+func TestExecuteInvalid(t *testing.T) {
+	_, client := setupServer(t)
+
+	_, err := client.Execute(context.Background(), "SELECT * FROM missing")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Execute(context.Background(), "SELECT * FROM samples AS a JOIN samples AS b")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExecuteDynamicColumns(t *testing.T) {
+	table, client := setupServer(t)
+
+	r, err := dynparquet.Samples{{
+		ExampleType: "cpu",
+		Labels:      map[string]string{"zone": "a"},
+		Timestamp:   3,
+		Value:       1,
+	}}.ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+
+	info, err := client.Execute(context.Background(), "SELECT * FROM samples")
+	require.NoError(t, err)
+	records := readAll(t, client, info)
+
+	rows := int64(0)
+	for _, r := range records {
+		require.True(t, r.Schema().HasField("labels.namespace"))
+		require.True(t, r.Schema().HasField("labels.zone"))
+		rows += r.NumRows()
+	}
+	require.Equal(t, int64(4), rows)
+}
+
+func TestExecuteStream(t *testing.T) {
+	table, client := setupServer(t)
+
+	// Insert more records than are buffered for a stream, and compact some
+	// of them so records read from parquet and from memory are mixed.
+	for i := 0; i < 2*streamBuffer; i++ {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		_, err = table.InsertRecord(context.Background(), r)
+		r.Release()
+		require.NoError(t, err)
+		if i == streamBuffer {
+			require.NoError(t, table.EnsureCompaction())
+		}
+	}
+
+	stringDict := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Uint32, ValueType: arrow.BinaryTypes.String}
+	for _, tc := range []struct {
+		query  string
+		fields []arrow.Field
+		rows   int64
+	}{
+		{
+			query: "SELECT example_type, labels.namespace, value FROM samples",
+			fields: []arrow.Field{
+				{Name: "example_type", Type: stringDict, Nullable: true},
+				{Name: "labels.namespace", Type: stringDict, Nullable: true},
+				{Name: "value", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			},
+			rows: 3 * (2*streamBuffer + 1),
+		},
+		{
+			query: "SELECT example_type, count(value) AS n FROM samples GROUP BY example_type",
+			fields: []arrow.Field{
+				{Name: "example_type", Type: stringDict, Nullable: true},
+				{Name: "n", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			},
+			rows: 1,
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			info, err := client.Execute(context.Background(), tc.query)
+			require.NoError(t, err)
+			// The schema is known before the statement is executed.
+			schema, err := flight.DeserializeSchema(info.Schema, memory.DefaultAllocator)
+			require.NoError(t, err)
+			require.Equal(t, tc.fields, schema.Fields())
+			rdr, err := client.DoGet(context.Background(), info.Endpoint[0].Ticket)
+			require.NoError(t, err)
+			defer rdr.Release()
+
+			require.Equal(t, tc.fields, rdr.Schema().Fields())
+			rows := int64(0)
+			for rdr.Next() {
+				require.Equal(t, tc.fields, rdr.Record().Schema().Fields())
+				rows += rdr.Record().NumRows()
+			}
+			require.NoError(t, rdr.Err())
+			require.Equal(t, tc.rows, rows)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package server implements the FrostDBService gRPC API on top of FrostDB
+// databases.
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	frostdb "github.com/youscentia/ydb-frostdb"
+	pb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/storage/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/query/exprpb"
+	"github.com/youscentia/ydb-frostdb/query/physicalplan"
+)
+
+// DatabaseProvider resolves the database referenced by the scan node of a
+// query plan. *frostdb.ColumnStore implements this interface.
+type DatabaseProvider interface {
+	GetDB(name string) (*frostdb.DB, error)
+}
+
+// SingleDB returns a DatabaseProvider that serves every query from the given
+// database, regardless of the database name set in the plan.
+func SingleDB(db *frostdb.DB) DatabaseProvider {
+	return singleDB{db: db}
+}
+
+type singleDB struct {
+	db *frostdb.DB
+}
+
+func (s singleDB) GetDB(_ string) (*frostdb.DB, error) {
+	return s.db, nil
+}
+
+// Server serves FrostDBService queries. Each result record of a query is
+// streamed back to the client as a single Arrow IPC stream.
+type Server struct {
+	pb.UnimplementedFrostDBServiceServer
+
+	logger   log.Logger
+	tracer   trace.Tracer
+	pool     memory.Allocator
+	dbs      DatabaseProvider
+	execOpts []physicalplan.Option
+}
+
+type Option func(*Server)
+
+func WithLogger(logger log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+func WithTracer(tracer trace.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// WithAllocator sets the allocator used to execute queries.
+func WithAllocator(pool memory.Allocator) Option {
+	return func(s *Server) {
+		s.pool = pool
+	}
+}
+
+func WithPhysicalplanOptions(opts ...physicalplan.Option) Option {
+	return func(s *Server) {
+		s.execOpts = opts
+	}
+}
+
+// New returns a new Server that executes queries against the databases
+// returned by dbs.
+func New(dbs DatabaseProvider, options ...Option) *Server {
+	s := &Server{
+		logger: log.NewNopLogger(),
+		tracer: noop.NewTracerProvider().Tracer(""),
+		pool:   memory.DefaultAllocator,
+		dbs:    dbs,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Register registers the server with the given gRPC service registrar.
+func (s *Server) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterFrostDBServiceServer(r, s)
+}
+
+// Query executes the plan contained in the request and streams every
+// resulting record back to the client in Arrow IPC format.
+func (s *Server) Query(req *pb.QueryRequest, stream pb.FrostDBService_QueryServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "Server/Query")
+	defer span.End()
+
+	if req.GetPlanRoot() == nil {
+		return status.Error(codes.InvalidArgument, "plan root must be set")
+	}
+
+	scan := scanBase(req.GetPlanRoot())
+	if scan == nil {
+		return status.Error(codes.InvalidArgument, "plan does not contain a table or schema scan")
+	}
+
+	db, err := s.dbs.GetDB(scan.GetDatabase())
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	execOpts := s.execOpts
+	if tx := req.GetReadTx(); tx != 0 {
+		execOpts = append(slices.Clip(execOpts), physicalplan.WithReadTx(tx))
+	}
+	engine := exprpb.NewEngine(
+		s.pool,
+		db.TableProvider(),
+		exprpb.WithTracer(s.tracer),
+		exprpb.WithPhysicalplanOptions(execOpts...),
+	)
+	builder, err := engine.FromProto(req.GetPlanRoot())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := builder.Execute(ctx, func(ctx context.Context, r arrow.Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := encodeRecord(s.pool, r)
+		if err != nil {
+			return fmt.Errorf("encode record: %w", err)
+		}
+		return stream.Send(&pb.QueryResponse{Record: data})
+	}); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		if _, ok := status.FromError(err); ok {
+			// Errors returned by stream.Send are already gRPC errors.
+			return err
+		}
+		if errors.Is(err, frostdb.ErrTxCompacted) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		level.Warn(s.logger).Log(
+			"msg", "query execution failed",
+			"description", req.GetDescription(),
+			"err", err,
+		)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// scanBase returns the ScanBase of the table or schema scan at the bottom of
+// the given plan, or nil if the plan does not contain one.
+func scanBase(plan *pb.PlanNode) *pb.ScanBase {
+	for node := plan; node != nil; node = node.GetNext() {
+		switch {
+		case node.GetSpec().GetTableScan() != nil:
+			return node.GetSpec().GetTableScan().GetBase()
+		case node.GetSpec().GetSchemaScan() != nil:
+			return node.GetSpec().GetSchemaScan().GetBase()
+		}
+	}
+	return nil
+}
+
+// encodeRecord serializes the record as an Arrow IPC stream.
+func encodeRecord(pool memory.Allocator, r arrow.Record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := ipc.NewWriter(buf, ipc.WithSchema(r.Schema()), ipc.WithAllocator(pool))
+	if err := w.Write(r); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	frostdb "github.com/youscentia/ydb-frostdb"
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	pb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/storage/v1alpha1"
+)
+
+func setupServer(t *testing.T) (*frostdb.ColumnStore, pb.FrostDBServiceClient) {
+	t.Helper()
+
+	c, err := frostdb.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, c.Close()) })
+
+	db, err := c.DB(context.Background(), "test")
+	require.NoError(t, err)
+	table, err := db.Table("samples", frostdb.NewTableConfig(dynparquet.SampleDefinition()))
+	require.NoError(t, err)
+
+	r, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	New(c).Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return c, pb.NewFrostDBServiceClient(conn)
+}
+
+func tableScan(database, table string) *pb.PlanNode {
+	return &pb.PlanNode{
+		Spec: &pb.PlanNodeSpec{
+			Spec: &pb.PlanNodeSpec_TableScan{
+				TableScan: &pb.TableScan{
+					Base: &pb.ScanBase{
+						Database: database,
+						Table:    table,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestServerQuery(t *testing.T) {
+	_, client := setupServer(t)
+
+	plan := &pb.PlanNode{
+		Spec: &pb.PlanNodeSpec{
+			Spec: &pb.PlanNodeSpec_Projection{
+				Projection: &pb.Projection{
+					Exprs: []*pb.Expr{{
+						Def: &pb.ExprDef{
+							Content: &pb.ExprDef_Column{
+								Column: &pb.Column{Name: "timestamp"},
+							},
+						},
+					}},
+				},
+			},
+		},
+		Next: tableScan("test", "samples"),
+	}
+
+	stream, err := client.Query(context.Background(), &pb.QueryRequest{PlanRoot: plan})
+	require.NoError(t, err)
+
+	rows := int64(0)
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		reader, err := ipc.NewReader(bytes.NewReader(resp.GetRecord()), ipc.WithAllocator(memory.DefaultAllocator))
+		require.NoError(t, err)
+		for reader.Next() {
+			rec := reader.Record()
+			require.Equal(t, 1, int(rec.NumCols()))
+			require.Equal(t, "timestamp", rec.ColumnName(0))
+			rows += rec.NumRows()
+		}
+		require.NoError(t, reader.Err())
+		reader.Release()
+	}
+	require.Equal(t, int64(3), rows)
+}
+
+func TestServerQueryErrors(t *testing.T) {
+	_, client := setupServer(t)
+
+	for _, tc := range []struct {
+		name string
+		plan *pb.PlanNode
+		code codes.Code
+	}{
+		{
+			name: "no plan",
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unknown database",
+			plan: tableScan("unknown", "samples"),
+			code: codes.NotFound,
+		},
+		{
+			name: "unknown table",
+			plan: tableScan("test", "unknown"),
+			code: codes.InvalidArgument,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Query(context.Background(), &pb.QueryRequest{PlanRoot: tc.plan})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestServerQueryCanceled(t *testing.T) {
+	_, client := setupServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stream, err := client.Query(ctx, &pb.QueryRequest{PlanRoot: tableScan("test", "samples")})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestServerQueryReadTx(t *testing.T) {
+	c, client := setupServer(t)
+
+	db, err := c.GetDB("test")
+	require.NoError(t, err)
+	table, err := db.GetTable("samples")
+	require.NoError(t, err)
+	tx := db.HighWatermark()
+
+	r, err := dynparquet.NewTestSamples().ToRecord()
+	require.NoError(t, err)
+	defer r.Release()
+	_, err = table.InsertRecord(context.Background(), r)
+	require.NoError(t, err)
+
+	rows := func(tx uint64) (int64, error) {
+		stream, err := client.Query(context.Background(), &pb.QueryRequest{
+			PlanRoot: tableScan("test", "samples"),
+			ReadTx:   tx,
+		})
+		require.NoError(t, err)
+		rows := int64(0)
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			if err != nil {
+				return rows, err
+			}
+			reader, err := ipc.NewReader(bytes.NewReader(resp.GetRecord()), ipc.WithAllocator(memory.DefaultAllocator))
+			require.NoError(t, err)
+			for reader.Next() {
+				rows += reader.Record().NumRows()
+			}
+			require.NoError(t, reader.Err())
+			reader.Release()
+		}
+	}
+
+	n, err := rows(tx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	n, err = rows(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), n)
+
+	require.NoError(t, table.EnsureCompaction())
+	_, err = rows(tx)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -26,6 +26,7 @@ import (
 	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
 	"github.com/youscentia/ydb-frostdb/index"
 	"github.com/youscentia/ydb-frostdb/parts"
+	"github.com/youscentia/ydb-frostdb/query/exprpb"
 	"github.com/youscentia/ydb-frostdb/vfs"
 )
 
@@ -158,24 +159,13 @@ func (db *DB) snapshotAtTX(ctx context.Context, tx uint64, writeSnapshot func(co
 	if err := func() error {
 		snapshotsDir := SnapshotDir(db, tx)
 		fileName := filepath.Join(snapshotsDir, snapshotFileName(tx))
-		file, err := db.fs.OpenFile(fileName, os.O_RDWR|os.O_CREATE, filePerms)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		info, err := file.Stat()
-		if err != nil {
-			return err
-		}
-
-		if info.Size() > 0 { // Snapshot file already exists
+		if info, err := db.fs.Stat(fileName); err == nil && info.Size() > 0 { // Snapshot file already exists
 			if db.validateSnapshotTxn(ctx, tx) == nil {
 				return nil // valid snapshot already exists at tx no need to re-snapshot
 			}
 
 			// Snapshot exists but is invalid. Remove it.
-			if err := db.fs.RemoveAll(SnapshotDir(db, tx)); err != nil {
+			if err := db.fs.RemoveAll(snapshotsDir); err != nil {
 				return fmt.Errorf("failed to remove invalid snapshot %v: %w", tx, err)
 			}
 		}
@@ -183,9 +173,11 @@ func (db *DB) snapshotAtTX(ctx context.Context, tx uint64, writeSnapshot func(co
 			return err
 		}
 
-		if err := file.Truncate(0); err != nil {
+		file, err := db.fs.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, filePerms)
+		if err != nil {
 			return err
 		}
+		defer file.Close()
 
 		if err := func() error {
 			if err := writeSnapshot(ctx, file); err != nil {
@@ -423,7 +415,7 @@ func WriteSnapshot(ctx context.Context, tx uint64, db *DB, w io.Writer) error {
 			}
 
 			tableMeta := &snapshotpb.Table{
-				Name:   t.name,
+				Name:   t.Name(),
 				Config: t.config.Load(),
 				ActiveBlock: &snapshotpb.Table_TableBlock{
 					Ulid:   blockUlid,
@@ -433,6 +425,22 @@ func WriteSnapshot(ctx context.Context, tx uint64, db *DB, w io.Writer) error {
 				},
 			}
 
+			for _, ts := range t.visibleTombstones(tx) {
+				filter, err := exprpb.ExprToProto(ts.filter)
+				if err != nil {
+					return err
+				}
+				blockID, err := ts.block.MarshalBinary()
+				if err != nil {
+					return err
+				}
+				tableMeta.Tombstones = append(tableMeta.Tombstones, &snapshotpb.Table_Tombstone{
+					Tx:      ts.tx,
+					Filter:  filter,
+					BlockId: blockID,
+				})
+			}
+
 			if err := block.Index().Snapshot(tx, func(p parts.Part) error {
 				granuleMeta := &snapshotpb.Granule{}
 				partMeta := &snapshotpb.Part{
@@ -458,8 +466,8 @@ func WriteSnapshot(ctx context.Context, tx uint64, db *DB, w io.Writer) error {
 				granuleMeta.PartMetadata = append(granuleMeta.PartMetadata, partMeta)
 				tableMeta.GranuleMetadata = append(tableMeta.GranuleMetadata, granuleMeta) // TODO: we have one part per granule now
 				return nil
-			}, snapshotIndexDir(db, tx, t.name, block.ulid.String())); err != nil {
-				return fmt.Errorf("failed to snapshot table %s index: %w", t.name, err)
+			}, snapshotIndexDir(db, tx, t.Name(), block.ulid.String())); err != nil {
+				return fmt.Errorf("failed to snapshot table %s index: %w", t.Name(), err)
 			}
 
 			metadata.TableMetadata = append(metadata.TableMetadata, tableMeta)
@@ -546,6 +554,19 @@ func readFooter(r io.ReaderAt, size int64) (*snapshotpb.FooterData, error) {
 // txnMetadata (if any) the snapshot was created with and an error if any
 // occurred.
 func loadSnapshot(ctx context.Context, db *DB, r io.ReaderAt, size int64, dir string) error {
+	return loadSnapshotTables(ctx, db, r, size, dir, db.table)
+}
+
+// loadSnapshotTables loads the snapshot like loadSnapshot, getting or creating
+// the tables of the snapshot with the given function.
+func loadSnapshotTables(
+	ctx context.Context,
+	db *DB,
+	r io.ReaderAt,
+	size int64,
+	dir string,
+	getTable func(name string, config *tablepb.TableConfig, id ulid.ULID) (*Table, error),
+) error {
 	footer, err := readFooter(r, size)
 	if err != nil {
 		return err
@@ -566,6 +587,7 @@ func loadSnapshot(ctx context.Context, db *DB, r io.ReaderAt, size int64, dir st
 			options := []TableOption{
 				WithRowGroupSize(int(tableMeta.Config.RowGroupSize)),
 				WithBlockReaderLimit(int(tableMeta.Config.BlockReaderLimit)),
+				WithRetention(time.Duration(tableMeta.Config.RetentionMs) * time.Millisecond),
 			}
 			if tableMeta.Config.DisableWal {
 				options = append(options, WithoutWAL())
@@ -574,18 +596,22 @@ func loadSnapshot(ctx context.Context, db *DB, r io.ReaderAt, size int64, dir st
 				schemaMsg,
 				options...,
 			)
+			tableConfig.SchemaVersion = tableMeta.Config.SchemaVersion
 
 			var blockUlid ulid.ULID
 			if err := blockUlid.UnmarshalBinary(tableMeta.ActiveBlock.Ulid); err != nil {
 				return err
 			}
 
-			// Restore the table index from tx snapshot dir
-			if err := restoreIndexFilesFromSnapshot(db, tableMeta.Name, dir, blockUlid.String()); err != nil {
-				return err
+			// Restore the table index from tx snapshot dir. Databases without
+			// a storage path have no index files.
+			if db.storagePath != "" {
+				if err := restoreIndexFilesFromSnapshot(db, tableMeta.Name, dir, blockUlid.String()); err != nil {
+					return err
+				}
 			}
 
-			table, err := db.table(tableMeta.Name, tableConfig, blockUlid)
+			table, err := getTable(tableMeta.Name, tableConfig, blockUlid)
 			if err != nil {
 				return err
 			}
@@ -602,6 +628,14 @@ func loadSnapshot(ctx context.Context, db *DB, r io.ReaderAt, size int64, dir st
 			block.mtx.Unlock()
 			table.mtx.Unlock()
 
+			for _, tombstoneMeta := range tableMeta.Tombstones {
+				ts, err := tombstoneFromProto(tombstoneMeta.Tx, tombstoneMeta.Filter, tombstoneMeta.BlockId)
+				if err != nil {
+					return err
+				}
+				table.addTombstone(ts)
+			}
+
 			for _, granuleMeta := range tableMeta.GranuleMetadata {
 				resultParts := make([]parts.Part, 0, len(granuleMeta.PartMetadata))
 				for _, partMeta := range granuleMeta.PartMetadata {
@@ -638,7 +672,7 @@ func loadSnapshot(ctx context.Context, db *DB, r io.ReaderAt, size int64, dir st
 							record.Retain()
 							resultParts = append(
 								resultParts,
-								parts.NewArrowPart(partMeta.Tx, record, uint64(util.TotalRecordSize(record)), table.schema, partOptions),
+								parts.NewArrowPart(partMeta.Tx, record, uint64(util.TotalRecordSize(record)), table.schema.Load(), partOptions),
 							)
 							return nil
 						}(); err != nil {
@@ -691,7 +725,11 @@ func (db *DB) cleanupSnapshotDir(ctx context.Context, tx uint64) error {
 // false or an error is returned by the callback, the iteration is aborted and
 // the error returned.
 func (db *DB) snapshotsDo(ctx context.Context, dir string, callback func(tx uint64, entry os.DirEntry) (bool, error)) error {
-	files, err := db.fs.ReadDir(dir)
+	return snapshotsDo(ctx, db.fs, dir, callback)
+}
+
+func snapshotsDo(ctx context.Context, fs vfs.FileSystem, dir string, callback func(tx uint64, entry os.DirEntry) (bool, error)) error {
+	files, err := fs.ReadDir(dir)
 	if err != nil {
 		return err
 	}
@@ -162,8 +162,8 @@ func TestSnapshot(t *testing.T) {
 				)
 			}
 			// Reset sync.Maps so reflect.DeepEqual can be used below.
-			db.tables[testCase.name].schema.ResetWriters()
-			db.tables[testCase.name].schema.ResetBuffers()
+			db.tables[testCase.name].schema.Load().ResetWriters()
+			db.tables[testCase.name].schema.Load().ResetBuffers()
 			require.Equal(t, db.tables[testCase.name].config.Load(), snapshotDB.tables[testCase.name].config.Load())
 		}
 	})
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 
 	"github.com/youscentia/ydb-frostdb/query"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
 )
 
 type Parser struct {
@@ -26,7 +28,8 @@ type ParseResult struct {
 // TODO(asubiotto): This API will change over time. Currently,
 // queryEngine.ScanTable is provided as a starting point and no table needs to
 // be specified in the SQL statement. Additionally, the idea is to change to
-// creating logical plans directly (rather than through a builder).
+// creating logical plans directly (rather than through a builder), which Parse
+// does.
 func (p *Parser) ExperimentalParse(
 	builder query.Builder,
 	dynColNames []string,
@@ -49,3 +52,48 @@ func (p *Parser) ExperimentalParse(
 
 	return ParseResult{Explain: v.explain, Plan: v.builder}, nil
 }
+
+// Parse returns the logical plan of the given SQL query. The queried table is
+// resolved from the FROM clause using the given provider. EXPLAIN queries are
+// planned like the query they explain, with explain set to true. Queries
+// using syntax that can't be planned return an error wrapping ErrUnsupported.
+func (p *Parser) Parse(
+	provider logicalplan.TableProvider,
+	sql string,
+) (plan *logicalplan.LogicalPlan, explain bool, err error) {
+	asts, _, err := p.p.Parse(sql, "", "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(asts) != 1 {
+		return nil, false, fmt.Errorf("cannot handle multiple asts, found %d", len(asts))
+	}
+
+	stmt := asts[0]
+	if e, ok := stmt.(*ast.ExplainStmt); ok {
+		if e.Analyze {
+			return nil, false, unsupported("EXPLAIN ANALYZE")
+		}
+		explain = true
+		stmt = e.Stmt
+	}
+
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok {
+		return nil, false, unsupported("%T statements", stmt)
+	}
+
+	planner := &selectPlanner{
+		provider: provider,
+		v: &astVisitor{
+			dynColNames: make(map[string]struct{}),
+			tableNames:  make(map[string]struct{}),
+		},
+	}
+	plan, err = planner.plan(sel)
+	if err != nil {
+		return nil, false, err
+	}
+	return plan, explain, nil
+}
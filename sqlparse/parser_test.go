@@ -0,0 +1,157 @@
+package sqlparse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+func TestParse(t *testing.T) {
+	provider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+	scan := logicalplan.Builder{}.Scan(provider, "samples")
+
+	for _, tc := range []struct {
+		name    string
+		sql     string
+		explain bool
+		plan    logicalplan.Builder
+	}{{
+		name: "all",
+		sql:  "SELECT * FROM samples",
+		plan: scan,
+	}, {
+		name: "filter",
+		sql:  "SELECT timestamp, value AS v FROM samples WHERE labels.label1 = 'value1'",
+		plan: scan.
+			Filter(logicalplan.Col("labels.label1").Eq(logicalplan.Literal("value1"))).
+			Project(logicalplan.Col("timestamp"), logicalplan.Col("value").Alias("v")),
+	}, {
+		name: "table alias",
+		sql:  "SELECT s.value, labels FROM samples AS s",
+		plan: scan.Project(logicalplan.Col("value"), logicalplan.DynCol("labels")),
+	}, {
+		name: "order by alias",
+		sql:  "SELECT value AS v FROM samples ORDER BY v DESC LIMIT 10",
+		plan: scan.
+			Project(logicalplan.Col("value").Alias("v")).
+			OrderBy(logicalplan.Desc(logicalplan.Col("v"))).
+			Limit(logicalplan.Literal(10)),
+	}, {
+		name: "order by column not selected",
+		sql:  "SELECT value FROM samples ORDER BY timestamp",
+		plan: scan.
+			Project(logicalplan.Col("value"), logicalplan.Col("timestamp")).
+			OrderBy(logicalplan.Asc(logicalplan.Col("timestamp"))).
+			Project(logicalplan.Col("value")),
+	}, {
+		name: "group by",
+		sql:  "SELECT labels.label1, sum(value) AS total FROM samples GROUP BY labels.label1 HAVING total > 5 ORDER BY total",
+		plan: scan.
+			Project(logicalplan.Col("labels.label1"), logicalplan.Col("value")).
+			Aggregate(
+				[]*logicalplan.AggregationFunction{logicalplan.Sum(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("labels.label1")},
+			).
+			Project(logicalplan.Col("labels.label1"), logicalplan.Sum(logicalplan.Col("value")).Alias("total")).
+			Filter(logicalplan.Col("total").Gt(logicalplan.Literal(5))).
+			OrderBy(logicalplan.Asc(logicalplan.Col("total"))),
+	}, {
+		name: "having aggregation not selected",
+		sql:  "SELECT labels.label1 FROM samples GROUP BY labels.label1 HAVING count(value) > 1",
+		plan: scan.
+			Project(logicalplan.Col("labels.label1"), logicalplan.Col("value")).
+			Aggregate(
+				[]*logicalplan.AggregationFunction{logicalplan.Count(logicalplan.Col("value"))},
+				[]logicalplan.Expr{logicalplan.Col("labels.label1")},
+			).
+			Project(logicalplan.Col("labels.label1"), logicalplan.Col("count(value)")).
+			Filter(logicalplan.Col("count(value)").Gt(logicalplan.Literal(1))).
+			Project(logicalplan.Col("labels.label1")),
+	}, {
+		name:    "explain",
+		sql:     "EXPLAIN SELECT DISTINCT example_type FROM samples",
+		explain: true,
+		plan: scan.
+			Project(logicalplan.Col("example_type")).
+			Distinct(logicalplan.Col("example_type")),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, explain, err := NewParser().Parse(provider, tc.sql)
+			require.NoError(t, err)
+			require.Equal(t, tc.explain, explain)
+
+			expected, err := tc.plan.Build()
+			require.NoError(t, err)
+			require.Equal(t, expected.String(), plan.String())
+		})
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	provider := &mockTableProvider{schema: dynparquet.NewSampleSchema()}
+	for _, sql := range []string{
+		"SELECT value",
+		"SELECT * FROM samples AS a JOIN samples AS b",
+		"SELECT * FROM (SELECT value FROM samples) AS s",
+		"SELECT value FROM samples LIMIT 10, 10",
+		"SELECT *, value FROM samples",
+		"SELECT * FROM samples GROUP BY example_type",
+		"SELECT * FROM samples ORDER BY value * 2",
+		"SELECT DISTINCT example_type FROM samples ORDER BY value",
+		"SELECT value FROM samples WHERE value IN (SELECT value FROM samples)",
+		"EXPLAIN ANALYZE SELECT * FROM samples",
+		"DELETE FROM samples",
+	} {
+		t.Run(sql, func(t *testing.T) {
+			_, _, err := NewParser().Parse(provider, sql)
+			require.ErrorIs(t, err, ErrUnsupported)
+		})
+	}
+}
+
+type mockTableReader struct {
+	schema *dynparquet.Schema
+}
+
+func (m *mockTableReader) Schema() *dynparquet.Schema {
+	return m.schema
+}
+
+func (m *mockTableReader) View(_ context.Context, _ func(ctx context.Context, tx uint64) error) error {
+	return nil
+}
+
+func (m *mockTableReader) Iterator(
+	_ context.Context,
+	_ uint64,
+	_ memory.Allocator,
+	_ []logicalplan.Callback,
+	_ ...logicalplan.Option,
+) error {
+	return nil
+}
+
+func (m *mockTableReader) SchemaIterator(
+	_ context.Context,
+	_ uint64,
+	_ memory.Allocator,
+	_ []logicalplan.Callback,
+	_ ...logicalplan.Option,
+) error {
+	return nil
+}
+
+type mockTableProvider struct {
+	schema *dynparquet.Schema
+}
+
+func (m *mockTableProvider) GetTable(_ string) (logicalplan.TableReader, error) {
+	return &mockTableReader{
+		schema: m.schema,
+	}, nil
+}
@@ -0,0 +1,341 @@
+package sqlparse
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+)
+
+// ErrUnsupported is returned by Parse for SQL that is valid but cannot be
+// planned.
+var ErrUnsupported = errors.New("unsupported SQL")
+
+func unsupported(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, fmt.Sprintf(format, args...))
+}
+
+// selectPlanner plans a single SELECT statement.
+type selectPlanner struct {
+	provider logicalplan.TableProvider
+	v        *astVisitor
+}
+
+func (p *selectPlanner) plan(stmt *ast.SelectStmt) (*logicalplan.LogicalPlan, error) {
+	if err := checkSupported(stmt); err != nil {
+		return nil, err
+	}
+
+	b, err := p.scan(stmt.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.Where != nil {
+		where, err := p.expr(stmt.Where)
+		if err != nil {
+			return nil, fmt.Errorf("WHERE: %w", err)
+		}
+		b = b.Filter(where)
+	}
+
+	// A nil list of fields means all columns are selected.
+	var fields []logicalplan.Expr
+	for _, field := range stmt.Fields.Fields {
+		if field.WildCard != nil {
+			if len(stmt.Fields.Fields) > 1 {
+				return nil, unsupported("* combined with other fields")
+			}
+			break
+		}
+		e, err := p.expr(field)
+		if err != nil {
+			return nil, fmt.Errorf("SELECT: %w", err)
+		}
+		fields = append(fields, e)
+	}
+
+	var groups []logicalplan.Expr
+	if stmt.GroupBy != nil {
+		for _, item := range stmt.GroupBy.Items {
+			e, err := p.expr(item)
+			if err != nil {
+				return nil, fmt.Errorf("GROUP BY: %w", err)
+			}
+			groups = append(groups, e)
+		}
+	}
+
+	var having logicalplan.Expr
+	if stmt.Having != nil {
+		having, err = p.expr(stmt.Having.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("HAVING: %w", err)
+		}
+	}
+
+	var orderBy []*logicalplan.SortExpr
+	if stmt.OrderBy != nil {
+		for _, item := range stmt.OrderBy.Items {
+			e, err := p.expr(item.Expr)
+			if err != nil {
+				return nil, fmt.Errorf("ORDER BY: %w", err)
+			}
+			if item.Desc {
+				orderBy = append(orderBy, logicalplan.Desc(e))
+			} else {
+				orderBy = append(orderBy, logicalplan.Asc(e))
+			}
+		}
+	}
+
+	aggregate := groups != nil || having != nil || hasAggregation(fields...)
+	switch {
+	case aggregate && fields == nil:
+		return nil, unsupported("* in an aggregation")
+	case aggregate && stmt.Distinct:
+		return nil, unsupported("DISTINCT in an aggregation")
+	case stmt.Distinct && fields == nil:
+		return nil, unsupported("DISTINCT *")
+	}
+
+	// The expressions of the HAVING and ORDER BY clauses are evaluated on
+	// the output of the projection. Any columns they need that aren't
+	// selected are projected as well and removed again by a final
+	// projection.
+	output := fields
+	var hidden []logicalplan.Expr
+	if aggregate {
+		var pre, post []logicalplan.Expr
+		var aggs []*logicalplan.AggregationFunction
+		pre, aggs, post = aggregationProjections(fields, groups)
+		if having != nil {
+			pre, aggs = addAggregations(pre, aggs, having)
+			having = replaceAggregations(having)
+			for _, col := range having.ColumnsUsedExprs() {
+				hidden = addHidden(post, hidden, col)
+			}
+		}
+		for _, e := range orderBy {
+			pre, aggs = addAggregations(pre, aggs, e.Expr)
+			e.Expr, hidden = sortByOutput(post, hidden, replaceAggregations(e.Expr))
+		}
+
+		b = b.Project(pre...).
+			Aggregate(aggs, groups).
+			Project(append(post, hidden...)...)
+		output = post
+		if having != nil {
+			b = b.Filter(having)
+		}
+	} else if fields != nil {
+		for _, e := range orderBy {
+			e.Expr, hidden = sortByOutput(fields, hidden, e.Expr)
+		}
+		if stmt.Distinct && len(hidden) > 0 {
+			return nil, unsupported("ORDER BY expressions that are not selected in a DISTINCT query")
+		}
+		b = b.Project(append(fields, hidden...)...)
+		if stmt.Distinct {
+			b = b.Distinct(fields...)
+		}
+	} else {
+		for _, e := range orderBy {
+			switch e.Expr.(type) {
+			case *logicalplan.Column, *logicalplan.DynamicColumn:
+			default:
+				return nil, unsupported("ORDER BY expression %s when selecting *", e.Expr)
+			}
+		}
+	}
+
+	if orderBy != nil {
+		b = b.OrderBy(orderBy...)
+	}
+
+	if stmt.Limit != nil {
+		limit, err := p.expr(stmt.Limit.Count)
+		if err != nil {
+			return nil, fmt.Errorf("LIMIT: %w", err)
+		}
+		b = b.Limit(limit)
+	}
+
+	if len(hidden) > 0 {
+		refs := make([]logicalplan.Expr, 0, len(output))
+		for _, e := range output {
+			refs = append(refs, outputRef(e))
+		}
+		b = b.Project(refs...)
+	}
+
+	return b.Build()
+}
+
+// checkSupported returns an error for the clauses of the statement that
+// can't be planned.
+func checkSupported(stmt *ast.SelectStmt) error {
+	switch {
+	case stmt.Kind != ast.SelectStmtKindSelect:
+		return unsupported("%s statements", stmt.Kind.String())
+	case stmt.With != nil:
+		return unsupported("WITH")
+	case len(stmt.WindowSpecs) > 0:
+		return unsupported("WINDOW")
+	case stmt.LockInfo != nil:
+		return unsupported("locking reads")
+	case stmt.SelectIntoOpt != nil:
+		return unsupported("SELECT INTO")
+	case stmt.From == nil:
+		return unsupported("SELECT without FROM")
+	case stmt.Limit != nil && stmt.Limit.Offset != nil:
+		return unsupported("LIMIT with an offset")
+	}
+	return nil
+}
+
+// scan returns a builder scanning the table of the FROM clause.
+func (p *selectPlanner) scan(from *ast.TableRefsClause) (logicalplan.Builder, error) {
+	join := from.TableRefs
+	if join.Right != nil {
+		return logicalplan.Builder{}, unsupported("JOIN")
+	}
+	source, ok := join.Left.(*ast.TableSource)
+	if !ok {
+		return logicalplan.Builder{}, unsupported("FROM %T", join.Left)
+	}
+	table, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return logicalplan.Builder{}, unsupported("subqueries")
+	}
+	if table.Schema.String() != "" {
+		return logicalplan.Builder{}, unsupported("qualified table name %s.%s", table.Schema, table.Name)
+	}
+
+	name := table.Name.String()
+	reader, err := p.provider.GetTable(name)
+	if err != nil {
+		return logicalplan.Builder{}, fmt.Errorf("get table %q: %w", name, err)
+	}
+
+	for _, col := range reader.Schema().Columns() {
+		if col.Dynamic {
+			p.v.dynColNames[col.Name] = struct{}{}
+		}
+	}
+	p.v.tableNames[name] = struct{}{}
+	if alias := source.AsName.String(); alias != "" {
+		p.v.tableNames[alias] = struct{}{}
+	}
+
+	return logicalplan.Builder{}.Scan(p.provider, name), nil
+}
+
+// expr converts the given AST node to an expression.
+func (p *selectPlanner) expr(n ast.Node) (logicalplan.Expr, error) {
+	p.v.exprStack = p.v.exprStack[:0]
+	n.Accept(p.v)
+	if p.v.err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupported, p.v.err)
+	}
+	if len(p.v.exprStack) != 1 {
+		return nil, unsupported("expression %T", n)
+	}
+	return p.v.exprStack[0], nil
+}
+
+func hasAggregation(exprs ...logicalplan.Expr) bool {
+	for _, e := range exprs {
+		c := &aggregationCollector{}
+		e.Accept(c)
+		if len(c.aggregations) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// addAggregations adds the aggregations of the given expression that aren't
+// computed yet to aggs, and the expressions they aggregate to pre.
+func addAggregations(
+	pre []logicalplan.Expr,
+	aggs []*logicalplan.AggregationFunction,
+	expr logicalplan.Expr,
+) ([]logicalplan.Expr, []*logicalplan.AggregationFunction) {
+	c := &aggregationCollector{}
+	expr.Accept(c)
+	for _, agg := range c.aggregations {
+		if !containsName(aggs, agg.Name()) {
+			aggs = append(aggs, agg)
+		}
+		if !containsName(pre, agg.Expr.Name()) {
+			pre = append(pre, agg.Expr)
+		}
+	}
+	return pre, aggs
+}
+
+// replaceAggregations returns the given expression with its aggregations
+// replaced by references to their results.
+func replaceAggregations(expr logicalplan.Expr) logicalplan.Expr {
+	switch e := expr.(type) {
+	case *logicalplan.AggregationFunction:
+		return logicalplan.Col(e.Name())
+	case *logicalplan.BinaryExpr:
+		return &logicalplan.BinaryExpr{
+			Left:  replaceAggregations(e.Left),
+			Op:    e.Op,
+			Right: replaceAggregations(e.Right),
+		}
+	case *logicalplan.ConvertExpr:
+		return logicalplan.Convert(replaceAggregations(e.Expr), e.Type)
+	case *logicalplan.AliasExpr:
+		return &logicalplan.AliasExpr{Expr: replaceAggregations(e.Expr), Alias: e.Alias}
+	default:
+		return expr
+	}
+}
+
+// sortByOutput returns the expression to sort the output of a projection of
+// the given expressions by expr. If the projection doesn't include expr, it
+// is added to the hidden expressions.
+func sortByOutput(
+	output, hidden []logicalplan.Expr,
+	expr logicalplan.Expr,
+) (logicalplan.Expr, []logicalplan.Expr) {
+	for _, e := range output {
+		if e.Name() == expr.Name() {
+			return outputRef(e), hidden
+		}
+	}
+	return outputRef(expr), addHidden(output, hidden, expr)
+}
+
+// addHidden adds expr to the hidden expressions unless it is already
+// projected.
+func addHidden(output, hidden []logicalplan.Expr, expr logicalplan.Expr) []logicalplan.Expr {
+	if containsName(output, expr.Name()) || containsName(hidden, expr.Name()) {
+		return hidden
+	}
+	return append(hidden, expr)
+}
+
+// outputRef returns an expression referencing the output of the given
+// projected expression.
+func outputRef(expr logicalplan.Expr) logicalplan.Expr {
+	if _, ok := expr.(*logicalplan.DynamicColumn); ok {
+		return expr
+	}
+	return logicalplan.Col(expr.Name())
+}
+
+func containsName[T interface{ Name() string }](exprs []T, name string) bool {
+	for _, e := range exprs {
+		if e.Name() == name {
+			return true
+		}
+	}
+	return false
+}
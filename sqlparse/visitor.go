@@ -1,6 +1,7 @@
 package sqlparse
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -20,7 +21,10 @@ type astVisitor struct {
 	explain     bool
 	builder     query.Builder
 	dynColNames map[string]struct{}
-	err         error
+	// tableNames are the names and aliases of the queried table, which
+	// columns may be qualified with.
+	tableNames map[string]struct{}
+	err        error
 
 	exprStack []logicalplan.Expr
 }
@@ -44,6 +48,12 @@ func newASTVisitor(
 func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 	switch expr := n.(type) {
 	case *ast.SelectStmt:
+		if v.builder == nil {
+			// Only expressions are converted when planning a query with
+			// Parse, so this is a subquery.
+			v.err = errors.New("subqueries")
+			return n, true
+		}
 		// The SelectStmt is handled in during pre-visit given that it has many
 		// clauses we need to handle independently (e.g. a group by with a
 		// filter).
@@ -63,64 +73,7 @@ func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 			afterGroupBy := v.exprStack[len(beforeGroupBy):]
 			groups := afterGroupBy
 
-			includedPreprojections := make(map[string]struct{})
-			preProjections := []logicalplan.Expr{}
-			postProjections := []logicalplan.Expr{}
-			aggregations := []*logicalplan.AggregationFunction{}
-
-			for _, expr := range beforeGroupBy {
-				// Walk the expression tree and separate out what projections
-				// need to be done before aggregations, and which can be done
-				// after. We don't support nested aggregations, so we can just
-				// find aggregation functions and put their expressions into
-				// the pre-projections. If we wanted to support nested
-				// aggregations, this would need to be more sophisticated.
-				aggCollector := &aggregationCollector{}
-				expr.Accept(aggCollector)
-
-				// If we have any aggregations nested in the AST like
-				// "sum(value) as value_sum_or_anything_else" then the actual
-				// query plan nesting looks something like:
-				// alias(value_sum_or_anything_else, sum(value))
-
-				if len(aggCollector.aggregations) > 0 {
-					// This is the expression that will be aggregated so we
-					// need to ensure that it is in the pre-projection.
-					for _, agg := range aggCollector.aggregations {
-						if _, ok := includedPreprojections[agg.Expr.Name()]; !ok {
-							preProjections = append(preProjections, agg.Expr)
-							// The same expression can be used in multiple
-							// aggregations, but we only need to project it
-							// once.
-							includedPreprojections[agg.Expr.Name()] = struct{}{}
-						}
-
-						aggregations = append(aggregations, agg)
-					}
-					postProjections = append(postProjections, expr)
-				} else {
-					preProjections = append(preProjections, expr)
-					if _, ok := expr.(*logicalplan.DynamicColumn); ok {
-						postProjections = append(postProjections, expr)
-					} else {
-						postProjections = append(postProjections, logicalplan.Col(expr.Name()))
-					}
-				}
-			}
-
-			// We need to ensure that anything we group by is in the pre-projection.
-			for _, expr := range afterGroupBy {
-				found := false
-				for _, preExpr := range preProjections {
-					if expr.Name() == preExpr.Name() {
-						found = true
-						break
-					}
-				}
-				if !found {
-					preProjections = append(preProjections, expr)
-				}
-			}
+			preProjections, aggregations, postProjections := aggregationProjections(beforeGroupBy, groups)
 
 			// Insert a projection for any groups that need to be computed
 			// before they can be used for an aggregation, for example:
@@ -157,6 +110,76 @@ func (v *astVisitor) Enter(n ast.Node) (nRes ast.Node, skipChildren bool) {
 	return n, false
 }
 
+// aggregationProjections splits the given select expressions of an
+// aggregation by the given groups into the projections to compute before the
+// aggregation, the aggregations themselves and the projections to compute
+// after the aggregation.
+func aggregationProjections(
+	exprs []logicalplan.Expr,
+	groups []logicalplan.Expr,
+) ([]logicalplan.Expr, []*logicalplan.AggregationFunction, []logicalplan.Expr) {
+	includedPreprojections := make(map[string]struct{})
+	preProjections := []logicalplan.Expr{}
+	postProjections := []logicalplan.Expr{}
+	aggregations := []*logicalplan.AggregationFunction{}
+
+	for _, expr := range exprs {
+		// Walk the expression tree and separate out what projections
+		// need to be done before aggregations, and which can be done
+		// after. We don't support nested aggregations, so we can just
+		// find aggregation functions and put their expressions into
+		// the pre-projections. If we wanted to support nested
+		// aggregations, this would need to be more sophisticated.
+		aggCollector := &aggregationCollector{}
+		expr.Accept(aggCollector)
+
+		// If we have any aggregations nested in the AST like
+		// "sum(value) as value_sum_or_anything_else" then the actual
+		// query plan nesting looks something like:
+		// alias(value_sum_or_anything_else, sum(value))
+
+		if len(aggCollector.aggregations) > 0 {
+			// This is the expression that will be aggregated so we
+			// need to ensure that it is in the pre-projection.
+			for _, agg := range aggCollector.aggregations {
+				if _, ok := includedPreprojections[agg.Expr.Name()]; !ok {
+					preProjections = append(preProjections, agg.Expr)
+					// The same expression can be used in multiple
+					// aggregations, but we only need to project it
+					// once.
+					includedPreprojections[agg.Expr.Name()] = struct{}{}
+				}
+
+				aggregations = append(aggregations, agg)
+			}
+			postProjections = append(postProjections, expr)
+		} else {
+			preProjections = append(preProjections, expr)
+			if _, ok := expr.(*logicalplan.DynamicColumn); ok {
+				postProjections = append(postProjections, expr)
+			} else {
+				postProjections = append(postProjections, logicalplan.Col(expr.Name()))
+			}
+		}
+	}
+
+	// We need to ensure that anything we group by is in the pre-projection.
+	for _, expr := range groups {
+		found := false
+		for _, preExpr := range preProjections {
+			if expr.Name() == preExpr.Name() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			preProjections = append(preProjections, expr)
+		}
+	}
+
+	return preProjections, aggregations, postProjections
+}
+
 type aggregationCollector struct {
 	aggregations []*logicalplan.AggregationFunction
 }
@@ -177,6 +200,10 @@ func (a *aggregationCollector) PostVisit(_ logicalplan.Expr) bool {
 }
 
 func (v *astVisitor) Leave(n ast.Node) (nRes ast.Node, ok bool) {
+	if v.err != nil {
+		// Stop at the first error.
+		return n, false
+	}
 	if err := v.leaveImpl(n); err != nil {
 		v.err = err
 		return n, false
@@ -253,7 +280,7 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 			Right: rightExpr,
 		})
 	case *ast.ColumnName:
-		colName := columnNameToString(expr)
+		colName := v.columnName(expr)
 		var col logicalplan.Expr
 		if _, ok := v.dynColNames[colName]; ok {
 			col = logicalplan.DynCol(colName)
@@ -353,6 +380,19 @@ func (v *astVisitor) leaveImpl(n ast.Node) error {
 	return nil
 }
 
+// columnName returns the name of the column referenced by c. A qualifier
+// naming the queried table is dropped, unless it is the name of a dynamic
+// column.
+func (v *astVisitor) columnName(c *ast.ColumnName) string {
+	table := c.Table.String()
+	if _, ok := v.tableNames[table]; ok {
+		if _, ok := v.dynColNames[table]; !ok {
+			return c.Name.String()
+		}
+	}
+	return columnNameToString(c)
+}
+
 func columnNameToString(c *ast.ColumnName) string {
 	// Note that in SQL labels.label2 is interpreted as referencing
 	// the label2 column of a table called labels. In our case,
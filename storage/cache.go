@@ -0,0 +1,565 @@
+package storage
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/thanos-io/objstore"
+
+	"github.com/youscentia/ydb-frostdb/vfs"
+)
+
+const (
+	// DefaultCacheSize is the default number of bytes of pages a
+	// CachedBucket keeps on disk.
+	DefaultCacheSize = 1 << 30 // 1GiB
+	// DefaultCachePageSize is the default size of the pages a CachedBucket
+	// splits objects into.
+	DefaultCachePageSize = 1 << 20 // 1MiB
+	// DefaultCacheFooterSize is the default number of bytes at the end of
+	// an object that a CachedBucket keeps in memory. Parquet files store
+	// their metadata at the end of the file, so this covers the footer of
+	// most blocks.
+	DefaultCacheFooterSize = 64 << 10 // 64KiB
+	// DefaultCacheMetadataSize is the default number of bytes of object
+	// attributes and footers a CachedBucket keeps in memory.
+	DefaultCacheMetadataSize = 64 << 20 // 64MiB
+)
+
+// metadataEntrySize approximates the number of bytes a metadata entry holds
+// besides its footer.
+const metadataEntrySize = 128
+
+// CachedBucket is a Bucket that caches the objects read through GetReaderAt.
+// The attributes and footers of objects are cached in memory, all other
+// reads are cached as fixed-size pages on a file system. Both caches are
+// bounded in size and evict the least recently used entries first. Objects
+// are assumed to be immutable: cached entries are only invalidated when an
+// object is uploaded or deleted through the CachedBucket.
+type CachedBucket struct {
+	Bucket
+
+	fs  vfs.FileSystem
+	dir string
+
+	size         int64
+	pageSize     int64
+	footerSize   int64
+	metadataSize int64
+	reg          prometheus.Registerer
+	metrics      *cacheMetrics
+
+	mtx      sync.Mutex
+	metadata *lru[string, *objectMetadata]
+	pages    *lru[pageKey, string]
+	// objectPages are the pages cached for each object.
+	objectPages map[string]map[int64]struct{}
+	// generation is incremented whenever an object is invalidated, so that
+	// reads that started before don't cache stale data.
+	generation uint64
+	tmpFiles   atomic.Uint64
+}
+
+type objectMetadata struct {
+	attrs objstore.ObjectAttributes
+	// footer are the last bytes of the object, if they have been read.
+	footer []byte
+}
+
+type pageKey struct {
+	name string
+	page int64
+}
+
+type cacheMetrics struct {
+	hits      *prometheus.CounterVec
+	misses    *prometheus.CounterVec
+	evictions *prometheus.CounterVec
+	size      *prometheus.GaugeVec
+}
+
+func newCacheMetrics(reg prometheus.Registerer) *cacheMetrics {
+	reg = prometheus.WrapRegistererWithPrefix("frostdb_bucket_cache_", reg)
+	return &cacheMetrics{
+		hits: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hits_total",
+			Help: "Number of reads served from the bucket cache.",
+		}, []string{"cache"}),
+		misses: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "misses_total",
+			Help: "Number of reads that missed the bucket cache.",
+		}, []string{"cache"}),
+		evictions: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "evictions_total",
+			Help: "Number of entries evicted from the bucket cache.",
+		}, []string{"cache"}),
+		size: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "size_bytes",
+			Help: "Number of bytes held by the bucket cache.",
+		}, []string{"cache"}),
+	}
+}
+
+const (
+	metadataCache = "metadata"
+	pageCache     = "pages"
+)
+
+type CacheOption func(*CachedBucket)
+
+// CacheWithSize sets the number of bytes of pages kept on disk.
+func CacheWithSize(size int64) CacheOption {
+	return func(b *CachedBucket) {
+		b.size = size
+	}
+}
+
+// CacheWithPageSize sets the size of the pages objects are split into.
+func CacheWithPageSize(size int64) CacheOption {
+	return func(b *CachedBucket) {
+		b.pageSize = size
+	}
+}
+
+// CacheWithFooterSize sets the number of bytes at the end of each object that
+// are kept in memory.
+func CacheWithFooterSize(size int64) CacheOption {
+	return func(b *CachedBucket) {
+		b.footerSize = size
+	}
+}
+
+// CacheWithMetadataSize sets the number of bytes of object attributes and
+// footers kept in memory.
+func CacheWithMetadataSize(size int64) CacheOption {
+	return func(b *CachedBucket) {
+		b.metadataSize = size
+	}
+}
+
+// CacheWithRegisterer sets the registerer the cache metrics are registered
+// with.
+func CacheWithRegisterer(reg prometheus.Registerer) CacheOption {
+	return func(b *CachedBucket) {
+		b.reg = reg
+	}
+}
+
+// NewCachedBucket returns a CachedBucket that caches pages of the objects of
+// the given bucket in the given directory of the file system. Any contents of
+// the directory are removed, pages are not reused across restarts.
+func NewCachedBucket(bucket Bucket, fs vfs.FileSystem, dir string, options ...CacheOption) (*CachedBucket, error) {
+	b := &CachedBucket{
+		Bucket:       bucket,
+		fs:           fs,
+		dir:          dir,
+		size:         DefaultCacheSize,
+		pageSize:     DefaultCachePageSize,
+		footerSize:   DefaultCacheFooterSize,
+		metadataSize: DefaultCacheMetadataSize,
+		objectPages:  map[string]map[int64]struct{}{},
+	}
+
+	for _, option := range options {
+		option(b)
+	}
+	if b.pageSize <= 0 {
+		return nil, fmt.Errorf("invalid cache page size %d", b.pageSize)
+	}
+
+	if err := fs.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("clear cache directory: %w", err)
+	}
+	if err := fs.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	b.metrics = newCacheMetrics(b.reg)
+	b.metadata = newLRU(b.metadataSize, func(_ string, _ *objectMetadata) {
+		b.metrics.evictions.WithLabelValues(metadataCache).Inc()
+	})
+	b.pages = newLRU(b.size, func(key pageKey, path string) {
+		b.metrics.evictions.WithLabelValues(pageCache).Inc()
+		b.forgetPageLocked(key)
+		_ = b.fs.RemoveAll(path)
+	})
+	return b, nil
+}
+
+// NewCachedObjstoreBucket returns a CachedBucket for the given objstore.Bucket.
+func NewCachedObjstoreBucket(bucket objstore.Bucket, fs vfs.FileSystem, dir string, options ...CacheOption) (*CachedBucket, error) {
+	return NewCachedBucket(NewBucketReaderAt(bucket), fs, dir, options...)
+}
+
+// Attributes returns the attributes of the given object.
+func (b *CachedBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	md, err := b.objectMetadata(ctx, name)
+	if err != nil {
+		return objstore.ObjectAttributes{}, err
+	}
+	return md.attrs, nil
+}
+
+// Upload uploads the object to the underlying bucket and invalidates any
+// cached data of a previous version of it.
+func (b *CachedBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	err := b.Bucket.Upload(ctx, name, r)
+	b.invalidate(name)
+	return err
+}
+
+// Delete deletes the object from the underlying bucket and invalidates any
+// cached data of it.
+func (b *CachedBucket) Delete(ctx context.Context, name string) error {
+	err := b.Bucket.Delete(ctx, name)
+	b.invalidate(name)
+	return err
+}
+
+// GetReaderAt returns an io.ReaderAt for the given object that is served
+// from the cache.
+func (b *CachedBucket) GetReaderAt(ctx context.Context, name string) (io.ReaderAt, error) {
+	md, err := b.objectMetadata(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedReaderAt{
+		ctx:  ctx,
+		b:    b,
+		name: name,
+		size: md.attrs.Size,
+	}, nil
+}
+
+func (b *CachedBucket) objectMetadata(ctx context.Context, name string) (*objectMetadata, error) {
+	b.mtx.Lock()
+	md, ok := b.metadata.get(name)
+	generation := b.generation
+	b.mtx.Unlock()
+	if ok {
+		b.metrics.hits.WithLabelValues(metadataCache).Inc()
+		return md, nil
+	}
+	b.metrics.misses.WithLabelValues(metadataCache).Inc()
+
+	attrs, err := b.Bucket.Attributes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	md = &objectMetadata{attrs: attrs}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.generation == generation {
+		if cached, ok := b.metadata.get(name); ok {
+			return cached, nil
+		}
+		b.metadata.add(name, md, metadataEntrySize)
+		b.updateSizeLocked()
+	}
+	return md, nil
+}
+
+// footer returns the last bytes of the object that are kept in memory.
+func (b *CachedBucket) footer(ctx context.Context, name string, size int64) ([]byte, error) {
+	b.mtx.Lock()
+	md, ok := b.metadata.get(name)
+	generation := b.generation
+	b.mtx.Unlock()
+	if ok && md.footer != nil {
+		b.metrics.hits.WithLabelValues(metadataCache).Inc()
+		return md.footer, nil
+	}
+	b.metrics.misses.WithLabelValues(metadataCache).Inc()
+
+	n := min(b.footerSize, size)
+	footer, err := b.getRange(ctx, name, size-n, n)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.generation == generation {
+		if md, ok := b.metadata.get(name); ok && md.footer == nil && md.attrs.Size == size {
+			// Entries are immutable as they are handed out without holding
+			// the lock, so the entry is replaced.
+			b.metadata.add(name, &objectMetadata{attrs: md.attrs, footer: footer}, metadataEntrySize+int64(len(footer)))
+			b.updateSizeLocked()
+		}
+	}
+	return footer, nil
+}
+
+// readPage reads from the given page of the object into p starting at the
+// given offset within the page. It returns the number of bytes read, which is
+// less than len(p) if the page ends before p is filled.
+func (b *CachedBucket) readPage(ctx context.Context, name string, size, page int64, p []byte, off int64) (int, error) {
+	key := pageKey{name: name, page: page}
+	b.mtx.Lock()
+	path, ok := b.pages.get(key)
+	generation := b.generation
+	b.mtx.Unlock()
+	if ok {
+		if n, err := b.readPageFile(path, p, off); err == nil {
+			b.metrics.hits.WithLabelValues(pageCache).Inc()
+			return n, nil
+		}
+		// The page was evicted in the meantime, read it again.
+	}
+	b.metrics.misses.WithLabelValues(pageCache).Inc()
+
+	start := page * b.pageSize
+	data, err := b.getRange(ctx, name, start, min(b.pageSize, size-start))
+	if err != nil {
+		return 0, err
+	}
+	b.storePage(key, generation, data)
+
+	if off >= int64(len(data)) {
+		return 0, nil
+	}
+	return copy(p, data[off:]), nil
+}
+
+func (b *CachedBucket) readPageFile(path string, p []byte, off int64) (int, error) {
+	f, err := b.fs.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n, err := f.ReadAt(p, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	return n, nil
+}
+
+// storePage writes the page to disk and adds it to the cache. Failing to do so
+// is not an error, the page is simply not cached.
+func (b *CachedBucket) storePage(key pageKey, generation uint64, data []byte) {
+	dir := filepath.Join(b.dir, url.PathEscape(key.name))
+	path := filepath.Join(dir, strconv.FormatInt(key.page, 10))
+	if err := b.fs.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	// Write to a temporary file first so that concurrent readers never see a
+	// partially written page.
+	tmp := path + ".tmp" + strconv.FormatUint(b.tmpFiles.Add(1), 10)
+	if err := b.writeFile(tmp, data); err != nil {
+		_ = b.fs.RemoveAll(tmp)
+		return
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if _, ok := b.pages.get(key); ok || b.generation != generation {
+		_ = b.fs.RemoveAll(tmp)
+		return
+	}
+	if err := b.fs.Rename(tmp, path); err != nil {
+		_ = b.fs.RemoveAll(tmp)
+		return
+	}
+	pages, ok := b.objectPages[key.name]
+	if !ok {
+		pages = map[int64]struct{}{}
+		b.objectPages[key.name] = pages
+	}
+	pages[key.page] = struct{}{}
+	b.pages.add(key, path, int64(len(data)))
+	b.updateSizeLocked()
+}
+
+func (b *CachedBucket) writeFile(path string, data []byte) error {
+	f, err := b.fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (b *CachedBucket) getRange(ctx context.Context, name string, off, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	rc, err := b.Bucket.GetRange(ctx, name, off, length)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	buf := bytes.NewBuffer(make([]byte, 0, length))
+	if _, err := buf.ReadFrom(rc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// invalidate removes all cached data of the given object.
+func (b *CachedBucket) invalidate(name string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.generation++
+	b.metadata.remove(name)
+	if pages, ok := b.objectPages[name]; ok {
+		for page := range pages {
+			b.pages.remove(pageKey{name: name, page: page})
+		}
+		delete(b.objectPages, name)
+		_ = b.fs.RemoveAll(filepath.Join(b.dir, url.PathEscape(name)))
+	}
+	b.updateSizeLocked()
+}
+
+// forgetPageLocked removes an evicted page from the pages of its object.
+func (b *CachedBucket) forgetPageLocked(key pageKey) {
+	pages := b.objectPages[key.name]
+	delete(pages, key.page)
+	if len(pages) == 0 {
+		delete(b.objectPages, key.name)
+	}
+}
+
+func (b *CachedBucket) updateSizeLocked() {
+	b.metrics.size.WithLabelValues(metadataCache).Set(float64(b.metadata.size))
+	b.metrics.size.WithLabelValues(pageCache).Set(float64(b.pages.size))
+}
+
+// cachedReaderAt reads an object through the cache of a CachedBucket.
+type cachedReaderAt struct {
+	ctx  context.Context
+	b    *CachedBucket
+	name string
+	size int64
+}
+
+// ReadAt implements the io.ReaderAt interface.
+func (r *cachedReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	footerStart := r.size - min(r.b.footerSize, r.size)
+	n := 0
+	for n < len(p) && off+int64(n) < r.size {
+		pos := off + int64(n)
+		if pos >= footerStart {
+			footer, err := r.b.footer(r.ctx, r.name, r.size)
+			if err != nil {
+				return n, err
+			}
+			n += copy(p[n:], footer[pos-footerStart:])
+			break
+		}
+
+		// Reads that reach into the footer are served from it.
+		end := min(off+int64(len(p)), footerStart)
+		page := pos / r.b.pageSize
+		read, err := r.b.readPage(r.ctx, r.name, r.size, page, p[n:end-off], pos-page*r.b.pageSize)
+		if err != nil {
+			return n, err
+		}
+		if read == 0 {
+			// The object is shorter than its attributes claimed.
+			break
+		}
+		n += read
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// lru is a size-bounded map that evicts the least recently used entries once
+// the sum of the sizes of its entries exceeds its maximum size.
+type lru[K comparable, V any] struct {
+	maxSize int64
+	size    int64
+	ll      *list.List
+	items   map[K]*list.Element
+	onEvict func(K, V)
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+func newLRU[K comparable, V any](maxSize int64, onEvict func(K, V)) *lru[K, V] {
+	return &lru[K, V]{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   map[K]*list.Element{},
+		onEvict: onEvict,
+	}
+}
+
+func (l *lru[K, V]) get(key K) (V, bool) {
+	e, ok := l.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	l.ll.MoveToFront(e)
+	return e.Value.(*lruEntry[K, V]).value, true
+}
+
+// add adds or replaces the entry of the given key and evicts entries until
+// the lru fits into its maximum size again.
+func (l *lru[K, V]) add(key K, value V, size int64) {
+	if e, ok := l.items[key]; ok {
+		entry := e.Value.(*lruEntry[K, V])
+		l.size += size - entry.size
+		entry.value = value
+		entry.size = size
+		l.ll.MoveToFront(e)
+	} else {
+		l.items[key] = l.ll.PushFront(&lruEntry[K, V]{key: key, value: value, size: size})
+		l.size += size
+	}
+
+	for l.size > l.maxSize && l.ll.Len() > 0 {
+		e := l.ll.Back()
+		entry := e.Value.(*lruEntry[K, V])
+		l.removeElement(e)
+		if l.onEvict != nil {
+			l.onEvict(entry.key, entry.value)
+		}
+	}
+}
+
+// remove removes the entry of the given key without calling onEvict.
+func (l *lru[K, V]) remove(key K) {
+	if e, ok := l.items[key]; ok {
+		l.removeElement(e)
+	}
+}
+
+func (l *lru[K, V]) removeElement(e *list.Element) {
+	entry := e.Value.(*lruEntry[K, V])
+	l.ll.Remove(e)
+	delete(l.items, entry.key)
+	l.size -= entry.size
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	vfsadapters "github.com/youscentia/ydb-frostdb/vfs/adapters"
+)
+
+// countingBucket counts the ranges read from the underlying bucket.
+type countingBucket struct {
+	objstore.Bucket
+	ranges atomic.Int64
+}
+
+func (b *countingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	b.ranges.Add(1)
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+func newTestCachedBucket(t *testing.T, options ...CacheOption) (*countingBucket, *CachedBucket, string) {
+	t.Helper()
+	bucket := &countingBucket{Bucket: objstore.NewInMemBucket()}
+	dir := t.TempDir()
+	options = append([]CacheOption{
+		CacheWithPageSize(1024),
+		CacheWithFooterSize(512),
+		CacheWithRegisterer(prometheus.NewRegistry()),
+	}, options...)
+	cache, err := NewCachedObjstoreBucket(bucket, vfsadapters.NewOSAdapter(), dir, options...)
+	require.NoError(t, err)
+	return bucket, cache, dir
+}
+
+func readAt(t *testing.T, b Bucket, name string, off, length int64) []byte {
+	t.Helper()
+	r, err := b.GetReaderAt(context.Background(), name)
+	require.NoError(t, err)
+	p := make([]byte, length)
+	n, err := r.ReadAt(p, off)
+	if err != nil {
+		require.ErrorIs(t, err, io.EOF)
+	}
+	return p[:n]
+}
+
+func TestCachedBucket(t *testing.T) {
+	ctx := context.Background()
+	bucket, cache, _ := newTestCachedBucket(t)
+
+	data := make([]byte, 10_000)
+	rand.New(rand.NewSource(1)).Read(data)
+	require.NoError(t, cache.Upload(ctx, "block/data.parquet", bytes.NewReader(data)))
+
+	reads := []struct{ off, length int64 }{
+		{off: 0, length: 100},
+		{off: 1000, length: 100},   // spans two pages
+		{off: 3000, length: 5000},  // spans many pages
+		{off: 9400, length: 200},   // spans the footer
+		{off: 9900, length: 100},   // within the footer
+		{off: 9950, length: 100},   // past the end
+		{off: 0, length: 10_000},   // the whole object
+		{off: 10_000, length: 100}, // at the end
+	}
+	for _, read := range reads {
+		end := min(read.off+read.length, int64(len(data)))
+		require.Equal(t, data[read.off:end], readAt(t, cache, "block/data.parquet", read.off, read.length))
+	}
+
+	// All reads are served from the cache the second time.
+	ranges := bucket.ranges.Load()
+	for _, read := range reads {
+		end := min(read.off+read.length, int64(len(data)))
+		require.Equal(t, data[read.off:end], readAt(t, cache, "block/data.parquet", read.off, read.length))
+	}
+	require.Equal(t, ranges, bucket.ranges.Load())
+	require.Greater(t, testutil.ToFloat64(cache.metrics.hits.WithLabelValues(pageCache)), 0.0)
+	require.Greater(t, testutil.ToFloat64(cache.metrics.hits.WithLabelValues(metadataCache)), 0.0)
+	require.Greater(t, testutil.ToFloat64(cache.metrics.misses.WithLabelValues(pageCache)), 0.0)
+}
+
+func TestCachedBucketEviction(t *testing.T) {
+	ctx := context.Background()
+	_, cache, _ := newTestCachedBucket(t, CacheWithSize(2048))
+
+	data := make([]byte, 10_000)
+	rand.New(rand.NewSource(1)).Read(data)
+	require.NoError(t, cache.Upload(ctx, "block/data.parquet", bytes.NewReader(data)))
+	require.Equal(t, data, readAt(t, cache, "block/data.parquet", 0, int64(len(data))))
+
+	// Only the two most recently read pages are kept.
+	require.LessOrEqual(t, cache.pages.size, int64(2048))
+	require.Len(t, cache.objectPages["block/data.parquet"], 2)
+	entries, err := cache.fs.ReadDir(filepath.Join(cache.dir, "block%2Fdata.parquet"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, 8.0, testutil.ToFloat64(cache.metrics.evictions.WithLabelValues(pageCache)))
+	require.Equal(t, data, readAt(t, cache, "block/data.parquet", 0, int64(len(data))))
+}
+
+func TestCachedBucketInvalidation(t *testing.T) {
+	ctx := context.Background()
+	_, cache, dir := newTestCachedBucket(t)
+
+	require.NoError(t, cache.Upload(ctx, "block/data.parquet", bytes.NewReader(bytes.Repeat([]byte{1}, 4096))))
+	require.Equal(t, bytes.Repeat([]byte{1}, 100), readAt(t, cache, "block/data.parquet", 0, 100))
+
+	// Overwriting an object invalidates its cached pages and footer.
+	require.NoError(t, cache.Upload(ctx, "block/data.parquet", bytes.NewReader(bytes.Repeat([]byte{2}, 2048))))
+	require.Equal(t, bytes.Repeat([]byte{2}, 100), readAt(t, cache, "block/data.parquet", 0, 100))
+	require.Equal(t, bytes.Repeat([]byte{2}, 100), readAt(t, cache, "block/data.parquet", 1948, 100))
+
+	// Deleting an object removes it from the cache.
+	require.NoError(t, cache.Delete(ctx, "block/data.parquet"))
+	_, err := cache.Attributes(ctx, "block/data.parquet")
+	require.True(t, cache.IsObjNotFoundErr(err))
+	entries, err := cache.fs.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, 0.0, testutil.ToFloat64(cache.metrics.size.WithLabelValues(pageCache)))
+}
+
+func TestCachedBucketParquet(t *testing.T) {
+	ctx := context.Background()
+	_, cache, _ := newTestCachedBucket(t)
+
+	type row struct {
+		Name  string
+		Value int64
+	}
+	rows := make([]row, 1000)
+	for i := range rows {
+		rows[i] = row{Name: "name", Value: int64(i)}
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, parquet.Write(buf, rows))
+	require.NoError(t, cache.Upload(ctx, "block/data.parquet", bytes.NewReader(buf.Bytes())))
+
+	for i := 0; i < 2; i++ {
+		attrs, err := cache.Attributes(ctx, "block/data.parquet")
+		require.NoError(t, err)
+		r, err := cache.GetReaderAt(ctx, "block/data.parquet")
+		require.NoError(t, err)
+		f, err := parquet.OpenFile(r, attrs.Size)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(rows)), f.NumRows())
+
+		read, err := parquet.Read[row](r, attrs.Size)
+		require.NoError(t, err)
+		require.Equal(t, rows, read)
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
-	"github.com/oklog/ulid"
+	"github.com/oklog/ulid/v2"
 	"github.com/parquet-go/parquet-go"
 	"github.com/polarsignals/iceberg-go"
 	"github.com/polarsignals/iceberg-go/catalog"
@@ -293,12 +293,26 @@ func (i *Iceberg) Scan(ctx context.Context, prefix string, _ *dynparquet.Schema,
 				return err
 			}
 
+			// The data files are named by the upload, the block they were
+			// uploaded from is recorded in their metadata.
+			var block *ulid.ULID
+			if v, ok := file.Lookup(dynparquet.BlockKey); ok {
+				id, err := ulid.Parse(v)
+				if err != nil {
+					return fmt.Errorf("parse block of data file %s: %w", e.DataFile().FilePath(), err)
+				}
+				block = &id
+			}
+
 			for i := 0; i < buf.NumRowGroups(); i++ {
 				rg := buf.DynamicRowGroup(i)
 				mayContainUsefulData, err := fltr.Eval(rg, false)
 				if err != nil {
 					return err
 				}
+				if block != nil {
+					rg = blockRowGroup{DynamicRowGroup: rg, block: *block}
+				}
 				if mayContainUsefulData {
 					if err := callback(ctx, rg); err != nil {
 						return err
@@ -311,6 +325,16 @@ func (i *Iceberg) Scan(ctx context.Context, prefix string, _ *dynparquet.Schema,
 	return nil
 }
 
+// blockRowGroup is a row group of a data file. It records the block the data
+// file was uploaded from so that deletes performed after the block was
+// persisted can be applied to it.
+type blockRowGroup struct {
+	dynparquet.DynamicRowGroup
+	block ulid.ULID
+}
+
+func (rg blockRowGroup) Block() ulid.ULID { return rg.block }
+
 // Prefixes lists all the tables found in the warehouse for the given database(prefix).
 func (i *Iceberg) Prefixes(ctx context.Context, prefix string) ([]string, error) {
 	tables, err := i.catalog.ListTables(ctx, []string{filepath.Join(i.bucketURI, prefix)})
@@ -355,6 +379,74 @@ func (i *Iceberg) Upload(ctx context.Context, name string, r io.Reader) error {
 	return w.Close(ctx)
 }
 
+// DeleteBlocksBefore removes the data files of the table at the given prefix
+// whose ULID timestamp is before the given time. A new snapshot without the
+// data files is committed before the data files are removed from the bucket,
+// so concurrent scans of the latest snapshot never read a removed file. All
+// previous snapshots still reference the removed data files, so they are
+// expired by the same commit, which also removes their manifest lists and the
+// manifests only they reference.
+func (i *Iceberg) DeleteBlocksBefore(ctx context.Context, prefix string, before time.Time) (int, int64, error) {
+	tablePath := filepath.Join(i.bucketURI, prefix)
+	t, err := i.catalog.LoadTable(ctx, []string{tablePath}, iceberg.Properties{})
+	if err != nil {
+		if errors.Is(err, catalog.ErrorTableNotFound) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	if t.CurrentSnapshot() == nil {
+		// Nothing was uploaded to the table yet.
+		return 0, 0, nil
+	}
+
+	options := append(defaultWriterOptions[:len(defaultWriterOptions):len(defaultWriterOptions)],
+		table.WithExpireSnapshotsOlderThan(time.Nanosecond),
+		table.WithLogger(i.logger),
+	)
+	w, err := t.SnapshotWriter(options...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var expired []iceberg.DataFile
+	if err := w.DeleteDataFile(ctx, func(d iceberg.DataFile) bool {
+		id, err := ulid.Parse(strings.TrimSuffix(filepath.Base(d.FilePath()), ".parquet"))
+		if err != nil {
+			level.Error(i.logger).Log("msg", "failed to parse ulid", "err", err)
+			return false
+		}
+
+		if !ulid.Time(id.Time()).Before(before) {
+			return false
+		}
+		expired = append(expired, d)
+		return true
+	}); err != nil {
+		return 0, 0, err
+	}
+	if len(expired) == 0 {
+		// Don't commit an empty snapshot that would expire the history of
+		// the table for nothing.
+		return 0, 0, nil
+	}
+
+	if err := w.Close(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	var bytes int64
+	for n, d := range expired {
+		if err := i.bucket.Delete(ctx, d.FilePath()); err != nil && !i.bucket.IsObjNotFoundErr(err) {
+			return n, bytes, fmt.Errorf("delete data file %s: %w", d.FilePath(), err)
+		}
+		bytes += d.FileSizeBytes()
+	}
+
+	return len(expired), bytes, nil
+}
+
 func (i *Iceberg) Delete(_ context.Context, _ string) error {
 	// Noop
 	// NOTE: Deletes are used in DataSinks when an upload fails for any reason. Because an Iceberg table is not updated
@@ -477,7 +569,7 @@ type virtualColumnChunk struct {
 	upperBounds []byte
 }
 
-func (v *virtualColumnChunk) Type() parquet.Type   { return nil }
+func (v *virtualColumnChunk) Type() parquet.Type   { return v.pType }
 func (v *virtualColumnChunk) Column() int          { return v.column }
 func (v *virtualColumnChunk) Pages() parquet.Pages { return nil }
 func (v *virtualColumnChunk) ColumnIndex() (parquet.ColumnIndex, error) {
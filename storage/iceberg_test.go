@@ -78,3 +78,82 @@ func Test_IcebergMaintenance(t *testing.T) {
 		return nil
 	}, objstore.WithRecursiveIter))
 }
+
+func Test_IcebergDeleteBlocksBefore(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	iceberg, err := NewIceberg("/", catalog.NewHDFS("/", bucket), bucket)
+	require.NoError(t, err)
+
+	type Element struct {
+		Name, Symbol string
+		Number       int
+		Mass         float64
+	}
+
+	ctx := context.Background()
+	upload := func(elements ...Element) {
+		b := &bytes.Buffer{}
+		require.NoError(t, parquet.Write(b, elements))
+		require.NoError(t, iceberg.Upload(ctx, "db/table/ulid/data.parquet", b))
+	}
+	dataFiles := func() []string {
+		var files []string
+		require.NoError(t, bucket.Iter(ctx, "", func(name string) error {
+			if strings.HasSuffix(name, ".parquet") {
+				files = append(files, filepath.Base(name))
+			}
+			return nil
+		}, objstore.WithRecursiveIter))
+		return files
+	}
+
+	upload(Element{"Hydrogen", "H", 1, 1.00794}, Element{"Helium", "He", 2, 4.002602})
+	deleted := dataFiles()
+	require.Len(t, deleted, 1)
+
+	// Make sure the ULID of the next data file is after the cutoff.
+	time.Sleep(2 * time.Millisecond)
+	before := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	upload(Element{"Lithium", "Li", 3, 6.941}, Element{"Beryllium", "Be", 4, 9.012182})
+
+	blocks, _, err := iceberg.DeleteBlocksBefore(ctx, "db/table", before)
+	require.NoError(t, err)
+	require.Equal(t, 1, blocks)
+	require.NotContains(t, dataFiles(), deleted[0])
+
+	// Only the snapshot without the deleted data file is left, and neither it
+	// nor any manifest left in the bucket references the deleted data file.
+	tbl, err := iceberg.catalog.LoadTable(ctx, []string{"/db/table"}, nil)
+	require.NoError(t, err)
+	snapshots := tbl.Metadata().Snapshots()
+	require.Len(t, snapshots, 1)
+
+	referenced := map[string]struct{}{
+		filepath.Base(snapshots[0].ManifestList): {},
+	}
+	manifests, err := snapshots[0].Manifests(tbl.Bucket())
+	require.NoError(t, err)
+	for _, manifest := range manifests {
+		referenced[filepath.Base(manifest.FilePath())] = struct{}{}
+		entries, _, err := manifest.FetchEntries(tbl.Bucket(), false)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			require.NotEqual(t, deleted[0], filepath.Base(entry.DataFile().FilePath()))
+		}
+	}
+	require.NoError(t, bucket.Iter(ctx, "", func(name string) error {
+		if strings.HasSuffix(name, ".avro") {
+			require.Contains(t, referenced, filepath.Base(name))
+		}
+		return nil
+	}, objstore.WithRecursiveIter))
+
+	// Nothing is left to delete, so no snapshot is committed.
+	blocks, _, err = iceberg.DeleteBlocksBefore(ctx, "db/table", before)
+	require.NoError(t, err)
+	require.Equal(t, 0, blocks)
+	tbl, err = iceberg.catalog.LoadTable(ctx, []string{"/db/table"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, snapshots[0].SnapshotID, tbl.CurrentSnapshot().SnapshotID)
+}
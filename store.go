@@ -1,10 +1,13 @@
 package frostdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/trace/noop"
 
@@ -18,6 +21,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/youscentia/ydb-frostdb/dynparquet"
+	catalogpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/catalog/v1alpha1"
 	"github.com/youscentia/ydb-frostdb/query/expr"
 	"github.com/youscentia/ydb-frostdb/query/logicalplan"
 	"github.com/youscentia/ydb-frostdb/storage"
@@ -26,38 +30,153 @@ import (
 // DefaultBlockReaderLimit is the concurrency limit for reading blocks.
 const DefaultBlockReaderLimit = 10
 
-// Persist uploads the block to the underlying bucket.
+// Persist uploads the block to the sinks of the database. With a single sink
+// the block is streamed to it, with multiple sinks the block is serialized
+// once and replicated to all of them according to the replication policy.
 func (t *TableBlock) Persist() error {
-	if len(t.table.db.sinks) == 0 {
+	switch sinks := t.table.db.sinks; len(sinks) {
+	case 0:
 		return nil
+	case 1:
+		if err := t.upload(sinks[0]); err != nil {
+			return err
+		}
+	default:
+		if err := t.replicate(sinks); err != nil {
+			return err
+		}
+	}
+
+	t.table.metrics.blockPersisted.Inc()
+	return nil
+}
+
+func (t *TableBlock) fileName() string {
+	return filepath.Join(t.table.db.name, t.table.Name(), t.ulid.String(), "data.parquet")
+}
+
+func (t *TableBlock) upload(sink DataSink) error {
+	r, w := io.Pipe()
+	var err error
+	go func() {
+		defer w.Close()
+		err = t.Serialize(w)
+	}()
+	defer r.Close()
+
+	fileName := t.fileName()
+	if err := sink.Upload(context.Background(), fileName, r); err != nil {
+		return fmt.Errorf("failed to upload block %v", err)
 	}
 
-	for i, sink := range t.table.db.sinks {
-		if i > 0 {
-			return fmt.Errorf("multiple sinks not supported")
+	if err != nil {
+		if deleteErr := sink.Delete(context.Background(), fileName); deleteErr != nil {
+			err = fmt.Errorf("%v failed to delete file on error: %w", err, deleteErr)
 		}
-		r, w := io.Pipe()
-		var err error
-		go func() {
-			defer w.Close()
-			err = t.Serialize(w)
-		}()
-		defer r.Close()
-
-		fileName := filepath.Join(t.table.db.name, t.table.name, t.ulid.String(), "data.parquet")
-		if err := sink.Upload(context.Background(), fileName, r); err != nil {
-			return fmt.Errorf("failed to upload block %v", err)
+		return fmt.Errorf("failed to serialize block: %w", err)
+	}
+
+	if err := t.addToCatalog(context.Background(), sink); err != nil {
+		if deleteErr := sink.Delete(context.Background(), fileName); deleteErr != nil {
+			err = fmt.Errorf("%v failed to delete file on error: %w", err, deleteErr)
 		}
+		return err
+	}
+	return nil
+}
 
-		if err != nil {
-			if deleteErr := sink.Delete(context.Background(), fileName); deleteErr != nil {
+func (t *TableBlock) addToCatalog(ctx context.Context, sink DataSink) error {
+	return t.table.addToCatalog(ctx, sink, t.ulid)
+}
+
+// addToCatalog adds the given persisted block of the table to the catalog of
+// the sink if it keeps one.
+func (t *Table) addToCatalog(ctx context.Context, sink DataSink, block ulid.ULID) error {
+	cs, ok := sink.(CatalogSink)
+	if !ok {
+		return nil
+	}
+	if err := cs.AddBlock(ctx, filepath.Join(t.db.name, t.Name()), block, t.schema.Load()); err != nil {
+		return fmt.Errorf("failed to add block to catalog: %w", err)
+	}
+	return nil
+}
+
+// replicate uploads the block to all given sinks. The first sink is the
+// primary sink, an upload to it must always succeed. Under the
+// ReplicateToAll policy the uploads to all secondary sinks must succeed as
+// well, otherwise the block is removed from the sinks it was already uploaded
+// to. Under the ReplicateBestEffort policy failed uploads to secondary sinks
+// are queued to be retried instead.
+func (t *TableBlock) replicate(sinks []DataSink) error {
+	buf := &bytes.Buffer{}
+	if err := t.Serialize(buf); err != nil {
+		return fmt.Errorf("failed to serialize block: %w", err)
+	}
+	data := buf.Bytes()
+
+	ctx := context.Background()
+	fileName := t.fileName()
+	bestEffort := t.table.db.columnStore.replicationPolicy == ReplicateBestEffort
+	retry := t.table.db.columnStore.replicationRetryInterval > 0
+	queueRetry := func(sink DataSink, err error) {
+		t.table.metrics.replicationsFailed.Inc()
+		if !retry {
+			level.Error(t.logger).Log(
+				"msg", "failed to replicate block, retries are disabled",
+				"ulid", t.ulid,
+				"sink", sink.String(),
+				"err", err,
+			)
+			t.table.metrics.replicationsDropped.Inc()
+			return
+		}
+		level.Warn(t.logger).Log(
+			"msg", "failed to replicate block, queueing retry",
+			"ulid", t.ulid,
+			"sink", sink.String(),
+			"err", err,
+		)
+		t.table.db.replication.add(t.table.db.logger, pendingReplication{
+			table: t.table,
+			sink:  sink,
+			block: t.ulid,
+			name:  fileName,
+		})
+	}
+	deleteFrom := func(sinks []DataSink, err error) error {
+		for _, sink := range sinks {
+			if deleteErr := sink.Delete(ctx, fileName); deleteErr != nil {
 				err = fmt.Errorf("%v failed to delete file on error: %w", err, deleteErr)
 			}
-			return fmt.Errorf("failed to serialize block: %w", err)
 		}
+		return err
 	}
 
-	t.table.metrics.blockPersisted.Inc()
+	// The block is only added to the catalogs once its data is stored in all
+	// sinks it must be stored in, so a failed upload doesn't leave catalog
+	// entries behind.
+	uploaded := make([]DataSink, 0, len(sinks))
+	for i, sink := range sinks {
+		if err := sink.Upload(ctx, fileName, bytes.NewReader(data)); err != nil {
+			if bestEffort && i > 0 {
+				queueRetry(sink, err)
+				continue
+			}
+			return fmt.Errorf("failed to upload block to %s: %w", sink, deleteFrom(uploaded, err))
+		}
+		uploaded = append(uploaded, sink)
+	}
+
+	for i, sink := range uploaded {
+		if err := t.addToCatalog(ctx, sink); err != nil {
+			if bestEffort && i > 0 {
+				queueRetry(sink, deleteFrom([]DataSink{sink}, err))
+				continue
+			}
+			return fmt.Errorf("failed to upload block to %s: %w", sink, deleteFrom(uploaded, err))
+		}
+	}
 	return nil
 }
 
@@ -68,6 +187,9 @@ type DefaultObjstoreBucket struct {
 	logger log.Logger
 
 	blockReaderLimit int
+
+	// catalogMtx serializes updates of the catalogs of the bucket.
+	catalogMtx sync.Mutex
 }
 
 type DefaultObjstoreBucketOption func(*DefaultObjstoreBucket)
@@ -140,7 +262,7 @@ func (b *DefaultObjstoreBucket) String() string {
 	return b.Name()
 }
 
-func (b *DefaultObjstoreBucket) Scan(ctx context.Context, prefix string, _ *dynparquet.Schema, filter logicalplan.Expr, lastBlockTimestamp uint64, callback func(context.Context, any) error) error {
+func (b *DefaultObjstoreBucket) Scan(ctx context.Context, prefix string, schema *dynparquet.Schema, filter logicalplan.Expr, lastBlockTimestamp uint64, callback func(context.Context, any) error) error {
 	ctx, span := b.tracer.Start(ctx, "Source/Scan")
 	span.SetAttributes(attribute.Int64("lastBlockTimestamp", int64(lastBlockTimestamp)))
 	defer span.End()
@@ -150,10 +272,21 @@ func (b *DefaultObjstoreBucket) Scan(ctx context.Context, prefix string, _ *dynp
 		return err
 	}
 
+	c, err := b.readCatalog(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("read catalog: %w", err)
+	}
+	if c != nil {
+		return b.scanCatalog(ctx, prefix, c, schema, filter, f, lastBlockTimestamp, callback)
+	}
+
 	n := 0
 	errg := &errgroup.Group{}
 	errg.SetLimit(int(b.blockReaderLimit))
 	err = b.Iter(ctx, prefix, func(blockDir string) error {
+		if _, ok := b.parseBlockDir(blockDir); !ok {
+			return nil
+		}
 		n++
 		errg.Go(func() error { return b.ProcessFile(ctx, blockDir, lastBlockTimestamp, f, callback) })
 		return nil
@@ -167,6 +300,333 @@ func (b *DefaultObjstoreBucket) Scan(ctx context.Context, prefix string, _ *dynp
 	return errg.Wait()
 }
 
+// scanCatalog scans the blocks of the given catalog instead of listing the
+// prefix. Blocks whose metadata shows that they can't hold rows satisfying
+// the filter are skipped without opening them.
+func (b *DefaultObjstoreBucket) scanCatalog(
+	ctx context.Context,
+	prefix string,
+	c *catalogpb.Catalog,
+	schema *dynparquet.Schema,
+	filter logicalplan.Expr,
+	f expr.TrueNegativeFilter,
+	lastBlockTimestamp uint64,
+	callback func(context.Context, any) error,
+) error {
+	span := trace.SpanFromContext(ctx)
+
+	// The blocks are pruned with a filter of their own, since filters may
+	// keep state across evaluations.
+	var (
+		blockFilter    expr.TrueNegativeFilter
+		dynamicColumns []string
+	)
+	if canPruneBlocks(filter) {
+		var err error
+		if blockFilter, err = expr.BooleanExpr(filter); err != nil {
+			return err
+		}
+		dynamicColumns = dynamicFilterColumns(schema, filter)
+	}
+
+	n, pruned := 0, 0
+	errg := &errgroup.Group{}
+	errg.SetLimit(int(b.blockReaderLimit))
+	for _, md := range c.Blocks {
+		if blockFilter != nil {
+			ok, err := mayContainUsefulData(blockFilter, md, dynamicColumns)
+			if err != nil {
+				level.Warn(b.logger).Log("msg", "failed to evaluate filter on block metadata", "block", md.Ulid, "err", err)
+			} else if !ok {
+				pruned++
+				continue
+			}
+		}
+
+		n++
+		blockDir := filepath.Join(prefix, md.Ulid)
+		errg.Go(func() error {
+			err := b.ProcessFile(ctx, blockDir, lastBlockTimestamp, f, callback)
+			if err != nil && b.IsObjNotFoundErr(err) {
+				// The block was removed after the catalog was read.
+				return nil
+			}
+			return err
+		})
+	}
+
+	span.SetAttributes(attribute.Int("blocks", n), attribute.Int("pruned", pruned))
+	level.Debug(b.logger).Log("msg", "read blocks", "n", n, "pruned", pruned)
+	return errg.Wait()
+}
+
+// parseBlockDir returns the ULID of the block stored in the given directory.
+// Directories that don't hold a block are ignored.
+func (b *DefaultObjstoreBucket) parseBlockDir(blockDir string) (ulid.ULID, bool) {
+	base := filepath.Base(blockDir)
+	if base == catalogDir {
+		return ulid.ULID{}, false
+	}
+	blockUlid, err := ulid.Parse(base)
+	if err != nil {
+		level.Warn(b.logger).Log("msg", "ignoring block with invalid ulid", "block", blockDir, "err", err)
+		return ulid.ULID{}, false
+	}
+	return blockUlid, true
+}
+
+// Blocks returns the ULIDs of the blocks stored under the given prefix. They
+// are read from the catalog of the prefix if it has one. Blocks with an
+// invalid ULID are ignored.
+func (b *DefaultObjstoreBucket) Blocks(ctx context.Context, prefix string) ([]ulid.ULID, error) {
+	ctx, span := b.tracer.Start(ctx, "Source/Blocks")
+	defer span.End()
+
+	c, err := b.readCatalog(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+
+	var blocks []ulid.ULID
+	if c != nil {
+		for _, md := range c.Blocks {
+			blockUlid, err := ulid.Parse(md.Ulid)
+			if err != nil {
+				level.Warn(b.logger).Log("msg", "ignoring catalog entry with invalid ulid", "block", md.Ulid, "err", err)
+				continue
+			}
+			blocks = append(blocks, blockUlid)
+		}
+	} else if err := b.Iter(ctx, prefix, func(blockDir string) error {
+		if blockUlid, ok := b.parseBlockDir(blockDir); ok {
+			blocks = append(blocks, blockUlid)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("blocks", len(blocks)))
+	return blocks, nil
+}
+
+// ScanBlock scans the given block stored under the given prefix.
+func (b *DefaultObjstoreBucket) ScanBlock(ctx context.Context, prefix string, block ulid.ULID, _ *dynparquet.Schema, filter logicalplan.Expr, lastBlockTimestamp uint64, callback func(context.Context, any) error) error {
+	ctx, span := b.tracer.Start(ctx, "Source/ScanBlock")
+	defer span.End()
+
+	f, err := expr.BooleanExpr(filter)
+	if err != nil {
+		return err
+	}
+	return b.ProcessFile(ctx, filepath.Join(prefix, block.String()), lastBlockTimestamp, f, callback)
+}
+
+// DeleteBlocksBefore removes the blocks stored under the given prefix whose
+// ULID timestamp is before the given time.
+func (b *DefaultObjstoreBucket) DeleteBlocksBefore(ctx context.Context, prefix string, before time.Time) (int, int64, error) {
+	ctx, span := b.tracer.Start(ctx, "Sink/DeleteBlocksBefore")
+	defer span.End()
+
+	// Remove the blocks from the catalog first, so that it never refers to
+	// blocks that don't exist anymore.
+	if err := b.removeFromCatalog(ctx, prefix, before); err != nil {
+		return 0, 0, fmt.Errorf("remove blocks from catalog: %w", err)
+	}
+
+	var expired []string
+	if err := b.Iter(ctx, prefix, func(blockDir string) error {
+		blockUlid, ok := b.parseBlockDir(blockDir)
+		if ok && ulid.Time(blockUlid.Time()).Before(before) {
+			expired = append(expired, blockDir)
+		}
+		return nil
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	blocks, bytes, err := b.deleteBlockDirs(ctx, expired)
+	span.SetAttributes(attribute.Int("blocks", blocks), attribute.Int64("bytes", bytes))
+	return blocks, bytes, err
+}
+
+// deleteBlockDirs removes the metadata and data of the blocks stored in the
+// given directories. It returns the number of blocks and bytes that were
+// removed.
+func (b *DefaultObjstoreBucket) deleteBlockDirs(ctx context.Context, blockDirs []string) (int, int64, error) {
+	var (
+		blocks int
+		bytes  int64
+	)
+	for _, blockDir := range blockDirs {
+		metadataName := filepath.Join(blockDir, blockMetadataFile)
+		if err := b.Delete(ctx, metadataName); err != nil && !b.IsObjNotFoundErr(err) {
+			return blocks, bytes, fmt.Errorf("delete block metadata %s: %w", metadataName, err)
+		}
+
+		blockName := filepath.Join(blockDir, "data.parquet")
+		attribs, err := b.Attributes(ctx, blockName)
+		if err != nil {
+			if b.IsObjNotFoundErr(err) {
+				continue
+			}
+			return blocks, bytes, err
+		}
+		if err := b.Delete(ctx, blockName); err != nil {
+			return blocks, bytes, fmt.Errorf("delete block %s: %w", blockName, err)
+		}
+		blocks++
+		bytes += attribs.Size
+	}
+	return blocks, bytes, nil
+}
+
+// PersistedBlocks returns the blocks stored under the given prefix as listed
+// by the catalog of the prefix, which is built if it doesn't exist yet.
+func (b *DefaultObjstoreBucket) PersistedBlocks(ctx context.Context, prefix string, schema *dynparquet.Schema) ([]PersistedBlock, error) {
+	ctx, span := b.tracer.Start(ctx, "Sink/PersistedBlocks")
+	defer span.End()
+
+	c, err := b.readCatalog(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+	if c == nil {
+		if c, err = b.buildCatalog(ctx, prefix, schema); err != nil {
+			return nil, fmt.Errorf("build catalog: %w", err)
+		}
+	}
+
+	blocks := make([]PersistedBlock, 0, len(c.Blocks))
+	for _, md := range c.Blocks {
+		blockUlid, err := ulid.Parse(md.Ulid)
+		if err != nil {
+			level.Warn(b.logger).Log("msg", "ignoring catalog entry with invalid ulid", "block", md.Ulid, "err", err)
+			continue
+		}
+		blocks = append(blocks, PersistedBlock{
+			ULID:       blockUlid,
+			Size:       md.Size,
+			Resolution: time.Duration(md.ResolutionMs) * time.Millisecond,
+		})
+	}
+	span.SetAttributes(attribute.Int("blocks", len(blocks)))
+	return blocks, nil
+}
+
+// MoveBlocks moves the blocks stored under the prefix from whose ULID
+// timestamp is before the given time to the prefix to. Each block is
+// streamed to its new prefix and added to its catalog before it is removed
+// from the previous one, so moving the blocks again after a failure
+// completes the move.
+func (b *DefaultObjstoreBucket) MoveBlocks(ctx context.Context, from, to string, before time.Time, schema *dynparquet.Schema) (int, error) {
+	ctx, span := b.tracer.Start(ctx, "Sink/MoveBlocks")
+	defer span.End()
+
+	blocks, err := b.PersistedBlocks(ctx, from, schema)
+	if err != nil {
+		return 0, fmt.Errorf("list blocks: %w", err)
+	}
+	moved := 0
+	for _, block := range blocks {
+		if !ulid.Time(block.ULID.Time()).Before(before) {
+			continue
+		}
+		if err := b.copyBlock(ctx, from, to, block.ULID); err != nil {
+			return moved, fmt.Errorf("copy block %s: %w", block.ULID, err)
+		}
+		if err := b.AddBlock(ctx, to, block.ULID, schema); err != nil {
+			return moved, fmt.Errorf("add block %s: %w", block.ULID, err)
+		}
+		if err := b.updateCatalog(ctx, from, nil, &catalogpb.CatalogDelta{
+			Removed: []string{block.ULID.String()},
+		}); err != nil {
+			return moved, fmt.Errorf("remove block %s from catalog: %w", block.ULID, err)
+		}
+		if _, _, err := b.DeleteBlocks(ctx, from, []ulid.ULID{block.ULID}); err != nil {
+			return moved, fmt.Errorf("delete block %s: %w", block.ULID, err)
+		}
+		moved++
+	}
+	span.SetAttributes(attribute.Int("blocks", moved))
+	return moved, nil
+}
+
+// copyBlock streams the data of the given block stored under the prefix from
+// to the prefix to. Copying a block again overwrites the copy.
+func (b *DefaultObjstoreBucket) copyBlock(ctx context.Context, from, to string, block ulid.ULID) error {
+	rc, err := b.Get(ctx, filepath.Join(from, block.String(), "data.parquet"))
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return b.Upload(ctx, filepath.Join(to, block.String(), "data.parquet"), rc)
+}
+
+// ReadBlock returns the data of the given block stored under the given
+// prefix.
+func (b *DefaultObjstoreBucket) ReadBlock(ctx context.Context, prefix string, block ulid.ULID) ([]byte, error) {
+	ctx, span := b.tracer.Start(ctx, "Sink/ReadBlock")
+	defer span.End()
+
+	rc, err := b.Get(ctx, filepath.Join(prefix, block.String(), "data.parquet"))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// ReplaceBlocks uploads a new block holding the given data and swaps it for
+// the given blocks in the catalog of the prefix in a single delta, so scans
+// see either the old blocks or the new one. The replaced blocks are left in
+// place for scans that read the catalog before the swap and have to be
+// removed with DeleteBlocks.
+func (b *DefaultObjstoreBucket) ReplaceBlocks(ctx context.Context, prefix string, blocks []ulid.ULID, block ulid.ULID, data []byte, schema *dynparquet.Schema) error {
+	ctx, span := b.tracer.Start(ctx, "Sink/ReplaceBlocks")
+	defer span.End()
+
+	blockDir := filepath.Join(prefix, block.String())
+	blockName := filepath.Join(blockDir, "data.parquet")
+	if err := b.Upload(ctx, blockName, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("upload block: %w", err)
+	}
+	md, err := b.computeBlockMetadata(ctx, blockDir, block, schema)
+	if err == nil {
+		err = b.writeBlockMetadata(ctx, blockDir, md)
+	}
+	if err == nil {
+		delta := &catalogpb.CatalogDelta{Added: []*catalogpb.BlockMetadata{md}}
+		for _, old := range blocks {
+			delta.Removed = append(delta.Removed, old.String())
+		}
+		err = b.updateCatalog(ctx, prefix, schema, delta)
+	}
+	if err != nil {
+		if _, _, deleteErr := b.deleteBlockDirs(ctx, []string{blockDir}); deleteErr != nil {
+			err = fmt.Errorf("%v failed to delete block on error: %w", err, deleteErr)
+		}
+		return fmt.Errorf("replace blocks: %w", err)
+	}
+	return nil
+}
+
+// DeleteBlocks removes the given blocks stored under the given prefix. They
+// must have been removed from the catalog of the prefix before. It returns
+// the number of blocks and bytes that were removed.
+func (b *DefaultObjstoreBucket) DeleteBlocks(ctx context.Context, prefix string, blocks []ulid.ULID) (int, int64, error) {
+	ctx, span := b.tracer.Start(ctx, "Sink/DeleteBlocks")
+	defer span.End()
+
+	blockDirs := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		blockDirs = append(blockDirs, filepath.Join(prefix, block.String()))
+	}
+	n, size, err := b.deleteBlockDirs(ctx, blockDirs)
+	span.SetAttributes(attribute.Int("blocks", n), attribute.Int64("bytes", size))
+	return n, size, err
+}
+
 func (b *DefaultObjstoreBucket) openBlockFile(ctx context.Context, blockName string, size int64) (*parquet.File, error) {
 	ctx, span := b.tracer.Start(ctx, "Source/Scan/OpenFile")
 	defer span.End()
@@ -237,10 +697,19 @@ func (b *DefaultObjstoreBucket) ProcessFile(ctx context.Context, blockDir string
 		return err
 	}
 
-	return b.filterRowGroups(ctx, buf, filter, callback)
+	return b.filterRowGroups(ctx, blockUlid, buf, filter, callback)
 }
 
-func (b *DefaultObjstoreBucket) filterRowGroups(ctx context.Context, buf *dynparquet.SerializedBuffer, filter expr.TrueNegativeFilter, callback func(context.Context, any) error) error {
+// persistedRowGroup is the BlockRowGroup of the row groups of the blocks
+// read from a bucket.
+type persistedRowGroup struct {
+	dynparquet.DynamicRowGroup
+	block ulid.ULID
+}
+
+func (rg persistedRowGroup) Block() ulid.ULID { return rg.block }
+
+func (b *DefaultObjstoreBucket) filterRowGroups(ctx context.Context, block ulid.ULID, buf *dynparquet.SerializedBuffer, filter expr.TrueNegativeFilter, callback func(context.Context, any) error) error {
 	for i := 0; i < buf.NumRowGroups(); i++ {
 		rg := buf.DynamicRowGroup(i)
 		mayContainUsefulData, err := filter.Eval(rg, false)
@@ -248,7 +717,7 @@ func (b *DefaultObjstoreBucket) filterRowGroups(ctx context.Context, buf *dynpar
 			return err
 		}
 		if mayContainUsefulData {
-			if err := callback(ctx, rg); err != nil {
+			if err := callback(ctx, persistedRowGroup{DynamicRowGroup: rg, block: block}); err != nil {
 				return err
 			}
 		}
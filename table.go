@@ -58,6 +58,10 @@ func DefaultIndexConfig() []*index.LevelConfig {
 	}
 }
 
+// ErrTxCompacted is returned when reading a table at a txn whose data has
+// since been compacted or persisted, so it can't be read as it was anymore.
+var ErrTxCompacted = errors.New("txn is older than the oldest readable txn")
+
 type ErrWriteRow struct{ err error }
 
 func (e ErrWriteRow) Error() string { return "failed to write row: " + e.err.Error() }
@@ -98,6 +102,58 @@ func WithoutWAL() TableOption {
 	}
 }
 
+// WithRetention sets the duration persisted blocks of this table are kept in
+// storage for. Blocks older than this are removed by the retention loop of the
+// database. A zero duration disables retention. Retention is kept with
+// millisecond precision, so shorter durations are rejected rather than
+// truncated to zero.
+func WithRetention(d time.Duration) TableOption {
+	return func(config *tablepb.TableConfig) error {
+		if d < 0 {
+			return fmt.Errorf("retention must not be negative: %s", d)
+		}
+		if d > 0 && d < time.Millisecond {
+			return fmt.Errorf("retention must be at least 1ms: %s", d)
+		}
+		config.RetentionMs = uint64(d.Milliseconds())
+		return nil
+	}
+}
+
+// WithBlockCompaction sets the duration of the time windows persisted blocks
+// of this table are compacted in. Blocks whose ULID timestamps fall into the
+// same window are merged into a single block by the block compaction loop of
+// the database once the window has passed. A zero duration disables block
+// compaction.
+func WithBlockCompaction(window time.Duration) TableOption {
+	return func(config *tablepb.TableConfig) error {
+		if window < 0 {
+			return fmt.Errorf("block compaction window must not be negative: %s", window)
+		}
+		config.BlockCompactionWindowMs = uint64(window.Milliseconds())
+		return nil
+	}
+}
+
+// WithDownsampling downsamples the rows of persisted blocks of this table to
+// the given resolution when they are compacted (see WithBlockCompaction). The
+// values of the given time column, an int64 column of milliseconds or a
+// timestamp column, are truncated to the resolution, and rows that are then
+// equal on all sorting columns are merged into one by summing up their other
+// numeric columns.
+func WithDownsampling(column string, resolution time.Duration) TableOption {
+	return func(config *tablepb.TableConfig) error {
+		if resolution <= 0 {
+			return fmt.Errorf("downsampling resolution must be positive: %s", resolution)
+		}
+		config.Downsampling = &tablepb.Downsampling{
+			Column:       column,
+			ResolutionMs: uint64(resolution.Milliseconds()),
+		}
+		return nil
+	}
+}
+
 func WithUniquePrimaryIndex(unique bool) TableOption {
 	return func(config *tablepb.TableConfig) error {
 		switch e := config.Schema.(type) {
@@ -119,6 +175,9 @@ func FromConfig(config *tablepb.TableConfig) TableOption {
 		}
 		cfg.DisableWal = config.DisableWal
 		cfg.RowGroupSize = config.RowGroupSize
+		cfg.RetentionMs = config.RetentionMs
+		cfg.BlockCompactionWindowMs = config.BlockCompactionWindowMs
+		cfg.Downsampling = config.Downsampling
 		return nil
 	}
 }
@@ -254,24 +313,44 @@ func NewGenericTable[T any](db *DB, name string, mem memory.Allocator, options .
 }
 
 type Table struct {
-	db      *DB
-	name    string
+	db *DB
+	// name is the name of the table, it changes when the table is renamed.
+	name    atomic.Pointer[string]
 	metrics tableMetrics
 	logger  log.Logger
 	tracer  trace.Tracer
 
 	config atomic.Pointer[tablepb.TableConfig]
-	schema *dynparquet.Schema
+	// schema is the schema of the table, it changes when the schema of the
+	// table is altered.
+	schema atomic.Pointer[dynparquet.Schema]
 
 	pendingBlocks   map[*TableBlock]struct{}
 	completedBlocks []completedBlock
 	lastCompleted   uint64
+	// droppedTx is the min txn of the active block when a block of the table
+	// was last dropped from memory. The rows of dropped blocks are only read
+	// from storage, which doesn't filter them by txn.
+	droppedTx uint64
 
 	mtx    *sync.RWMutex
 	active *TableBlock
 
+	// tombstones are the deletes performed on the table, in transaction
+	// order. They are guarded by their own mutex as they are read during
+	// compactions, which may be waited on while holding mtx. Tombstones are
+	// dropped once they no longer apply to any rows, see dropTombstones.
+	tombstonesMtx sync.RWMutex
+	tombstones    []*tombstone
+	// purgedTx is the newest txn of the tombstones whose rows were removed
+	// by compactions. Reads at earlier txns miss the rows deleted after them.
+	purgedTx uint64
+
 	wal     WAL
 	closing bool
+	// dropped is set once the drop of the table was logged. Blocks of the
+	// table that are persisted afterwards are not logged anymore.
+	dropped bool
 }
 
 type Sync interface {
@@ -282,6 +361,12 @@ type WAL interface {
 	Close() error
 	Log(tx uint64, record *walpb.Record) error
 	LogRecord(tx uint64, table string, record arrow.Record) error
+	// LogRecords logs the records written to the given tables in a single
+	// transaction as one WAL record.
+	LogRecords(tx uint64, tables []string, records []arrow.Record) error
+	// WaitDurable blocks until the record with the given tx has been written
+	// and fsynced.
+	WaitDurable(tx uint64) error
 	// Replay replays WAL records from the given first index. If firstIndex is
 	// 0, the first index read from the WAL is used (i.e. given a truncation,
 	// using 0 is still valid). If the given firstIndex is less than the WAL's
@@ -366,18 +451,22 @@ func newTable(
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDownsampling(s, tableConfig.Downsampling); err != nil {
+		return nil, err
+	}
 
 	t := &Table{
 		db:      db,
-		name:    name,
 		logger:  logger,
 		tracer:  tracer,
 		mtx:     &sync.RWMutex{},
 		wal:     wal,
-		schema:  s,
 		metrics: metrics,
 	}
 
+	t.name.Store(&name)
+	t.schema.Store(s)
+
 	// Store the table config
 	t.config.Store(tableConfig)
 
@@ -401,7 +490,7 @@ func (t *Table) newTableBlock(prevTx, tx uint64, id ulid.ULID) error {
 		Entry: &walpb.Entry{
 			EntryType: &walpb.Entry_NewTableBlock_{
 				NewTableBlock: &walpb.Entry_NewTableBlock{
-					TableName: t.name,
+					TableName: t.Name(),
 					BlockId:   b,
 					Config:    t.config.Load(),
 				},
@@ -423,6 +512,9 @@ func (t *Table) dropPendingBlock(block *TableBlock) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 	delete(t.pendingBlocks, block)
+	if t.active != nil {
+		t.droppedTx = max(t.droppedTx, t.active.minTx)
+	}
 
 	// Wait for outstanding readers/writers to finish with the block before releasing underlying resources.
 	block.pendingReadersWg.Wait()
@@ -456,6 +548,11 @@ func (t *Table) writeBlock(
 		err = block.Persist()
 	}
 	t.dropPendingBlock(block)
+	if len(t.db.sinks) == 0 && len(t.db.sources) == 0 {
+		// Without persisted blocks, the deletes only apply to the parts of
+		// the blocks that are still in memory.
+		t.dropTombstones(t.persistedTombstones())
+	}
 	if err != nil {
 		level.Error(t.logger).Log("msg", "failed to persist block")
 		level.Error(t.logger).Log("msg", err.Error())
@@ -463,6 +560,14 @@ func (t *Table) writeBlock(
 	}
 
 	if err := func() error {
+		// The entry is logged under the table lock to order it with drops
+		// and renames of the table.
+		t.mtx.RLock()
+		defer t.mtx.RUnlock()
+		if t.dropped {
+			return ErrTableNotFound{TableName: t.Name()}
+		}
+
 		tx, _, commit := t.db.begin()
 		defer commit()
 
@@ -477,7 +582,7 @@ func (t *Table) writeBlock(
 			Entry: &walpb.Entry{
 				EntryType: &walpb.Entry_TableBlockPersisted_{
 					TableBlockPersisted: &walpb.Entry_TableBlockPersisted{
-						TableName: t.name,
+						TableName: t.Name(),
 						BlockId:   buf,
 						// NOTE: nextTxn is used here instead of tx, since some
 						// writes could have happened between block rotation
@@ -634,7 +739,7 @@ func (t *Table) ActiveWriteBlock() (*TableBlock, func(), error) {
 	t.mtx.RLock()
 	defer t.mtx.RUnlock()
 
-	if t.closing {
+	if t.closing || t.dropped {
 		return nil, nil, ErrTableClosing
 	}
 
@@ -642,18 +747,29 @@ func (t *Table) ActiveWriteBlock() (*TableBlock, func(), error) {
 	return t.active, t.active.pendingWritersWg.Done, nil
 }
 
+// Name returns the name of the table.
+func (t *Table) Name() string {
+	return *t.name.Load()
+}
+
 func (t *Table) Schema() *dynparquet.Schema {
 	if t.config.Load() == nil {
 		return nil
 	}
-	return t.schema
+	return t.schema.Load()
 }
 
 func (t *Table) EnsureCompaction() error {
 	return t.ActiveBlock().EnsureCompaction()
 }
 
+// InsertRecord inserts the record into the table and returns the txn it is
+// visible after. A synchronous write that fails with ErrDurabilityUnknown was
+// inserted nonetheless.
 func (t *Table) InsertRecord(ctx context.Context, record arrow.Record) (uint64, error) {
+	if t.db.leader != nil {
+		return 0, ErrFollower
+	}
 	block, finish, err := t.appender(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("get appender: %w", err)
@@ -663,10 +779,10 @@ func (t *Table) InsertRecord(ctx context.Context, record arrow.Record) (uint64,
 	tx, _, commit := t.db.begin()
 	defer commit()
 
-	preHashedRecord := dynparquet.PrehashColumns(t.schema, record)
+	preHashedRecord := dynparquet.PrehashColumns(t.schema.Load(), record)
 	defer preHashedRecord.Release()
 
-	if err := t.wal.LogRecord(tx, t.name, preHashedRecord); err != nil {
+	if err := t.wal.LogRecord(tx, t.Name(), preHashedRecord); err != nil {
 		return tx, fmt.Errorf("append to log: %w", err)
 	}
 
@@ -674,6 +790,12 @@ func (t *Table) InsertRecord(ctx context.Context, record arrow.Record) (uint64,
 		return tx, fmt.Errorf("insert buffer into block: %w", err)
 	}
 
+	// The record is inserted into the block while it is being written to the
+	// log, the txn is only committed once it is durable or failed to be.
+	if err := t.db.waitDurable(ctx, t.wal, tx); err != nil {
+		return tx, err
+	}
+
 	return tx, nil
 }
 
@@ -728,12 +850,64 @@ func (t *Table) appender(ctx context.Context) (*TableBlock, func(), error) {
 	}
 }
 
+// View calls fn with the txn to read the table at. It returns ErrTxCompacted
+// if the txn set on the context with logicalplan.WithReadTx can no longer be
+// read as it was, including when the table is compacted past the txn while
+// fn is reading it.
 func (t *Table) View(ctx context.Context, fn func(ctx context.Context, tx uint64) error) error {
 	ctx, span := t.tracer.Start(ctx, "Table/View")
+	defer span.End()
 	tx := t.db.beginRead()
+	readTx, pinned := logicalplan.ReadTx(ctx)
+	if pinned {
+		if readTx > tx {
+			return fmt.Errorf("read table %s at txn %d: txn is newer than the high watermark %d", t.Name(), readTx, tx)
+		}
+		if err := t.checkReadableTx(readTx); err != nil {
+			return err
+		}
+		tx = readTx
+	}
 	span.SetAttributes(attribute.Int64("tx", int64(tx))) // Attributes don't support uint64...
-	defer span.End()
-	return fn(ctx, tx)
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	if pinned {
+		// Compactions merge parts up to the high watermark, so one that ran
+		// during the read may have hidden rows of the txn from it.
+		return t.checkReadableTx(readTx)
+	}
+	return nil
+}
+
+// checkReadableTx returns ErrTxCompacted if the table can no longer be read
+// at tx as it was.
+func (t *Table) checkReadableTx(tx uint64) error {
+	if oldest := t.oldestReadableTx(); tx < oldest {
+		return fmt.Errorf("read table %s at txn %d: %w %d", t.Name(), tx, ErrTxCompacted, oldest)
+	}
+	return nil
+}
+
+// oldestReadableTx returns the oldest txn the table can be read at as it was.
+// Reads at earlier txns miss rows that were compacted into parts of later
+// txns or deleted by later txns, and see rows of later txns in blocks that
+// were dropped from memory.
+func (t *Table) oldestReadableTx() uint64 {
+	t.tombstonesMtx.RLock()
+	tx := t.purgedTx
+	t.tombstonesMtx.RUnlock()
+
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	tx = max(tx, t.droppedTx)
+	for block := range t.pendingBlocks {
+		tx = max(tx, block.index.CompactedTx())
+	}
+	if t.active != nil {
+		tx = max(tx, t.active.index.CompactedTx())
+	}
+	return tx
 }
 
 // Iterator iterates in order over all granules in the table. It stops iterating when the iterator function returns false.
@@ -779,9 +953,17 @@ func (t *Table) Iterator(
 	// buffered results are flushed to the next operator.
 	const bufferSize = 1024
 
+	adapter, err := t.newSchemaAdapter(ctx, *iterOpts)
+	if err != nil {
+		return fmt.Errorf("adapt schema: %w", err)
+	}
+
 	errg, ctx := errgroup.WithContext(ctx)
 	for _, callback := range callbacks {
 		callback := callback
+		if adapter != nil {
+			callback = adapter.callback(pool, callback)
+		}
 		errg.Go(recovery.Do(func() error {
 			converter := pqarrow.NewParquetConverter(pool, *iterOpts)
 			defer converter.Close()
@@ -814,7 +996,7 @@ func (t *Table) Iterator(
 						}
 					case index.ReleaseableRowGroup:
 						defer rg.Release()
-						if err := converter.Convert(ctx, rg, t.schema); err != nil {
+						if err := converter.Convert(ctx, rg, t.schema.Load()); err != nil {
 							return fmt.Errorf("failed to convert row group to arrow record: %v", err)
 						}
 						// This RowGroup had no relevant data. Ignore it.
@@ -833,7 +1015,7 @@ func (t *Table) Iterator(
 							}
 						}
 					case dynparquet.DynamicRowGroup:
-						if err := converter.Convert(ctx, rg, t.schema); err != nil {
+						if err := converter.Convert(ctx, rg, t.schema.Load()); err != nil {
 							return fmt.Errorf("failed to convert row group to arrow record: %v", err)
 						}
 						// This RowGroup had no relevant data. Ignore it.
@@ -861,7 +1043,7 @@ func (t *Table) Iterator(
 
 	errg.Go(func() error {
 		defer close(rowGroups)
-		return t.collectRowGroups(ctx, tx, iterOpts.Filter, iterOpts.ReadMode, rowGroups)
+		return t.collectRowGroups(ctx, tx, pool, iterOpts.Filter, iterOpts.ReadMode, rowGroups)
 	})
 
 	return errg.Wait()
@@ -982,7 +1164,7 @@ func (t *Table) SchemaIterator(
 	}
 
 	errg.Go(func() error {
-		if err := t.collectRowGroups(ctx, tx, iterOpts.Filter, iterOpts.ReadMode, rowGroups); err != nil {
+		if err := t.collectRowGroups(ctx, tx, pool, iterOpts.Filter, iterOpts.ReadMode, rowGroups); err != nil {
 			return err
 		}
 		close(rowGroups)
@@ -1011,8 +1193,8 @@ func newTableBlock(table *Table, prevTx, tx uint64, id ulid.ULID) (*TableBlock,
 
 	var err error
 	tb.index, err = index.NewLSM(
-		filepath.Join(table.db.indexDir(), table.name, id.String()), // Any index files are found at <db.indexDir>/<table.name>/<block.id>
-		table.schema,
+		filepath.Join(table.db.indexDir(), table.Name(), id.String()), // Any index files are found at <db.indexDir>/<table.name>/<block.id>
+		table.schema.Load(),
 		table.IndexConfig(),
 		table.db.HighWatermark,
 		index.LSMWithMetrics(&table.metrics.indexMetrics),
@@ -1061,7 +1243,7 @@ func (t *TableBlock) Index() *index.LSM {
 
 // Serialize the table block into a single Parquet file.
 func (t *TableBlock) Serialize(writer io.Writer) error {
-	return t.index.Rotate(t.table.externalParquetCompaction(writer))
+	return t.index.Rotate(t.table.externalParquetCompaction(writer, parquet.KeyValueMetadata(dynparquet.BlockKey, t.ulid.String())))
 }
 
 type ParquetWriter interface {
@@ -1096,7 +1278,7 @@ func (t *TableBlock) rowWriter(w ParquetWriter, options ...parquetRowWriterOptio
 
 	p := &parquetRowWriter{
 		w:            w,
-		schema:       t.table.schema,
+		schema:       t.table.schema.Load(),
 		rowsBuf:      make([]parquet.Row, buffSize),
 		rowGroupSize: int(config.RowGroupSize),
 	}
@@ -1175,10 +1357,13 @@ func (t *Table) memoryBlocks() ([]*TableBlock, uint64) {
 	return memoryBlocks, lastReadBlockTimestamp
 }
 
-// collectRowGroups collects all the row groups from the table for the given filter.
+// collectRowGroups collects all the row groups from the table for the given
+// filter. Row groups containing rows deleted before tx are converted to arrow
+// records with those rows removed.
 func (t *Table) collectRowGroups(
 	ctx context.Context,
 	tx uint64,
+	pool memory.Allocator,
 	filterExpr logicalplan.Expr,
 	readMode logicalplan.ReadMode,
 	rowGroups chan<- any,
@@ -1186,6 +1371,8 @@ func (t *Table) collectRowGroups(
 	ctx, span := t.tracer.Start(ctx, "Table/collectRowGroups")
 	defer span.End()
 
+	tombstones := t.visibleTombstones(tx)
+
 	// pending blocks could be uploaded to the bucket while we iterate on them.
 	// to avoid to iterate on them again while reading the block file
 	// we keep the last block timestamp to be read from the bucket and pass it to the IterateBucketBlocks() function
@@ -1200,7 +1387,18 @@ func (t *Table) collectRowGroups(
 			}
 		}()
 		for _, block := range memoryBlocks {
-			if err := block.index.Scan(ctx, "", t.schema, filterExpr, tx, func(ctx context.Context, v any) error {
+			if err := block.index.ScanWithTx(ctx, filterExpr, tx, func(ctx context.Context, partTx uint64, v any) error {
+				if ts := partTombstones(tombstones, partTx); len(ts) > 0 {
+					r, err := t.applyTombstones(ctx, pool, v, ts)
+					if err != nil || r == nil {
+						return err
+					}
+					if r.NumRows() == 0 {
+						r.Release()
+						return nil
+					}
+					v = r
+				}
 				select {
 				case <-ctx.Done():
 					if rg, ok := v.(index.ReleaseableRowGroup); ok {
@@ -1220,27 +1418,152 @@ func (t *Table) collectRowGroups(
 		return nil
 	}
 
-	// Collect from all other data sources.
-	for _, source := range t.db.sources {
-		span.AddEvent(fmt.Sprintf("source/%s", source.String()))
-		if err := source.Scan(ctx, filepath.Join(t.db.name, t.name), t.schema, filterExpr, lastBlockTimestamp, func(ctx context.Context, v any) error {
-			select {
-			case <-ctx.Done():
+	collect := func(ctx context.Context, v any) error {
+		if len(tombstones) > 0 {
+			// Deletes can only be applied to row groups of sources that
+			// record the block they were read from.
+			rg, ok := v.(BlockRowGroup)
+			if !ok {
 				if rg, ok := v.(index.ReleaseableRowGroup); ok {
 					rg.Release()
 				}
-				return ctx.Err()
-			case rowGroups <- v:
-				return nil
+				return fmt.Errorf("apply deletes to row group of type %T: the block it was read from is unknown", v)
 			}
-		}); err != nil {
+			if ts := blockTombstones(tombstones, rg.Block()); len(ts) > 0 {
+				r, err := t.applyTombstones(ctx, pool, rg, ts)
+				if err != nil || r == nil {
+					return err
+				}
+				if r.NumRows() == 0 {
+					r.Release()
+					return nil
+				}
+				v = r
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if rg, ok := v.(index.ReleaseableRowGroup); ok {
+				rg.Release()
+			}
+			return ctx.Err()
+		case rowGroups <- v:
+			return nil
+		}
+	}
+
+	// Multiple sources that are able to scan blocks individually are
+	// replicas of each other, so each block is only read from one of them.
+	var (
+		sources  []DataSource
+		replicas []BlockSource
+	)
+	for _, source := range t.db.sources {
+		if bs, ok := source.(BlockSource); ok {
+			replicas = append(replicas, bs)
+			continue
+		}
+		sources = append(sources, source)
+	}
+	if len(replicas) < 2 {
+		sources = t.db.sources
+		replicas = nil
+	}
+
+	// Collect from all other data sources.
+	for _, source := range sources {
+		span.AddEvent(fmt.Sprintf("source/%s", source.String()))
+		if err := source.Scan(ctx, filepath.Join(t.db.name, t.Name()), t.schema.Load(), filterExpr, lastBlockTimestamp, collect); err != nil {
 			return err
 		}
 	}
+	if len(replicas) > 0 {
+		span.AddEvent("replicas")
+		return t.scanReplicas(ctx, replicas, filterExpr, lastBlockTimestamp, collect)
+	}
 
 	return nil
 }
 
+// scanReplicas scans the persisted blocks of the table held by the given
+// sources. Every block is read from the first source that holds it. If
+// reading a block fails before any of its row groups were collected, it is
+// read from the next source that holds it instead.
+func (t *Table) scanReplicas(
+	ctx context.Context,
+	sources []BlockSource,
+	filterExpr logicalplan.Expr,
+	lastBlockTimestamp uint64,
+	collect func(context.Context, any) error,
+) error {
+	prefix := filepath.Join(t.db.name, t.Name())
+
+	var (
+		blocks  []ulid.ULID
+		holders = map[ulid.ULID][]BlockSource{}
+		listed  int
+		listErr error
+	)
+	for _, source := range sources {
+		ids, err := source.Blocks(ctx, prefix)
+		if err != nil {
+			level.Warn(t.logger).Log("msg", "failed to list blocks", "source", source.String(), "err", err)
+			listErr = err
+			continue
+		}
+		listed++
+		for _, id := range ids {
+			if _, ok := holders[id]; !ok {
+				blocks = append(blocks, id)
+			}
+			holders[id] = append(holders[id], source)
+		}
+	}
+	if listed == 0 {
+		return fmt.Errorf("failed to list blocks: %w", listErr)
+	}
+
+	errg, ctx := errgroup.WithContext(ctx)
+	errg.SetLimit(DefaultBlockReaderLimit)
+	for _, block := range blocks {
+		errg.Go(func() error {
+			return t.scanReplicatedBlock(ctx, prefix, block, holders[block], filterExpr, lastBlockTimestamp, collect)
+		})
+	}
+	return errg.Wait()
+}
+
+func (t *Table) scanReplicatedBlock(
+	ctx context.Context,
+	prefix string,
+	block ulid.ULID,
+	sources []BlockSource,
+	filterExpr logicalplan.Expr,
+	lastBlockTimestamp uint64,
+	collect func(context.Context, any) error,
+) error {
+	var err error
+	for _, source := range sources {
+		collected := false
+		err = source.ScanBlock(ctx, prefix, block, t.schema.Load(), filterExpr, lastBlockTimestamp, func(ctx context.Context, v any) error {
+			collected = true
+			return collect(ctx, v)
+		})
+		// Row groups that were already collected can't be taken back, so
+		// falling back to another source would duplicate them.
+		if err == nil || collected || ctx.Err() != nil {
+			return err
+		}
+		level.Warn(t.logger).Log(
+			"msg", "failed to read block from source",
+			"block", block,
+			"source", source.String(),
+			"err", err,
+		)
+	}
+	return err
+}
+
 // close notifies a table to stop accepting writes.
 func (t *Table) close() {
 	t.mtx.Lock()
@@ -1251,9 +1574,9 @@ func (t *Table) close() {
 	t.active.index.WaitForPendingCompactions()
 }
 
-func (t *Table) externalParquetCompaction(writer io.Writer) func(compact []parts.Part) (parts.Part, int64, int64, error) {
+func (t *Table) externalParquetCompaction(writer io.Writer, options ...parquet.WriterOption) func(compact []parts.Part) (parts.Part, int64, int64, error) {
 	return func(compact []parts.Part) (parts.Part, int64, int64, error) {
-		size, err := t.compactParts(writer, compact)
+		size, err := t.compactParts(writer, compact, options...)
 		if err != nil {
 			return nil, 0, 0, err
 		}
@@ -1274,7 +1597,17 @@ func (t *Table) compactParts(w io.Writer, compact []parts.Part, options ...parqu
 		preCompactionSize += p.Size()
 	}
 
-	if t.schema.UniquePrimaryIndex {
+	compact, release, err := t.purgeDeletedRows(compact)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted rows: %w", err)
+	}
+	defer release()
+	if len(compact) == 0 {
+		// All the rows were deleted.
+		return preCompactionSize, t.writeRecordsToParquet(w, nil, false, options...)
+	}
+
+	if t.schema.Load().UniquePrimaryIndex {
 		distinctRecords, err := t.distinctRecordsForCompaction(compact)
 		if err != nil {
 			return 0, err
@@ -1301,53 +1634,54 @@ func (t *Table) compactParts(w io.Writer, compact []parts.Part, options ...parqu
 		return preCompactionSize, nil
 	}
 
-	merged, err := t.schema.MergeDynamicRowGroups(bufs)
+	merged, err := t.schema.Load().MergeDynamicRowGroups(bufs)
 	if err != nil {
 		return 0, err
 	}
-	err = func() error {
-		var writer dynparquet.ParquetWriter
-		if len(options) > 0 {
-			writer, err = t.schema.NewWriter(w, merged.DynamicColumns(), false, options...)
-			if err != nil {
-				return err
-			}
-		} else {
-			pw, err := t.schema.GetWriter(w, merged.DynamicColumns(), false)
-			if err != nil {
-				return err
-			}
-			defer t.schema.PutWriter(pw)
-			writer = pw.ParquetWriter
-		}
-		p, err := t.active.rowWriter(writer)
-		if err != nil {
-			return err
-		}
-		defer p.close()
+	rows := merged.Rows()
+	defer rows.Close()
 
-		rows := merged.Rows()
-		defer rows.Close()
+	var rowReader parquet.RowReader = rows
+	if t.schema.Load().UniquePrimaryIndex {
+		// Given all inputs are sorted, we can deduplicate the rows using
+		// DedupeRowReader, which deduplicates consecutive rows that are
+		// equal on the sorting columns.
+		rowReader = parquet.DedupeRowReader(rows, merged.Schema().Comparator(merged.SortingColumns()...))
+	}
 
-		var rowReader parquet.RowReader = rows
-		if t.schema.UniquePrimaryIndex {
-			// Given all inputs are sorted, we can deduplicate the rows using
-			// DedupeRowReader, which deduplicates consecutive rows that are
-			// equal on the sorting columns.
-			rowReader = parquet.DedupeRowReader(rows, merged.Schema().Comparator(merged.SortingColumns()...))
-		}
+	if err := t.writeRows(w, merged.DynamicColumns(), rowReader, options...); err != nil {
+		return 0, err
+	}
 
-		if _, err := p.writeRows(rowReader); err != nil {
+	return preCompactionSize, nil
+}
+
+// writeRows writes the given sorted rows with the given dynamic columns to w
+// as a Parquet file.
+func (t *Table) writeRows(w io.Writer, dynamicColumns map[string][]string, rows parquet.RowReader, options ...parquet.WriterOption) error {
+	var writer dynparquet.ParquetWriter
+	if len(options) > 0 {
+		var err error
+		writer, err = t.schema.Load().NewWriter(w, dynamicColumns, false, options...)
+		if err != nil {
 			return err
 		}
-
-		return nil
-	}()
+	} else {
+		pw, err := t.schema.Load().GetWriter(w, dynamicColumns, false)
+		if err != nil {
+			return err
+		}
+		defer t.schema.Load().PutWriter(pw)
+		writer = pw.ParquetWriter
+	}
+	p, err := t.active.rowWriter(writer)
 	if err != nil {
-		return 0, err
+		return err
 	}
+	defer p.close()
 
-	return preCompactionSize, nil
+	_, err = p.writeRows(rows)
+	return err
 }
 
 // buffersForCompaction, given a slice of possibly overlapping parts, returns
@@ -1355,13 +1689,13 @@ func (t *Table) compactParts(w io.Writer, compact []parts.Part, options ...parqu
 // If nil, nil is returned, the resulting serialized buffer is written directly
 // to w as an optimization.
 func (t *Table) buffersForCompaction(w io.Writer, inputParts []parts.Part, options ...parquet.WriterOption) ([]dynparquet.DynamicRowGroup, error) {
-	nonOverlappingParts, overlappingParts, err := parts.FindMaximumNonOverlappingSet(t.schema, inputParts)
+	nonOverlappingParts, overlappingParts, err := parts.FindMaximumNonOverlappingSet(t.schema.Load(), inputParts)
 	if err != nil {
 		return nil, err
 	}
 	result := make([]dynparquet.DynamicRowGroup, 0, len(inputParts))
 	for _, p := range overlappingParts {
-		buf, err := p.AsSerializedBuffer(t.schema)
+		buf, err := p.AsSerializedBuffer(t.schema.Load())
 		if err != nil {
 			return nil, err
 		}
@@ -1383,7 +1717,7 @@ func (t *Table) buffersForCompaction(w io.Writer, inputParts []parts.Part, optio
 		// is at least one non-arrow part then optimizations cannot be made.
 		nonOverlappingRowGroups := make([]dynparquet.DynamicRowGroup, 0, len(nonOverlappingParts))
 		for _, p := range nonOverlappingParts {
-			buf, err := p.AsSerializedBuffer(t.schema)
+			buf, err := p.AsSerializedBuffer(t.schema.Load())
 			if err != nil {
 				return nil, err
 			}
@@ -1394,7 +1728,7 @@ func (t *Table) buffersForCompaction(w io.Writer, inputParts []parts.Part, optio
 			// WithAlreadySorted ensures that a parquet.MultiRowGroup is created
 			// here, which is much cheaper than actually merging all these row
 			// groups.
-			merged, err = t.schema.MergeDynamicRowGroups(nonOverlappingRowGroups, dynparquet.WithAlreadySorted())
+			merged, err = t.schema.Load().MergeDynamicRowGroups(nonOverlappingRowGroups, dynparquet.WithAlreadySorted())
 			if err != nil {
 				return nil, err
 			}
@@ -1442,20 +1776,20 @@ func (t *Table) writeRecordsToParquet(w io.Writer, records []arrow.Record, sortI
 	var writer dynparquet.ParquetWriter
 	if len(options) > 0 {
 		var err error
-		writer, err = t.schema.NewWriter(w, dynCols, false, options...)
+		writer, err = t.schema.Load().NewWriter(w, dynCols, false, options...)
 		if err != nil {
 			return err
 		}
 	} else {
-		pw, err := t.schema.GetWriter(w, dynCols, sortInput)
+		pw, err := t.schema.Load().GetWriter(w, dynCols, sortInput)
 		if err != nil {
 			return err
 		}
-		defer t.schema.PutWriter(pw)
+		defer t.schema.Load().PutWriter(pw)
 		writer = pw
 	}
 
-	return pqarrow.RecordsToFile(t.schema, writer, records)
+	return pqarrow.RecordsToFile(t.schema.Load(), writer, records)
 }
 
 // distinctRecordsForCompaction performs a distinct on the given parts. If at
@@ -1463,7 +1797,7 @@ func (t *Table) writeRecordsToParquet(w io.Writer, records []arrow.Record, sortI
 // caller should fall back to normal compaction. On success, the caller is
 // responsible for releasing the returned records.
 func (t *Table) distinctRecordsForCompaction(compact []parts.Part) ([]arrow.Record, error) {
-	sortingCols := t.schema.ColumnDefinitionsForSortingColumns()
+	sortingCols := t.schema.Load().ColumnDefinitionsForSortingColumns()
 	columnExprs := make([]logicalplan.Expr, 0, len(sortingCols))
 	for _, col := range sortingCols {
 		var expr logicalplan.Expr
@@ -1519,10 +1853,12 @@ func (t *Table) IndexConfig() []*index.LevelConfig {
 			compactFunc = nil
 		}
 		config = append(config, &index.LevelConfig{
-			Level:   c.Level,
-			MaxSize: c.MaxSize,
-			Type:    c.Type,
-			Compact: compactFunc, // TODO: this is bad and it should feel bad. We shouldn't need the table object to define how parts are compacted. Refactor needed.
+			Level:    c.Level,
+			MaxSize:  c.MaxSize,
+			Type:     c.Type,
+			Compact:  compactFunc, // TODO: this is bad and it should feel bad. We shouldn't need the table object to define how parts are compacted. Refactor needed.
+			NewLevel: c.NewLevel,
+			Strategy: c.Strategy,
 		})
 	}
 
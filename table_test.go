@@ -337,6 +337,91 @@ func Test_Table_ReadIsolation(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_Table_Delete(t *testing.T) {
+	c, table := basicTable(t)
+	defer c.Close()
+
+	ctx := context.Background()
+	insert := func() uint64 {
+		r, err := dynparquet.NewTestSamples().ToRecord()
+		require.NoError(t, err)
+		defer r.Release()
+		tx, err := table.InsertRecord(ctx, r)
+		require.NoError(t, err)
+		return tx
+	}
+	countRows := func(tx uint64) int64 {
+		pool := memory.NewCheckedAllocator(memory.DefaultAllocator)
+		defer pool.AssertSize(t, 0)
+		rows := int64(0)
+		require.NoError(t, table.Iterator(
+			ctx,
+			tx,
+			pool,
+			[]logicalplan.Callback{func(_ context.Context, ar arrow.Record) error {
+				rows += ar.NumRows()
+				return nil
+			}},
+		))
+		return rows
+	}
+
+	insertTx := insert()
+	deleteTx, err := table.Delete(ctx, logicalplan.Col("labels.namespace").Eq(logicalplan.Literal("default")))
+	require.NoError(t, err)
+	lastTx := insert()
+	table.db.Wait(lastTx)
+
+	// The delete is only visible to transactions after it, and does not apply
+	// to rows inserted after it.
+	require.Equal(t, int64(3), countRows(insertTx))
+	require.Equal(t, int64(1), countRows(deleteTx))
+	require.Equal(t, int64(4), countRows(lastTx))
+
+	// Compaction physically removes the deleted rows.
+	require.NoError(t, table.EnsureCompaction())
+	rows := int64(0)
+	table.ActiveBlock().Index().Iterate(func(node *index.Node) bool {
+		if p := node.Part(); p != nil {
+			rows += p.NumRows()
+		}
+		return true
+	})
+	require.Equal(t, int64(4), rows)
+	require.Equal(t, int64(4), countRows(lastTx))
+
+	// Compacting parts that had all their rows deleted leaves no parts.
+	c, table = basicTable(t)
+	defer c.Close()
+	insert()
+	deleteTx, err = table.Delete(ctx, logicalplan.Col("example_type").Eq(logicalplan.Literal("cpu")))
+	require.NoError(t, err)
+	table.db.Wait(deleteTx)
+	require.Equal(t, int64(0), countRows(deleteTx))
+	require.NoError(t, table.EnsureCompaction())
+	parts := 0
+	table.ActiveBlock().Index().Iterate(func(node *index.Node) bool {
+		if node.Part() != nil {
+			parts++
+		}
+		return true
+	})
+	require.Equal(t, 0, parts)
+	require.Equal(t, int64(0), countRows(deleteTx))
+
+	// The tombstone is dropped once the block holding the rows written before
+	// it left memory, while the txn of the delete is still known to have
+	// purged rows.
+	insert()
+	require.Len(t, table.tombstones, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	require.NoError(t, table.RotateBlock(ctx, table.ActiveBlock(), WithRotateBlockWaitGroup(&wg)))
+	wg.Wait()
+	require.Empty(t, table.tombstones)
+	require.Equal(t, deleteTx, table.purgedTx)
+}
+
 func Test_Table_NewTableValidIndexDegree(t *testing.T) {
 	config := NewTableConfig(dynparquet.SampleDefinition())
 	c, err := New(
@@ -677,10 +762,10 @@ func Test_RowWriter(t *testing.T) {
 	defer c.Close()
 
 	b := &bytes.Buffer{}
-	pw, err := table.schema.GetWriter(b, map[string][]string{
+	pw, err := table.schema.Load().GetWriter(b, map[string][]string{
 		"labels": {"node"},
 	}, false)
-	defer table.schema.PutWriter(pw)
+	defer table.schema.Load().PutWriter(pw)
 	require.NoError(t, err)
 	rowWriter, err := table.ActiveBlock().rowWriter(pw)
 	require.NoError(t, err)
@@ -0,0 +1,482 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid/v2"
+
+	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
+)
+
+// droppedTablesDir is the directory under the prefix of a database the blocks
+// of dropped tables are moved to, so that they aren't read by tables created
+// with the same name.
+const droppedTablesDir = ".dropped"
+
+// DropOption configures DropTable and TruncateTable.
+type DropOption func(*dropOptions)
+
+type dropOptions struct {
+	deletePersistedBlocks bool
+}
+
+// WithDeletePersistedBlocks deletes the blocks the table persisted before it
+// was dropped or truncated from the sinks of the database that implement
+// RetentionSink. The blocks that are still being persisted are waited for.
+func WithDeletePersistedBlocks() DropOption {
+	return func(o *dropOptions) {
+		o.deletePersistedBlocks = true
+	}
+}
+
+// DropTable removes the table with the given name from the database. Writes to
+// the table fail once it is dropped and its in-memory data and index files are
+// released. Blocks the table persisted are deleted if WithDeletePersistedBlocks
+// is given, and moved out of the prefix of the table otherwise, so that they
+// aren't read by a table created with the same name. Tables can only be
+// dropped without deleting their blocks if all the sinks of the database
+// implement MoveSink.
+func (db *DB) DropTable(ctx context.Context, name string, opts ...DropOption) error {
+	if db.leader != nil {
+		return ErrFollower
+	}
+	o := &dropOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if !o.deletePersistedBlocks {
+		if err := db.checkMoveSinks(); err != nil {
+			return fmt.Errorf("drop table %s without deleting its persisted blocks: %w", name, err)
+		}
+	}
+
+	table, err := db.GetTable(name)
+	if err != nil {
+		return err
+	}
+	// Writes are rejected once the drop is logged, so a failure to log it
+	// leaves the table untouched.
+	if err := table.logDrop(); err != nil {
+		return err
+	}
+	table.close()
+
+	db.mtx.Lock()
+	if db.tables[name] == table {
+		delete(db.tables, name)
+	}
+	db.mtx.Unlock()
+
+	active := table.ActiveBlock()
+	table.dropPendingBlock(active)
+	db.removeTableIndexDir(name)
+	level.Info(db.logger).Log("msg", "dropped table", "table", name)
+
+	if o.deletePersistedBlocks {
+		return table.deletePersistedBlocks(ctx, name, active.ulid)
+	}
+	if err := table.waitPersisted(ctx, active.ulid); err != nil {
+		return err
+	}
+	// The blocks are moved to a prefix unique to the drop, the table may be
+	// dropped again once it is created with the same name.
+	dropped := filepath.Join(droppedTablesDir, name+"-"+active.ulid.String())
+	return db.movePersistedBlocks(ctx, table, name, dropped, ulid.Time(active.ulid.Time()))
+}
+
+// TruncateTable removes all the rows of the table with the given name. The
+// active block of the table is replaced by an empty block and its index files
+// are released. The rows are hidden from transactions after the returned one,
+// rows of pending and persisted blocks are removed when they are persisted or
+// compacted unless the blocks are deleted with WithDeletePersistedBlocks.
+func (db *DB) TruncateTable(ctx context.Context, name string, opts ...DropOption) (uint64, error) {
+	if db.leader != nil {
+		return 0, ErrFollower
+	}
+	o := &dropOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	table, err := db.GetTable(name)
+	if err != nil {
+		return 0, err
+	}
+	tx, prev, err := table.truncate()
+	if err != nil {
+		return tx, err
+	}
+	table.dropPendingBlock(prev)
+	level.Info(db.logger).Log("msg", "truncated table", "table", name, "tx", tx)
+
+	if err := db.waitDurable(ctx, table.wal, tx); err != nil {
+		return tx, err
+	}
+	if o.deletePersistedBlocks {
+		return tx, table.deletePersistedBlocks(ctx, name, prev.ulid)
+	}
+	return tx, nil
+}
+
+// RenameTable renames the table with the given name. The blocks the table
+// persisted are moved to the new name, so tables can only be renamed if all
+// the sinks of the database implement MoveSink.
+func (db *DB) RenameTable(ctx context.Context, name, newName string) error {
+	if db.leader != nil {
+		return ErrFollower
+	}
+	if !validateTableName(newName) {
+		return errors.New("invalid table name")
+	}
+	if err := db.checkMoveSinks(); err != nil {
+		return fmt.Errorf("rename table %s: %w", name, err)
+	}
+
+	table, err := db.GetTable(name)
+	if err != nil {
+		return err
+	}
+	var active *TableBlock
+	for active == nil {
+		// Blocks are uploaded under the name of the table, wait for the
+		// pending blocks to be persisted to move them with the others.
+		if err := table.waitPersisted(ctx, table.ActiveBlock().ulid); err != nil {
+			return err
+		}
+		if active, err = db.logRename(table, name, newName); err != nil {
+			return err
+		}
+	}
+	level.Info(db.logger).Log("msg", "renamed table", "table", name, "new_table", newName)
+
+	return db.movePersistedBlocks(ctx, table, name, newName, ulid.Time(active.ulid.Time()))
+}
+
+// logDrop logs the drop of the table. Writes to the table are rejected
+// afterwards and blocks of the table that are persisted afterwards are not
+// logged anymore.
+func (t *Table) logDrop() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.dropped {
+		return ErrTableNotFound{TableName: t.Name()}
+	}
+	if t.closing {
+		return ErrTableClosing
+	}
+	// Writes are logged with the name of the table, so the writes in flight
+	// must be logged before the drop.
+	t.active.pendingWritersWg.Wait()
+
+	tx, _, commit := t.db.begin()
+	defer commit()
+
+	if err := t.wal.Log(tx, &walpb.Record{
+		Entry: &walpb.Entry{
+			EntryType: &walpb.Entry_DropTable_{
+				DropTable: &walpb.Entry_DropTable{
+					TableName: t.Name(),
+				},
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("append to log: %w", err)
+	}
+	t.dropped = true
+	return nil
+}
+
+// truncate logs the truncation of the table and replaces its active block with
+// an empty block. It returns the txn of the truncation and the replaced block,
+// which must be released with dropPendingBlock.
+func (t *Table) truncate() (uint64, *TableBlock, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.closing {
+		return 0, nil, ErrTableClosing
+	}
+
+	// The writes to the replaced block are truncated as well.
+	block := t.active
+	block.pendingWritersWg.Wait()
+
+	tx, _, commit := t.db.begin()
+	defer commit()
+
+	id := generateULID()
+	for id.Time() == block.ulid.Time() { // Ensure the new block has a different timestamp.
+		time.Sleep(time.Millisecond)
+		id = generateULID()
+	}
+	blockID, err := id.MarshalBinary()
+	if err != nil {
+		return tx, nil, err
+	}
+
+	if err := t.wal.Log(tx, &walpb.Record{
+		Entry: &walpb.Entry{
+			EntryType: &walpb.Entry_TruncateTable_{
+				TruncateTable: &walpb.Entry_TruncateTable{
+					TableName: t.Name(),
+					BlockId:   blockID,
+				},
+			},
+		},
+	}); err != nil {
+		return tx, nil, fmt.Errorf("append to log: %w", err)
+	}
+
+	if err := t.truncateLocked(tx, id); err != nil {
+		return tx, nil, err
+	}
+	t.addTombstone(&tombstone{tx: tx, block: id})
+	return tx, block, nil
+}
+
+// truncateLocked replaces the active block of the table with an empty block
+// with the given ID created at the given txn. The rows written before the txn
+// are hidden by the tombstone of the truncation, which callers must add.
+// t.mtx must be held.
+func (t *Table) truncateLocked(tx uint64, id ulid.ULID) error {
+	// The replaced block is never persisted, so the new block follows the
+	// block that preceded it.
+	block, err := newTableBlock(t, t.active.prevTx, tx, id)
+	if err != nil {
+		return err
+	}
+	t.active = block
+	t.metrics.numParts.Set(float64(0))
+	return nil
+}
+
+// logRename logs the rename of the table and renames it. It returns the active
+// block of the table at the time of the rename, or nil if the table has
+// pending blocks that need to be persisted first.
+func (db *DB) logRename(table *Table, name, newName string) (*TableBlock, error) {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	if _, ok := db.tables[newName]; ok {
+		return nil, fmt.Errorf("table %s already exists", newName)
+	}
+	if db.tables[name] != table {
+		return nil, ErrTableNotFound{TableName: name}
+	}
+
+	table.mtx.Lock()
+	defer table.mtx.Unlock()
+	if table.closing {
+		return nil, ErrTableClosing
+	}
+	if len(table.pendingBlocks) > 0 {
+		return nil, nil
+	}
+	// Writes are logged with the name of the table, so the writes in flight
+	// must be logged before the rename.
+	table.active.pendingWritersWg.Wait()
+
+	tx, _, commit := db.begin()
+	defer commit()
+
+	if err := table.wal.Log(tx, &walpb.Record{
+		Entry: &walpb.Entry{
+			EntryType: &walpb.Entry_RenameTable_{
+				RenameTable: &walpb.Entry_RenameTable{
+					TableName:    name,
+					NewTableName: newName,
+				},
+			},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("append to log: %w", err)
+	}
+	db.renameTableLocked(name, newName)
+	return table.active, nil
+}
+
+// renameTableLocked renames the table with the given name. A read-only table
+// with the new name is replaced. db.mtx must be held.
+func (db *DB) renameTableLocked(name, newName string) {
+	table := db.tables[name]
+	delete(db.tables, name)
+	table.name.Store(&newName)
+	db.tables[newName] = table
+	delete(db.roTables, newName)
+}
+
+// removeTable removes the table with the given name from the database, if it
+// exists, and releases all its blocks without persisting them. It applies
+// drops logged to the WAL.
+func (db *DB) removeTable(name string) {
+	db.mtx.Lock()
+	table, ok := db.tables[name]
+	delete(db.tables, name)
+	db.mtx.Unlock()
+	if !ok {
+		return
+	}
+
+	table.close()
+	table.mtx.Lock()
+	table.dropped = true
+	blocks := []*TableBlock{table.active}
+	for block := range table.pendingBlocks {
+		blocks = append(blocks, block)
+	}
+	table.mtx.Unlock()
+	for _, block := range blocks {
+		table.dropPendingBlock(block)
+	}
+	db.removeTableIndexDir(name)
+}
+
+// removeTableIndexDir removes the index directory of the table with the given
+// name if it is empty. The directories of the blocks are removed when their
+// indexes are closed, a table created with the same name in the meantime
+// keeps the directory.
+func (db *DB) removeTableIndexDir(name string) {
+	if db.storagePath == "" {
+		return
+	}
+	dir := filepath.Join(db.indexDir(), name)
+	if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+		level.Debug(db.logger).Log("msg", "failed to remove table index directory", "dir", dir, "err", err)
+	}
+}
+
+// waitPersisted waits until the pending blocks of the table created before the
+// given block are persisted.
+func (t *Table) waitPersisted(ctx context.Context, block ulid.ULID) error {
+	for {
+		t.mtx.RLock()
+		pending := false
+		for b := range t.pendingBlocks {
+			if b.ulid.Compare(block) < 0 {
+				pending = true
+				break
+			}
+		}
+		t.mtx.RUnlock()
+		if !pending {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// deletePersistedBlocks deletes the blocks the table with the given name
+// persisted before the given block was created from the sinks that implement
+// RetentionSink.
+func (t *Table) deletePersistedBlocks(ctx context.Context, name string, block ulid.ULID) error {
+	if err := t.waitPersisted(ctx, block); err != nil {
+		return err
+	}
+
+	prefix := filepath.Join(t.db.name, name)
+	before := ulid.Time(block.Time())
+	for _, sink := range t.db.sinks {
+		rs, ok := sink.(RetentionSink)
+		if !ok {
+			continue
+		}
+		blocks, bytes, err := rs.DeleteBlocksBefore(ctx, prefix, before)
+		if err != nil {
+			return fmt.Errorf("delete blocks from %s: %w", sink.String(), err)
+		}
+		level.Debug(t.logger).Log(
+			"msg", "deleted persisted blocks",
+			"table", name,
+			"sink", sink.String(),
+			"blocks", blocks,
+			"bytes", bytes,
+		)
+	}
+	return nil
+}
+
+// ErrMoveUnsupported is returned when renaming or dropping a table whose
+// persisted blocks can't be moved, as a sink of the database doesn't implement
+// MoveSink.
+var ErrMoveUnsupported = errors.New("sink can't move persisted blocks")
+
+// checkMoveSinks returns ErrMoveUnsupported if a sink of the database doesn't
+// implement MoveSink.
+func (db *DB) checkMoveSinks() error {
+	for _, sink := range db.sinks {
+		if _, ok := sink.(MoveSink); !ok {
+			return fmt.Errorf("%s: %w", sink.String(), ErrMoveUnsupported)
+		}
+	}
+	return nil
+}
+
+// movePersistedBlocks moves the blocks the table persisted under its previous
+// name before the given time to its new name in the sinks of the database,
+// which all implement MoveSink (see checkMoveSinks). The blocks keep their
+// ULIDs, so deletes of the table still apply to them.
+func (db *DB) movePersistedBlocks(ctx context.Context, table *Table, name, newName string, before time.Time) error {
+	from := filepath.Join(db.name, name)
+	to := filepath.Join(db.name, newName)
+	for _, sink := range db.sinks {
+		ms, ok := sink.(MoveSink)
+		if !ok {
+			return fmt.Errorf("%s: %w", sink.String(), ErrMoveUnsupported)
+		}
+
+		moved, err := ms.MoveBlocks(ctx, from, to, before, table.schema.Load())
+		if err != nil {
+			return fmt.Errorf("move blocks in %s: %w", sink.String(), err)
+		}
+		if moved == 0 {
+			continue
+		}
+		level.Debug(db.logger).Log(
+			"msg", "moved persisted blocks",
+			"table", newName,
+			"sink", sink.String(),
+			"blocks", moved,
+		)
+	}
+	return nil
+}
+
+// tableEvent is a drop or a rename of a table logged to the WAL.
+type tableEvent struct {
+	tx   uint64
+	name string
+	// newName is the name of the table after a rename, empty for drops.
+	newName string
+}
+
+// tableHistory is the drops and renames of tables in a WAL replay, in txn
+// order. WAL entries refer to a table by the name it had when they were
+// logged, the history resolves it to the name the table has at the end of the
+// replay.
+type tableHistory []tableEvent
+
+// resolve returns the name the table named name at the given txn has at the
+// end of the replay, or false if the table is dropped.
+func (h tableHistory) resolve(tx uint64, name string) (string, bool) {
+	i := sort.Search(len(h), func(i int) bool { return h[i].tx > tx })
+	for _, e := range h[i:] {
+		if e.name != name {
+			continue
+		}
+		if e.newName == "" {
+			return "", false
+		}
+		name = e.newName
+	}
+	return name, true
+}
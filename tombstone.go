@@ -0,0 +1,356 @@
+package frostdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/arrow/util"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+	storagepb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/storage/v1alpha1"
+	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
+	"github.com/youscentia/ydb-frostdb/index"
+	"github.com/youscentia/ydb-frostdb/parts"
+	"github.com/youscentia/ydb-frostdb/pqarrow"
+	"github.com/youscentia/ydb-frostdb/query/exprpb"
+	"github.com/youscentia/ydb-frostdb/query/logicalplan"
+	"github.com/youscentia/ydb-frostdb/query/physicalplan"
+)
+
+// tombstone records the deletion of the rows matching a filter that were
+// written before the transaction of the delete. Tombstones without a filter
+// delete all the rows, they are the result of a truncation of the table.
+type tombstone struct {
+	tx     uint64
+	filter logicalplan.Expr
+	expr   physicalplan.BooleanExpression
+	// block is the active block at the time of the delete. Persisted blocks
+	// created before this block only contain rows written before the delete,
+	// while later blocks had the delete applied when they were persisted.
+	block ulid.ULID
+}
+
+func newTombstone(tx uint64, filter logicalplan.Expr, block ulid.ULID) (*tombstone, error) {
+	ts := &tombstone{
+		tx:     tx,
+		filter: filter,
+		block:  block,
+	}
+	if filter == nil {
+		return ts, nil
+	}
+	expr, err := physicalplan.NewBooleanExpr(filter)
+	if err != nil {
+		return nil, fmt.Errorf("create bool expr: %w", err)
+	}
+	ts.expr = expr
+	return ts, nil
+}
+
+// tombstoneFromProto returns the tombstone of a delete recorded in the WAL or
+// in a snapshot.
+func tombstoneFromProto(tx uint64, filter *storagepb.Expr, blockID []byte) (*tombstone, error) {
+	expr, err := exprpb.ExprFromProto(filter)
+	if err != nil {
+		return nil, fmt.Errorf("delete filter from proto: %w", err)
+	}
+	var block ulid.ULID
+	if err := block.UnmarshalBinary(blockID); err != nil {
+		return nil, err
+	}
+	return newTombstone(tx, expr, block)
+}
+
+// Delete deletes all the rows of the table that match the given filter. The
+// rows are hidden from transactions after the returned one, and are
+// physically removed once the parts containing them are compacted or the
+// block containing them is persisted. A synchronous delete that fails with
+// ErrDurabilityUnknown was applied nonetheless.
+func (t *Table) Delete(ctx context.Context, filter logicalplan.Expr) (uint64, error) {
+	_, span := t.tracer.Start(ctx, "Table/Delete")
+	defer span.End()
+
+	if t.db.leader != nil {
+		return 0, ErrFollower
+	}
+	if filter == nil {
+		return 0, errors.New("delete filter must be set")
+	}
+	filterProto, err := exprpb.ExprToProto(filter)
+	if err != nil {
+		return 0, fmt.Errorf("convert filter to proto: %w", err)
+	}
+
+	block, finish, err := t.ActiveWriteBlock()
+	if err != nil {
+		return 0, err
+	}
+	defer finish()
+
+	blockID, err := block.ulid.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, _, commit := t.db.begin()
+	defer commit()
+
+	ts, err := newTombstone(tx, filter, block.ulid)
+	if err != nil {
+		return tx, err
+	}
+
+	if err := t.wal.Log(tx, &walpb.Record{
+		Entry: &walpb.Entry{
+			EntryType: &walpb.Entry_Delete_{
+				Delete: &walpb.Entry_Delete{
+					TableName: t.Name(),
+					Filter:    filterProto,
+					BlockId:   blockID,
+				},
+			},
+		},
+	}); err != nil {
+		return tx, fmt.Errorf("append to log: %w", err)
+	}
+
+	t.addTombstone(ts)
+	if err := t.db.waitDurable(ctx, t.wal, tx); err != nil {
+		return tx, err
+	}
+	return tx, nil
+}
+
+func (t *Table) addTombstone(ts *tombstone) {
+	t.tombstonesMtx.Lock()
+	defer t.tombstonesMtx.Unlock()
+	t.tombstones = append(t.tombstones, ts)
+}
+
+// visibleTombstones returns the tombstones of the deletes visible to the given
+// transaction.
+func (t *Table) visibleTombstones(tx uint64) []*tombstone {
+	t.tombstonesMtx.RLock()
+	defer t.tombstonesMtx.RUnlock()
+
+	var visible []*tombstone
+	for _, ts := range t.tombstones {
+		if ts.tx <= tx {
+			visible = append(visible, ts)
+		}
+	}
+	return visible
+}
+
+// persistedTombstones returns the tombstones that no longer apply to any part
+// of the blocks in memory: all the parts written before them are in blocks
+// that were persisted or dropped. The parts of a block are all written after
+// the block was created.
+func (t *Table) persistedTombstones() []*tombstone {
+	t.mtx.RLock()
+	memoryTx := uint64(math.MaxUint64)
+	if t.active != nil {
+		memoryTx = t.active.minTx
+	}
+	for block := range t.pendingBlocks {
+		memoryTx = min(memoryTx, block.minTx)
+	}
+	t.mtx.RUnlock()
+
+	t.tombstonesMtx.RLock()
+	defer t.tombstonesMtx.RUnlock()
+	var persisted []*tombstone
+	for _, ts := range t.tombstones {
+		if ts.tx <= memoryTx {
+			persisted = append(persisted, ts)
+		}
+	}
+	return persisted
+}
+
+// dropTombstones drops the given tombstones from the table. They must not
+// apply to any rows anymore: neither to parts in memory (see
+// persistedTombstones) nor to persisted blocks, either because the table has
+// none or because block compaction removed the deleted rows from all of them.
+// The purged txn of the table is kept, as the reads at earlier txns still miss
+// the rows deleted by them.
+func (t *Table) dropTombstones(drop []*tombstone) {
+	if len(drop) == 0 {
+		return
+	}
+	t.tombstonesMtx.Lock()
+	defer t.tombstonesMtx.Unlock()
+	// The slice may still be read by compactions, so it is copied rather
+	// than modified in place.
+	kept := make([]*tombstone, 0, len(t.tombstones))
+	for _, ts := range t.tombstones {
+		if !slices.Contains(drop, ts) {
+			kept = append(kept, ts)
+		}
+	}
+	t.tombstones = kept
+}
+
+// partTombstones returns the tombstones that apply to a part written at the
+// given transaction.
+func partTombstones(tombstones []*tombstone, partTx uint64) []*tombstone {
+	var applicable []*tombstone
+	for _, ts := range tombstones {
+		if partTx < ts.tx {
+			applicable = append(applicable, ts)
+		}
+	}
+	return applicable
+}
+
+// blockTombstones returns the tombstones that apply to the given persisted
+// block.
+func blockTombstones(tombstones []*tombstone, block ulid.ULID) []*tombstone {
+	var applicable []*tombstone
+	for _, ts := range tombstones {
+		if block.Compare(ts.block) < 0 {
+			applicable = append(applicable, ts)
+		}
+	}
+	return applicable
+}
+
+// applyTombstones removes the rows deleted by the given tombstones from v,
+// which is either an arrow.Record or a dynparquet.DynamicRowGroup, and returns
+// the remaining rows. v is released. The caller is responsible for releasing
+// the returned record, which may have no rows. nil is returned if all the rows
+// were deleted by a truncation.
+func (t *Table) applyTombstones(ctx context.Context, pool memory.Allocator, v any, tombstones []*tombstone) (arrow.Record, error) {
+	for _, ts := range tombstones {
+		if ts.expr != nil {
+			continue
+		}
+		switch v := v.(type) {
+		case arrow.Record:
+			v.Release()
+		case index.ReleaseableRowGroup:
+			v.Release()
+		}
+		return nil, nil
+	}
+
+	var record arrow.Record
+	switch v := v.(type) {
+	case arrow.Record:
+		record = v
+	case dynparquet.DynamicRowGroup:
+		if rg, ok := v.(index.ReleaseableRowGroup); ok {
+			defer rg.Release()
+		}
+		converter := pqarrow.NewParquetConverter(pool, logicalplan.IterOptions{})
+		defer converter.Close()
+		if err := converter.Convert(ctx, v, t.schema.Load()); err != nil {
+			return nil, fmt.Errorf("failed to convert row group to arrow record: %v", err)
+		}
+		record = converter.NewRecord()
+		if record == nil {
+			// This RowGroup had no data.
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown row group type: %T", v)
+	}
+
+	for _, ts := range tombstones {
+		r, err := physicalplan.Exclude(pool, ts.expr, record)
+		record.Release()
+		if err != nil {
+			return nil, fmt.Errorf("apply delete at tx %d: %w", ts.tx, err)
+		}
+		record = r
+	}
+	return record, nil
+}
+
+// purgeDeletedRows returns the given parts with the rows deleted by the
+// table's tombstones removed. Parts that have all their rows deleted are
+// omitted. The returned function must be called to release the parts created
+// by purgeDeletedRows once they are no longer used. The purged txn of the
+// table is advanced to the newest tombstone that removed rows before the
+// parts are returned, so that reads at earlier txns, which still see these
+// rows, fail rather than miss them once the parts are compacted.
+func (t *Table) purgeDeletedRows(compact []parts.Part) ([]parts.Part, func(), error) {
+	t.tombstonesMtx.RLock()
+	tombstones := t.tombstones
+	t.tombstonesMtx.RUnlock()
+
+	var purged []parts.Part
+	release := func() {
+		for _, p := range purged {
+			p.Release()
+		}
+	}
+	if len(tombstones) == 0 {
+		return compact, release, nil
+	}
+
+	result := make([]parts.Part, 0, len(compact))
+	purgedTx := uint64(0)
+	for _, p := range compact {
+		ts := partTombstones(tombstones, p.TX())
+		if len(ts) == 0 {
+			result = append(result, p)
+			continue
+		}
+
+		var v any
+		if r := p.Record(); r != nil {
+			r.Retain()
+			v = r
+		} else {
+			buf, err := p.AsSerializedBuffer(t.schema.Load())
+			if err != nil {
+				release()
+				return nil, nil, err
+			}
+			v = buf.MultiDynamicRowGroup()
+		}
+
+		record, err := t.applyTombstones(context.Background(), memory.NewGoAllocator(), v, ts)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		if record != nil && record.NumRows() == p.NumRows() {
+			// None of the rows were deleted.
+			record.Release()
+			result = append(result, p)
+			continue
+		}
+		// The tombstones are in transaction order.
+		purgedTx = max(purgedTx, ts[len(ts)-1].tx)
+		if record == nil {
+			continue
+		}
+		if record.NumRows() == 0 {
+			record.Release()
+			continue
+		}
+
+		part := parts.NewArrowPart(
+			p.TX(),
+			record,
+			uint64(util.TotalRecordSize(record)),
+			t.schema.Load(),
+			parts.WithCompactionLevel(p.CompactionLevel()),
+		)
+		purged = append(purged, part)
+		result = append(result, part)
+	}
+
+	t.tombstonesMtx.Lock()
+	t.purgedTx = max(t.purgedTx, purgedTx)
+	t.tombstonesMtx.Unlock()
+	return result, release, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -25,6 +26,19 @@ import (
 
 type ReplayHandlerFunc func(tx uint64, record *walpb.Record) error
 
+var (
+	// ErrClosed is returned when waiting for a record that will not be
+	// written since the WAL was closed.
+	ErrClosed = errors.New("wal closed")
+	// ErrRecordDropped is returned when waiting for a record that was not
+	// written, e.g. due to a failed write or a concurrent truncation of the
+	// WAL.
+	ErrRecordDropped = errors.New("wal record dropped")
+	// ErrTruncated is returned when tailing the WAL from a record that was
+	// truncated.
+	ErrTruncated = errors.New("wal truncated")
+)
+
 type NopWAL struct{}
 
 func (w *NopWAL) Close() error {
@@ -43,6 +57,14 @@ func (w *NopWAL) LogRecord(_ uint64, _ string, _ arrow.Record) error {
 	return nil
 }
 
+func (w *NopWAL) LogRecords(_ uint64, _ []string, _ []arrow.Record) error {
+	return nil
+}
+
+func (w *NopWAL) WaitDurable(_ uint64) error {
+	return nil
+}
+
 func (w *NopWAL) Truncate(_ uint64) error {
 	return nil
 }
@@ -66,6 +88,8 @@ type Metrics struct {
 	WalRepairsLostRecords prometheus.Counter
 	WalCloseTimeouts      prometheus.Counter
 	WalQueueSize          prometheus.Gauge
+	SyncDuration          prometheus.Observer
+	GroupCommitSize       prometheus.Observer
 }
 
 func newMetrics(reg prometheus.Registerer) *Metrics {
@@ -94,6 +118,16 @@ func newMetrics(reg prometheus.Registerer) *Metrics {
 			Name: "queue_size",
 			Help: "The number of unprocessed requests in the WAL queue",
 		}),
+		SyncDuration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:                        "sync_duration_seconds",
+			Help:                        "Time synchronous writes waited for their record to be written and fsynced",
+			NativeHistogramBucketFactor: 1.1,
+		}),
+		GroupCommitSize: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "group_commit_records",
+			Help:    "The number of records written with a single fsync that synchronous writes waited for",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		}),
 	}
 }
 
@@ -125,6 +159,21 @@ type FileWAL struct {
 		// nextTx is the next expected txn. The FileWAL will only log a record
 		// with this txn.
 		nextTx uint64
+		// durableTx is the highest txn known to be written and fsynced.
+		durableTx uint64
+		// writingFrom is the first txn of the batch currently being
+		// written, or 0 if none is. Records with a txn below nextTx that
+		// are neither durable nor being written were not written.
+		writingFrom uint64
+		// waiters are the channels of the callers waiting for the record with
+		// the given txn to be written.
+		waiters map[uint64][]chan error
+		// written is closed and replaced whenever durableTx changes to
+		// notify the callers tailing the WAL.
+		written chan struct{}
+		// closed is set once the run loop exits. No records are written
+		// afterwards.
+		closed bool
 	}
 
 	// scratch memory reused to reduce allocations.
@@ -138,11 +187,19 @@ type FileWAL struct {
 	segmentSize int
 	// lastBatchWrite is used to determine when to force a close of the WAL.
 	lastBatchWrite time.Time
+	// syncCh is signaled when a caller waits for a record to be written so
+	// that the run loop writes the pending records without waiting for the
+	// next tick.
+	syncCh chan struct{}
 
 	cancel       func()
 	shutdownCh   chan struct{}
 	closeTimeout time.Duration
 
+	// compression is the compression of the body of the arrow records
+	// logged with LogRecord.
+	compression walpb.Entry_Write_Compression
+
 	newLogStoreWrapper func(wal.LogStore) wal.LogStore
 	ticker             Ticker
 	testingDroppedLogs func([]types.LogEntry)
@@ -199,6 +256,15 @@ func WithStoreMetrics(m *wal.Metrics) Option {
 	}
 }
 
+// WithCompression sets the compression of the body of the arrow records logged
+// with LogRecord. Records are decoded on replay regardless of the compression
+// they were written with, so it can be changed for an existing WAL.
+func WithCompression(c walpb.Entry_Write_Compression) Option {
+	return func(w *FileWAL) {
+		w.compression = c
+	}
+}
+
 type Ticker interface {
 	C() <-chan time.Time
 	Stop()
@@ -244,6 +310,7 @@ func Open(
 		path:         path,
 		fs:           fs,
 		logRequestCh: make(chan *logRequest),
+		syncCh:       make(chan struct{}, 1),
 		logRequestPool: &sync.Pool{
 			New: func() any {
 				return &logRequest{
@@ -275,6 +342,9 @@ func Open(
 		return nil, err
 	}
 	w.protected.nextTx = lastIndex + 1
+	w.protected.durableTx = lastIndex
+	w.protected.waiters = make(map[uint64][]chan error)
+	w.protected.written = make(chan struct{})
 
 	if w.newLogStoreWrapper != nil {
 		w.log = w.newLogStoreWrapper(logStore)
@@ -297,6 +367,7 @@ func (w *FileWAL) run(ctx context.Context) {
 		w.ticker = realTicker{Ticker: time.NewTicker(defaultTickTime)}
 	}
 	defer w.ticker.Stop()
+	defer w.closeWaiters()
 	// lastQueueSize is only used on shutdown to reduce debug logging verbosity.
 	lastQueueSize := 0
 	w.lastBatchWrite = time.Now()
@@ -357,11 +428,20 @@ func (w *FileWAL) run(ctx context.Context) {
 			return
 		case <-w.ticker.C():
 			w.process()
+		case <-w.syncCh:
+			// A caller is waiting for its record to be written. All the
+			// records queued in the meantime are written with it (group
+			// commit), keep going if the batch was cut short by its size.
+			for w.process() {
+			}
 		}
 	}
 }
 
-func (w *FileWAL) process() {
+// process writes a batch of the queued records and performs any pending
+// truncation. It returns true if the batch was full, i.e. more records might
+// be ready to be written.
+func (w *FileWAL) process() bool {
 	w.scratch.reqBatch = w.scratch.reqBatch[:0]
 
 	w.protected.Lock()
@@ -377,6 +457,7 @@ func (w *FileWAL) process() {
 					"expected", w.protected.nextTx,
 					"found", minTx,
 				)
+				w.notifyLocked(minTx, ErrRecordDropped)
 				w.logRequestPool.Put(heap.Pop(&w.protected.queue))
 				w.metrics.WalQueueSize.Sub(1)
 				// Keep on going since there might be other transactions
@@ -396,10 +477,14 @@ func (w *FileWAL) process() {
 		}
 		r := heap.Pop(&w.protected.queue).(*logRequest)
 		w.metrics.WalQueueSize.Sub(1)
+		if len(w.scratch.reqBatch) == 0 {
+			w.protected.writingFrom = r.tx
+		}
 		w.scratch.reqBatch = append(w.scratch.reqBatch, r)
 		batchSize += len(r.data)
 		w.protected.nextTx++
 	}
+	full := batchSize >= w.segmentSize
 	// truncateTx will be non-zero if we either are about to log a
 	// record with a txn past the txn to truncate, or we have logged one
 	// in the past.
@@ -411,7 +496,7 @@ func (w *FileWAL) process() {
 	w.protected.Unlock()
 	if len(w.scratch.reqBatch) == 0 && truncateTx == 0 {
 		// No records to log or truncations.
-		return
+		return false
 	}
 
 	w.scratch.walBatch = w.scratch.walBatch[:0]
@@ -425,7 +510,8 @@ func (w *FileWAL) process() {
 	}
 
 	if len(w.scratch.walBatch) > 0 {
-		if err := w.log.StoreLogs(w.scratch.walBatch); err != nil {
+		err := w.log.StoreLogs(w.scratch.walBatch)
+		if err != nil {
 			w.metrics.FailedLogs.Add(float64(len(w.scratch.reqBatch)))
 			lastIndex, lastIndexErr := w.log.LastIndex()
 			level.Error(w.logger).Log(
@@ -434,7 +520,23 @@ func (w *FileWAL) process() {
 				"lastIndex", lastIndex,
 				"lastIndexErr", lastIndexErr,
 			)
+			err = fmt.Errorf("write WAL batch: %w", err)
+		}
+		// StoreLogs fsyncs the batch before returning, so the waiters of
+		// the records in the batch can be released.
+		w.protected.Lock()
+		w.protected.writingFrom = 0
+		if err == nil {
+			w.protected.durableTx = w.scratch.reqBatch[len(w.scratch.reqBatch)-1].tx
+			w.notifyWrittenLocked()
+		}
+		if len(w.protected.waiters) > 0 {
+			w.metrics.GroupCommitSize.Observe(float64(len(w.scratch.reqBatch)))
+			for _, r := range w.scratch.reqBatch {
+				w.notifyLocked(r.tx, err)
+			}
 		}
+		w.protected.Unlock()
 	}
 
 	if truncateTx != 0 {
@@ -452,12 +554,18 @@ func (w *FileWAL) process() {
 				// record with any index to be written, however we only
 				// want to allow the next index to be logged.
 				w.protected.nextTx = truncateTx
+				// Records below the nextTx will never be written, they are
+				// covered by whatever caused the truncation.
+				w.protected.durableTx = max(w.protected.durableTx, truncateTx-1)
+				w.notifyWrittenLocked()
 				// Remove any records that have not yet been written and
 				// are now below the nextTx.
 				for w.protected.queue.Len() > 0 {
-					if minTx := w.protected.queue[0].tx; minTx >= w.protected.nextTx {
+					minTx := w.protected.queue[0].tx
+					if minTx >= w.protected.nextTx {
 						break
 					}
+					w.notifyLocked(minTx, ErrRecordDropped)
 					w.logRequestPool.Put(heap.Pop(&w.protected.queue))
 					w.metrics.WalQueueSize.Sub(1)
 				}
@@ -480,6 +588,68 @@ func (w *FileWAL) process() {
 	}
 
 	w.lastBatchWrite = time.Now()
+	return full
+}
+
+// notifyLocked releases the callers waiting for the record with the given
+// txn. w.protected must be locked.
+func (w *FileWAL) notifyLocked(tx uint64, err error) {
+	for _, ch := range w.protected.waiters[tx] {
+		ch <- err
+	}
+	delete(w.protected.waiters, tx)
+}
+
+// notifyWrittenLocked notifies the callers tailing the WAL that durableTx
+// changed. w.protected must be locked.
+func (w *FileWAL) notifyWrittenLocked() {
+	close(w.protected.written)
+	w.protected.written = make(chan struct{})
+}
+
+// closeWaiters releases all the callers still waiting for records once the
+// run loop exits.
+func (w *FileWAL) closeWaiters() {
+	w.protected.Lock()
+	defer w.protected.Unlock()
+	w.protected.closed = true
+	w.notifyWrittenLocked()
+	for tx := range w.protected.waiters {
+		w.notifyLocked(tx, ErrClosed)
+	}
+}
+
+// WaitDurable blocks until the record with the given txn has been written and
+// fsynced. Waiting triggers a write of all the queued records instead of
+// waiting for the next tick, so that concurrent callers share a single fsync
+// (group commit). It must only be called once the WAL is running.
+func (w *FileWAL) WaitDurable(tx uint64) error {
+	start := time.Now()
+	w.protected.Lock()
+	if tx <= w.protected.durableTx {
+		w.protected.Unlock()
+		return nil
+	}
+	if tx < w.protected.nextTx && (w.protected.writingFrom == 0 || tx < w.protected.writingFrom) {
+		w.protected.Unlock()
+		return ErrRecordDropped
+	}
+	if w.protected.closed {
+		w.protected.Unlock()
+		return ErrClosed
+	}
+	ch := make(chan error, 1)
+	w.protected.waiters[tx] = append(w.protected.waiters[tx], ch)
+	w.protected.Unlock()
+
+	select {
+	case w.syncCh <- struct{}{}:
+	default:
+		// A write is already pending and will pick up this record.
+	}
+	err := <-ch
+	w.metrics.SyncDuration.Observe(time.Since(start).Seconds())
+	return err
 }
 
 // Truncate queues a truncation of the WAL at the given tx. Note that the
@@ -499,10 +669,15 @@ func (w *FileWAL) Reset(nextTx uint64) error {
 	defer w.protected.Unlock()
 	// Drain any pending records.
 	for w.protected.queue.Len() > 0 {
-		_ = heap.Pop(&w.protected.queue)
+		r := heap.Pop(&w.protected.queue).(*logRequest)
+		w.notifyLocked(r.tx, ErrRecordDropped)
 	}
 	// Set the next expected transaction.
 	w.protected.nextTx = nextTx
+	if nextTx > 0 {
+		w.protected.durableTx = nextTx - 1
+	}
+	w.notifyWrittenLocked()
 	// This truncation will fully reset the underlying WAL. Any index can be
 	// logged, but setting the nextTx above will ensure that only a record with
 	// a matching txn will be accepted as the first record.
@@ -520,6 +695,9 @@ func (w *FileWAL) Close() error {
 }
 
 func (w *FileWAL) Log(tx uint64, record *walpb.Record) error {
+	if record.Timestamp == 0 {
+		record.Timestamp = time.Now().UnixMilli()
+	}
 	r := w.logRequestPool.Get().(*logRequest)
 	r.tx = tx
 	size := record.SizeVT()
@@ -550,16 +728,77 @@ func (w *FileWAL) putArrowBuf(b *bytes.Buffer) {
 }
 
 func (w *FileWAL) writeRecord(buf *bytes.Buffer, record arrow.Record) error {
-	writer := ipc.NewWriter(
-		buf,
-		ipc.WithSchema(record.Schema()),
-	)
+	opts := []ipc.Option{ipc.WithSchema(record.Schema())}
+	switch w.compression {
+	case walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED:
+	case walpb.Entry_Write_COMPRESSION_ZSTD:
+		opts = append(opts, ipc.WithZstd())
+	default:
+		return fmt.Errorf("unsupported compression: %s", w.compression)
+	}
+	writer := ipc.NewWriter(buf, opts...)
 	defer writer.Close()
 
 	return writer.Write(record)
 }
 
 func (w *FileWAL) LogRecord(tx uint64, table string, record arrow.Record) error {
+	if !w.shouldLog(tx) {
+		return nil
+	}
+	buf := w.getArrowBuf()
+	defer w.putArrowBuf(buf)
+	if err := w.writeRecord(buf, record); err != nil {
+		return err
+	}
+
+	return w.logEntry(tx, &walpb.Entry{
+		EntryType: &walpb.Entry_Write_{
+			Write: &walpb.Entry_Write{
+				Data:        buf.Bytes(),
+				TableName:   table,
+				Arrow:       true,
+				Compression: w.compression,
+			},
+		},
+	})
+}
+
+// LogRecords logs the records written to the given tables in a single
+// transaction entry, so they are replayed either all together or not at all.
+func (w *FileWAL) LogRecords(tx uint64, tables []string, records []arrow.Record) error {
+	if len(tables) != len(records) {
+		return fmt.Errorf("number of tables %d does not match number of records %d", len(tables), len(records))
+	}
+	if !w.shouldLog(tx) {
+		return nil
+	}
+	writes := make([]*walpb.Entry_Write, 0, len(records))
+	for i, record := range records {
+		buf := w.getArrowBuf()
+		defer w.putArrowBuf(buf)
+		if err := w.writeRecord(buf, record); err != nil {
+			return err
+		}
+		writes = append(writes, &walpb.Entry_Write{
+			Data:        buf.Bytes(),
+			TableName:   tables[i],
+			Arrow:       true,
+			Compression: w.compression,
+		})
+	}
+
+	return w.logEntry(tx, &walpb.Entry{
+		EntryType: &walpb.Entry_Transaction_{
+			Transaction: &walpb.Entry_Transaction{
+				Writes: writes,
+			},
+		},
+	})
+}
+
+// shouldLog returns whether a record with the given tx should be logged.
+func (w *FileWAL) shouldLog(tx uint64) bool {
 	w.protected.Lock()
 	nextTx := w.protected.nextTx
 	w.protected.Unlock()
@@ -571,24 +810,16 @@ func (w *FileWAL) LogRecord(tx uint64, table string, record arrow.Record) error
 			"tx", tx,
 			"next_tx", nextTx,
 		)
-		return nil
-	}
-	buf := w.getArrowBuf()
-	defer w.putArrowBuf(buf)
-	if err := w.writeRecord(buf, record); err != nil {
-		return err
+		return false
 	}
+	return true
+}
 
+// logEntry queues a record with the given entry to be written.
+func (w *FileWAL) logEntry(tx uint64, entry *walpb.Entry) error {
 	walrecord := &walpb.Record{
-		Entry: &walpb.Entry{
-			EntryType: &walpb.Entry_Write_{
-				Write: &walpb.Entry_Write{
-					Data:      buf.Bytes(),
-					TableName: table,
-					Arrow:     true,
-				},
-			},
-		},
+		Entry:     entry,
+		Timestamp: time.Now().UnixMilli(),
 	}
 
 	r := w.logRequestPool.Get().(*logRequest)
@@ -688,6 +919,69 @@ func (w *FileWAL) Replay(tx uint64, handler ReplayHandlerFunc) (err error) {
 	return nil
 }
 
+// Tail calls the handler with the records starting at the given tx as they are
+// written and fsynced, until the context is canceled, the WAL is closed or the
+// handler returns an error. ErrTruncated is returned if the record at tx is no
+// longer in the WAL, in which case the caller needs to catch up by other means
+// (e.g. a snapshot).
+func (w *FileWAL) Tail(ctx context.Context, tx uint64, handler ReplayHandlerFunc) error {
+	if tx == 0 {
+		tx = 1
+	}
+	// readErr returns ErrClosed instead of the given error if the log store
+	// failed to read because the WAL was closed in the meantime.
+	readErr := func(err error) error {
+		w.protected.Lock()
+		defer w.protected.Unlock()
+		if w.protected.closed {
+			return ErrClosed
+		}
+		return err
+	}
+	var entry types.LogEntry
+	for {
+		w.protected.Lock()
+		durableTx := w.protected.durableTx
+		written := w.protected.written
+		closed := w.protected.closed
+		w.protected.Unlock()
+		if closed {
+			return ErrClosed
+		}
+
+		for ; tx <= durableTx; tx++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			first, err := w.log.FirstIndex()
+			if err != nil {
+				return readErr(fmt.Errorf("read first index: %w", err))
+			}
+			// The first index is 0 if the WAL was reset.
+			if first == 0 || tx < first {
+				return ErrTruncated
+			}
+			if err := w.log.GetLog(tx, &entry); err != nil {
+				return readErr(fmt.Errorf("read index %d: %w", tx, err))
+			}
+
+			record := &walpb.Record{}
+			if err := record.UnmarshalVT(entry.Data); err != nil {
+				return fmt.Errorf("unmarshal WAL record: %w", err)
+			}
+			if err := handler(tx, record); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-written:
+		}
+	}
+}
+
 func (w *FileWAL) RunAsync() {
 	ctx, cancel := context.WithCancel(context.Background())
 	w.cancel = cancel
@@ -1,13 +1,21 @@
 package wal
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	walpb "github.com/youscentia/ydb-frostdb/gen/proto/go/frostdb/wal/v1alpha1"
@@ -24,6 +32,7 @@ func TestWAL(t *testing.T) {
 	require.NoError(t, err)
 	w.RunAsync()
 
+	start := time.Now()
 	require.NoError(t, w.Log(1, &walpb.Record{
 		Entry: &walpb.Entry{
 			EntryType: &walpb.Entry_Write_{
@@ -49,6 +58,8 @@ func TestWAL(t *testing.T) {
 		require.Equal(t, uint64(1), tx)
 		require.Equal(t, []byte("test-data"), r.Entry.GetWrite().Data)
 		require.Equal(t, "test-table", r.Entry.GetWrite().TableName)
+		// Records are timestamped when they are logged.
+		require.GreaterOrEqual(t, r.Timestamp, start.UnixMilli())
 		return nil
 	})
 	require.NoError(t, err)
@@ -301,3 +312,238 @@ func TestWALCloseTimeout(t *testing.T) {
 	err = w.Close()
 	require.NoError(t, err)
 }
+
+// nopTicker never ticks, records are only written when waited for.
+type nopTicker struct{}
+
+func (nopTicker) C() <-chan time.Time { return nil }
+
+func (nopTicker) Stop() {}
+
+func TestWALWaitDurable(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	w, err := Open(
+		log.NewNopLogger(),
+		t.TempDir(),
+		adapters.NewOSAdapter(),
+		WithTestingLoopTicker(nopTicker{}),
+		WithMetrics(newMetrics(reg)),
+	)
+	require.NoError(t, err)
+	w.RunAsync()
+
+	const n = 20
+	var wg sync.WaitGroup
+	for tx := uint64(1); tx <= n; tx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, w.Log(tx, &walpb.Record{}))
+			require.NoError(t, w.WaitDurable(tx))
+
+			// The record is written as soon as the wait returns.
+			lastIndex, err := w.LastIndex()
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, lastIndex, tx)
+		}()
+	}
+	wg.Wait()
+
+	// Waiting for a record that is already written returns immediately.
+	require.NoError(t, w.WaitDurable(1))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	observations := map[string]uint64{}
+	for _, f := range families {
+		observations[f.GetName()] = f.GetMetric()[0].GetHistogram().GetSampleCount()
+	}
+	require.Greater(t, observations["sync_duration_seconds"], uint64(0))
+	require.Greater(t, observations["group_commit_records"], uint64(0))
+
+	// A record that cannot be written since the previous txn is missing is
+	// released on close.
+	require.NoError(t, w.Log(n+2, &walpb.Record{}))
+	errCh := make(chan error)
+	go func() {
+		errCh <- w.WaitDurable(n + 2)
+	}()
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, <-errCh, ErrClosed)
+	require.ErrorIs(t, w.WaitDurable(n+3), ErrClosed)
+}
+
+// labelsRecord returns a record of n rows with repetitive labels, similar to
+// profiling data.
+func labelsRecord(n int) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "labels.namespace", Type: arrow.BinaryTypes.String},
+		{Name: "labels.pod", Type: arrow.BinaryTypes.String},
+		{Name: "timestamp", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	for i := 0; i < n; i++ {
+		b.Field(0).(*array.StringBuilder).Append("kube-system")
+		b.Field(1).(*array.StringBuilder).Append(fmt.Sprintf("kube-proxy-%d", i%8))
+		b.Field(2).(*array.Int64Builder).Append(int64(i / 10))
+		b.Field(3).(*array.Int64Builder).Append(int64(i % 3))
+	}
+	return b.NewRecord()
+}
+
+func readRecord(t testing.TB, data []byte) arrow.Record {
+	t.Helper()
+	r, err := ipc.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer r.Release()
+	require.True(t, r.Next())
+	rec := r.Record()
+	rec.Retain()
+	return rec
+}
+
+func TestWALCompression(t *testing.T) {
+	dir := t.TempDir()
+	record := labelsRecord(1000)
+	defer record.Release()
+
+	// Each record is written with a different compression to the same WAL.
+	compressions := []walpb.Entry_Write_Compression{
+		walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED,
+		walpb.Entry_Write_COMPRESSION_ZSTD,
+	}
+	for i, c := range compressions {
+		w, err := Open(log.NewNopLogger(), dir, adapters.NewOSAdapter(), WithCompression(c))
+		require.NoError(t, err)
+		w.RunAsync()
+		require.NoError(t, w.LogRecord(uint64(i+1), "test-table", record))
+		require.NoError(t, w.Close())
+	}
+
+	w, err := Open(log.NewNopLogger(), dir, adapters.NewOSAdapter())
+	require.NoError(t, err)
+	w.RunAsync()
+	sizes := map[walpb.Entry_Write_Compression]int{}
+	require.NoError(t, w.Replay(0, func(tx uint64, r *walpb.Record) error {
+		write := r.Entry.GetWrite()
+		require.Equal(t, compressions[tx-1], write.Compression)
+		sizes[write.Compression] = len(write.Data)
+
+		replayed := readRecord(t, write.Data)
+		defer replayed.Release()
+		require.True(t, array.RecordEqual(record, replayed))
+		return nil
+	}))
+	require.Len(t, sizes, len(compressions))
+	require.Less(t, sizes[walpb.Entry_Write_COMPRESSION_ZSTD], sizes[walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED])
+	require.NoError(t, w.Close())
+}
+
+// BenchmarkWALCompression compares the size of the WAL and the time it takes
+// to replay it for each compression.
+func BenchmarkWALCompression(b *testing.B) {
+	const records = 100
+	record := labelsRecord(10_000)
+	defer record.Release()
+
+	for _, c := range []walpb.Entry_Write_Compression{
+		walpb.Entry_Write_COMPRESSION_NONE_UNSPECIFIED,
+		walpb.Entry_Write_COMPRESSION_ZSTD,
+	} {
+		b.Run(c.String(), func(b *testing.B) {
+			dir := b.TempDir()
+			w, err := Open(log.NewNopLogger(), dir, adapters.NewOSAdapter(), WithCompression(c))
+			require.NoError(b, err)
+			w.RunAsync()
+			for tx := uint64(1); tx <= records; tx++ {
+				require.NoError(b, w.LogRecord(tx, "test-table", record))
+			}
+			require.NoError(b, w.Close())
+
+			size := 0
+			w, err = Open(log.NewNopLogger(), dir, adapters.NewOSAdapter())
+			require.NoError(b, err)
+			w.RunAsync()
+			defer w.Close()
+			require.NoError(b, w.Replay(0, func(_ uint64, r *walpb.Record) error {
+				size += len(r.Entry.GetWrite().Data)
+				return nil
+			}))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, w.Replay(0, func(_ uint64, r *walpb.Record) error {
+					readRecord(b, r.Entry.GetWrite().Data).Release()
+					return nil
+				}))
+			}
+			b.ReportMetric(float64(size)/records, "bytes/record")
+		})
+	}
+}
+
+func TestWALTail(t *testing.T) {
+	w, err := Open(
+		log.NewNopLogger(),
+		t.TempDir(),
+		adapters.NewOSAdapter(),
+	)
+	require.NoError(t, err)
+	w.RunAsync()
+
+	logRecord := func(tx uint64) {
+		require.NoError(t, w.Log(tx, &walpb.Record{
+			Entry: &walpb.Entry{
+				EntryType: &walpb.Entry_Write_{
+					Write: &walpb.Entry_Write{
+						Data:      []byte(fmt.Sprintf("test-data-%d", tx)),
+						TableName: "test-table",
+					},
+				},
+			},
+		}))
+	}
+	for tx := uint64(1); tx <= 3; tx++ {
+		logRecord(tx)
+	}
+
+	// Tailing starts with the records already written and receives the new
+	// ones as they are written.
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan uint64)
+	errCh := make(chan error)
+	go func() {
+		errCh <- w.Tail(ctx, 2, func(tx uint64, r *walpb.Record) error {
+			require.Equal(t, []byte(fmt.Sprintf("test-data-%d", tx)), r.Entry.GetWrite().Data)
+			received <- tx
+			return nil
+		})
+	}()
+	require.Equal(t, uint64(2), <-received)
+	require.Equal(t, uint64(3), <-received)
+	logRecord(4)
+	require.Equal(t, uint64(4), <-received)
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+
+	// Records that were truncated cannot be tailed.
+	require.NoError(t, w.Truncate(3))
+	require.Eventually(t, func() bool {
+		first, _ := w.FirstIndex()
+		return first == 3
+	}, time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, w.Tail(context.Background(), 2, func(uint64, *walpb.Record) error {
+		return nil
+	}), ErrTruncated)
+
+	// Tailing stops once the WAL is closed.
+	go func() {
+		errCh <- w.Tail(context.Background(), 3, func(uint64, *walpb.Record) error {
+			return nil
+		})
+	}()
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, <-errCh, ErrClosed)
+}
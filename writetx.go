@@ -0,0 +1,136 @@
+package frostdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/youscentia/ydb-frostdb/dynparquet"
+)
+
+// WriteTx stages the inserts of a write transaction started with DB.Update.
+// The staged inserts are only applied once the function given to DB.Update
+// returns.
+type WriteTx struct {
+	db     *DB
+	writes []stagedWrite
+}
+
+type stagedWrite struct {
+	table  *Table
+	record arrow.Record
+}
+
+// InsertRecord stages the insert of the record into the table with the given
+// name. The record is retained until the transaction is done.
+func (tx *WriteTx) InsertRecord(table string, record arrow.Record) error {
+	t, err := tx.db.GetTable(table)
+	if err != nil {
+		return err
+	}
+	record.Retain()
+	tx.writes = append(tx.writes, stagedWrite{table: t, record: record})
+	return nil
+}
+
+func (tx *WriteTx) release() {
+	for _, w := range tx.writes {
+		w.record.Release()
+	}
+	tx.writes = nil
+}
+
+// Update calls fn with a write transaction and applies the inserts staged by
+// fn into any number of tables of the database under a single tx. The inserts
+// are logged as one WAL record and become visible to readers at once, so
+// neither concurrent readers nor recovery after a crash observe only some of
+// them. Nothing is written if fn returns an error. Update returns the tx of
+// the inserts, or zero if none were staged. A synchronous update that fails
+// with ErrDurabilityUnknown was applied nonetheless.
+func (db *DB) Update(ctx context.Context, fn func(tx *WriteTx) error) (uint64, error) {
+	if db.leader != nil {
+		return 0, ErrFollower
+	}
+	wtx := &WriteTx{db: db}
+	defer wtx.release()
+	if err := fn(wtx); err != nil {
+		return 0, err
+	}
+	if len(wtx.writes) == 0 {
+		return 0, nil
+	}
+
+	// The writers of all blocks are registered before the tx begins, so no
+	// block is rotated before all inserts are applied. Tables are acquired in
+	// order of their names to not deadlock with concurrent updates.
+	tables := make([]*Table, 0, len(wtx.writes))
+	blocks := make(map[*Table]*TableBlock, len(wtx.writes))
+	for _, w := range wtx.writes {
+		if _, ok := blocks[w.table]; !ok {
+			blocks[w.table] = nil
+			tables = append(tables, w.table)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name() < tables[j].Name()
+	})
+	for _, table := range tables {
+		block, finish, err := table.appender(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("get appender of table %s: %w", table.Name(), err)
+		}
+		defer finish()
+		blocks[table] = block
+	}
+
+	tx, _, commit := db.begin()
+	defer commit()
+
+	// The writes are logged to the WAL of their table, which is the WAL of
+	// the database unless the table disabled it.
+	type walWrites struct {
+		tables  []string
+		records []arrow.Record
+	}
+	var wals []WAL
+	writesByWAL := map[WAL]*walWrites{}
+	records := make([]arrow.Record, 0, len(wtx.writes))
+	defer func() {
+		for _, r := range records {
+			r.Release()
+		}
+	}()
+	for _, w := range wtx.writes {
+		record := dynparquet.PrehashColumns(w.table.schema.Load(), w.record)
+		records = append(records, record)
+		ww, ok := writesByWAL[w.table.wal]
+		if !ok {
+			ww = &walWrites{}
+			writesByWAL[w.table.wal] = ww
+			wals = append(wals, w.table.wal)
+		}
+		ww.tables = append(ww.tables, w.table.Name())
+		ww.records = append(ww.records, record)
+	}
+	for _, wal := range wals {
+		ww := writesByWAL[wal]
+		if err := wal.LogRecords(tx, ww.tables, ww.records); err != nil {
+			return tx, fmt.Errorf("append to log: %w", err)
+		}
+	}
+
+	for i, w := range wtx.writes {
+		if err := blocks[w.table].InsertRecord(ctx, tx, records[i]); err != nil {
+			return tx, fmt.Errorf("insert buffer into block of table %s: %w", w.table.Name(), err)
+		}
+	}
+
+	for _, wal := range wals {
+		if err := db.waitDurable(ctx, wal, tx); err != nil {
+			return tx, err
+		}
+	}
+	return tx, nil
+}